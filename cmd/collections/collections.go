@@ -0,0 +1,147 @@
+package collections
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stellar/freighter-backend-v2/internal/config"
+	"github.com/stellar/freighter-backend-v2/internal/db"
+	"github.com/stellar/freighter-backend-v2/internal/logger"
+	"github.com/stellar/freighter-backend-v2/internal/store"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+// CollectionsCmd edits the collections table that serve discovers
+// collectibles from. Running servers pick changes up on their next registry
+// refresh (--collections-refresh-interval), without a restart.
+type CollectionsCmd struct {
+	Cfg *config.Config
+}
+
+func (c *CollectionsCmd) Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "collections",
+		Short:         "Manage the registered collectible collections (add/remove/list)",
+		SilenceErrors: true,
+		PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
+			// Like migrate, this needs only DATABASE_URL.
+			if c.Cfg.DatabaseConfig.URL == "" {
+				c.Cfg.DatabaseConfig.URL = os.Getenv("DATABASE_URL")
+			}
+			return c.Cfg.DatabaseConfig.Validate()
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&c.Cfg.DatabaseConfig.URL, "database-url", "", "PostgreSQL connection string (env DATABASE_URL). Required.")
+
+	var actor string
+	requireActor := func() error {
+		if actor == "" {
+			return fmt.Errorf("--actor is required: it names who made the change in the audit log")
+		}
+		return nil
+	}
+
+	var enumeration, label string
+	add := &cobra.Command{
+		Use:   "add <network> <contract-id>",
+		Short: "Register a collection, or update an existing one",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entry := types.RegisteredCollection{
+				Network:     strings.ToUpper(args[0]),
+				ContractID:  args[1],
+				Enumeration: types.CollectionEnumeration(enumeration),
+				Label:       label,
+			}
+			if err := requireActor(); err != nil {
+				return err
+			}
+			if err := entry.Validate(); err != nil {
+				return err
+			}
+			return c.withStore(cmd.Context(), func(s *store.CollectionsStore) error {
+				if _, err := s.UpsertCollection(cmd.Context(), actor, entry); err != nil {
+					return err
+				}
+				logger.Info("Registered collection", "network", entry.Network, "contract_id", entry.ContractID, "enumeration", entry.Enumeration)
+				return nil
+			})
+		},
+	}
+	add.Flags().StringVar(&enumeration, "enumeration", string(types.CollectionEnumerationOwnerTokens), fmt.Sprintf("How the contract lists an owner's tokens: %q (get_owner_tokens) or %q (balance + get_owner_token_id)", types.CollectionEnumerationOwnerTokens, types.CollectionEnumerationOwnerIndex))
+	add.Flags().StringVar(&label, "label", "", "Operator-facing name for the collection; never returned to clients")
+	add.Flags().StringVar(&actor, "actor", os.Getenv("USER"), "Who is making the change, as recorded in the audit log")
+	cmd.AddCommand(add)
+
+	remove := &cobra.Command{
+		Use:   "remove <network> <contract-id>",
+		Short: "Unregister a collection",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireActor(); err != nil {
+				return err
+			}
+			network, contractID := strings.ToUpper(args[0]), args[1]
+			return c.withStore(cmd.Context(), func(s *store.CollectionsStore) error {
+				removed, err := s.DeleteCollection(cmd.Context(), actor, network, contractID)
+				if err != nil {
+					return err
+				}
+				if !removed {
+					return fmt.Errorf("%s is not registered on %s", contractID, network)
+				}
+				logger.Info("Removed collection", "network", network, "contract_id", contractID)
+				return nil
+			})
+		},
+	}
+	remove.Flags().StringVar(&actor, "actor", os.Getenv("USER"), "Who is making the change, as recorded in the audit log")
+	cmd.AddCommand(remove)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list [network]",
+		Short: "List registered collections, optionally for one network",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var network string
+			if len(args) == 1 {
+				network = strings.ToUpper(args[0])
+			}
+			return c.withStore(cmd.Context(), func(s *store.CollectionsStore) error {
+				entries, err := s.ListCollections(cmd.Context(), network)
+				if err != nil {
+					return err
+				}
+				w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+				_, _ = fmt.Fprintln(w, "NETWORK\tCONTRACT ID\tENUMERATION\tLABEL")
+				for _, e := range entries {
+					_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.Network, e.ContractID, e.Enumeration, e.Label)
+				}
+				return w.Flush()
+			})
+		},
+	})
+
+	return cmd
+}
+
+// withStore opens a short-lived pool with the package defaults for one
+// subcommand.
+func (c *CollectionsCmd) withStore(ctx context.Context, fn func(*store.CollectionsStore) error) error {
+	pool, err := db.OpenDBConnectionPool(ctx, c.Cfg.DatabaseConfig.URL)
+	if err != nil {
+		return fmt.Errorf("connecting to the database: %w", err)
+	}
+	defer pool.Close()
+	return fn(store.NewCollectionsStore(pool))
+}
+
+// Run satisfies the SubCommand interface; the real work lives in the
+// add/remove/list subcommands.
+func (c *CollectionsCmd) Run() error { return nil }
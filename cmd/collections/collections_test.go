@@ -0,0 +1,85 @@
+package collections
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/config"
+)
+
+func TestCollectionsCmd_RejectsEmptyDatabaseURL(t *testing.T) {
+	// No t.Parallel(): t.Setenv controls DATABASE_URL process-wide.
+	t.Setenv("DATABASE_URL", "")
+
+	collectionsCmd := &CollectionsCmd{Cfg: &config.Config{}}
+	cmd := collectionsCmd.Command()
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"list"})
+
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "database-url")
+}
+
+func TestCollectionsCmd_AddRejectsInvalidEntries(t *testing.T) {
+	t.Parallel()
+
+	// Each case must fail validation before any attempt to reach the database.
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"add", "MAINNET", "CBIELTK6YBZJU5UP2WWQEUCYKLPU6AUNZ2BQ4WWFEIE3USCIHMXQDAMA"}, "network must be"},
+		{[]string{"add", "PUBLIC", "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"}, "invalid contract_id"},
+		{[]string{"add", "PUBLIC", "CBIELTK6YBZJU5UP2WWQEUCYKLPU6AUNZ2BQ4WWFEIE3USCIHMXQDAMA", "--enumeration", "get_tokens"}, "enumeration must be"},
+	} {
+		collectionsCmd := &CollectionsCmd{Cfg: &config.Config{}}
+		cmd := collectionsCmd.Command()
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs(append(tc.args, "--database-url", "postgres://localhost/test", "--actor", "alice"))
+
+		err := cmd.Execute()
+		require.Error(t, err, "args %v should be rejected", tc.args)
+		assert.Contains(t, err.Error(), tc.want)
+	}
+}
+
+func TestCollectionsCmd_RejectsWrongArgCounts(t *testing.T) {
+	t.Parallel()
+
+	for _, args := range [][]string{
+		{"add", "PUBLIC"},
+		{"remove", "PUBLIC"},
+		{"list", "PUBLIC", "TESTNET"},
+	} {
+		collectionsCmd := &CollectionsCmd{Cfg: &config.Config{}}
+		cmd := collectionsCmd.Command()
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs(append(args, "--database-url", "postgres://localhost/test"))
+
+		require.Error(t, cmd.Execute(), "args %v should be rejected", args)
+	}
+}
+
+func TestCollectionsCmd_MutationsRequireActor(t *testing.T) {
+	t.Parallel()
+
+	for _, args := range [][]string{
+		{"add", "PUBLIC", "CBIELTK6YBZJU5UP2WWQEUCYKLPU6AUNZ2BQ4WWFEIE3USCIHMXQDAMA"},
+		{"remove", "PUBLIC", "CBIELTK6YBZJU5UP2WWQEUCYKLPU6AUNZ2BQ4WWFEIE3USCIHMXQDAMA"},
+	} {
+		collectionsCmd := &CollectionsCmd{Cfg: &config.Config{}}
+		cmd := collectionsCmd.Command()
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs(append(args, "--database-url", "postgres://localhost/test", "--actor", ""))
+
+		assert.ErrorContains(t, cmd.Execute(), "--actor is required", "args %v", args)
+	}
+}
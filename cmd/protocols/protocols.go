@@ -0,0 +1,110 @@
+package protocols
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stellar/freighter-backend-v2/internal/config"
+	"github.com/stellar/freighter-backend-v2/internal/db"
+	"github.com/stellar/freighter-backend-v2/internal/logger"
+	"github.com/stellar/freighter-backend-v2/internal/store"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+// ProtocolsCmd loads the Discover catalog into the protocols table that
+// serve reads with --protocols-source=database. Running servers pick the new
+// catalog up on their next refresh (--protocols-refresh-interval).
+type ProtocolsCmd struct {
+	Cfg *config.Config
+}
+
+func (c *ProtocolsCmd) Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "protocols",
+		Short:         "Manage the protocols catalog served by GET /api/v1/protocols",
+		SilenceErrors: true,
+		PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
+			// Like migrate, this needs only DATABASE_URL.
+			if c.Cfg.DatabaseConfig.URL == "" {
+				c.Cfg.DatabaseConfig.URL = os.Getenv("DATABASE_URL")
+			}
+			return c.Cfg.DatabaseConfig.Validate()
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&c.Cfg.DatabaseConfig.URL, "database-url", "", "PostgreSQL connection string (env DATABASE_URL). Required.")
+
+	var networks []string
+	var rules types.ProtocolRules
+	var actor string
+	importCmd := &cobra.Command{
+		Use:   "import <protocols.json>",
+		Short: "Replace the catalog with the protocols in a protocols.json file",
+		Long: "Replace the whole catalog with the protocols listed in a protocols.json file, in file order. " +
+			"Entries without a \"networks\" list are made available on --networks.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if actor == "" {
+				return fmt.Errorf("--actor is required: it names who made the change in the audit log")
+			}
+			protocols, err := readProtocolsFile(args[0], networks, rules)
+			if err != nil {
+				return err
+			}
+			pool, err := db.OpenDBConnectionPool(cmd.Context(), c.Cfg.DatabaseConfig.URL)
+			if err != nil {
+				return fmt.Errorf("connecting to the database: %w", err)
+			}
+			defer pool.Close()
+			if err := store.NewProtocolsStore(pool).ReplaceProtocols(cmd.Context(), actor, protocols); err != nil {
+				return err
+			}
+			logger.Info("Imported protocols", "path", args[0], "count", len(protocols))
+			return nil
+		},
+	}
+	importCmd.Flags().StringSliceVar(&networks, "networks", []string{types.PUBLIC, types.TESTNET, types.FUTURENET}, "Networks assigned to entries that don't list their own")
+	importCmd.Flags().StringSliceVar(&rules.AllowedTags, "allowed-tags", nil, "Tags the file may use, as serve's --protocols-allowed-tags. Empty accepts any tag")
+	importCmd.Flags().BoolVar(&rules.Strict, "strict", false, "Also require descriptions and https URLs, as serve's --protocols-strict-validation")
+	importCmd.Flags().StringVar(&actor, "actor", os.Getenv("USER"), "Who is making the change, as recorded in the audit log")
+	cmd.AddCommand(importCmd)
+
+	return cmd
+}
+
+// readProtocolsFile parses and validates a protocols.json file against rules,
+// filling in defaultNetworks where an entry lists none.
+func readProtocolsFile(path string, defaultNetworks []string, rules types.ProtocolRules) ([]types.Protocol, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	var protocols []types.Protocol
+	if err := json.Unmarshal(data, &protocols); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	for i := range protocols {
+		if len(protocols[i].Networks) == 0 {
+			if len(defaultNetworks) == 0 {
+				return nil, fmt.Errorf("%s: protocol %q lists no networks and --networks is empty", path, protocols[i].Name)
+			}
+			protocols[i].Networks = slices.Clone(defaultNetworks)
+		}
+		for j, network := range protocols[i].Networks {
+			protocols[i].Networks[j] = strings.ToUpper(network)
+		}
+	}
+	if err := types.ValidateProtocols(protocols, rules); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return protocols, nil
+}
+
+// Run satisfies the SubCommand interface; the real work lives in the import
+// subcommand.
+func (c *ProtocolsCmd) Run() error { return nil }
@@ -0,0 +1,88 @@
+package protocols
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/config"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+func writeProtocolsFile(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "protocols.json")
+	require.NoError(t, os.WriteFile(path, []byte(body), 0o600))
+	return path
+}
+
+func TestReadProtocolsFile_FillsDefaultNetworks(t *testing.T) {
+	t.Parallel()
+
+	protocols, err := readProtocolsFile("../../internal/api/handlers/testdata/protocols.json", []string{types.PUBLIC}, types.ProtocolRules{})
+	require.NoError(t, err)
+	require.Len(t, protocols, 3)
+	assert.Equal(t, "Blend", protocols[0].Name)
+	for _, p := range protocols {
+		assert.Equal(t, []string{types.PUBLIC}, p.Networks)
+	}
+
+	path := writeProtocolsFile(t, `[{"name": "Blend", "description": "d", "website_url": "https://blend.capital", "icon_url": "https://icons/blend.svg", "networks": ["testnet"]}]`)
+	protocols, err = readProtocolsFile(path, []string{types.PUBLIC}, types.ProtocolRules{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{types.TESTNET}, protocols[0].Networks, "an entry's own networks win and are upper-cased")
+}
+
+func TestReadProtocolsFile_RejectsInvalidFiles(t *testing.T) {
+	t.Parallel()
+
+	for name, body := range map[string]string{
+		"not json":        `{`,
+		"missing name":    `[{"description": "d", "website_url": "https://blend.capital", "icon_url": "https://icons/blend.svg"}]`,
+		"duplicate name":  `[{"name": "Blend", "description": "d", "website_url": "https://a", "icon_url": "https://b"}, {"name": "Blend", "description": "d", "website_url": "https://a", "icon_url": "https://b"}]`,
+		"missing icon":    `[{"name": "Blend", "description": "d", "website_url": "https://blend.capital"}]`,
+		"unknown network": `[{"name": "Blend", "description": "d", "website_url": "https://a", "icon_url": "https://b", "networks": ["MAINNET"]}]`,
+		"unknown tag":     `[{"name": "Blend", "description": "d", "tags": ["Lendng"], "website_url": "https://a", "icon_url": "https://b"}]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := readProtocolsFile(writeProtocolsFile(t, body), []string{types.PUBLIC}, types.ProtocolRules{AllowedTags: []string{"Lending"}, Strict: true})
+			assert.Error(t, err)
+		})
+	}
+
+	path := writeProtocolsFile(t, `[{"name": "Blend", "description": "d", "website_url": "https://a", "icon_url": "https://b"}]`)
+	_, err := readProtocolsFile(path, nil, types.ProtocolRules{})
+	assert.ErrorContains(t, err, "lists no networks")
+}
+
+func TestProtocolsCmd_ImportRejectsBeforeConnecting(t *testing.T) {
+	t.Parallel()
+
+	protocolsCmd := &ProtocolsCmd{Cfg: &config.Config{}}
+	cmd := protocolsCmd.Command()
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"import", filepath.Join(t.TempDir(), "missing.json"), "--database-url", "postgres://localhost/test", "--actor", "alice"})
+
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestProtocolsCmd_ImportRequiresActor(t *testing.T) {
+	t.Parallel()
+
+	protocolsCmd := &ProtocolsCmd{Cfg: &config.Config{}}
+	cmd := protocolsCmd.Command()
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"import", "../../internal/api/handlers/testdata/protocols.json", "--database-url", "postgres://localhost/test", "--actor", ""})
+
+	err := cmd.Execute()
+	assert.ErrorContains(t, err, "--actor is required")
+}
@@ -3,7 +3,9 @@ package cmd
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/stellar/freighter-backend-v2/cmd/collections"
 	"github.com/stellar/freighter-backend-v2/cmd/migrate"
+	"github.com/stellar/freighter-backend-v2/cmd/protocols"
 	"github.com/stellar/freighter-backend-v2/cmd/serve"
 	"github.com/stellar/freighter-backend-v2/internal/config"
 	"github.com/stellar/freighter-backend-v2/internal/logger"
@@ -40,6 +42,12 @@ func NewRootCmd() *RootCmd {
 		&migrate.MigrateCmd{
 			Cfg: &config.Config{},
 		},
+		&collections.CollectionsCmd{
+			Cfg: &config.Config{},
+		},
+		&protocols.ProtocolsCmd{
+			Cfg: &config.Config{},
+		},
 	}
 	for _, subcmd := range subcommands {
 		cmd.AddCommand(subcmd.Command())
@@ -2,6 +2,7 @@ package serve
 
 import (
 	"fmt"
+	"net/url"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -42,9 +43,107 @@ func (s *ServeCmd) Command() *cobra.Command {
 			if n := s.Cfg.PricesConfig.PriceFetchTimeoutSeconds; n < 0 {
 				return fmt.Errorf("--price-fetch-timeout-seconds=%d must be >= 0", n)
 			}
+			if n := s.Cfg.PricesConfig.PriceStaleTTLSeconds; n < 0 {
+				return fmt.Errorf("--price-stale-ttl-seconds=%d must be >= 0", n)
+			}
+			if n := s.Cfg.PricesConfig.HotPriceTokens; n < 0 {
+				return fmt.Errorf("--hot-price-tokens=%d must be >= 0", n)
+			}
+			if d := s.Cfg.PricesConfig.HotPriceRefreshInterval; d <= 0 {
+				return fmt.Errorf("--hot-price-refresh-interval=%s must be positive", d)
+			}
+			if n := s.Cfg.PricesConfig.FXRatesCacheTTLSeconds; n <= 0 {
+				return fmt.Errorf("--fx-rates-cache-ttl-seconds=%d must be positive", n)
+			}
 			if d, m := s.Cfg.AppConfig.AccountHistoryDefaultLimit, s.Cfg.AppConfig.AccountHistoryMaxLimit; d <= 0 || m <= 0 || d > m || m > handlers.AccountHistoryUpstreamMaxLimit {
 				return fmt.Errorf("--account-history-default-limit=%d / --account-history-max-limit=%d must be positive, default <= max, and max <= %d", d, m, handlers.AccountHistoryUpstreamMaxLimit)
 			}
+			// Every Blockaid route is off by default; turning one on without a key
+			// would register a route that 502s on every request.
+			if s.Cfg.BlockaidConfig.AnyEnabled() && s.Cfg.BlockaidConfig.BlockaidAPIKey == "" {
+				return fmt.Errorf("--blockaid-api-key is required when any --use-blockaid-* flag is enabled")
+			}
+			if s.Cfg.BlockaidConfig.ReportingEnabled() && !s.Cfg.DatabaseConfig.Enabled {
+				return fmt.Errorf("--use-blockaid-*-warning-reporting requires the database; it cannot be enabled with --db-enabled=false")
+			}
+			if n := s.Cfg.RpcConfig.MaxConcurrentRPCCalls; n <= 0 {
+				return fmt.Errorf("--max-concurrent-rpc-calls=%d must be positive", n)
+			}
+			if n := s.Cfg.AppConfig.MaxTokenDetailsContracts; n <= 0 {
+				return fmt.Errorf("--max-token-details-contracts=%d must be positive", n)
+			}
+			if n := s.Cfg.AppConfig.MaxContractCalls; n <= 0 {
+				return fmt.Errorf("--max-contract-calls=%d must be positive", n)
+			}
+			if n := s.Cfg.AppConfig.TokenBalanceCacheTTLSeconds; n < 0 {
+				return fmt.Errorf("--token-balance-cache-ttl-seconds=%d must be >= 0", n)
+			}
+			if n := s.Cfg.BlockaidConfig.BlockaidCacheTTLSeconds; n < 0 {
+				return fmt.Errorf("--blockaid-cache-ttl-seconds=%d must be >= 0", n)
+			}
+			switch s.Cfg.AppConfig.ProtocolsSource {
+			case config.ProtocolsSourceFile:
+			case config.ProtocolsSourceDatabase:
+				if !s.Cfg.DatabaseConfig.Enabled {
+					return fmt.Errorf("--protocols-source=%s requires the database; it cannot be used with --db-enabled=false", config.ProtocolsSourceDatabase)
+				}
+			default:
+				return fmt.Errorf("--protocols-source=%q must be %q or %q", s.Cfg.AppConfig.ProtocolsSource, config.ProtocolsSourceFile, config.ProtocolsSourceDatabase)
+			}
+			if d := s.Cfg.AppConfig.ProtocolsRefreshInterval; d <= 0 {
+				return fmt.Errorf("--protocols-refresh-interval=%s must be positive", d)
+			}
+			if err := validateAdminListener(s.Cfg); err != nil {
+				return err
+			}
+			if err := validatePriceProviders(s.Cfg.PricesConfig); err != nil {
+				return err
+			}
+			switch s.Cfg.AppConfig.FeatureFlagsSource {
+			case config.FeatureFlagsSourceBuiltin:
+			case config.FeatureFlagsSourceFile:
+				if s.Cfg.AppConfig.FeatureFlagsPath == "" {
+					return fmt.Errorf("--feature-flags-source=%s requires --feature-flags-path", config.FeatureFlagsSourceFile)
+				}
+			case config.FeatureFlagsSourceDatabase:
+				if !s.Cfg.DatabaseConfig.Enabled {
+					return fmt.Errorf("--feature-flags-source=%s requires the database; it cannot be used with --db-enabled=false", config.FeatureFlagsSourceDatabase)
+				}
+			default:
+				return fmt.Errorf("--feature-flags-source=%q must be %q, %q or %q", s.Cfg.AppConfig.FeatureFlagsSource,
+					config.FeatureFlagsSourceBuiltin, config.FeatureFlagsSourceFile, config.FeatureFlagsSourceDatabase)
+			}
+			if d := s.Cfg.AppConfig.FeatureFlagsRefreshInterval; d <= 0 {
+				return fmt.Errorf("--feature-flags-refresh-interval=%s must be positive", d)
+			}
+			if c := s.Cfg.CollectiblesConfig; c.CollectionsRegistryPath != "" && len(c.MeridianPayAddresses()) > 0 {
+				return fmt.Errorf("the deprecated --meridian-pay-* flags cannot be combined with --collections-registry-path; list those contracts in the registry file instead")
+			}
+			if n := s.Cfg.CollectiblesConfig.MaxDiscoveredTokens; n <= 0 {
+				return fmt.Errorf("--max-discovered-tokens=%d must be positive", n)
+			}
+			if d := s.Cfg.CollectiblesConfig.CollectionsRefreshInterval; d <= 0 {
+				return fmt.Errorf("--collections-refresh-interval=%s must be positive", d)
+			}
+			if n := s.Cfg.CollectiblesConfig.MetadataCacheTTLSeconds; n < 0 {
+				return fmt.Errorf("--collectible-metadata-cache-ttl-seconds=%d must be >= 0", n)
+			}
+			if n := s.Cfg.CollectiblesConfig.MetadataMaxBytes; n <= 0 {
+				return fmt.Errorf("--collectible-metadata-max-bytes=%d must be positive", n)
+			}
+			if d := s.Cfg.CollectiblesConfig.MetadataFetchTimeout; d <= 0 {
+				return fmt.Errorf("--collectible-metadata-fetch-timeout=%s must be positive", d)
+			}
+			if s.Cfg.CollectiblesConfig.ResolveMetadata {
+				if u, err := url.Parse(s.Cfg.CollectiblesConfig.IPFSGatewayURL); err != nil || u.Scheme != "https" || u.Host == "" {
+					return fmt.Errorf("--ipfs-gateway-url=%q must be an https URL", s.Cfg.CollectiblesConfig.IPFSGatewayURL)
+				}
+			}
+			// Half a CDP credential is always a mistake: the onramp route would
+			// silently stay off instead of failing where the operator can see it.
+			if c := s.Cfg.CoinbaseConfig; (c.CoinbaseAPIKey == "") != (c.CoinbaseAPISecret == "") {
+				return fmt.Errorf("--coinbase-api-key and --coinbase-api-secret must be set together")
+			}
 			if _, err := auth.ParseMode(s.Cfg.AppConfig.AuthMode); err != nil {
 				return fmt.Errorf("--auth-mode: %w", err)
 			}
@@ -83,21 +182,31 @@ func (s *ServeCmd) Command() *cobra.Command {
 	cmd.Flags().IntVar(&s.Cfg.AppConfig.FreighterBackendPort, "freighter-backend-port", 3002, "The port of the freighter backend server")
 	cmd.Flags().StringVar(&s.Cfg.AppConfig.MetricsHost, "metrics-host", "localhost", "The host of the internal metrics server (Prometheus /metrics)")
 	cmd.Flags().IntVar(&s.Cfg.AppConfig.MetricsPort, "metrics-port", 9090, "The port of the internal metrics server (Prometheus /metrics)")
+	cmd.Flags().StringVar(&s.Cfg.AppConfig.AdminHost, "admin-host", "localhost", "The host of the internal admin API server")
+	cmd.Flags().IntVar(&s.Cfg.AppConfig.AdminPort, "admin-port", 0, "The port of the internal admin API server, which edits feature flags and protocols in the database; 0 disables it")
+	cmd.Flags().StringToStringVar(&s.Cfg.AppConfig.AdminCredentials, "admin-credentials", nil, "Admin API bearer tokens as name=token pairs; the name is recorded as the actor in the audit log")
 	cmd.Flags().StringVar(&s.Cfg.AppConfig.Mode, "mode", "development", "The mode of the server")
 	cmd.Flags().StringVar(&s.Cfg.AppConfig.AuthMode, "auth-mode", "permissive", "JWT auth enforcement for gated routes: \"permissive\" (allow no-token requests, reject invalid tokens) or \"strict\" (require a valid token)")
 	cmd.Flags().DurationVar(&s.Cfg.AppConfig.AuthClockSkewLeeway, "auth-clock-skew-leeway", auth.ClockSkewLeeway, "Clock-skew tolerance for JWT iat/exp validation (e.g. 5s, 2m). Wider values tolerate more device clock drift but proportionally widen the token replay window; signature verification is unaffected.")
 	cmd.Flags().StringVar(&s.Cfg.AppConfig.SentryKey, "sentry-key", "", "The Sentry key")
 	cmd.Flags().StringVar(&s.Cfg.AppConfig.ProtocolsConfigPath, "protocols-config-path", "/app/config/protocols.json", "The path to the protocols config file while lists all supported protocols in Freighter")
-	cmd.Flags().StringVar(&s.Cfg.AppConfig.MeridianPayTreasureHuntAddress, "meridian-pay-treasure-hunt-address", "", "The Meridian Pay Treasure Hunt collection address")
-	cmd.Flags().StringVar(&s.Cfg.AppConfig.MeridianPayTreasurePoapAddress, "meridian-pay-poap-address", "", "The Meridian Pay Poap collection address")
-	cmd.Flags().StringVar(&s.Cfg.AppConfig.MeridianPayStellarHouseAddress, "meridian-pay-stellar-house-address", "", "The Meridian Pay Stellar House collection address")
+	cmd.Flags().StringVar(&s.Cfg.AppConfig.ProtocolsSource, "protocols-source", config.ProtocolsSourceFile, "Where GET /api/v1/protocols reads the catalog: \"file\" (--protocols-config-path, hot-reloaded) or \"database\" (the protocols table, loaded with `protocols import`)")
+	cmd.Flags().DurationVar(&s.Cfg.AppConfig.ProtocolsRefreshInterval, "protocols-refresh-interval", time.Minute, "How often the protocols catalog is re-read from its source; a file that changed is validated and swapped in only when valid")
+	cmd.Flags().StringSliceVar(&s.Cfg.AppConfig.ProtocolsAllowedTags, "protocols-allowed-tags", nil, "Tags protocols may use; a file with any other tag is rejected and the last good version kept. Empty accepts any tag")
+	cmd.Flags().BoolVar(&s.Cfg.AppConfig.ProtocolsStrictValidation, "protocols-strict-validation", false, "Also require each protocol to have a description and https website, icon and background URLs")
+	cmd.Flags().StringVar(&s.Cfg.AppConfig.FeatureFlagsSource, "feature-flags-source", config.FeatureFlagsSourceBuiltin, "Where GET /api/v1/feature-flags reads its flags: \"builtin\" (swap, discover and onramp on, except iOS 1.6.23), \"file\" (--feature-flags-path) or \"database\" (the feature_flags table)")
+	cmd.Flags().StringVar(&s.Cfg.AppConfig.FeatureFlagsPath, "feature-flags-path", "", "The path to a JSON file of feature flags, used with --feature-flags-source=file")
+	cmd.Flags().DurationVar(&s.Cfg.AppConfig.FeatureFlagsRefreshInterval, "feature-flags-refresh-interval", time.Minute, "How often feature flags are re-read from their source; a file that changed is validated and swapped in only when valid")
 	cmd.Flags().Int64Var(&s.Cfg.AppConfig.MaxRequestBodySize, "max-request-body-size", 1<<20, "Maximum request body size in bytes (default: 1MB)")
 	cmd.Flags().IntVar(&s.Cfg.AppConfig.MaxBalanceAddresses, "max-balance-addresses", 100, "Maximum number of addresses allowed in account balances request")
-	cmd.Flags().BoolVar(&s.Cfg.AppConfig.WalletBackendRoutesEnabled, "wallet-backend-routes-enabled", true, "Register the wallet-backend-fronted routes (POST /api/v1/accounts/balances and GET /api/v1/accounts/{address}/transactions). Set false (env WALLET_BACKEND_ROUTES_ENABLED) to leave both unregistered so their paths 404 — used where wallet-backend is not configured, since without it both routes 500 on every request.")
+	cmd.Flags().BoolVar(&s.Cfg.AppConfig.WalletBackendRoutesEnabled, "wallet-backend-routes-enabled", true, "Use wallet-backend: register GET /api/v1/accounts/{address}/transactions and serve POST /api/v1/accounts/balances from it on networks where it is configured. Set false (env WALLET_BACKEND_ROUTES_ENABLED) where wallet-backend is not configured: account history then 404s and balances are built from Horizon.")
 	cmd.Flags().IntVar(&s.Cfg.AppConfig.MaxLedgerKeyAddresses, "max-ledger-key-addresses", 100, "Maximum number of public keys allowed in a ledger-key/accounts request")
 	cmd.Flags().IntVar(&s.Cfg.AppConfig.WalletBackendBalanceConcurrency, "wallet-backend-balance-concurrency", 10, "Per-request maximum number of concurrent wallet-backend balance fetches (the /accounts/balances handler fans out to one accountByAddress call per address)")
 	cmd.Flags().IntVar(&s.Cfg.AppConfig.AccountHistoryDefaultLimit, "account-history-default-limit", 20, "Default page size for GET /accounts/{address}/transactions")
 	cmd.Flags().IntVar(&s.Cfg.AppConfig.AccountHistoryMaxLimit, "account-history-max-limit", 100, "Maximum page size for GET /accounts/{address}/transactions (upstream hard-caps at 100)")
+	cmd.Flags().IntVar(&s.Cfg.AppConfig.MaxTokenDetailsContracts, "max-token-details-contracts", 50, "Maximum number of contract IDs allowed in a token-details request")
+	cmd.Flags().IntVar(&s.Cfg.AppConfig.MaxContractCalls, "max-contract-calls", 20, "Maximum number of calls allowed in a contract-calls request")
+	cmd.Flags().IntVar(&s.Cfg.AppConfig.TokenBalanceCacheTTLSeconds, "token-balance-cache-ttl-seconds", 10, "TTL for cached SEP-41 token balances in Redis (seconds); 0 disables balance caching. Token metadata is cached without expiry.")
 
 	// RPC Config
 	cmd.Flags().StringVar(&s.Cfg.RpcConfig.PubnetRpcUrl, "pubnet-rpc-url", "", "The Pubnet URL of the Pubnet RPC instance")
@@ -124,7 +233,9 @@ func (s *ServeCmd) Command() *cobra.Command {
 	cmd.Flags().DurationVar(&s.Cfg.DatabaseConfig.MaxConnIdleTime, "db-max-conn-idle-time", 10*time.Second, "Maximum idle time before a pooled DB connection is closed")
 
 	// Blockaid Config
+	cmd.Flags().StringVar(&s.Cfg.BlockaidConfig.BlockaidBaseURL, "blockaid-base-url", services.DefaultBlockaidBaseURL, "Blockaid API base URL")
 	cmd.Flags().StringVar(&s.Cfg.BlockaidConfig.BlockaidAPIKey, "blockaid-api-key", "", "Blockaid API key")
+	cmd.Flags().IntVar(&s.Cfg.BlockaidConfig.BlockaidCacheTTLSeconds, "blockaid-cache-ttl-seconds", 3600, "TTL for cached Blockaid dapp and asset verdicts in Redis (seconds); 0 disables caching")
 	cmd.Flags().BoolVar(&s.Cfg.BlockaidConfig.UseBlockaidDappScanning, "use-blockaid-dapp-scanning", false, "Enable Blockaid dapp scanning")
 	cmd.Flags().BoolVar(&s.Cfg.BlockaidConfig.UseBlockaidTxScanning, "use-blockaid-tx-scanning", false, "Enable Blockaid transaction scanning")
 	cmd.Flags().BoolVar(&s.Cfg.BlockaidConfig.UseBlockaidAssetScanning, "use-blockaid-asset-scanning", false, "Enable Blockaid asset scanning")
@@ -132,8 +243,22 @@ func (s *ServeCmd) Command() *cobra.Command {
 	cmd.Flags().BoolVar(&s.Cfg.BlockaidConfig.UseBlockaidTransactionWarningReporting, "use-blockaid-transaction-warning-reporting", false, "Enable Blockaid transaction warning reporting")
 
 	// Coinbase Config
+	cmd.Flags().StringVar(&s.Cfg.CoinbaseConfig.CoinbaseBaseURL, "coinbase-base-url", services.DefaultCoinbaseBaseURL, "Coinbase Developer Platform API base URL")
 	cmd.Flags().StringVar(&s.Cfg.CoinbaseConfig.CoinbaseAPIKey, "coinbase-api-key", "", "Coinbase API key")
-	cmd.Flags().StringVar(&s.Cfg.CoinbaseConfig.CoinbaseAPISecret, "coinbase-api-secret", "", "Coinbase API secret")
+	cmd.Flags().StringVar(&s.Cfg.CoinbaseConfig.CoinbaseAPISecret, "coinbase-api-secret", "", "Coinbase API secret (EC private key PEM; literal \\n sequences are expanded)")
+
+	// Collectibles Config
+	cmd.Flags().StringVar(&s.Cfg.CollectiblesConfig.CollectionsRegistryPath, "collections-registry-path", "", "Path to a JSON file of collection contracts whose tokens POST /api/v1/collectibles discovers for the owner; empty uses the collections table when the database is enabled")
+	cmd.Flags().StringVar(&s.Cfg.CollectiblesConfig.MeridianPayTreasureHuntAddress, "meridian-pay-treasure-hunt-address", "", "Deprecated: list the collection in --collections-registry-path or the collections table. The Meridian Pay Treasure Hunt collection address")
+	cmd.Flags().StringVar(&s.Cfg.CollectiblesConfig.MeridianPayPoapAddress, "meridian-pay-poap-address", "", "Deprecated: list the collection in --collections-registry-path or the collections table. The Meridian Pay Poap collection address")
+	cmd.Flags().StringVar(&s.Cfg.CollectiblesConfig.MeridianPayStellarHouseAddress, "meridian-pay-stellar-house-address", "", "Deprecated: list the collection in --collections-registry-path or the collections table. The Meridian Pay Stellar House collection address")
+	cmd.Flags().IntVar(&s.Cfg.CollectiblesConfig.MaxDiscoveredTokens, "max-discovered-tokens", handlers.DefaultMaxDiscoveredTokens, "Maximum tokens discovered for the owner in one registered collection; a collection holding more is returned with \"truncated\": true")
+	cmd.Flags().DurationVar(&s.Cfg.CollectiblesConfig.CollectionsRefreshInterval, "collections-refresh-interval", time.Minute, "How often the collections table is re-read for collection discovery")
+	cmd.Flags().BoolVar(&s.Cfg.CollectiblesConfig.ResolveMetadata, "resolve-collectible-metadata", false, "Resolve each collectible's token_uri and return its SEP-50 metadata inline in POST /api/v1/collectibles")
+	cmd.Flags().StringVar(&s.Cfg.CollectiblesConfig.IPFSGatewayURL, "ipfs-gateway-url", services.DefaultIPFSGatewayURL, "Path-style HTTPS IPFS gateway used to fetch ipfs:// token URIs")
+	cmd.Flags().IntVar(&s.Cfg.CollectiblesConfig.MetadataCacheTTLSeconds, "collectible-metadata-cache-ttl-seconds", 86400, "TTL for cached collectible metadata in Redis (seconds); 0 disables caching")
+	cmd.Flags().Int64Var(&s.Cfg.CollectiblesConfig.MetadataMaxBytes, "collectible-metadata-max-bytes", 256<<10, "Maximum size of a collectible metadata document in bytes")
+	cmd.Flags().DurationVar(&s.Cfg.CollectiblesConfig.MetadataFetchTimeout, "collectible-metadata-fetch-timeout", 3*time.Second, "Timeout for fetching one collectible metadata document, redirects included")
 
 	// Wallet Backend Config
 	cmd.Flags().StringVar(&s.Cfg.WalletBackendConfig.PubnetUrl, "wallet-backend-pubnet-url", "", "Wallet backend pubnet URL")
@@ -147,12 +272,84 @@ func (s *ServeCmd) Command() *cobra.Command {
 	cmd.Flags().StringVar(&s.Cfg.PricesConfig.StellarExpertAPIKey, "stellar-expert-api-key", "", "Bearer token for the Stellar Expert API (required)")
 	cmd.Flags().StringVar(&s.Cfg.PricesConfig.StellarExpertOrigin, "stellar-expert-origin", "https://stellar.expert", "Origin header sent on Stellar Expert requests; Stellar Expert associates the API key with this origin (e.g. https://api.freighter.app in production)")
 	cmd.Flags().IntVar(&s.Cfg.PricesConfig.PriceCacheTTLSeconds, "price-cache-ttl-seconds", 30, "TTL for cached token prices in Redis (seconds)")
+	cmd.Flags().IntVar(&s.Cfg.PricesConfig.PriceStaleTTLSeconds, "price-stale-ttl-seconds", 300, "How long past --price-cache-ttl-seconds a cached token price is still served while it refreshes in the background (seconds); 0 disables stale serving")
+	cmd.Flags().IntVar(&s.Cfg.PricesConfig.HotPriceTokens, "hot-price-tokens", 100, "How many of each network's most requested tokens have their prices refreshed ahead of expiry; 0 disables the refresher")
+	cmd.Flags().DurationVar(&s.Cfg.PricesConfig.HotPriceRefreshInterval, "hot-price-refresh-interval", 10*time.Second, "How often the most requested token prices are checked and refreshed if they would turn stale before the next check")
 	cmd.Flags().IntVar(&s.Cfg.PricesConfig.PriceFetchTimeoutSeconds, "price-fetch-timeout-seconds", 9, "Budget for uncached token price fetches before returning best-effort results (seconds)")
 	cmd.Flags().IntVar(&s.Cfg.PricesConfig.MaxTokensPerRequest, "max-tokens-per-request", 1000, "Maximum tokens accepted in a single token-prices request")
-	cmd.Flags().IntVar(&s.Cfg.PricesConfig.MaxConcurrentPriceFetches, "max-concurrent-price-fetches", 25, "Per-request token-in-flight cap; each token issues GetAsset and GetAssetCandles in parallel, so the upstream HTTP-call ceiling is up to 2× this value")
+	cmd.Flags().StringSliceVar(&s.Cfg.PricesConfig.PriceProviders, "price-providers", []string{services.PriceProviderStellarExpert}, "Spot price providers in priority order: stellar-expert, price-feed")
+	cmd.Flags().StringVar(&s.Cfg.PricesConfig.PriceAggregation, "price-aggregation", services.PriceAggregationFirstSuccess, "How provider prices are combined: first-success (first provider in priority order with a price) or median (all providers, median price)")
+	cmd.Flags().StringVar(&s.Cfg.PricesConfig.PriceFeedPubnetURL, "price-feed-pubnet-url", "", "HTTP price feed endpoint for pubnet, queried as GET <url>?asset=<id>")
+	cmd.Flags().StringVar(&s.Cfg.PricesConfig.PriceFeedTestnetURL, "price-feed-testnet-url", "", "HTTP price feed endpoint for testnet, queried as GET <url>?asset=<id>")
+	cmd.Flags().StringVar(&s.Cfg.PricesConfig.PriceFeedAPIKey, "price-feed-api-key", "", "Bearer token for the HTTP price feed")
+	cmd.Flags().StringVar(&s.Cfg.PricesConfig.FXRatesURL, "fx-rates-url", services.DefaultFrankfurterBaseURL, "Frankfurter API base URL for the exchange rates used to convert token prices to other currencies")
+	cmd.Flags().IntVar(&s.Cfg.PricesConfig.FXRatesCacheTTLSeconds, "fx-rates-cache-ttl-seconds", int(services.DefaultFXRatesCacheTTL/time.Second), "TTL for the cached exchange rates table in Redis (seconds)")
+	cmd.Flags().IntVar(&s.Cfg.PricesConfig.MaxConcurrentPriceFetches, "max-concurrent-price-fetches", 25, "Per-request token-in-flight cap; with Stellar Expert each token issues GetAsset and GetAssetCandles in parallel, so the upstream HTTP-call ceiling is up to 2× this value (more with --price-aggregation=median)")
 	return cmd
 }
 
+// MinAdminTokenLength is the shortest admin token serve accepts.
+const MinAdminTokenLength = 32
+
+// validateAdminListener checks the admin API settings when it is enabled: it
+// writes to the database, needs at least one credential, and must not share a
+// port with the public or metrics listener.
+func validateAdminListener(cfg *config.Config) error {
+	c := cfg.AppConfig
+	if c.AdminPort == 0 {
+		return nil
+	}
+	if !cfg.DatabaseConfig.Enabled {
+		return fmt.Errorf("--admin-port requires the database; it cannot be used with --db-enabled=false")
+	}
+	if c.AdminPort == c.FreighterBackendPort || c.AdminPort == c.MetricsPort {
+		return fmt.Errorf("--admin-port=%d must differ from the API and metrics ports", c.AdminPort)
+	}
+	if len(c.AdminCredentials) == 0 {
+		return fmt.Errorf("--admin-port requires --admin-credentials")
+	}
+	for name, token := range c.AdminCredentials {
+		if name == "" {
+			return fmt.Errorf("--admin-credentials: every token needs a name")
+		}
+		if len(token) < MinAdminTokenLength {
+			return fmt.Errorf("--admin-credentials: the token for %q must be at least %d characters", name, MinAdminTokenLength)
+		}
+	}
+	return nil
+}
+
+// validatePriceProviders checks that --price-providers names each known
+// provider at most once, that the price feed has an endpoint when it is used,
+// and that --price-aggregation is a known policy.
+func validatePriceProviders(c config.PricesConfig) error {
+	if len(c.PriceProviders) == 0 {
+		return fmt.Errorf("--price-providers must name at least one provider")
+	}
+	seen := make(map[string]bool, len(c.PriceProviders))
+	for _, name := range c.PriceProviders {
+		switch name {
+		case services.PriceProviderStellarExpert:
+		case services.PriceProviderFeed:
+			if c.PriceFeedPubnetURL == "" && c.PriceFeedTestnetURL == "" {
+				return fmt.Errorf("--price-providers=%s requires --price-feed-pubnet-url or --price-feed-testnet-url", services.PriceProviderFeed)
+			}
+		default:
+			return fmt.Errorf("--price-providers: unknown provider %q; must be %q or %q", name, services.PriceProviderStellarExpert, services.PriceProviderFeed)
+		}
+		if seen[name] {
+			return fmt.Errorf("--price-providers: %q is listed more than once", name)
+		}
+		seen[name] = true
+	}
+	switch c.PriceAggregation {
+	case services.PriceAggregationFirstSuccess, services.PriceAggregationMedian:
+	default:
+		return fmt.Errorf("--price-aggregation=%q must be %q or %q", c.PriceAggregation, services.PriceAggregationFirstSuccess, services.PriceAggregationMedian)
+	}
+	return nil
+}
+
 func (s *ServeCmd) Run() error {
 	server := api.NewApiServer(s.Cfg)
 	return server.Start()
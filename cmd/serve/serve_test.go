@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
@@ -166,7 +167,7 @@ func TestServeCmd_WalletBackendRoutesEnabledFalseFromEnv(t *testing.T) {
 
 	require.NoError(t, cmd.Execute())
 	assert.False(t, serveCmd.Cfg.AppConfig.WalletBackendRoutesEnabled,
-		"WALLET_BACKEND_ROUTES_ENABLED=false must reach AppConfig.WalletBackendRoutesEnabled; a true here means prd would still serve both routes")
+		"WALLET_BACKEND_ROUTES_ENABLED=false must reach AppConfig.WalletBackendRoutesEnabled; a true here means prd would still serve account history")
 }
 
 // TestServeCmd_WalletBackendRoutesEnabledEnvTrueKeepsRoutesOn is the other half:
@@ -232,6 +233,73 @@ func TestServeCmd_RejectsNonPositiveMaxTokensPerRequest(t *testing.T) {
 	assert.Contains(t, err.Error(), "--max-tokens-per-request=0 must be positive")
 }
 
+func TestServeCmd_RejectsNonPositiveMaxConcurrentRPCCalls(t *testing.T) {
+	t.Parallel()
+
+	serveCmd := &ServeCmd{Cfg: &config.Config{}}
+	cmd := serveCmd.Command()
+	cmd.RunE = func(*cobra.Command, []string) error { return nil }
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--max-concurrent-rpc-calls", "0"})
+
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--max-concurrent-rpc-calls=0 must be positive")
+}
+
+func TestServeCmd_RejectsInvalidBatchReadFlags(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--max-token-details-contracts", "0"}, "--max-token-details-contracts=0 must be positive"},
+		{[]string{"--token-balance-cache-ttl-seconds", "-1"}, "--token-balance-cache-ttl-seconds=-1 must be >= 0"},
+		{[]string{"--max-contract-calls", "0"}, "--max-contract-calls=0 must be positive"},
+	} {
+		serveCmd := &ServeCmd{Cfg: &config.Config{}}
+		cmd := serveCmd.Command()
+		cmd.RunE = func(*cobra.Command, []string) error { return nil }
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs(tc.args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), tc.want)
+	}
+}
+
+func TestServeCmd_RejectsInvalidCollectibleMetadataFlags(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--collectible-metadata-cache-ttl-seconds", "-1"}, "--collectible-metadata-cache-ttl-seconds=-1 must be >= 0"},
+		{[]string{"--collections-refresh-interval", "0s"}, "--collections-refresh-interval=0s must be positive"},
+		{[]string{"--max-discovered-tokens", "0"}, "--max-discovered-tokens=0 must be positive"},
+		{[]string{"--collections-registry-path", "collections.json", "--meridian-pay-poap-address", "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC"}, "--meridian-pay-* flags cannot be combined with --collections-registry-path"},
+		{[]string{"--collectible-metadata-max-bytes", "0"}, "--collectible-metadata-max-bytes=0 must be positive"},
+		{[]string{"--collectible-metadata-fetch-timeout", "0s"}, "--collectible-metadata-fetch-timeout=0s must be positive"},
+		{[]string{"--resolve-collectible-metadata", "--ipfs-gateway-url", "http://gateway.local"}, `--ipfs-gateway-url="http://gateway.local" must be an https URL`},
+	} {
+		serveCmd := &ServeCmd{Cfg: &config.Config{}}
+		cmd := serveCmd.Command()
+		cmd.RunE = func(*cobra.Command, []string) error { return nil }
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs(tc.args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), tc.want)
+	}
+}
+
 func TestServeCmd_RejectsNegativePriceFetchTimeout(t *testing.T) {
 	t.Parallel()
 
@@ -247,6 +315,30 @@ func TestServeCmd_RejectsNegativePriceFetchTimeout(t *testing.T) {
 	assert.Contains(t, err.Error(), "--price-fetch-timeout-seconds=-1 must be >= 0")
 }
 
+func TestServeCmd_RejectsInvalidPriceRefreshFlags(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--price-stale-ttl-seconds", "-1"}, "--price-stale-ttl-seconds=-1 must be >= 0"},
+		{[]string{"--hot-price-tokens", "-1"}, "--hot-price-tokens=-1 must be >= 0"},
+		{[]string{"--hot-price-refresh-interval", "0s"}, "--hot-price-refresh-interval=0s must be positive"},
+	} {
+		serveCmd := &ServeCmd{Cfg: &config.Config{}}
+		cmd := serveCmd.Command()
+		cmd.RunE = func(*cobra.Command, []string) error { return nil }
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs(tc.args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), tc.want)
+	}
+}
+
 func TestServeCmd_RejectsAccountHistoryMaxLimitAbove100(t *testing.T) {
 	t.Parallel()
 
@@ -265,6 +357,140 @@ func TestServeCmd_RejectsAccountHistoryMaxLimitAbove100(t *testing.T) {
 	assert.Contains(t, err.Error(), "max <= 100")
 }
 
+func TestServeCmd_RejectsBlockaidFlagWithoutAPIKey(t *testing.T) {
+	t.Parallel()
+
+	serveCmd := &ServeCmd{Cfg: &config.Config{}}
+	cmd := serveCmd.Command()
+	cmd.RunE = func(*cobra.Command, []string) error { return nil }
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--use-blockaid-tx-scanning", "--database-url", "postgres://localhost/test"})
+
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--blockaid-api-key is required")
+}
+
+func TestServeCmd_AcceptsBlockaidFlagWithAPIKey(t *testing.T) {
+	t.Parallel()
+
+	serveCmd := &ServeCmd{Cfg: &config.Config{}}
+	cmd := serveCmd.Command()
+	cmd.RunE = func(*cobra.Command, []string) error { return nil }
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--use-blockaid-tx-scanning", "--blockaid-api-key", "k", "--database-url", "postgres://localhost/test"})
+
+	require.NoError(t, cmd.Execute())
+}
+
+func TestServeCmd_RejectsBlockaidReportingWithoutDatabase(t *testing.T) {
+	t.Parallel()
+
+	serveCmd := &ServeCmd{Cfg: &config.Config{}}
+	cmd := serveCmd.Command()
+	cmd.RunE = func(*cobra.Command, []string) error { return nil }
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--use-blockaid-asset-warning-reporting", "--blockaid-api-key", "k", "--db-enabled=false"})
+
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requires the database")
+}
+
+func TestServeCmd_RejectsInvalidProtocolsSourceFlags(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--protocols-source", "s3", "--db-enabled=false"}, `--protocols-source="s3" must be "file" or "database"`},
+		{[]string{"--protocols-source", "database", "--db-enabled=false"}, "requires the database"},
+		{[]string{"--protocols-refresh-interval", "0s", "--db-enabled=false"}, "--protocols-refresh-interval=0s must be positive"},
+	} {
+		serveCmd := &ServeCmd{Cfg: &config.Config{}}
+		cmd := serveCmd.Command()
+		cmd.RunE = func(*cobra.Command, []string) error { return nil }
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs(tc.args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), tc.want)
+	}
+}
+
+func TestServeCmd_RejectsInvalidFeatureFlagsSourceFlags(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--feature-flags-source", "launchdarkly", "--db-enabled=false"}, `--feature-flags-source="launchdarkly" must be "builtin", "file" or "database"`},
+		{[]string{"--feature-flags-source", "file", "--db-enabled=false"}, "requires --feature-flags-path"},
+		{[]string{"--feature-flags-source", "database", "--db-enabled=false"}, "requires the database"},
+		{[]string{"--feature-flags-refresh-interval", "0s", "--db-enabled=false"}, "--feature-flags-refresh-interval=0s must be positive"},
+	} {
+		serveCmd := &ServeCmd{Cfg: &config.Config{}}
+		cmd := serveCmd.Command()
+		cmd.RunE = func(*cobra.Command, []string) error { return nil }
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs(tc.args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), tc.want)
+	}
+}
+
+func TestServeCmd_RejectsInvalidAdminFlags(t *testing.T) {
+	t.Parallel()
+
+	token := strings.Repeat("a", MinAdminTokenLength)
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--admin-port", "9091", "--db-enabled=false"}, "--admin-port requires the database"},
+		{[]string{"--admin-port", "9090", "--database-url", "postgres://localhost/test"}, "must differ from the API and metrics ports"},
+		{[]string{"--admin-port", "9091", "--database-url", "postgres://localhost/test"}, "--admin-port requires --admin-credentials"},
+		{[]string{"--admin-port", "9091", "--database-url", "postgres://localhost/test", "--admin-credentials", "alice=short"}, `the token for "alice" must be at least`},
+		{[]string{"--admin-port", "9091", "--database-url", "postgres://localhost/test", "--admin-credentials", "=" + token}, "every token needs a name"},
+	} {
+		serveCmd := &ServeCmd{Cfg: &config.Config{}}
+		cmd := serveCmd.Command()
+		cmd.RunE = func(*cobra.Command, []string) error { return nil }
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs(tc.args)
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), tc.want)
+	}
+}
+
+func TestServeCmd_RejectsHalfCoinbaseCredential(t *testing.T) {
+	t.Parallel()
+
+	serveCmd := &ServeCmd{Cfg: &config.Config{}}
+	cmd := serveCmd.Command()
+	cmd.RunE = func(*cobra.Command, []string) error { return nil }
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--coinbase-api-key", "organizations/o/apiKeys/k", "--database-url", "postgres://localhost/test"})
+
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be set together")
+}
+
 func TestServeCmd_RejectsInvalidAuthMode(t *testing.T) {
 	t.Parallel()
 
@@ -341,3 +567,28 @@ func TestServeCmd_AcceptsAuthClockSkewLeewayBoundaries(t *testing.T) {
 		require.NoErrorf(t, cmd.Execute(), "leeway %s should be accepted", leeway)
 	}
 }
+
+func TestServeCmd_RejectsInvalidPriceProviderFlags(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--price-providers", "coingecko"}, `unknown provider "coingecko"`},
+		{[]string{"--price-providers", "stellar-expert,stellar-expert"}, "listed more than once"},
+		{[]string{"--price-providers", "stellar-expert,price-feed"}, "requires --price-feed-pubnet-url"},
+		{[]string{"--price-aggregation", "mean"}, `--price-aggregation="mean" must be`},
+	} {
+		serveCmd := &ServeCmd{Cfg: &config.Config{}}
+		cmd := serveCmd.Command()
+		cmd.RunE = func(*cobra.Command, []string) error { return nil }
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs(append(tc.args, "--database-url", "postgres://localhost/test"))
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), tc.want)
+	}
+}
@@ -20,15 +20,18 @@ const (
 	AccountBalancesContextTimeout = 10 * time.Second
 )
 
+// AccountBalancesHandler serves balances from a pluggable source: the
+// wallet-backend service, or a per-network router that falls back to Horizon
+// where wallet-backend is not configured.
 type AccountBalancesHandler struct {
-	WalletBackendService types.WalletBackendService
-	MaxAddresses         int
+	BalanceSource types.AccountBalancesSource
+	MaxAddresses  int
 }
 
-func NewAccountBalancesHandler(walletBackendService types.WalletBackendService, maxAddresses int) *AccountBalancesHandler {
+func NewAccountBalancesHandler(balanceSource types.AccountBalancesSource, maxAddresses int) *AccountBalancesHandler {
 	return &AccountBalancesHandler{
-		WalletBackendService: walletBackendService,
-		MaxAddresses:         maxAddresses,
+		BalanceSource: balanceSource,
+		MaxAddresses:  maxAddresses,
 	}
 }
 
@@ -65,7 +68,8 @@ func validateAccountBalancesRequest(r *http.Request, maxAddresses int) (*Account
 	return &req, nil
 }
 
-// GetAccountBalances handles fetching account balances from wallet backend
+// GetAccountBalances handles fetching account balances from the configured
+// balance source
 func (h *AccountBalancesHandler) GetAccountBalances(w http.ResponseWriter, r *http.Request) error {
 	contextWithTimeout, cancel := context.WithTimeout(r.Context(), AccountBalancesContextTimeout)
 	defer cancel()
@@ -82,7 +86,7 @@ func (h *AccountBalancesHandler) GetAccountBalances(w http.ResponseWriter, r *ht
 		return validationErr
 	}
 
-	balances, err := h.WalletBackendService.GetBalancesByAccountAddresses(contextWithTimeout, req.Addresses, network)
+	balances, err := h.BalanceSource.GetBalancesByAccountAddresses(contextWithTimeout, req.Addresses, network)
 	if err != nil {
 		// address is intentionally empty: this is a multi-address fan-out
 		// endpoint, and individual ErrAccountNotFound outcomes are already
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/stellar/freighter-backend-v2/internal/api/httperror"
+	response "github.com/stellar/freighter-backend-v2/internal/api/httpresponse"
+	"github.com/stellar/freighter-backend-v2/internal/auth"
+	"github.com/stellar/freighter-backend-v2/internal/logger"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+const (
+	DefaultAuditLogLimit = 50
+	MaxAuditLogLimit     = 500
+)
+
+var ErrAdminStoreFailed = httperror.ErrorMessage{
+	LogMessage:    "admin: %s failed: %v",
+	ClientMessage: "An error occurred while accessing the database.",
+}
+
+// AdminHandler serves the admin API, which edits the feature flags and
+// protocols catalog in the database. It is mounted only on the admin
+// listener, behind middleware.AdminAuth. Running servers pick edits up on
+// their next refresh of the database source.
+type AdminHandler struct {
+	store types.AdminStore
+	// protocolRules are the optional checks protocols are validated with, as
+	// for the protocols file.
+	protocolRules types.ProtocolRules
+}
+
+func NewAdminHandler(store types.AdminStore, protocolRules types.ProtocolRules) *AdminHandler {
+	return &AdminHandler{store: store, protocolRules: protocolRules}
+}
+
+type AdminFeatureFlagsPayload struct {
+	FeatureFlags []types.FeatureFlag `json:"feature_flags"`
+}
+
+type AdminAuditLogPayload struct {
+	Entries []types.AuditEntry `json:"entries"`
+}
+
+// ListFeatureFlags handles GET /admin/v1/feature-flags.
+func (h *AdminHandler) ListFeatureFlags(w http.ResponseWriter, r *http.Request) error {
+	flags, err := h.store.ListFeatureFlags(r.Context())
+	if err != nil {
+		return h.storeError(r, "listing feature flags", err)
+	}
+	return response.OK(w, HttpResponse{Data: AdminFeatureFlagsPayload{FeatureFlags: flags}})
+}
+
+// PutFeatureFlag handles PUT /admin/v1/feature-flags/{key}, creating or
+// replacing the flag. The body's key may be left out; if set it must match
+// the path.
+func (h *AdminHandler) PutFeatureFlag(w http.ResponseWriter, r *http.Request) error {
+	key := r.PathValue("key")
+	var flag types.FeatureFlag
+	if httpErr := decodeJSONBody(r, &flag); httpErr != nil {
+		return httpErr
+	}
+	if flag.Key == "" {
+		flag.Key = key
+	}
+	if flag.Key != key {
+		return httperror.BadRequest(fmt.Sprintf("body key %q does not match path key %q", flag.Key, key), errors.New("key mismatch"))
+	}
+	if err := flag.Validate(); err != nil {
+		return httperror.BadRequest(err.Error(), err)
+	}
+
+	created, err := h.store.UpsertFeatureFlag(r.Context(), adminActor(r), flag)
+	if err != nil {
+		return h.storeError(r, "saving feature flag", err)
+	}
+	if created {
+		return response.Created(w, HttpResponse{Data: flag})
+	}
+	return response.OK(w, HttpResponse{Data: flag})
+}
+
+// DeleteFeatureFlag handles DELETE /admin/v1/feature-flags/{key}.
+func (h *AdminHandler) DeleteFeatureFlag(w http.ResponseWriter, r *http.Request) error {
+	key := r.PathValue("key")
+	deleted, err := h.store.DeleteFeatureFlag(r.Context(), adminActor(r), key)
+	if err != nil {
+		return h.storeError(r, "deleting feature flag", err)
+	}
+	if !deleted {
+		return httperror.NotFoundf("feature flag %q not found", key)
+	}
+	return response.NoContent(w)
+}
+
+// ListProtocols handles GET /admin/v1/protocols. Unlike GET /api/v1/protocols
+// it reads the table directly and returns entries untranslated.
+func (h *AdminHandler) ListProtocols(w http.ResponseWriter, r *http.Request) error {
+	protocols, err := h.store.ListProtocols(r.Context())
+	if err != nil {
+		return h.storeError(r, "listing protocols", err)
+	}
+	return response.OK(w, HttpResponse{Data: GetProtocolsPayload{Protocols: protocols}})
+}
+
+// PutProtocol handles PUT /admin/v1/protocols/{name}. A new protocol goes to
+// the end of the catalog; an existing one is replaced in place. The body's
+// name may be left out; if set it must match the path.
+func (h *AdminHandler) PutProtocol(w http.ResponseWriter, r *http.Request) error {
+	name := r.PathValue("name")
+	var protocol types.Protocol
+	if httpErr := decodeJSONBody(r, &protocol); httpErr != nil {
+		return httpErr
+	}
+	if protocol.Name == "" {
+		protocol.Name = name
+	}
+	if protocol.Name != name {
+		return httperror.BadRequest(fmt.Sprintf("body name %q does not match path name %q", protocol.Name, name), errors.New("name mismatch"))
+	}
+	if len(protocol.Networks) == 0 {
+		return httperror.BadRequest(fmt.Sprintf("protocol %q: networks is required", name), errors.New("missing networks"))
+	}
+	if err := types.ValidateProtocols([]types.Protocol{protocol}, h.protocolRules); err != nil {
+		return httperror.BadRequest(err.Error(), err)
+	}
+
+	created, err := h.store.UpsertProtocol(r.Context(), adminActor(r), protocol)
+	if err != nil {
+		return h.storeError(r, "saving protocol", err)
+	}
+	if created {
+		return response.Created(w, HttpResponse{Data: protocol})
+	}
+	return response.OK(w, HttpResponse{Data: protocol})
+}
+
+// DeleteProtocol handles DELETE /admin/v1/protocols/{name}.
+func (h *AdminHandler) DeleteProtocol(w http.ResponseWriter, r *http.Request) error {
+	name := r.PathValue("name")
+	deleted, err := h.store.DeleteProtocol(r.Context(), adminActor(r), name)
+	if err != nil {
+		return h.storeError(r, "deleting protocol", err)
+	}
+	if !deleted {
+		return httperror.NotFoundf("protocol %q not found", name)
+	}
+	return response.NoContent(w)
+}
+
+// ListAuditLog handles GET /admin/v1/audit-log, newest entries first:
+//
+//	entity_type=<type>   feature_flag, protocol, protocols or collection
+//	entity_key=<key>     a flag key, protocol name or NETWORK/CONTRACT_ID
+//	limit=<n>            1 to MaxAuditLogLimit, default DefaultAuditLogLimit
+func (h *AdminHandler) ListAuditLog(w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+	limit := DefaultAuditLogLimit
+	if s := query.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > MaxAuditLogLimit {
+			return httperror.BadRequest(fmt.Sprintf("invalid limit %q: must be between 1 and %d", s, MaxAuditLogLimit), err)
+		}
+		limit = n
+	}
+	entries, err := h.store.ListAuditLog(r.Context(), query.Get("entity_type"), query.Get("entity_key"), limit)
+	if err != nil {
+		return h.storeError(r, "listing audit log", err)
+	}
+	return response.OK(w, HttpResponse{Data: AdminAuditLogPayload{Entries: entries}})
+}
+
+func (h *AdminHandler) storeError(r *http.Request, op string, err error) *httperror.HttpError {
+	logger.ErrorWithContext(r.Context(), fmt.Sprintf(ErrAdminStoreFailed.LogMessage, op, err))
+	return httperror.InternalServerError(ErrAdminStoreFailed.ClientMessage, err)
+}
+
+// adminActor names who is making the change. AdminAuth always sets it on the
+// admin listener.
+func adminActor(r *http.Request) string {
+	actor, _ := auth.AdminActorFromContext(r.Context())
+	return actor
+}
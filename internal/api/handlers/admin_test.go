@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/api/httperror"
+	"github.com/stellar/freighter-backend-v2/internal/auth"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils"
+)
+
+// adminRequest runs one admin request as alice through a mux, so path values
+// are populated as on the admin listener.
+func adminRequest(t *testing.T, h *AdminHandler, method, target, body string) (int, []byte) {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.Handle("GET /admin/v1/feature-flags", CustomHandler(h.ListFeatureFlags))
+	mux.Handle("PUT /admin/v1/feature-flags/{key}", CustomHandler(h.PutFeatureFlag))
+	mux.Handle("DELETE /admin/v1/feature-flags/{key}", CustomHandler(h.DeleteFeatureFlag))
+	mux.Handle("GET /admin/v1/protocols", CustomHandler(h.ListProtocols))
+	mux.Handle("PUT /admin/v1/protocols/{name}", CustomHandler(h.PutProtocol))
+	mux.Handle("DELETE /admin/v1/protocols/{name}", CustomHandler(h.DeleteProtocol))
+	mux.Handle("GET /admin/v1/audit-log", CustomHandler(h.ListAuditLog))
+
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	r = r.WithContext(auth.ContextWithAdminActor(r.Context(), "alice"))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	return w.Code, w.Body.Bytes()
+}
+
+func TestAdminHandler_FeatureFlags(t *testing.T) {
+	t.Parallel()
+
+	store := &utils.MockAdminStore{}
+	h := NewAdminHandler(store, types.ProtocolRules{})
+
+	code, _ := adminRequest(t, h, http.MethodPut, "/admin/v1/feature-flags/new_home", `{"enabled": true, "rules": [{"percentage": 10, "enabled": true}]}`)
+	assert.Equal(t, http.StatusCreated, code)
+	code, _ = adminRequest(t, h, http.MethodPut, "/admin/v1/feature-flags/new_home", `{"key": "new_home", "enabled": false}`)
+	assert.Equal(t, http.StatusOK, code)
+	require.Len(t, store.Flags, 1)
+	assert.False(t, store.Flags[0].Enabled)
+
+	for body, want := range map[string]int{
+		`{"key": "other"}`:                http.StatusBadRequest,
+		`{"rules": [{"versions": "~1"}]}`: http.StatusBadRequest,
+		`{`:                               http.StatusBadRequest,
+	} {
+		code, _ = adminRequest(t, h, http.MethodPut, "/admin/v1/feature-flags/new_home", body)
+		assert.Equal(t, want, code, body)
+	}
+
+	code, body := adminRequest(t, h, http.MethodGet, "/admin/v1/feature-flags", "")
+	require.Equal(t, http.StatusOK, code)
+	var list struct {
+		Data AdminFeatureFlagsPayload `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &list))
+	assert.Len(t, list.Data.FeatureFlags, 1)
+
+	code, _ = adminRequest(t, h, http.MethodDelete, "/admin/v1/feature-flags/new_home", "")
+	assert.Equal(t, http.StatusNoContent, code)
+	code, _ = adminRequest(t, h, http.MethodDelete, "/admin/v1/feature-flags/new_home", "")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	require.Len(t, store.AuditLog, 3, "rejected requests are not audited")
+	for _, e := range store.AuditLog {
+		assert.Equal(t, "alice", e.Actor)
+	}
+}
+
+func TestAdminHandler_Protocols(t *testing.T) {
+	t.Parallel()
+
+	store := &utils.MockAdminStore{}
+	h := NewAdminHandler(store, types.ProtocolRules{AllowedTags: []string{"DEX", "Lending"}, Strict: true})
+	blend := `{"tags": ["Lending"], "description": "Lending markets.", "website_url": "https://mainnet.blend.capital/", "icon_url": "https://icons/blend.svg", "networks": ["PUBLIC"]}`
+
+	code, _ := adminRequest(t, h, http.MethodPut, "/admin/v1/protocols/Blend", blend)
+	assert.Equal(t, http.StatusCreated, code)
+	require.Len(t, store.Protocols, 1)
+	assert.Equal(t, "Blend", store.Protocols[0].Name)
+
+	for name, body := range map[string]string{
+		"name mismatch":    `{"name": "Phoenix", "description": "d", "website_url": "https://a", "icon_url": "https://b", "networks": ["PUBLIC"]}`,
+		"missing networks": `{"description": "d", "website_url": "https://a", "icon_url": "https://b"}`,
+		"unknown tag":      `{"tags": ["Lendng"], "description": "d", "website_url": "https://a", "icon_url": "https://b", "networks": ["PUBLIC"]}`,
+		"http url":         `{"description": "d", "website_url": "http://a", "icon_url": "https://b", "networks": ["PUBLIC"]}`,
+	} {
+		code, _ = adminRequest(t, h, http.MethodPut, "/admin/v1/protocols/Blend", body)
+		assert.Equal(t, http.StatusBadRequest, code, name)
+	}
+
+	code, _ = adminRequest(t, h, http.MethodDelete, "/admin/v1/protocols/Blend", "")
+	assert.Equal(t, http.StatusNoContent, code)
+
+	code, body := adminRequest(t, h, http.MethodGet, "/admin/v1/audit-log?entity_type=protocol&entity_key=Blend&limit=1", "")
+	require.Equal(t, http.StatusOK, code)
+	var log struct {
+		Data AdminAuditLogPayload `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &log))
+	require.Len(t, log.Data.Entries, 1)
+	assert.Equal(t, types.AuditActionDelete, log.Data.Entries[0].Action)
+
+	code, _ = adminRequest(t, h, http.MethodGet, "/admin/v1/audit-log?limit=0", "")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestAdminHandler_StoreFailure(t *testing.T) {
+	t.Parallel()
+
+	h := NewAdminHandler(&utils.MockAdminStore{Err: errors.New("connection refused")}, types.ProtocolRules{})
+	r := httptest.NewRequest(http.MethodGet, "/admin/v1/feature-flags", nil)
+	err := h.ListFeatureFlags(httptest.NewRecorder(), r)
+	var httpErr *httperror.HttpError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusInternalServerError, httpErr.HttpStatus())
+	assert.NotContains(t, httpErr.Message, "connection refused")
+}
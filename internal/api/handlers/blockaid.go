@@ -0,0 +1,431 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+
+	"github.com/stellar/freighter-backend-v2/internal/api/httperror"
+	response "github.com/stellar/freighter-backend-v2/internal/api/httpresponse"
+	"github.com/stellar/freighter-backend-v2/internal/auth"
+	"github.com/stellar/freighter-backend-v2/internal/logger"
+	"github.com/stellar/freighter-backend-v2/internal/metrics"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils/assetid"
+)
+
+const (
+	BlockaidContextTimeout = 10 * time.Second
+	// blockaidReportPersistTimeout bounds the audit insert after a report has
+	// been forwarded.
+	blockaidReportPersistTimeout = 5 * time.Second
+	// MaxReportDetailsLength caps the free-text details of a warning report.
+	MaxReportDetailsLength = 1000
+	// MaxScanAssetsPerRequest caps the deduped asset list of one scan-assets
+	// call; it is sent to Blockaid as a single bulk request.
+	MaxScanAssetsPerRequest = 100
+)
+
+// BlockaidHandler serves the Blockaid-backed scanning and reporting routes.
+// Each route is switched on individually by its BlockaidConfig flag in
+// api.ApiServer.routes(). ReportStore is nil when the database is disabled.
+type BlockaidHandler struct {
+	BlockaidService types.BlockaidService
+	ReportStore     types.BlockaidReportStore
+}
+
+func NewBlockaidHandler(blockaidService types.BlockaidService, reportStore types.BlockaidReportStore) *BlockaidHandler {
+	return &BlockaidHandler{BlockaidService: blockaidService, ReportStore: reportStore}
+}
+
+type ScanTxRequest struct {
+	TxXDR string `json:"tx_xdr"`
+	URL   string `json:"url"`
+}
+
+type validatedScanTxRequest struct {
+	txXDR          string
+	originURL      string
+	accountAddress string
+}
+
+func validateScanTxRequest(r *http.Request) (*validatedScanTxRequest, *httperror.HttpError) {
+	var req ScanTxRequest
+	if decodeErr := decodeJSONBody(r, &req); decodeErr != nil {
+		return nil, decodeErr
+	}
+	return validateTxForScan(req.TxXDR, req.URL)
+}
+
+// validateTxForScan checks an envelope and origin URL as sent to ScanTx and
+// ReportTransaction, deriving the signing account from the envelope.
+func validateTxForScan(rawTxXDR, rawURL string) (*validatedScanTxRequest, *httperror.HttpError) {
+	txXDR := strings.TrimSpace(rawTxXDR)
+	if txXDR == "" {
+		errStr := "tx_xdr cannot be empty"
+		return nil, httperror.BadRequest(errStr, errors.New(errStr))
+	}
+	var envelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(txXDR, &envelope); err != nil {
+		return nil, httperror.BadRequest("invalid tx_xdr: must be a base64 TransactionEnvelope", err)
+	}
+
+	originURL, validationErr := validateOriginURL(rawURL)
+	if validationErr != nil {
+		return nil, validationErr
+	}
+
+	// Blockaid scores the transaction from the point of view of the account
+	// that will sign it; for a fee bump that is still the inner source.
+	source := envelope.SourceAccount().ToAccountId()
+	return &validatedScanTxRequest{
+		txXDR:          txXDR,
+		originURL:      originURL,
+		accountAddress: source.Address(),
+	}, nil
+}
+
+// validateOriginURL accepts an empty origin (a wallet-built transaction) or an
+// absolute http(s) URL. Anything else is forwarded to Blockaid as the dApp
+// being scored, so it is rejected here instead of producing a meaningless
+// verdict.
+func validateOriginURL(raw string) (string, *httperror.HttpError) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errStr := "invalid url: must be an absolute http or https URL"
+		return "", httperror.BadRequest(errStr, errors.New(errStr))
+	}
+	return raw, nil
+}
+
+// ScanTx handles POST /api/v1/scan-tx.
+func (h *BlockaidHandler) ScanTx(w http.ResponseWriter, r *http.Request) error {
+	ctx, cancel := context.WithTimeout(r.Context(), BlockaidContextTimeout)
+	defer cancel()
+
+	network := r.URL.Query().Get("network")
+	if !isValidNetwork(network) {
+		return httperror.BadRequest(fmt.Sprintf("invalid network: network must be %s, %s or %s", types.PUBLIC, types.TESTNET, types.FUTURENET), errors.New("invalid network"))
+	}
+
+	req, validationErr := validateScanTxRequest(r)
+	if validationErr != nil {
+		return validationErr
+	}
+
+	result, err := h.BlockaidService.ScanTx(ctx, network, req.accountAddress, req.txXDR, req.originURL)
+	if err != nil {
+		return translateBlockaidError(r.Context(), err, "transaction scan", network)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return response.OK(w, HttpResponse{Data: result})
+}
+
+type ScanDappRequest struct {
+	URL string `json:"url"`
+}
+
+// validateScanDappRequest reduces the requested URL to its origin. Blockaid
+// scores sites per origin, so keying scans (and the cache) on scheme://host
+// keeps /swap and /pool of the same dApp from costing two lookups.
+func validateScanDappRequest(r *http.Request) (string, *httperror.HttpError) {
+	var req ScanDappRequest
+	if decodeErr := decodeJSONBody(r, &req); decodeErr != nil {
+		return "", decodeErr
+	}
+	if strings.TrimSpace(req.URL) == "" {
+		errStr := "url cannot be empty"
+		return "", httperror.BadRequest(errStr, errors.New(errStr))
+	}
+	raw, validationErr := validateOriginURL(req.URL)
+	if validationErr != nil {
+		return "", validationErr
+	}
+	u, _ := url.Parse(raw)
+	return strings.ToLower(u.Scheme + "://" + u.Host), nil
+}
+
+// ScanDapp handles POST /api/v1/scan-dapp.
+func (h *BlockaidHandler) ScanDapp(w http.ResponseWriter, r *http.Request) error {
+	ctx, cancel := context.WithTimeout(r.Context(), BlockaidContextTimeout)
+	defer cancel()
+
+	origin, validationErr := validateScanDappRequest(r)
+	if validationErr != nil {
+		return validationErr
+	}
+
+	result, err := h.BlockaidService.ScanDapp(ctx, origin)
+	if err != nil {
+		return translateBlockaidError(r.Context(), err, "dapp scan", "")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return response.OK(w, HttpResponse{Data: result})
+}
+
+type ScanAssetsRequest struct {
+	Assets []string `json:"assets"`
+}
+
+type validatedScanAssetsRequest struct {
+	originalInputs      []string
+	canonicalIDs        []string
+	canonicalByOriginal map[string]string
+}
+
+func validateScanAssetsRequest(r *http.Request) (*validatedScanAssetsRequest, *httperror.HttpError) {
+	var req ScanAssetsRequest
+	if decodeErr := decodeJSONBody(r, &req); decodeErr != nil {
+		return nil, decodeErr
+	}
+	if len(req.Assets) == 0 {
+		errStr := "assets array cannot be empty"
+		return nil, httperror.BadRequest(errStr, errors.New(errStr))
+	}
+
+	canonicalIDs := make([]string, 0, len(req.Assets))
+	canonicalByOriginal := make(map[string]string, len(req.Assets))
+	seen := make(map[string]struct{}, len(req.Assets))
+	for _, a := range req.Assets {
+		canonical, err := assetid.Normalize(a)
+		if err != nil {
+			return nil, httperror.BadRequest("invalid asset id", err)
+		}
+		canonicalByOriginal[a] = canonical
+		if _, dup := seen[canonical]; !dup {
+			seen[canonical] = struct{}{}
+			canonicalIDs = append(canonicalIDs, canonical)
+		}
+	}
+	if len(canonicalIDs) > MaxScanAssetsPerRequest {
+		errStr := fmt.Sprintf("too many assets: maximum is %d, got %d unique", MaxScanAssetsPerRequest, len(canonicalIDs))
+		return nil, httperror.BadRequest(errStr, errors.New(errStr))
+	}
+
+	return &validatedScanAssetsRequest{
+		originalInputs:      req.Assets,
+		canonicalIDs:        canonicalIDs,
+		canonicalByOriginal: canonicalByOriginal,
+	}, nil
+}
+
+// ScanAssets handles POST /api/v1/scan-assets. Blockaid only indexes pubnet
+// tokens, so other networks are rejected up front. The response is keyed by
+// the original client input, like token-prices.
+func (h *BlockaidHandler) ScanAssets(w http.ResponseWriter, r *http.Request) error {
+	ctx, cancel := context.WithTimeout(r.Context(), BlockaidContextTimeout)
+	defer cancel()
+
+	network := r.URL.Query().Get("network")
+	if !isValidNetwork(network) {
+		return httperror.BadRequest(fmt.Sprintf("invalid network: network must be %s, %s or %s", types.PUBLIC, types.TESTNET, types.FUTURENET), errors.New("invalid network"))
+	}
+	if network != types.PUBLIC {
+		return httperror.BadRequest(fmt.Sprintf("asset scanning is only available on %s", types.PUBLIC), errors.New("network not supported by blockaid"))
+	}
+
+	req, validationErr := validateScanAssetsRequest(r)
+	if validationErr != nil {
+		return validationErr
+	}
+
+	results, err := h.BlockaidService.ScanAssets(ctx, network, req.canonicalIDs)
+	if err != nil {
+		return translateBlockaidError(r.Context(), err, "asset scan", network)
+	}
+
+	out := make(map[string]*types.AssetScanResult, len(req.originalInputs))
+	for _, original := range req.originalInputs {
+		out[original] = results[req.canonicalByOriginal[original]]
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return response.OK(w, HttpResponse{Data: out})
+}
+
+type ReportAssetWarningRequest struct {
+	Asset   string `json:"asset"`
+	Event   string `json:"event"`
+	Details string `json:"details"`
+}
+
+type ReportTransactionWarningRequest struct {
+	TxXDR   string `json:"tx_xdr"`
+	URL     string `json:"url"`
+	Event   string `json:"event"`
+	Details string `json:"details"`
+}
+
+// ReportWarningResponse acknowledges a report. It is returned once Blockaid
+// has accepted the report; the audit copy is best-effort (see persistReport).
+type ReportWarningResponse struct {
+	Reported bool `json:"reported"`
+}
+
+func validateReportEvent(event, details string) *httperror.HttpError {
+	if event != types.ReportFalsePositive && event != types.ReportFalseNegative {
+		errStr := fmt.Sprintf("invalid event: must be %s or %s", types.ReportFalsePositive, types.ReportFalseNegative)
+		return httperror.BadRequest(errStr, errors.New(errStr))
+	}
+	if len(details) > MaxReportDetailsLength {
+		errStr := fmt.Sprintf("details too long: maximum is %d bytes", MaxReportDetailsLength)
+		return httperror.BadRequest(errStr, errors.New(errStr))
+	}
+	return nil
+}
+
+// reportStoreUnavailable guards the reporting routes when the database is
+// disabled: a report that cannot be audited is refused rather than forwarded.
+func (h *BlockaidHandler) reportStoreUnavailable() *httperror.HttpError {
+	if h.ReportStore != nil {
+		return nil
+	}
+	errStr := "warning reporting is unavailable"
+	return httperror.ServiceUnavailable(errStr, errors.New("blockaid report store not configured"))
+}
+
+// persistReport writes the audit copy of a report that has already been
+// forwarded. A write failure is logged, not returned: the user's report has
+// reached Blockaid, and failing the request would only invite a duplicate
+// submission on retry.
+// The write runs on its own deadline, detached from the request's: a forward
+// that used up the request budget must still leave an audit row behind.
+func (h *BlockaidHandler) persistReport(ctx context.Context, report types.BlockaidReport, forwardErr error) {
+	report.UserID, _ = auth.UserIDFromContext(ctx)
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), blockaidReportPersistTimeout)
+	defer cancel()
+	if forwardErr != nil {
+		report.ForwardError = forwardErr.Error()
+	}
+	if err := h.ReportStore.InsertBlockaidReport(ctx, report); err != nil {
+		logger.ErrorWithContext(ctx, "persisting blockaid report", "kind", report.Kind, "error", err)
+	}
+}
+
+// ReportAssetWarning handles POST /api/v1/report-asset-warning.
+func (h *BlockaidHandler) ReportAssetWarning(w http.ResponseWriter, r *http.Request) error {
+	ctx, cancel := context.WithTimeout(r.Context(), BlockaidContextTimeout)
+	defer cancel()
+
+	network := r.URL.Query().Get("network")
+	if !isValidNetwork(network) {
+		return httperror.BadRequest(fmt.Sprintf("invalid network: network must be %s, %s or %s", types.PUBLIC, types.TESTNET, types.FUTURENET), errors.New("invalid network"))
+	}
+	if network != types.PUBLIC {
+		return httperror.BadRequest(fmt.Sprintf("asset warning reporting is only available on %s", types.PUBLIC), errors.New("network not supported by blockaid"))
+	}
+	if unavailable := h.reportStoreUnavailable(); unavailable != nil {
+		return unavailable
+	}
+
+	var req ReportAssetWarningRequest
+	if decodeErr := decodeJSONBody(r, &req); decodeErr != nil {
+		return decodeErr
+	}
+	asset, err := assetid.Normalize(req.Asset)
+	if err != nil {
+		return httperror.BadRequest("invalid asset id", err)
+	}
+	if asset == assetid.NativeCanonical {
+		errStr := "native XLM is never flagged and cannot be reported"
+		return httperror.BadRequest(errStr, errors.New(errStr))
+	}
+	if validationErr := validateReportEvent(req.Event, req.Details); validationErr != nil {
+		return validationErr
+	}
+
+	report := types.AssetWarningReport{Asset: asset, Event: req.Event, Details: req.Details}
+	forwardErr := h.BlockaidService.ReportAsset(ctx, network, report)
+	h.persistReport(ctx, types.BlockaidReport{
+		Kind:    types.ReportKindAsset,
+		Event:   report.Event,
+		Network: network,
+		Subject: report.Asset,
+		Details: report.Details,
+	}, forwardErr)
+	if forwardErr != nil {
+		return translateBlockaidError(r.Context(), forwardErr, "asset warning report", network)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return response.OK(w, HttpResponse{Data: ReportWarningResponse{Reported: true}})
+}
+
+// ReportTransactionWarning handles POST /api/v1/report-transaction-warning.
+func (h *BlockaidHandler) ReportTransactionWarning(w http.ResponseWriter, r *http.Request) error {
+	ctx, cancel := context.WithTimeout(r.Context(), BlockaidContextTimeout)
+	defer cancel()
+
+	network := r.URL.Query().Get("network")
+	if !isValidNetwork(network) {
+		return httperror.BadRequest(fmt.Sprintf("invalid network: network must be %s, %s or %s", types.PUBLIC, types.TESTNET, types.FUTURENET), errors.New("invalid network"))
+	}
+	if unavailable := h.reportStoreUnavailable(); unavailable != nil {
+		return unavailable
+	}
+
+	var req ReportTransactionWarningRequest
+	if decodeErr := decodeJSONBody(r, &req); decodeErr != nil {
+		return decodeErr
+	}
+	tx, validationErr := validateTxForScan(req.TxXDR, req.URL)
+	if validationErr != nil {
+		return validationErr
+	}
+	if validationErr := validateReportEvent(req.Event, req.Details); validationErr != nil {
+		return validationErr
+	}
+
+	report := types.TransactionWarningReport{
+		AccountAddress: tx.accountAddress,
+		TxXDR:          tx.txXDR,
+		OriginURL:      tx.originURL,
+		Event:          req.Event,
+		Details:        req.Details,
+	}
+	forwardErr := h.BlockaidService.ReportTransaction(ctx, network, report)
+	h.persistReport(ctx, types.BlockaidReport{
+		Kind:      types.ReportKindTransaction,
+		Event:     report.Event,
+		Network:   network,
+		Subject:   report.TxXDR,
+		OriginURL: report.OriginURL,
+		Details:   report.Details,
+	}, forwardErr)
+	if forwardErr != nil {
+		return translateBlockaidError(r.Context(), forwardErr, "transaction warning report", network)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return response.OK(w, HttpResponse{Data: ReportWarningResponse{Reported: true}})
+}
+
+// translateBlockaidError maps a Blockaid service error to an HttpError. A
+// non-200 from Blockaid or a transport failure is a 502, a timeout a 504, and
+// a client disconnect a 503; anything else (encoding, decoding) is a 500.
+func translateBlockaidError(ctx context.Context, err error, resource, network string) *httperror.HttpError {
+	logger.ErrorWithContext(ctx, "blockaid call failed", "resource", resource, "network", network, "error", err)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return httperror.GatewayTimeout(fmt.Sprintf("Failed to get %s", resource), err)
+	case errors.Is(err, context.Canceled):
+		return httperror.ServiceUnavailable(fmt.Sprintf("Failed to get %s", resource), err)
+	}
+	var upErr *metrics.UpstreamError
+	if errors.As(err, &upErr) {
+		return httperror.BadGateway(fmt.Sprintf("Failed to get %s", resource), err)
+	}
+	return httperror.InternalServerError(fmt.Sprintf("Failed to get %s", resource), err)
+}
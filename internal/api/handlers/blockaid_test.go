@@ -0,0 +1,395 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/network"
+	"github.com/stellar/go-stellar-sdk/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/auth"
+	"github.com/stellar/freighter-backend-v2/internal/metrics"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils"
+)
+
+// testTxEnvelope builds a signed one-payment envelope and returns it with its
+// source account address.
+func testTxEnvelope(t *testing.T) (string, string) {
+	t.Helper()
+	source := keypair.MustRandom()
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &txnbuild.SimpleAccount{AccountID: source.Address(), Sequence: 1},
+		IncrementSequenceNum: true,
+		Operations: []txnbuild.Operation{&txnbuild.Payment{
+			Destination: keypair.MustRandom().Address(),
+			Amount:      "1",
+			Asset:       txnbuild.NativeAsset{},
+		}},
+		BaseFee:       txnbuild.MinBaseFee,
+		Preconditions: txnbuild.Preconditions{TimeBounds: txnbuild.NewTimeout(300)},
+	})
+	require.NoError(t, err)
+	tx, err = tx.Sign(network.TestNetworkPassphrase, source)
+	require.NoError(t, err)
+	envelope, err := tx.Base64()
+	require.NoError(t, err)
+	return envelope, source.Address()
+}
+
+func newScanTxRequest(network, body string) *http.Request {
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/scan-tx?network="+network, strings.NewReader(body))
+	return req
+}
+
+func TestBlockaid_ScanTx_Success(t *testing.T) {
+	t.Parallel()
+
+	envelope, source := testTxEnvelope(t)
+	var gotNetwork, gotAccount, gotXDR, gotOrigin string
+	mock := &utils.MockBlockaidService{
+		ScanTxFunc: func(ctx context.Context, network, accountAddress, txXDR, originURL string) (*types.TxScanResult, error) {
+			gotNetwork, gotAccount, gotXDR, gotOrigin = network, accountAddress, txXDR, originURL
+			return &types.TxScanResult{
+				ScanVerdict: types.ScanVerdict{ResultType: types.RiskWarning, IsSuspicious: true, Features: []types.BlockaidFeature{}},
+				Simulation:  json.RawMessage(`{"status":"Success"}`),
+			}, nil
+		},
+	}
+	handler := NewBlockaidHandler(mock, nil)
+
+	rr := httptest.NewRecorder()
+	body := `{"tx_xdr":"` + envelope + `","url":"https://dapp.example/swap"}`
+	require.NoError(t, handler.ScanTx(rr, newScanTxRequest(types.TESTNET, body)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	assert.Equal(t, types.TESTNET, gotNetwork)
+	assert.Equal(t, source, gotAccount)
+	assert.Equal(t, envelope, gotXDR)
+	assert.Equal(t, "https://dapp.example/swap", gotOrigin)
+
+	var resp struct {
+		Data types.TxScanResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, types.RiskWarning, resp.Data.ResultType)
+	assert.True(t, resp.Data.IsSuspicious)
+	assert.JSONEq(t, `{"status":"Success"}`, string(resp.Data.Simulation))
+}
+
+func TestBlockaid_ScanTx_BadRequests(t *testing.T) {
+	t.Parallel()
+
+	envelope, _ := testTxEnvelope(t)
+	tests := []struct {
+		name    string
+		network string
+		body    string
+	}{
+		{"invalid network", "MAINNET", `{"tx_xdr":"` + envelope + `"}`},
+		{"malformed body", types.PUBLIC, `{"tx_xdr":`},
+		{"empty tx_xdr", types.PUBLIC, `{"tx_xdr":"  "}`},
+		{"tx_xdr not an envelope", types.PUBLIC, `{"tx_xdr":"bm90IGFuIGVudmVsb3Bl"}`},
+		{"relative url", types.PUBLIC, `{"tx_xdr":"` + envelope + `","url":"/swap"}`},
+		{"non-http url", types.PUBLIC, `{"tx_xdr":"` + envelope + `","url":"javascript:alert(1)"}`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			mock := &utils.MockBlockaidService{
+				ScanTxFunc: func(context.Context, string, string, string, string) (*types.TxScanResult, error) {
+					t.Fatal("service must not be called for an invalid request")
+					return nil, nil
+				},
+			}
+			err := NewBlockaidHandler(mock, nil).ScanTx(httptest.NewRecorder(), newScanTxRequest(tc.network, tc.body))
+			require.Error(t, err)
+			assert.Equal(t, http.StatusBadRequest, unwrapHttpStatus(t, err))
+		})
+	}
+}
+
+func TestBlockaid_ScanTx_ServiceErrors(t *testing.T) {
+	t.Parallel()
+
+	envelope, _ := testTxEnvelope(t)
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"upstream non-200", &metrics.UpstreamError{Kind: "http_error", Code: http.StatusForbidden, Err: errors.New("status 403")}, http.StatusBadGateway},
+		{"deadline", context.DeadlineExceeded, http.StatusGatewayTimeout},
+		{"canceled", context.Canceled, http.StatusServiceUnavailable},
+		{"decode failure", errors.New("decoding blockaid response"), http.StatusInternalServerError},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			mock := &utils.MockBlockaidService{ScanTxError: tc.err}
+			err := NewBlockaidHandler(mock, nil).ScanTx(httptest.NewRecorder(), newScanTxRequest(types.PUBLIC, `{"tx_xdr":"`+envelope+`"}`))
+			require.Error(t, err)
+			assert.Equal(t, tc.want, unwrapHttpStatus(t, err))
+		})
+	}
+}
+
+func TestBlockaid_ScanDapp_ScansOrigin(t *testing.T) {
+	t.Parallel()
+
+	mock := &utils.MockBlockaidService{
+		ScanDappResult: &types.DappScanResult{
+			ScanVerdict: types.ScanVerdict{ResultType: types.RiskBenign, Features: []types.BlockaidFeature{}},
+			URL:         "https://app.example",
+			Status:      types.DappScanHit,
+		},
+	}
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/scan-dapp", strings.NewReader(`{"url":"https://App.Example/swap?x=1"}`))
+	rr := httptest.NewRecorder()
+
+	require.NoError(t, NewBlockaidHandler(mock, nil).ScanDapp(rr, req))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "https://app.example", mock.LastOrigin, "path and query must be stripped before scanning")
+
+	var resp struct {
+		Data types.DappScanResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, types.DappScanHit, resp.Data.Status)
+	assert.Equal(t, types.RiskBenign, resp.Data.ResultType)
+}
+
+func TestBlockaid_ScanDapp_BadRequests(t *testing.T) {
+	t.Parallel()
+
+	for name, body := range map[string]string{
+		"malformed body": `{"url":`,
+		"empty url":      `{"url":""}`,
+		"relative url":   `{"url":"app.example"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			req, _ := http.NewRequest(http.MethodPost, "/api/v1/scan-dapp", strings.NewReader(body))
+			err := NewBlockaidHandler(&utils.MockBlockaidService{}, nil).ScanDapp(httptest.NewRecorder(), req)
+			require.Error(t, err)
+			assert.Equal(t, http.StatusBadRequest, unwrapHttpStatus(t, err))
+		})
+	}
+}
+
+func TestBlockaid_ScanAssets_KeyedByOriginalInput(t *testing.T) {
+	t.Parallel()
+
+	mock := &utils.MockBlockaidService{}
+	body := `{"assets":["native","XLM","USDC:` + validIssuer + `"]}`
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/scan-assets?network=PUBLIC", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	require.NoError(t, NewBlockaidHandler(mock, nil).ScanAssets(rr, req))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, []string{"XLM", "USDC:" + validIssuer}, mock.LastAssets, "inputs are canonicalized and deduped")
+
+	var resp struct {
+		Data map[string]*types.AssetScanResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 3)
+	assert.Equal(t, types.RiskBenign, resp.Data["native"].ResultType)
+	assert.Equal(t, types.RiskBenign, resp.Data["USDC:"+validIssuer].ResultType)
+}
+
+func TestBlockaid_ScanAssets_BadRequests(t *testing.T) {
+	t.Parallel()
+
+	tooMany := make([]string, 0, MaxScanAssetsPerRequest+1)
+	for i := 0; i <= MaxScanAssetsPerRequest; i++ {
+		tooMany = append(tooMany, `"A`+strconv.Itoa(i)+`:`+validIssuer+`"`)
+	}
+	tests := []struct {
+		name    string
+		network string
+		body    string
+	}{
+		{"invalid network", "MAINNET", `{"assets":["XLM"]}`},
+		{"testnet unsupported", types.TESTNET, `{"assets":["XLM"]}`},
+		{"empty assets", types.PUBLIC, `{"assets":[]}`},
+		{"invalid asset id", types.PUBLIC, `{"assets":["USDC"]}`},
+		{"too many assets", types.PUBLIC, `{"assets":[` + strings.Join(tooMany, ",") + `]}`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			req, _ := http.NewRequest(http.MethodPost, "/api/v1/scan-assets?network="+tc.network, strings.NewReader(tc.body))
+			err := NewBlockaidHandler(&utils.MockBlockaidService{}, nil).ScanAssets(httptest.NewRecorder(), req)
+			require.Error(t, err)
+			assert.Equal(t, http.StatusBadRequest, unwrapHttpStatus(t, err))
+		})
+	}
+}
+
+func TestBlockaid_ScanAssets_UpstreamError(t *testing.T) {
+	t.Parallel()
+
+	mock := &utils.MockBlockaidService{ScanAssetsError: &metrics.UpstreamError{Kind: "http_error", Code: http.StatusTooManyRequests, Err: errors.New("status 429")}}
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/scan-assets?network=PUBLIC", strings.NewReader(`{"assets":["USDC:`+validIssuer+`"]}`))
+	err := NewBlockaidHandler(mock, nil).ScanAssets(httptest.NewRecorder(), req)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadGateway, unwrapHttpStatus(t, err))
+}
+
+func newReportRequest(path, network, body, userID string) *http.Request {
+	req, _ := http.NewRequest(http.MethodPost, path+"?network="+network, strings.NewReader(body))
+	if userID != "" {
+		req = req.WithContext(auth.ContextWithUserID(req.Context(), userID))
+	}
+	return req
+}
+
+func TestBlockaid_ReportAssetWarning_ForwardsAndPersists(t *testing.T) {
+	t.Parallel()
+
+	mock := &utils.MockBlockaidService{}
+	reports := &utils.MockBlockaidReportStore{}
+	body := `{"asset":"USDC:` + validIssuer + `","event":"FALSE_POSITIVE","details":"this is the real USDC"}`
+	rr := httptest.NewRecorder()
+
+	require.NoError(t, NewBlockaidHandler(mock, reports).ReportAssetWarning(rr, newReportRequest("/api/v1/report-asset-warning", types.PUBLIC, body, "user-1")))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"data":{"reported":true}}`, rr.Body.String())
+
+	require.NotNil(t, mock.LastAssetReport)
+	assert.Equal(t, types.AssetWarningReport{Asset: "USDC:" + validIssuer, Event: types.ReportFalsePositive, Details: "this is the real USDC"}, *mock.LastAssetReport)
+	require.Len(t, reports.Reports, 1)
+	assert.Equal(t, types.BlockaidReport{
+		Kind:    types.ReportKindAsset,
+		Event:   types.ReportFalsePositive,
+		Network: types.PUBLIC,
+		Subject: "USDC:" + validIssuer,
+		Details: "this is the real USDC",
+		UserID:  "user-1",
+	}, reports.Reports[0])
+}
+
+func TestBlockaid_ReportAssetWarning_BadRequests(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		network string
+		body    string
+	}{
+		{"testnet unsupported", types.TESTNET, `{"asset":"USDC:` + validIssuer + `","event":"FALSE_POSITIVE"}`},
+		{"invalid asset", types.PUBLIC, `{"asset":"USDC","event":"FALSE_POSITIVE"}`},
+		{"native asset", types.PUBLIC, `{"asset":"native","event":"FALSE_POSITIVE"}`},
+		{"unknown event", types.PUBLIC, `{"asset":"USDC:` + validIssuer + `","event":"WRONG"}`},
+		{"details too long", types.PUBLIC, `{"asset":"USDC:` + validIssuer + `","event":"FALSE_NEGATIVE","details":"` + strings.Repeat("x", MaxReportDetailsLength+1) + `"}`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			reports := &utils.MockBlockaidReportStore{}
+			err := NewBlockaidHandler(&utils.MockBlockaidService{}, reports).ReportAssetWarning(httptest.NewRecorder(), newReportRequest("/api/v1/report-asset-warning", tc.network, tc.body, ""))
+			require.Error(t, err)
+			assert.Equal(t, http.StatusBadRequest, unwrapHttpStatus(t, err))
+			assert.Empty(t, reports.Reports, "a rejected report must not be persisted")
+		})
+	}
+}
+
+// TestBlockaid_ReportAssetWarning_PersistsFailedForward pins that a report
+// Blockaid rejected still leaves an audit row, tagged with the forward error.
+func TestBlockaid_ReportAssetWarning_PersistsFailedForward(t *testing.T) {
+	t.Parallel()
+
+	mock := &utils.MockBlockaidService{ReportError: &metrics.UpstreamError{Kind: "http_error", Code: http.StatusInternalServerError, Err: errors.New("status 500")}}
+	reports := &utils.MockBlockaidReportStore{}
+	body := `{"asset":"USDC:` + validIssuer + `","event":"FALSE_NEGATIVE"}`
+
+	err := NewBlockaidHandler(mock, reports).ReportAssetWarning(httptest.NewRecorder(), newReportRequest("/api/v1/report-asset-warning", types.PUBLIC, body, ""))
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadGateway, unwrapHttpStatus(t, err))
+	require.Len(t, reports.Reports, 1)
+	assert.Equal(t, "http_error (code 500): status 500", reports.Reports[0].ForwardError)
+	assert.Empty(t, reports.Reports[0].UserID)
+}
+
+func TestBlockaid_ReportAssetWarning_StoreInsertFailureStillSucceeds(t *testing.T) {
+	t.Parallel()
+
+	reports := &utils.MockBlockaidReportStore{InsertError: errors.New("db down")}
+	body := `{"asset":"USDC:` + validIssuer + `","event":"FALSE_POSITIVE"}`
+	rr := httptest.NewRecorder()
+
+	require.NoError(t, NewBlockaidHandler(&utils.MockBlockaidService{}, reports).ReportAssetWarning(rr, newReportRequest("/api/v1/report-asset-warning", types.PUBLIC, body, "")))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestBlockaid_Reports_UnavailableWithoutStore(t *testing.T) {
+	t.Parallel()
+
+	envelope, _ := testTxEnvelope(t)
+	handler := NewBlockaidHandler(&utils.MockBlockaidService{}, nil)
+
+	err := handler.ReportAssetWarning(httptest.NewRecorder(), newReportRequest("/api/v1/report-asset-warning", types.PUBLIC, `{"asset":"USDC:`+validIssuer+`","event":"FALSE_POSITIVE"}`, ""))
+	require.Error(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, unwrapHttpStatus(t, err))
+
+	err = handler.ReportTransactionWarning(httptest.NewRecorder(), newReportRequest("/api/v1/report-transaction-warning", types.PUBLIC, `{"tx_xdr":"`+envelope+`","event":"FALSE_POSITIVE"}`, ""))
+	require.Error(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, unwrapHttpStatus(t, err))
+}
+
+func TestBlockaid_ReportTransactionWarning_ForwardsAndPersists(t *testing.T) {
+	t.Parallel()
+
+	envelope, source := testTxEnvelope(t)
+	mock := &utils.MockBlockaidService{}
+	reports := &utils.MockBlockaidReportStore{}
+	body := `{"tx_xdr":"` + envelope + `","url":"https://dapp.example","event":"FALSE_NEGATIVE","details":"drained my account"}`
+	rr := httptest.NewRecorder()
+
+	require.NoError(t, NewBlockaidHandler(mock, reports).ReportTransactionWarning(rr, newReportRequest("/api/v1/report-transaction-warning", types.TESTNET, body, "user-2")))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	require.NotNil(t, mock.LastTransactionReport)
+	assert.Equal(t, source, mock.LastTransactionReport.AccountAddress)
+	assert.Equal(t, "https://dapp.example", mock.LastTransactionReport.OriginURL)
+	require.Len(t, reports.Reports, 1)
+	assert.Equal(t, types.BlockaidReport{
+		Kind:      types.ReportKindTransaction,
+		Event:     types.ReportFalseNegative,
+		Network:   types.TESTNET,
+		Subject:   envelope,
+		OriginURL: "https://dapp.example",
+		Details:   "drained my account",
+		UserID:    "user-2",
+	}, reports.Reports[0])
+}
+
+func TestBlockaid_ReportTransactionWarning_BadRequests(t *testing.T) {
+	t.Parallel()
+
+	envelope, _ := testTxEnvelope(t)
+	for name, body := range map[string]string{
+		"invalid tx_xdr": `{"tx_xdr":"AAAA","event":"FALSE_POSITIVE"}`,
+		"bad url":        `{"tx_xdr":"` + envelope + `","url":"ftp://x","event":"FALSE_POSITIVE"}`,
+		"missing event":  `{"tx_xdr":"` + envelope + `"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := NewBlockaidHandler(&utils.MockBlockaidService{}, &utils.MockBlockaidReportStore{}).ReportTransactionWarning(httptest.NewRecorder(), newReportRequest("/api/v1/report-transaction-warning", types.PUBLIC, body, ""))
+			require.Error(t, err)
+			assert.Equal(t, http.StatusBadRequest, unwrapHttpStatus(t, err))
+		})
+	}
+}
@@ -62,6 +62,9 @@ type Collection struct {
 	Name              string        `json:"name"`
 	Symbol            string        `json:"symbol"`
 	Collectibles      []Collectible `json:"collectibles"`
+	// Truncated is set on a discovered collection when the owner holds more
+	// tokens than the handler's MaxDiscoveredTokens.
+	Truncated bool `json:"truncated,omitempty"`
 }
 
 type TokenError struct {
@@ -87,26 +90,32 @@ type GetCollectiblesPayload struct {
 }
 
 type CollectiblesHandler struct {
-	RpcService                     types.RPCService
-	MeridianPayTreasureHuntAddress string
-	MeridianPayTreasurePoapAddress string
-	MeridianPayStellarHouseAddress string
-	maxConcurrentRPCCalls          int
-	pool                           pond.Pool
-	tokenPool                      pond.Pool
-	rpcPool                        pond.Pool
+	RpcService types.RPCService
+	// MetadataService, when set, resolves each collectible's token_uri and
+	// attaches the SEP-50 metadata. A failed lookup leaves Metadata empty
+	// rather than failing the token.
+	MetadataService types.CollectibleMetadataService
+	// Registry lists the collections whose tokens are discovered for the
+	// owner on every request. Nil disables discovery.
+	Registry types.CollectionRegistry
+	// MaxDiscoveredTokens caps the tokens discovered per registered
+	// collection; a capped collection is marked Truncated.
+	MaxDiscoveredTokens   int
+	maxConcurrentRPCCalls int
+	pool                  pond.Pool
+	tokenPool             pond.Pool
+	rpcPool               pond.Pool
 }
 
-func NewCollectiblesHandler(rpc types.RPCService, meridianPayTreasureHuntAddress string, meridianPayTreasurePoapAddress string, meridianPayStellarHouseAddress string, maxConcurrentRPCCalls int) *CollectiblesHandler {
+func NewCollectiblesHandler(rpc types.RPCService, registry types.CollectionRegistry, maxConcurrentRPCCalls int) *CollectiblesHandler {
 	return &CollectiblesHandler{
-		RpcService:                     rpc,
-		MeridianPayTreasureHuntAddress: meridianPayTreasureHuntAddress,
-		MeridianPayTreasurePoapAddress: meridianPayTreasurePoapAddress,
-		MeridianPayStellarHouseAddress: meridianPayStellarHouseAddress,
-		maxConcurrentRPCCalls:          maxConcurrentRPCCalls,
-		pool:                           pond.NewPool(maxConcurrentRPCCalls),     // 10 contracts
-		tokenPool:                      pond.NewPool(maxConcurrentRPCCalls * 2), // 20 tokens
-		rpcPool:                        pond.NewPool(maxConcurrentRPCCalls * 4), // 40 RPC calls
+		RpcService:            rpc,
+		Registry:              registry,
+		MaxDiscoveredTokens:   DefaultMaxDiscoveredTokens,
+		maxConcurrentRPCCalls: maxConcurrentRPCCalls,
+		pool:                  pond.NewPool(maxConcurrentRPCCalls),     // 10 contracts
+		tokenPool:             pond.NewPool(maxConcurrentRPCCalls * 2), // 20 tokens
+		rpcPool:               pond.NewPool(maxConcurrentRPCCalls * 4), // 40 RPC calls
 	}
 }
 
@@ -203,6 +212,15 @@ func (h *CollectiblesHandler) fetchCollectibles(
 		tokenID := tokenID // Capture loop variable
 		group.Submit(func() {
 			c, err := fetchCollectible(h.RpcService, ctx, account, contractID, tokenID, network, h.rpcPool)
+			if err == nil && h.MetadataService != nil {
+				metadata, metaErr := h.MetadataService.GetMetadata(ctx, network, c.TokenUri)
+				if metaErr != nil {
+					// token_uri is contract-controlled, so a dead or malformed
+					// document is routine and not an error on our side.
+					logger.InfoWithContext(ctx, "collectible metadata unavailable", "contract", contractID, "token_id", tokenID, "error", metaErr)
+				}
+				c.Metadata = metadata
+			}
 			mu.Lock()
 			defer mu.Unlock()
 			if err != nil {
@@ -222,40 +240,45 @@ func (h *CollectiblesHandler) fetchCollectibles(
 	return results, tokenErrs
 }
 
-func (h *CollectiblesHandler) fetchMeridianPayCollectibles(
+// registeredCollections returns the registry's entries for network. A
+// registry failure only disables discovery for this request: the contracts the
+// client listed are still served.
+func (h *CollectiblesHandler) registeredCollections(ctx context.Context, network string) []types.RegisteredCollection {
+	if h.Registry == nil {
+		return nil
+	}
+	collections, err := h.Registry.Collections(ctx, network)
+	if err != nil {
+		logger.ErrorWithContext(ctx, fmt.Sprintf("loading collection registry for %s: %v", network, err))
+		return nil
+	}
+	return collections
+}
+
+func (h *CollectiblesHandler) fetchRegisteredCollectibles(
 	ctx context.Context,
 	account *txnbuild.SimpleAccount,
 	owner string,
 	network string,
+	collections []types.RegisteredCollection,
 ) ([]CollectionResult, error) {
-	contracts := []string{}
-	if h.MeridianPayTreasureHuntAddress != "" {
-		contracts = append(contracts, h.MeridianPayTreasureHuntAddress)
-	}
-	if h.MeridianPayTreasurePoapAddress != "" {
-		contracts = append(contracts, h.MeridianPayTreasurePoapAddress)
-	}
-	if h.MeridianPayStellarHouseAddress != "" {
-		contracts = append(contracts, h.MeridianPayStellarHouseAddress)
-	}
-
-	if len(contracts) == 0 {
+	if len(collections) == 0 {
 		return []CollectionResult{}, nil
 	}
 
-	results := make([]CollectionResult, len(contracts))
+	results := make([]CollectionResult, len(collections))
 	var wg sync.WaitGroup
-	errCh := make(chan error, len(contracts))
+	errCh := make(chan error, len(collections))
 
 	// Use WaitGroup instead of pool to avoid nesting since this is called from pool tasks
-	for i, contract := range contracts {
-		i, contract := i, contract // Capture loop variables
+	for i, entry := range collections {
+		i, entry, contract := i, entry, entry.ContractID // Capture loop variables
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 			defer func() {
 				if p := recover(); p != nil {
-					logger.ErrorWithContext(ctx, fmt.Sprintf("panic fetching meridian pay collectible %s: %v", contract, p))
+					logger.ErrorWithContext(ctx, fmt.Sprintf("panic fetching registered collectible %s: %v", contract, p))
 					results[i] = CollectionResult{
 						Error: &CollectionError{
 							ErrorMessage:      msgUnexpectedError,
@@ -268,7 +291,7 @@ func (h *CollectiblesHandler) fetchMeridianPayCollectibles(
 			// Check context before starting work
 			select {
 			case <-ctx.Done():
-				logger.ErrorWithContext(ctx, fmt.Sprintf("context done before fetching meridian pay collectible %s: %v", contract, ctx.Err()))
+				logger.ErrorWithContext(ctx, fmt.Sprintf("context done before fetching registered collectible %s: %v", contract, ctx.Err()))
 				results[i] = CollectionResult{
 					Error: &CollectionError{
 						ErrorMessage:      msgCollectionFetchFailed,
@@ -280,7 +303,7 @@ func (h *CollectiblesHandler) fetchMeridianPayCollectibles(
 			default:
 			}
 
-			tokenIds, err := fetchOwnerTokens(h.RpcService, ctx, account, contract, owner, network)
+			tokenIds, truncated, err := fetchOwnerTokens(h.RpcService, ctx, account, entry, owner, network, h.rpcPool, h.MaxDiscoveredTokens)
 			if err != nil {
 				logger.ErrorWithContext(ctx, fmt.Sprintf("fetching owner tokens for %s: %v", contract, err))
 				results[i] = CollectionResult{
@@ -298,6 +321,9 @@ func (h *CollectiblesHandler) fetchMeridianPayCollectibles(
 			}
 
 			collection, colErr := h.fetchCollection(ctx, account, contractData, network)
+			if collection != nil {
+				collection.Truncated = truncated
+			}
 			results[i] = CollectionResult{
 				Collection: collection,
 				Error:      colErr,
@@ -311,7 +337,7 @@ func (h *CollectiblesHandler) fetchMeridianPayCollectibles(
 	// Check if any goroutines reported context errors
 	for err := range errCh {
 		if err != nil {
-			return results, fmt.Errorf("waiting for meridian pay collectibles: %w", err)
+			return results, fmt.Errorf("waiting for registered collectibles: %w", err)
 		}
 	}
 
@@ -345,18 +371,14 @@ func (h *CollectiblesHandler) GetCollectibles(w http.ResponseWriter, r *http.Req
 	}
 
 	account := &txnbuild.SimpleAccount{AccountID: req.Owner}
+	registered := h.registeredCollections(ctx, network)
 	skipContracts := mapset.NewSet[string]()
-	if h.MeridianPayTreasureHuntAddress != "" {
-		skipContracts.Add(h.MeridianPayTreasureHuntAddress)
-	}
-	if h.MeridianPayTreasurePoapAddress != "" {
-		skipContracts.Add(h.MeridianPayTreasurePoapAddress)
-	}
-	if h.MeridianPayStellarHouseAddress != "" {
-		skipContracts.Add(h.MeridianPayStellarHouseAddress)
+	for _, c := range registered {
+		skipContracts.Add(c.ContractID)
 	}
 
-	// Filter user-requested contracts to exclude Meridian Pay addresses
+	// Registered collections are discovered below, so drop them from the
+	// client's list rather than fetching them twice.
 	var filteredContracts []contractDetails
 	for _, c := range req.Contracts {
 		if !skipContracts.Contains(c.ID) {
@@ -383,11 +405,11 @@ func (h *CollectiblesHandler) GetCollectibles(w http.ResponseWriter, r *http.Req
 	}
 	_ = group.Wait() // Wait for all contracts to complete or context to cancel
 
-	meridianResults, err := h.fetchMeridianPayCollectibles(ctx, account, owner, network)
+	registeredResults, err := h.fetchRegisteredCollectibles(ctx, account, owner, network, registered)
 	if err != nil {
 		logger.ErrorWithContext(ctx, fmt.Sprintf(ErrInternal.LogMessage, err))
 	}
-	allResults := append(results, meridianResults...)
+	allResults := append(results, registeredResults...)
 
 	responseData := HttpResponse{
 		Data: GetCollectiblesPayload{
@@ -3,9 +3,11 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stellar/go-stellar-sdk/txnbuild"
@@ -13,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/stellar/freighter-backend-v2/internal/types"
 	"github.com/stellar/freighter-backend-v2/internal/utils"
 )
 
@@ -30,7 +33,7 @@ func TestGetCollectibles(t *testing.T) {
 			TokenURIOverride: server.URL,
 		}
 
-		handler := NewCollectiblesHandler(mockRPC, "", "", "", 10)
+		handler := NewCollectiblesHandler(mockRPC, nil, 10)
 
 		body := `{
 			"owner": "GB7RQNG6ROYGLFKR3IDAABKI2Y2UAQKEO6BSJVR5IYS7UYQ743O7TOXE",
@@ -69,7 +72,7 @@ func TestGetCollectibles(t *testing.T) {
 func TestFetchCollection(t *testing.T) {
 	t.Run("returns collection when collectibles exist", func(t *testing.T) {
 		mockRPC := &utils.MockRPCService{}
-		handler := NewCollectiblesHandler(mockRPC, "", "", "", 10)
+		handler := NewCollectiblesHandler(mockRPC, nil, 10)
 
 		account := &txnbuild.SimpleAccount{AccountID: "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"}
 		contract := contractDetails{
@@ -86,7 +89,7 @@ func TestFetchCollection(t *testing.T) {
 
 	t.Run("returns collection-level error when all token fetches fail", func(t *testing.T) {
 		mockRPC := &utils.MockRPCService{}
-		handler := NewCollectiblesHandler(mockRPC, "", "", "", 10)
+		handler := NewCollectiblesHandler(mockRPC, nil, 10)
 
 		account := &txnbuild.SimpleAccount{AccountID: "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"}
 		// Use invalid token IDs (non-numeric) that will fail to parse
@@ -117,9 +120,48 @@ func TestFetchCollection(t *testing.T) {
 		assert.Equal(t, msgCollectibleFetchFailed, err.Tokens[1].ErrorMessage)
 	})
 
+	t.Run("decodes string results without ScVal formatting", func(t *testing.T) {
+		mockRPC := &utils.MockRPCService{TokenURIOverride: "ipfs://bafy/1.json"}
+		handler := NewCollectiblesHandler(mockRPC, nil, 10)
+
+		account := &txnbuild.SimpleAccount{AccountID: "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"}
+		contract := contractDetails{
+			ID:       "CBIELTK6YBZJU5UP2WWQEUCYKLPU6AUNZ2BQ4WWFEIE3USCIHMXQDAMA",
+			TokenIDs: []string{"0"},
+		}
+
+		collection, err := handler.fetchCollection(context.Background(), account, contract, "PUBLIC")
+		require.Nil(t, err)
+		require.NotNil(t, collection)
+		assert.Equal(t, "MockNFT", collection.Name)
+		assert.Equal(t, "MNFT", collection.Symbol)
+		require.Len(t, collection.Collectibles, 1)
+		assert.Equal(t, account.AccountID, collection.Collectibles[0].Owner)
+		assert.Equal(t, "ipfs://bafy/1.json", collection.Collectibles[0].TokenUri)
+	})
+
+	t.Run("returns collection-level error when contract returns non-text metadata", func(t *testing.T) {
+		n := xdr.Uint32(1)
+		mockRPC := &utils.MockRPCService{
+			SimulateResultOverride: &xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &n},
+		}
+		handler := NewCollectiblesHandler(mockRPC, nil, 10)
+
+		account := &txnbuild.SimpleAccount{AccountID: "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"}
+		contract := contractDetails{
+			ID:       "CBIELTK6YBZJU5UP2WWQEUCYKLPU6AUNZ2BQ4WWFEIE3USCIHMXQDAMA",
+			TokenIDs: []string{"0"},
+		}
+
+		collection, err := handler.fetchCollection(context.Background(), account, contract, "PUBLIC")
+		require.Nil(t, collection)
+		require.NotNil(t, err)
+		assert.Equal(t, msgCollectionFetchFailed, err.ErrorMessage)
+	})
+
 	t.Run("returns collection-level error when token IDs is empty", func(t *testing.T) {
 		mockRPC := &utils.MockRPCService{}
-		handler := NewCollectiblesHandler(mockRPC, "", "", "", 10)
+		handler := NewCollectiblesHandler(mockRPC, nil, 10)
 
 		account := &txnbuild.SimpleAccount{AccountID: "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"}
 		contract := contractDetails{
@@ -146,7 +188,7 @@ func TestFetchCollection(t *testing.T) {
 func TestFetchCollectibles(t *testing.T) {
 	t.Run("returns empty slice if no collectibles", func(t *testing.T) {
 		mockRPC := &utils.MockRPCService{}
-		handler := NewCollectiblesHandler(mockRPC, "", "", "", 10)
+		handler := NewCollectiblesHandler(mockRPC, nil, 10)
 
 		account := &txnbuild.SimpleAccount{AccountID: "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"}
 		tokenIDs := []string{}
@@ -158,14 +200,65 @@ func TestFetchCollectibles(t *testing.T) {
 	})
 }
 
-func TestFetchMeridianPayCollectibles(t *testing.T) {
+func TestFetchCollectibles_ResolvesMetadata(t *testing.T) {
+	mockRPC := &utils.MockRPCService{}
+	handler := NewCollectiblesHandler(mockRPC, nil, 10)
+
+	var mu sync.Mutex
+	var gotURIs []string
+	handler.MetadataService = &utils.MockCollectibleMetadataService{
+		GetMetadataFunc: func(network, tokenURI string) (*types.CollectibleMetadata, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			gotURIs = append(gotURIs, tokenURI)
+			assert.Equal(t, "PUBLIC", network)
+			if len(gotURIs) == 1 {
+				return &types.CollectibleMetadata{Name: "Mock #1", Image: "https://example.com/1.png"}, nil
+			}
+			return nil, errors.New("gateway timeout")
+		},
+	}
+
+	account := &txnbuild.SimpleAccount{AccountID: "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"}
+	results, tokenErrs := handler.fetchCollectibles(context.Background(), account, "CBIELTK6YBZJU5UP2WWQEUCYKLPU6AUNZ2BQ4WWFEIE3USCIHMXQDAMA", []string{"0", "1"}, "PUBLIC")
+
+	// A metadata failure drops only the metadata, never the collectible.
+	assert.Empty(t, tokenErrs)
+	require.Len(t, results, 2)
+	assert.Equal(t, []string{"https://example.com/token.json", "https://example.com/token.json"}, gotURIs)
+
+	var resolved []*types.CollectibleMetadata
+	for _, c := range results {
+		if c.Metadata != nil {
+			resolved = append(resolved, c.Metadata)
+		}
+	}
+	require.Len(t, resolved, 1)
+	assert.Equal(t, "Mock #1", resolved[0].Name)
+}
+
+const (
+	testCollectionA = "CBIELTK6YBZJU5UP2WWQEUCYKLPU6AUNZ2BQ4WWFEIE3USCIHMXQDAMA"
+	testCollectionB = "CDSN4MICK7U5XOP4DE6OIZQCRMYO3UTQ5VYZV7ZA7H63OICZPBLXYRGJ"
+	testCollectionC = "CAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABSC4"
+)
+
+func registered(network string, enumeration types.CollectionEnumeration, contracts ...string) []types.RegisteredCollection {
+	entries := make([]types.RegisteredCollection, len(contracts))
+	for i, c := range contracts {
+		entries[i] = types.RegisteredCollection{Network: network, ContractID: c, Enumeration: enumeration}
+	}
+	return entries
+}
+
+func TestFetchRegisteredCollectibles(t *testing.T) {
 	mockRPC := &utils.MockRPCService{}
 	account := &txnbuild.SimpleAccount{AccountID: "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"}
 
-	handler := NewCollectiblesHandler(mockRPC, "CBIELTK6YBZJU5UP2WWQEUCYKLPU6AUNZ2BQ4WWFEIE3USCIHMXQDAMA", "CDSN4MICK7U5XOP4DE6OIZQCRMYO3UTQ5VYZV7ZA7H63OICZPBLXYRGJ", "CAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABSC4", 10)
+	handler := NewCollectiblesHandler(mockRPC, nil, 10)
 
 	ctx := context.Background()
-	results, err := handler.fetchMeridianPayCollectibles(ctx, account, account.AccountID, "PUBLIC")
+	results, err := handler.fetchRegisteredCollectibles(ctx, account, account.AccountID, "PUBLIC", registered("PUBLIC", types.CollectionEnumerationOwnerTokens, testCollectionA, testCollectionB, testCollectionC))
 	require.NoError(t, err)
 	require.Len(t, results, 3)
 
@@ -177,18 +270,80 @@ func TestFetchMeridianPayCollectibles(t *testing.T) {
 	}
 }
 
-func TestFetchMeridianPayCollectibles_PanicRecovery(t *testing.T) {
+func TestFetchRegisteredCollectibles_Enumerations(t *testing.T) {
+	for _, enumeration := range []types.CollectionEnumeration{types.CollectionEnumerationOwnerTokens, types.CollectionEnumerationOwnerIndex} {
+		t.Run(string(enumeration), func(t *testing.T) {
+			mockRPC := &utils.MockRPCService{OwnerTokenIDs: []uint32{4, 9}}
+			handler := NewCollectiblesHandler(mockRPC, nil, 10)
+			account := &txnbuild.SimpleAccount{AccountID: "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"}
+
+			results, err := handler.fetchRegisteredCollectibles(context.Background(), account, account.AccountID, "TESTNET", registered("TESTNET", enumeration, testCollectionA))
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+			require.NotNil(t, results[0].Collection)
+			assert.Nil(t, results[0].Error)
+
+			var tokenIDs []string
+			for _, c := range results[0].Collection.Collectibles {
+				tokenIDs = append(tokenIDs, c.TokenId)
+			}
+			assert.ElementsMatch(t, []string{"4", "9"}, tokenIDs)
+		})
+	}
+}
+
+func TestFetchRegisteredCollectibles_CapsOwnerIndex(t *testing.T) {
+	ids := make([]uint32, DefaultMaxDiscoveredTokens+5)
+	for i := range ids {
+		ids[i] = uint32(i) //nolint:gosec // small test index
+	}
+	mockRPC := &utils.MockRPCService{OwnerTokenIDs: ids}
+	handler := NewCollectiblesHandler(mockRPC, nil, 10)
+	account := &txnbuild.SimpleAccount{AccountID: "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"}
+
+	collection := registered("PUBLIC", types.CollectionEnumerationOwnerIndex, testCollectionA)[0]
+	tokenIDs, truncated, err := fetchOwnerTokens(mockRPC, context.Background(), account, collection, account.AccountID, "PUBLIC", handler.rpcPool, DefaultMaxDiscoveredTokens)
+	require.NoError(t, err)
+	assert.Len(t, tokenIDs, DefaultMaxDiscoveredTokens)
+	assert.True(t, truncated)
+}
+
+func TestFetchRegisteredCollectibles_FlagsTruncatedCollections(t *testing.T) {
+	for _, enumeration := range []types.CollectionEnumeration{types.CollectionEnumerationOwnerTokens, types.CollectionEnumerationOwnerIndex} {
+		t.Run(string(enumeration), func(t *testing.T) {
+			mockRPC := &utils.MockRPCService{OwnerTokenIDs: []uint32{4, 9, 11}}
+			handler := NewCollectiblesHandler(mockRPC, nil, 10)
+			handler.MaxDiscoveredTokens = 2
+			account := &txnbuild.SimpleAccount{AccountID: "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"}
+
+			results, err := handler.fetchRegisteredCollectibles(context.Background(), account, account.AccountID, "TESTNET", registered("TESTNET", enumeration, testCollectionA))
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+			require.NotNil(t, results[0].Collection)
+			assert.True(t, results[0].Collection.Truncated)
+			assert.Len(t, results[0].Collection.Collectibles, 2)
+
+			handler.MaxDiscoveredTokens = 3
+			results, err = handler.fetchRegisteredCollectibles(context.Background(), account, account.AccountID, "TESTNET", registered("TESTNET", enumeration, testCollectionA))
+			require.NoError(t, err)
+			require.NotNil(t, results[0].Collection)
+			assert.False(t, results[0].Collection.Truncated)
+		})
+	}
+}
+
+func TestFetchRegisteredCollectibles_PanicRecovery(t *testing.T) {
 	// Mock that panics during SimulateInvocation — previously this would crash the process
 	mockRPC := &utils.MockRPCService{
 		SimulatePanic: true,
 	}
-	handler := NewCollectiblesHandler(mockRPC, "CBIELTK6YBZJU5UP2WWQEUCYKLPU6AUNZ2BQ4WWFEIE3USCIHMXQDAMA", "CDSN4MICK7U5XOP4DE6OIZQCRMYO3UTQ5VYZV7ZA7H63OICZPBLXYRGJ", "", 10)
+	handler := NewCollectiblesHandler(mockRPC, nil, 10)
 
 	account := &txnbuild.SimpleAccount{AccountID: "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"}
 	ctx := context.Background()
 
 	// Should not panic — goroutines recover and return error results
-	results, err := handler.fetchMeridianPayCollectibles(ctx, account, account.AccountID, "PUBLIC")
+	results, err := handler.fetchRegisteredCollectibles(ctx, account, account.AccountID, "PUBLIC", registered("PUBLIC", types.CollectionEnumerationOwnerTokens, testCollectionA, testCollectionB))
 	require.NoError(t, err)
 	require.Len(t, results, 2)
 
@@ -199,7 +354,7 @@ func TestFetchMeridianPayCollectibles_PanicRecovery(t *testing.T) {
 	}
 }
 
-func TestFetchMeridianPayCollectibles_NonVecResponse(t *testing.T) {
+func TestFetchRegisteredCollectibles_NonVecResponse(t *testing.T) {
 	// Simulate a contract returning a non-Vec type (e.g. on a different network)
 	nonVecResult := &xdr.ScVal{
 		Type: xdr.ScValTypeScvVoid,
@@ -207,12 +362,12 @@ func TestFetchMeridianPayCollectibles_NonVecResponse(t *testing.T) {
 	mockRPC := &utils.MockRPCService{
 		SimulateResultOverride: nonVecResult,
 	}
-	handler := NewCollectiblesHandler(mockRPC, "CBIELTK6YBZJU5UP2WWQEUCYKLPU6AUNZ2BQ4WWFEIE3USCIHMXQDAMA", "", "", 10)
+	handler := NewCollectiblesHandler(mockRPC, nil, 10)
 
 	account := &txnbuild.SimpleAccount{AccountID: "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"}
 	ctx := context.Background()
 
-	results, err := handler.fetchMeridianPayCollectibles(ctx, account, account.AccountID, "TESTNET")
+	results, err := handler.fetchRegisteredCollectibles(ctx, account, account.AccountID, "TESTNET", registered("TESTNET", types.CollectionEnumerationOwnerTokens, testCollectionA))
 	require.NoError(t, err)
 	require.Len(t, results, 1)
 
@@ -222,9 +377,15 @@ func TestFetchMeridianPayCollectibles_NonVecResponse(t *testing.T) {
 	assert.Equal(t, msgOwnerTokensFetchFailed, results[0].Error.ErrorMessage)
 }
 
-func TestGetCollectibles_WithMeridianPayAddresses(t *testing.T) {
+func TestGetCollectibles_WithRegisteredCollections(t *testing.T) {
 	mockRPC := &utils.MockRPCService{}
-	handler := NewCollectiblesHandler(mockRPC, "CBIELTK6YBZJU5UP2WWQEUCYKLPU6AUNZ2BQ4WWFEIE3USCIHMXQDAMA", "CDSN4MICK7U5XOP4DE6OIZQCRMYO3UTQ5VYZV7ZA7H63OICZPBLXYRGJ", "CAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABSC4", 10)
+	registry := &utils.MockCollectionRegistry{
+		Entries: append(
+			registered("PUBLIC", types.CollectionEnumerationOwnerTokens, testCollectionA, testCollectionB, testCollectionC),
+			registered("TESTNET", types.CollectionEnumerationOwnerTokens, testCollectionA)...,
+		),
+	}
+	handler := NewCollectiblesHandler(mockRPC, registry, 10)
 
 	body := `{
 		"owner": "GB7RQNG6ROYGLFKR3IDAABKI2Y2UAQKEO6BSJVR5IYS7UYQ743O7TOXE",
@@ -250,7 +411,7 @@ func TestGetCollectibles_WithMeridianPayAddresses(t *testing.T) {
 	err = json.Unmarshal(rr.Body.Bytes(), &response)
 	require.NoError(t, err)
 
-	// Should have 3 results (Meridian Pay collections, with empty token IDs return errors)
+	// Should have 3 results (the PUBLIC registered collections, with empty token IDs return errors)
 	collections := response.Data.Collections
 	require.Len(t, collections, 3)
 
@@ -262,9 +423,34 @@ func TestGetCollectibles_WithMeridianPayAddresses(t *testing.T) {
 	}
 }
 
+func TestGetCollectibles_RegistryFailureKeepsClientContracts(t *testing.T) {
+	mockRPC := &utils.MockRPCService{}
+	handler := NewCollectiblesHandler(mockRPC, &utils.MockCollectionRegistry{Err: errors.New("registry down")}, 10)
+
+	body := `{
+		"owner": "GB7RQNG6ROYGLFKR3IDAABKI2Y2UAQKEO6BSJVR5IYS7UYQ743O7TOXE",
+		"contracts": [{"id": "CBIELTK6YBZJU5UP2WWQEUCYKLPU6AUNZ2BQ4WWFEIE3USCIHMXQDAMA", "token_ids": ["0"]}]
+	}`
+
+	req, _ := http.NewRequest("POST", "/api/v1/collectibles", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	require.NoError(t, handler.GetCollectibles(rr, req))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response struct {
+		Data GetCollectiblesPayload `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	require.Len(t, response.Data.Collections, 1)
+	require.NotNil(t, response.Data.Collections[0].Collection)
+	assert.Len(t, response.Data.Collections[0].Collection.Collectibles, 1)
+}
+
 func TestGetCollectibles_Empty(t *testing.T) {
 	mockRPC := &utils.MockRPCService{}
-	handler := NewCollectiblesHandler(mockRPC, "CBIELTK6YBZJU5UP2WWQEUCYKLPU6AUNZ2BQ4WWFEIE3USCIHMXQDAMA", "CDSN4MICK7U5XOP4DE6OIZQCRMYO3UTQ5VYZV7ZA7H63OICZPBLXYRGJ", "CAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABSC4", 10)
+	registry := &utils.MockCollectionRegistry{Entries: registered("PUBLIC", types.CollectionEnumerationOwnerTokens, testCollectionA, testCollectionB, testCollectionC)}
+	handler := NewCollectiblesHandler(mockRPC, registry, 10)
 
 	body := `{
 		"owner": "GB7RQNG6ROYGLFKR3IDAABKI2Y2UAQKEO6BSJVR5IYS7UYQ743O7TOXE",
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alitto/pond/v2"
+	"github.com/stellar/go-stellar-sdk/txnbuild"
+	"github.com/stellar/go-stellar-sdk/xdr"
+
+	"github.com/stellar/freighter-backend-v2/internal/api/httperror"
+	response "github.com/stellar/freighter-backend-v2/internal/api/httpresponse"
+	"github.com/stellar/freighter-backend-v2/internal/logger"
+	"github.com/stellar/freighter-backend-v2/internal/metrics"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils"
+	"github.com/stellar/freighter-backend-v2/internal/utils/scval"
+)
+
+const (
+	ContractCallsContextTimeout = 8 * time.Second
+
+	// maxContractFunctionLen is the ScSymbol length limit.
+	maxContractFunctionLen = 32
+
+	msgContractCallFailed = "Unable to simulate contract call."
+)
+
+// contractFunctionPattern is the ScSymbol character set.
+var contractFunctionPattern = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+type ContractCallsHandler struct {
+	RpcService types.RPCService
+	MaxCalls   int
+	rpcPool    pond.Pool
+}
+
+// NewContractCallsHandler bounds simulations across all in-flight requests to
+// maxConcurrentRPCCalls through one shared pool.
+func NewContractCallsHandler(rpc types.RPCService, maxCalls int, maxConcurrentRPCCalls int) *ContractCallsHandler {
+	return &ContractCallsHandler{
+		RpcService: rpc,
+		MaxCalls:   maxCalls,
+		rpcPool:    pond.NewPool(maxConcurrentRPCCalls),
+	}
+}
+
+type ContractCall struct {
+	ContractID string        `json:"contract_id"`
+	Function   string        `json:"function"`
+	Args       []scval.Value `json:"args"`
+}
+
+type ContractCallsRequest struct {
+	Calls []ContractCall `json:"calls"`
+	// SourceAccount is optional; set it when a call's result depends on the
+	// invoker. It defaults to a placeholder account.
+	SourceAccount string `json:"source_account"`
+}
+
+type ContractCallError struct {
+	ErrorMessage string `json:"error_message"`
+}
+
+// ContractCallResult carries either the call's return value, as a base64
+// ScVal, or an error, so one failed call does not fail the batch.
+type ContractCallResult struct {
+	ContractID string             `json:"contract_id"`
+	Function   string             `json:"function"`
+	ResultXDR  string             `json:"result_xdr,omitempty"`
+	Error      *ContractCallError `json:"error,omitempty"`
+}
+
+type ContractCallsPayload struct {
+	Results []ContractCallResult `json:"results"`
+}
+
+type preparedContractCall struct {
+	contract xdr.ScAddress
+	function xdr.ScSymbol
+	args     []xdr.ScVal
+}
+
+// validateContractCallsRequest rejects the whole batch on any malformed call:
+// a bad contract ID or argument is a client bug, not a partial failure.
+func validateContractCallsRequest(r *http.Request, maxCalls int) (*ContractCallsRequest, []preparedContractCall, *httperror.HttpError) {
+	var req ContractCallsRequest
+	if decodeErr := decodeJSONBody(r, &req); decodeErr != nil {
+		return nil, nil, decodeErr
+	}
+
+	req.SourceAccount = strings.TrimSpace(req.SourceAccount)
+	if req.SourceAccount == "" {
+		req.SourceAccount = utils.SimulationSourceAccount
+	} else if !utils.IsValidStellarPublicKey(req.SourceAccount) {
+		errStr := "invalid source_account: must be a Stellar public key (G...)"
+		return nil, nil, httperror.BadRequest(errStr, errors.New(errStr))
+	}
+
+	if len(req.Calls) == 0 {
+		errStr := "calls array cannot be empty"
+		return nil, nil, httperror.BadRequest(errStr, errors.New(errStr))
+	}
+	if len(req.Calls) > maxCalls {
+		errStr := fmt.Sprintf("too many calls: maximum is %d, got %d", maxCalls, len(req.Calls))
+		return nil, nil, httperror.BadRequest(errStr, errors.New(errStr))
+	}
+
+	prepared := make([]preparedContractCall, len(req.Calls))
+	for i, call := range req.Calls {
+		contract, err := utils.ScAddressFromContractString(strings.TrimSpace(call.ContractID))
+		if err != nil {
+			return nil, nil, httperror.BadRequest(fmt.Sprintf("calls[%d].contract_id: %v", i, err), err)
+		}
+		if len(call.Function) > maxContractFunctionLen || !contractFunctionPattern.MatchString(call.Function) {
+			errStr := fmt.Sprintf("calls[%d].function must be 1-%d characters of [a-zA-Z0-9_]", i, maxContractFunctionLen)
+			return nil, nil, httperror.BadRequest(errStr, errors.New(errStr))
+		}
+		args := make([]xdr.ScVal, len(call.Args))
+		for j, arg := range call.Args {
+			v, err := arg.ToScVal()
+			if err != nil {
+				return nil, nil, httperror.BadRequest(fmt.Sprintf("calls[%d].args[%d]: %v", i, j, err), err)
+			}
+			args[j] = v
+		}
+		prepared[i] = preparedContractCall{contract: *contract, function: xdr.ScSymbol(call.Function), args: args}
+	}
+	return &req, prepared, nil
+}
+
+// GetContractCalls handles POST /api/v1/contract-calls. Results follow request
+// order. A contract that rejects the call (a simulation error) reports the
+// host's message, which is about the caller's own call; any other failure is
+// logged and reported generically.
+func (h *ContractCallsHandler) GetContractCalls(w http.ResponseWriter, r *http.Request) error {
+	ctx, cancel := context.WithTimeout(r.Context(), ContractCallsContextTimeout)
+	defer cancel()
+
+	network := r.URL.Query().Get("network")
+	if !isValidNetwork(network) {
+		return httperror.BadRequest(fmt.Sprintf("invalid network: network must be %s, %s or %s", types.PUBLIC, types.TESTNET, types.FUTURENET), errors.New("invalid network"))
+	}
+
+	req, prepared, validationErr := validateContractCallsRequest(r, h.MaxCalls)
+	if validationErr != nil {
+		return validationErr
+	}
+	source := txnbuild.SimpleAccount{AccountID: req.SourceAccount}
+
+	var mu sync.Mutex
+	results := make([]ContractCallResult, len(prepared))
+	for i, call := range req.Calls {
+		results[i] = ContractCallResult{ContractID: call.ContractID, Function: call.Function}
+	}
+
+	group := h.rpcPool.NewGroupContext(ctx)
+	for i, call := range prepared {
+		group.Submit(func() {
+			account := source
+			res, err := h.RpcService.SimulateInvocation(ctx, call.contract, &account, call.function, call.args, txnbuild.NewTimeout(300), network)
+			var resultXDR string
+			if err == nil && res == nil {
+				err = errors.New("simulation returned no value")
+			}
+			if err == nil {
+				resultXDR, err = xdr.MarshalBase64(*res)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				results[i].Error = contractCallError(ctx, req.Calls[i], err)
+				return
+			}
+			results[i].ResultXDR = resultXDR
+		})
+	}
+	// Wait returns early if ctx ends; calls still queued or in flight then
+	// report the generic failure.
+	_ = group.Wait()
+
+	mu.Lock()
+	out := slices.Clone(results)
+	mu.Unlock()
+	for i := range out {
+		if out[i].ResultXDR == "" && out[i].Error == nil {
+			out[i].Error = &ContractCallError{ErrorMessage: msgContractCallFailed}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return response.OK(w, HttpResponse{Data: ContractCallsPayload{Results: out}})
+}
+
+func contractCallError(ctx context.Context, call ContractCall, err error) *ContractCallError {
+	var upErr *metrics.UpstreamError
+	if errors.As(err, &upErr) && upErr.Kind == "simulation_error" {
+		return &ContractCallError{ErrorMessage: upErr.Err.Error()}
+	}
+	logger.ErrorWithContext(ctx, "simulating contract call", "contract_id", call.ContractID, "function", call.Function, "error", err)
+	return &ContractCallError{ErrorMessage: msgContractCallFailed}
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/txnbuild"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/metrics"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils"
+)
+
+// contractCallsRPC fails calls to "panics" with a host error and "broken" with
+// a transport error, echoing every other call's first u32 argument.
+type contractCallsRPC struct {
+	utils.MockRPCService
+	mu      sync.Mutex
+	sources []string
+}
+
+func (c *contractCallsRPC) SimulateInvocation(ctx context.Context, contractId xdr.ScAddress, sourceAccount *txnbuild.SimpleAccount, functionName xdr.ScSymbol, params []xdr.ScVal, timeout txnbuild.TimeBounds, network string) (types.SimulateTransactionResponse, error) {
+	c.mu.Lock()
+	c.sources = append(c.sources, sourceAccount.AccountID)
+	c.mu.Unlock()
+
+	switch functionName {
+	case "panics":
+		return nil, &metrics.UpstreamError{Kind: "simulation_error", Err: errors.New("HostError: Error(Contract, #3)")}
+	case "broken":
+		return nil, errors.New("dial tcp 10.0.0.1:8000: connection refused")
+	}
+	if len(params) == 0 {
+		return &xdr.ScVal{Type: xdr.ScValTypeScvVoid}, nil
+	}
+	return &params[0], nil
+}
+
+func newContractCallsRequest(network, body string) *http.Request {
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/contract-calls?network="+network, strings.NewReader(body))
+	return req
+}
+
+func TestContractCalls_GetContractCalls_PartialFailure(t *testing.T) {
+	t.Parallel()
+
+	rpc := &contractCallsRPC{}
+	rr := httptest.NewRecorder()
+	body := `{"calls":[
+		{"contract_id":"` + testTokenContractA + `","function":"echo","args":[{"type":"u32","value":42}]},
+		{"contract_id":"` + testTokenContractA + `","function":"panics","args":[]},
+		{"contract_id":"` + testTokenContractB + `","function":"broken"}
+	]}`
+
+	require.NoError(t, NewContractCallsHandler(rpc, 10, 2).GetContractCalls(rr, newContractCallsRequest(types.TESTNET, body)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp struct {
+		Data ContractCallsPayload `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Len(t, resp.Data.Results, 3)
+
+	var echoed xdr.ScVal
+	require.NoError(t, xdr.SafeUnmarshalBase64(resp.Data.Results[0].ResultXDR, &echoed))
+	assert.EqualValues(t, 42, *echoed.U32)
+	assert.Nil(t, resp.Data.Results[0].Error)
+
+	assert.Equal(t, ContractCallResult{ContractID: testTokenContractA, Function: "panics", Error: &ContractCallError{ErrorMessage: "HostError: Error(Contract, #3)"}}, resp.Data.Results[1])
+	assert.Equal(t, ContractCallResult{ContractID: testTokenContractB, Function: "broken", Error: &ContractCallError{ErrorMessage: msgContractCallFailed}}, resp.Data.Results[2], "transport details are not returned")
+
+	for _, source := range rpc.sources {
+		assert.Equal(t, utils.SimulationSourceAccount, source)
+	}
+}
+
+func TestContractCalls_GetContractCalls_SourceAccount(t *testing.T) {
+	t.Parallel()
+
+	rpc := &contractCallsRPC{}
+	body := `{"source_account":"` + validIssuer + `","calls":[{"contract_id":"` + testTokenContractA + `","function":"echo"}]}`
+	require.NoError(t, NewContractCallsHandler(rpc, 10, 2).GetContractCalls(httptest.NewRecorder(), newContractCallsRequest(types.PUBLIC, body)))
+	assert.Equal(t, []string{validIssuer}, rpc.sources)
+}
+
+func TestContractCalls_GetContractCalls_BadRequests(t *testing.T) {
+	t.Parallel()
+
+	call := `{"contract_id":"` + testTokenContractA + `","function":"echo"}`
+	for name, tc := range map[string]struct{ network, body string }{
+		"invalid network":  {"MAINNET", `{"calls":[` + call + `]}`},
+		"malformed body":   {types.PUBLIC, `{"calls":`},
+		"no calls":         {types.PUBLIC, `{"calls":[]}`},
+		"too many calls":   {types.PUBLIC, `{"calls":[` + call + `,` + call + `,` + call + `]}`},
+		"bad contract":     {types.PUBLIC, `{"calls":[{"contract_id":"` + validIssuer + `","function":"echo"}]}`},
+		"empty function":   {types.PUBLIC, `{"calls":[{"contract_id":"` + testTokenContractA + `","function":""}]}`},
+		"function charset": {types.PUBLIC, `{"calls":[{"contract_id":"` + testTokenContractA + `","function":"get balance"}]}`},
+		"bad argument":     {types.PUBLIC, `{"calls":[{"contract_id":"` + testTokenContractA + `","function":"echo","args":[{"type":"u32","value":-1}]}]}`},
+		"bad source":       {types.PUBLIC, `{"source_account":"` + testTokenContractA + `","calls":[` + call + `]}`},
+		"unknown arg type": {types.PUBLIC, `{"calls":[{"contract_id":"` + testTokenContractA + `","function":"echo","args":[{"type":"f64","value":1}]}]}`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := NewContractCallsHandler(&contractCallsRPC{}, 2, 2).GetContractCalls(httptest.NewRecorder(), newContractCallsRequest(tc.network, tc.body))
+			require.Error(t, err)
+			assert.Equal(t, http.StatusBadRequest, unwrapHttpStatus(t, err))
+		})
+	}
+}
@@ -1,47 +1,64 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
-	"time"
-)
 
-const (
-	FeatureFlagsContextTimeout = 5 * time.Second
+	"github.com/stellar/freighter-backend-v2/internal/api/httperror"
+	"github.com/stellar/freighter-backend-v2/internal/auth"
+	"github.com/stellar/freighter-backend-v2/internal/types"
 )
 
-type FeatureFlagsHandler struct{}
+type FeatureFlagsHandler struct {
+	flags types.FeatureFlagsService
+}
 
+// FeatureFlagsResponse carries every flag in Flags. The swap, discover and
+// onramp flags are also returned as top-level fields for clients that
+// predate the map.
 type FeatureFlagsResponse struct {
-	SwapEnabled     bool `json:"swap_enabled"`
-	DiscoverEnabled bool `json:"discover_enabled"`
-	OnrampEnabled   bool `json:"onramp_enabled"`
+	SwapEnabled     bool            `json:"swap_enabled"`
+	DiscoverEnabled bool            `json:"discover_enabled"`
+	OnrampEnabled   bool            `json:"onramp_enabled"`
+	Flags           map[string]bool `json:"flags"`
 }
 
-func NewFeatureFlagsHandler() *FeatureFlagsHandler {
-	return &FeatureFlagsHandler{}
+func NewFeatureFlagsHandler(flags types.FeatureFlagsService) *FeatureFlagsHandler {
+	return &FeatureFlagsHandler{flags: flags}
 }
 
+// GetFeatureFlags evaluates every flag for the caller, described by the
+// platform, version and network query params and the authenticated user ID
+// (which percentage rollouts are keyed on).
 func (h *FeatureFlagsHandler) GetFeatureFlags(w http.ResponseWriter, r *http.Request) error {
-	_, cancel := context.WithTimeout(r.Context(), FeatureFlagsContextTimeout)
-	defer cancel()
-
-	platform := r.URL.Query().Get("platform")
-	version := r.URL.Query().Get("version")
-
-	resp := FeatureFlagsResponse{
-		SwapEnabled:     true,
-		DiscoverEnabled: true,
-		OnrampEnabled:   true,
+	query := r.URL.Query()
+	fc := types.FlagContext{
+		Platform: query.Get("platform"),
+		Version:  query.Get("version"),
+		Network:  query.Get("network"),
 	}
+	if fc.Network != "" && !isValidNetwork(fc.Network) {
+		return httperror.BadRequest(fmt.Sprintf("invalid network: network must be %s, %s or %s", types.PUBLIC, types.TESTNET, types.FUTURENET), errors.New("invalid network"))
+	}
+	fc.UserID, _ = auth.UserIDFromContext(r.Context())
 
-	if platform == "ios" && version == "1.6.23" {
-		resp.SwapEnabled = false
-		resp.DiscoverEnabled = false
-		resp.OnrampEnabled = false
+	flags := h.flags.Evaluate(r.Context(), fc)
+	resp := FeatureFlagsResponse{
+		SwapEnabled:     legacyFlag(flags, types.FeatureFlagSwap),
+		DiscoverEnabled: legacyFlag(flags, types.FeatureFlagDiscover),
+		OnrampEnabled:   legacyFlag(flags, types.FeatureFlagOnramp),
+		Flags:           flags,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	return json.NewEncoder(w).Encode(resp)
 }
+
+// legacyFlag reads one of the top-level flags, which stay on when no flag by
+// that key is configured, as they were before flags were configurable.
+func legacyFlag(flags map[string]bool, key string) bool {
+	enabled, ok := flags[key]
+	return enabled || !ok
+}
@@ -8,10 +8,16 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/api/httperror"
+	"github.com/stellar/freighter-backend-v2/internal/auth"
+	"github.com/stellar/freighter-backend-v2/internal/services"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils"
 )
 
 func TestFeatureFlagsHandler(t *testing.T) {
-	handler := NewFeatureFlagsHandler()
+	handler := NewFeatureFlagsHandler(services.NewFeatureFlagsService(nil))
 
 	tests := []struct {
 		name             string
@@ -78,7 +84,52 @@ func TestFeatureFlagsHandler(t *testing.T) {
 			err = json.NewDecoder(rr.Body).Decode(&resp)
 			require.NoError(t, err)
 
-			assert.Equal(t, tc.expectedResponse, resp)
+			assert.Equal(t, tc.expectedResponse.SwapEnabled, resp.SwapEnabled)
+			assert.Equal(t, tc.expectedResponse.DiscoverEnabled, resp.DiscoverEnabled)
+			assert.Equal(t, tc.expectedResponse.OnrampEnabled, resp.OnrampEnabled)
+			assert.Equal(t, map[string]bool{
+				types.FeatureFlagSwap:     resp.SwapEnabled,
+				types.FeatureFlagDiscover: resp.DiscoverEnabled,
+				types.FeatureFlagOnramp:   resp.OnrampEnabled,
+			}, resp.Flags)
 		})
 	}
 }
+
+func TestFeatureFlagsHandler_EvaluatesConfiguredFlags(t *testing.T) {
+	full := 100
+	handler := NewFeatureFlagsHandler(services.NewFeatureFlagsService(&utils.MockFeatureFlagSource{Flags: []types.FeatureFlag{
+		{Key: types.FeatureFlagSwap, Enabled: true, Rules: []types.FlagRule{{Networks: []string{types.FUTURENET}, Enabled: false}}},
+		{Key: "new_home", Rules: []types.FlagRule{{Percentage: &full, Enabled: true}}},
+	}}))
+
+	get := func(t *testing.T, url string, userID string) (int, FeatureFlagsResponse) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		if userID != "" {
+			req = req.WithContext(auth.ContextWithUserID(req.Context(), userID))
+		}
+		rr := httptest.NewRecorder()
+		if err := handler.GetFeatureFlags(rr, req); err != nil {
+			var httpErr *httperror.HttpError
+			require.ErrorAs(t, err, &httpErr)
+			return httpErr.HttpStatus(), FeatureFlagsResponse{}
+		}
+		var resp FeatureFlagsResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+		return rr.Code, resp
+	}
+
+	code, resp := get(t, "/feature-flags?network=PUBLIC", "deadbeef")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, map[string]bool{types.FeatureFlagSwap: true, "new_home": true}, resp.Flags)
+	assert.True(t, resp.SwapEnabled)
+	assert.True(t, resp.DiscoverEnabled, "an unconfigured legacy flag stays on")
+
+	_, resp = get(t, "/feature-flags?network=FUTURENET", "")
+	assert.False(t, resp.SwapEnabled)
+	assert.False(t, resp.Flags[types.FeatureFlagSwap])
+
+	code, _ = get(t, "/feature-flags?network=MAINNET", "")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
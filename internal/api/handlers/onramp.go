@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/stellar/freighter-backend-v2/internal/api/httperror"
+	response "github.com/stellar/freighter-backend-v2/internal/api/httpresponse"
+	"github.com/stellar/freighter-backend-v2/internal/logger"
+	"github.com/stellar/freighter-backend-v2/internal/metrics"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils"
+)
+
+const (
+	OnrampContextTimeout = 10 * time.Second
+	// MaxOnrampAssets caps the asset list of one session token request.
+	MaxOnrampAssets = 10
+	maxAssetCodeLen = 12
+)
+
+type OnrampHandler struct {
+	CoinbaseService types.CoinbaseService
+}
+
+func NewOnrampHandler(coinbaseService types.CoinbaseService) *OnrampHandler {
+	return &OnrampHandler{CoinbaseService: coinbaseService}
+}
+
+type OnrampTokenRequest struct {
+	Address string   `json:"address"`
+	Assets  []string `json:"assets"`
+}
+
+func validateOnrampTokenRequest(r *http.Request) (*types.OnrampTokenRequest, *httperror.HttpError) {
+	var req OnrampTokenRequest
+	if decodeErr := decodeJSONBody(r, &req); decodeErr != nil {
+		return nil, decodeErr
+	}
+
+	address := strings.TrimSpace(req.Address)
+	if !utils.IsValidStellarPublicKey(address) {
+		errStr := "invalid address: must be a Stellar public key (G...)"
+		return nil, httperror.BadRequest(errStr, errors.New(errStr))
+	}
+
+	if len(req.Assets) == 0 {
+		errStr := "assets array cannot be empty"
+		return nil, httperror.BadRequest(errStr, errors.New(errStr))
+	}
+	if len(req.Assets) > MaxOnrampAssets {
+		errStr := fmt.Sprintf("too many assets: maximum is %d, got %d", MaxOnrampAssets, len(req.Assets))
+		return nil, httperror.BadRequest(errStr, errors.New(errStr))
+	}
+	assets := make([]string, 0, len(req.Assets))
+	seen := make(map[string]struct{}, len(req.Assets))
+	for _, a := range req.Assets {
+		code := strings.ToUpper(strings.TrimSpace(a))
+		if !isValidOnrampAssetCode(code) {
+			errStr := fmt.Sprintf("invalid asset %q: must be a 1-12 character alphanumeric asset code", a)
+			return nil, httperror.BadRequest(errStr, errors.New(errStr))
+		}
+		if _, dup := seen[code]; !dup {
+			seen[code] = struct{}{}
+			assets = append(assets, code)
+		}
+	}
+
+	return &types.OnrampTokenRequest{Address: address, Assets: assets}, nil
+}
+
+func isValidOnrampAssetCode(code string) bool {
+	if len(code) == 0 || len(code) > maxAssetCodeLen {
+		return false
+	}
+	for _, r := range code {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// GetSessionToken handles POST /api/v1/onramp/token.
+func (h *OnrampHandler) GetSessionToken(w http.ResponseWriter, r *http.Request) error {
+	ctx, cancel := context.WithTimeout(r.Context(), OnrampContextTimeout)
+	defer cancel()
+
+	req, validationErr := validateOnrampTokenRequest(r)
+	if validationErr != nil {
+		return validationErr
+	}
+
+	token, err := h.CoinbaseService.GetOnrampSessionToken(ctx, *req)
+	if err != nil {
+		logger.ErrorWithContext(r.Context(), "coinbase onramp token request failed", "error", err)
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			return httperror.GatewayTimeout("Failed to get onramp session token", err)
+		case errors.Is(err, context.Canceled):
+			return httperror.ServiceUnavailable("Failed to get onramp session token", err)
+		}
+		var upErr *metrics.UpstreamError
+		if errors.As(err, &upErr) {
+			return httperror.BadGateway("Failed to get onramp session token", err)
+		}
+		return httperror.InternalServerError("Failed to get onramp session token", err)
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "application/json")
+	return response.OK(w, HttpResponse{Data: token})
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/metrics"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils"
+)
+
+func newOnrampRequest(body string) *http.Request {
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/onramp/token", strings.NewReader(body))
+	return req
+}
+
+func TestOnramp_GetSessionToken_Success(t *testing.T) {
+	t.Parallel()
+
+	mock := &utils.MockCoinbaseService{Token: &types.OnrampSessionToken{Token: "tok", ChannelID: "chan"}}
+	rr := httptest.NewRecorder()
+
+	require.NoError(t, NewOnrampHandler(mock).GetSessionToken(rr, newOnrampRequest(`{"address":"`+validIssuer+`","assets":["xlm","USDC","XLM"]}`)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+	assert.JSONEq(t, `{"data":{"token":"tok","channel_id":"chan"}}`, rr.Body.String())
+
+	require.NotNil(t, mock.LastRequest)
+	assert.Equal(t, validIssuer, mock.LastRequest.Address)
+	assert.Equal(t, []string{"XLM", "USDC"}, mock.LastRequest.Assets, "asset codes are upper-cased and deduped")
+}
+
+func TestOnramp_GetSessionToken_BadRequests(t *testing.T) {
+	t.Parallel()
+
+	tooMany := `"A","B","C","D","E","F","G","H","I","J","K"`
+	for name, body := range map[string]string{
+		"malformed body":   `{"address":`,
+		"contract address": `{"address":"CAS3J7GYLGXMF6TDJBBYYSE3HQ6BBSMLNUQ34T6TZMYMW2EVH34XOWMA","assets":["XLM"]}`,
+		"invalid address":  `{"address":"GABC","assets":["XLM"]}`,
+		"no assets":        `{"address":"` + validIssuer + `","assets":[]}`,
+		"bad asset code":   `{"address":"` + validIssuer + `","assets":["US-DC"]}`,
+		"too many assets":  `{"address":"` + validIssuer + `","assets":[` + tooMany + `]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := NewOnrampHandler(&utils.MockCoinbaseService{}).GetSessionToken(httptest.NewRecorder(), newOnrampRequest(body))
+			require.Error(t, err)
+			assert.Equal(t, http.StatusBadRequest, unwrapHttpStatus(t, err))
+		})
+	}
+}
+
+func TestOnramp_GetSessionToken_ServiceErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"upstream", &metrics.UpstreamError{Kind: "http_error", Code: http.StatusUnauthorized, Err: errors.New("status 401")}, http.StatusBadGateway},
+		{"deadline", context.DeadlineExceeded, http.StatusGatewayTimeout},
+		{"other", errors.New("signing failed"), http.StatusInternalServerError},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := NewOnrampHandler(&utils.MockCoinbaseService{Error: tc.err}).GetSessionToken(httptest.NewRecorder(), newOnrampRequest(`{"address":"`+validIssuer+`","assets":["XLM"]}`))
+			require.Error(t, err)
+			assert.Equal(t, tc.want, unwrapHttpStatus(t, err))
+		})
+	}
+}
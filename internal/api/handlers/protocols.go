@@ -1,24 +1,25 @@
 package handlers
 
 import (
-	"encoding/json"
+	"cmp"
+	"errors"
 	"fmt"
+	"maps"
 	"net/http"
-	"os"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/stellar/freighter-backend-v2/internal/api/httperror"
 	response "github.com/stellar/freighter-backend-v2/internal/api/httpresponse"
 	"github.com/stellar/freighter-backend-v2/internal/logger"
+	"github.com/stellar/freighter-backend-v2/internal/types"
 )
 
 var (
-	ErrFailedToReadProtocolsConfig = httperror.ErrorMessage{
-		LogMessage:    "failed to read protocols config from %s: %v",
-		ClientMessage: "An error occurred while fetching protocol configurations.",
-	}
-	ErrFailedToUnmarshalProtocolsConfig = httperror.ErrorMessage{
-		LogMessage:    "failed to unmarshal protocols config: %v. Data (first %d bytes): %s",
-		ClientMessage: "An error occurred while processing protocol configurations.",
+	ErrProtocolsCatalogUnavailable = httperror.ErrorMessage{
+		LogMessage:    "failed to read protocols catalog: %v",
+		ClientMessage: "Protocol configurations are temporarily unavailable.",
 	}
 	ErrFailedToEncodeProtocolsToJSONResponse = httperror.ErrorMessage{
 		LogMessage:    "failed to encode protocols to JSON response: %v",
@@ -26,59 +27,55 @@ var (
 	}
 )
 
-type Protocol struct {
-	Name                        string   `json:"name"`
-	Tags                        []string `json:"tags"`
-	URL                         string   `json:"website_url"`
-	IconURL                     string   `json:"icon_url"`
-	BackgroundURL               string   `json:"background_url,omitempty"`
-	Description                 string   `json:"description"`
-	IsBlacklisted               bool     `json:"is_blacklisted"`
-	IsTrending                  *bool    `json:"is_trending,omitempty"`
-	IsWalletConnectNotSupported bool     `json:"is_wc_not_supported"`
-}
-
 // ProtocolsPayload encapsulates the list of protocols under a specific key.
 // This is used to structure the response under a "data" field.
 type GetProtocolsPayload struct {
-	Protocols []Protocol `json:"protocols"`
+	Protocols []types.Protocol `json:"protocols"`
 }
 
 // ProtocolHandler holds dependencies for protocol-related handlers.
 type ProtocolsHandler struct {
-	protocolsConfigPath string
+	catalog types.ProtocolCatalog
 }
 
 // NewProtocolHandler creates a new ProtocolHandler instance.
-func NewProtocolsHandler(protocolsConfigPath string) *ProtocolsHandler {
+func NewProtocolsHandler(catalog types.ProtocolCatalog) *ProtocolsHandler {
 	return &ProtocolsHandler{
-		protocolsConfigPath: protocolsConfigPath,
+		catalog: catalog,
 	}
 }
 
 // GetProtocols handles requests to fetch the list of supported protocols.
-// It reads the protocol information based on the configured path.
+// The catalog is held in memory, so the request does no I/O. Query params
+// narrow the list:
+//
+//	tag=<tag>                  protocols with any of the given tags (repeatable)
+//	trending=true              trending protocols only
+//	exclude_blacklisted=true   drop blacklisted protocols
+//	network=<network>          protocols available on network
+//
+// Names and descriptions are localized from Accept-Language. The response
+// carries an ETag; a matching If-None-Match gets a bodiless 304.
 func (h *ProtocolsHandler) GetProtocols(w http.ResponseWriter, r *http.Request) error {
 	ctx := r.Context()
 
-	data, err := os.ReadFile(h.protocolsConfigPath)
-	if err != nil {
-		logger.ErrorWithContext(ctx, fmt.Sprintf(ErrFailedToReadProtocolsConfig.LogMessage, h.protocolsConfigPath, err))
-		if os.IsNotExist(err) {
-			return httperror.NotFound(ErrFailedToReadProtocolsConfig.ClientMessage, err)
-		}
-		return httperror.InternalServerError(ErrFailedToReadProtocolsConfig.ClientMessage, err)
+	filter, httpErr := parseProtocolsFilter(r)
+	if httpErr != nil {
+		return httpErr
 	}
 
-	var protocols []Protocol
-	err = json.Unmarshal(data, &protocols)
+	catalog, err := h.catalog.Protocols(ctx)
 	if err != nil {
-		snippetLength := 100
-		if len(data) < snippetLength {
-			snippetLength = len(data)
+		logger.ErrorWithContext(ctx, fmt.Sprintf(ErrProtocolsCatalogUnavailable.LogMessage, err))
+		return httperror.ServiceUnavailable(ErrProtocolsCatalogUnavailable.ClientMessage, err)
+	}
+
+	languages := parseAcceptLanguage(r.Header.Get("Accept-Language"))
+	protocols := make([]types.Protocol, 0, len(catalog))
+	for _, p := range catalog {
+		if filter.matches(p) {
+			protocols = append(protocols, localizeProtocol(p, languages))
 		}
-		logger.ErrorWithContext(ctx, fmt.Sprintf(ErrFailedToUnmarshalProtocolsConfig.LogMessage, err, snippetLength, string(data[:snippetLength])))
-		return httperror.InternalServerError(ErrFailedToUnmarshalProtocolsConfig.ClientMessage, err)
 	}
 
 	responseData := HttpResponse{
@@ -87,9 +84,146 @@ func (h *ProtocolsHandler) GetProtocols(w http.ResponseWriter, r *http.Request)
 		},
 	}
 
-	if err := response.OK(w, responseData); err != nil {
+	w.Header().Set("Vary", "Accept-Language")
+	if err := response.OKWithETag(w, r, responseData); err != nil {
 		logger.ErrorWithContext(ctx, fmt.Sprintf(ErrFailedToEncodeProtocolsToJSONResponse.LogMessage, err))
 		return httperror.InternalServerError(ErrFailedToEncodeProtocolsToJSONResponse.ClientMessage, err)
 	}
 	return nil
 }
+
+// protocolsFilter is the parsed query of GET /api/v1/protocols. Its zero
+// value matches every protocol.
+type protocolsFilter struct {
+	tags               []string
+	trendingOnly       bool
+	excludeBlacklisted bool
+	network            string
+}
+
+func parseProtocolsFilter(r *http.Request) (protocolsFilter, *httperror.HttpError) {
+	query := r.URL.Query()
+	f := protocolsFilter{tags: query["tag"]}
+
+	for _, param := range []struct {
+		name string
+		dest *bool
+	}{{"trending", &f.trendingOnly}, {"exclude_blacklisted", &f.excludeBlacklisted}} {
+		if s := query.Get(param.name); s != "" {
+			v, err := strconv.ParseBool(s)
+			if err != nil {
+				return protocolsFilter{}, httperror.BadRequest(fmt.Sprintf("invalid %s %q: must be true or false", param.name, s), err)
+			}
+			*param.dest = v
+		}
+	}
+
+	if network := query.Get("network"); network != "" {
+		if !isValidNetwork(network) {
+			return protocolsFilter{}, httperror.BadRequest(fmt.Sprintf("invalid network: network must be %s, %s or %s", types.PUBLIC, types.TESTNET, types.FUTURENET), errors.New("invalid network"))
+		}
+		f.network = network
+	}
+	return f, nil
+}
+
+func (f protocolsFilter) matches(p types.Protocol) bool {
+	if f.trendingOnly && (p.IsTrending == nil || !*p.IsTrending) {
+		return false
+	}
+	if f.excludeBlacklisted && p.IsBlacklisted {
+		return false
+	}
+	// A protocol that lists no networks is available on all of them.
+	if f.network != "" && len(p.Networks) > 0 && !slices.Contains(p.Networks, f.network) {
+		return false
+	}
+	if len(f.tags) > 0 && !slices.ContainsFunc(p.Tags, func(tag string) bool {
+		return slices.ContainsFunc(f.tags, func(want string) bool { return strings.EqualFold(tag, want) })
+	}) {
+		return false
+	}
+	return true
+}
+
+// parseAcceptLanguage returns the language tags of an Accept-Language header,
+// most preferred first, dropping "*" and anything with q=0.
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var ranges []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			ranges = append(ranges, weighted{tag: tag, q: q})
+		}
+	}
+	slices.SortStableFunc(ranges, func(a, b weighted) int { return cmp.Compare(b.q, a.q) })
+
+	tags := make([]string, len(ranges))
+	for i, r := range ranges {
+		tags[i] = r.tag
+	}
+	return tags
+}
+
+// localizeProtocol returns p with its name and description taken from the
+// translation best matching languages, and without the translations map.
+// Each language matches a translation with the same tag, then one for its
+// primary language ("es-AR" falls back to "es"). Tags compare exactly first
+// and case-insensitively only when no tag matches exactly.
+func localizeProtocol(p types.Protocol, languages []string) types.Protocol {
+	translations := p.Translations
+	p.Translations = nil
+	if len(translations) == 0 {
+		return p
+	}
+	for _, language := range languages {
+		candidates := []string{language}
+		if base, _, found := strings.Cut(language, "-"); found {
+			candidates = append(candidates, base)
+		}
+		for _, candidate := range candidates {
+			translation, ok := findTranslation(translations, candidate)
+			if !ok {
+				continue
+			}
+			if translation.Name != "" {
+				p.Name = translation.Name
+			}
+			if translation.Description != "" {
+				p.Description = translation.Description
+			}
+			return p
+		}
+	}
+	return p
+}
+
+// findTranslation looks tag up exactly, then case-insensitively in sorted
+// key order, so keys differing only in case resolve the same way every time.
+func findTranslation(translations map[string]types.ProtocolTranslation, tag string) (types.ProtocolTranslation, bool) {
+	if translation, ok := translations[tag]; ok {
+		return translation, true
+	}
+	for _, key := range slices.Sorted(maps.Keys(translations)) {
+		if strings.EqualFold(key, tag) {
+			return translations[key], true
+		}
+	}
+	return types.ProtocolTranslation{}, false
+}
@@ -4,18 +4,31 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/stellar/freighter-backend-v2/internal/api/httperror"
+	"github.com/stellar/freighter-backend-v2/internal/types"
 	"github.com/stellar/freighter-backend-v2/internal/utils"
 )
 
+// testdataCatalog serves testdata/protocols.json as a loaded catalog.
+func testdataCatalog(t *testing.T) *utils.MockProtocolCatalog {
+	t.Helper()
+	data, err := os.ReadFile("testdata/protocols.json")
+	require.NoError(t, err)
+	catalog := &utils.MockProtocolCatalog{}
+	require.NoError(t, json.Unmarshal(data, &catalog.Entries))
+	return catalog
+}
+
 func TestGetProtocols(t *testing.T) {
 	t.Run("should return protocols", func(t *testing.T) {
 		t.Parallel()
-		handler := NewProtocolsHandler("testdata/protocols.json")
+		handler := NewProtocolsHandler(testdataCatalog(t))
 		req, _ := http.NewRequest("GET", "/api/v1/protocols", nil)
 		rr := httptest.NewRecorder()
 		err := handler.GetProtocols(rr, req)
@@ -62,31 +75,178 @@ func TestGetProtocols(t *testing.T) {
 		_, hasTrending := raw.Data.Protocols[2]["is_trending"]
 		assert.False(t, hasTrending, "is_trending key should be absent for protocols without a trending flag")
 	})
-	t.Run("should return error if protocols file is not found", func(t *testing.T) {
-		t.Parallel()
-		handler := NewProtocolsHandler("testdata/non_existent_file.json")
-		req, _ := http.NewRequest("GET", "/api/v1/protocols", nil)
-		rr := httptest.NewRecorder()
-		err := handler.GetProtocols(rr, req)
-		require.Error(t, err)
-		assert.Equal(t, ErrFailedToReadProtocolsConfig.ClientMessage, err.Error())
-	})
-	t.Run("should return error if protocols file is invalid", func(t *testing.T) {
-		t.Parallel()
-		handler := NewProtocolsHandler("testdata/invalid_protocols.json")
-		req, _ := http.NewRequest("GET", "/api/v1/protocols", nil)
-		rr := httptest.NewRecorder()
-		err := handler.GetProtocols(rr, req)
-		require.Error(t, err)
-		assert.Equal(t, ErrFailedToUnmarshalProtocolsConfig.ClientMessage, err.Error())
-	})
 	t.Run("should return error on encoding failure", func(t *testing.T) {
 		t.Parallel()
-		handler := NewProtocolsHandler("testdata/protocols.json") // Use valid data file
+		handler := NewProtocolsHandler(testdataCatalog(t))
 		req, _ := http.NewRequest("GET", "/api/v1/protocols", nil)
 		w := utils.NewErrorResponseWriter(true)
 		err := handler.GetProtocols(w, req)
 		require.Error(t, err)
 		assert.Equal(t, ErrFailedToEncodeProtocolsToJSONResponse.ClientMessage, err.Error())
 	})
+	t.Run("should return 503 while the catalog is not loaded", func(t *testing.T) {
+		t.Parallel()
+		handler := NewProtocolsHandler(&utils.MockProtocolCatalog{Err: types.ErrProtocolsNotLoaded})
+		req, _ := http.NewRequest("GET", "/api/v1/protocols", nil)
+		err := handler.GetProtocols(httptest.NewRecorder(), req)
+		require.Error(t, err)
+		var httpErr *httperror.HttpError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusServiceUnavailable, httpErr.HttpStatus())
+		assert.Equal(t, ErrProtocolsCatalogUnavailable.ClientMessage, err.Error())
+	})
+}
+
+// getProtocolNames runs GET /api/v1/protocols<query> and returns the names
+// served, in order.
+func getProtocolNames(t *testing.T, handler *ProtocolsHandler, query string, header http.Header) []string {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/protocols"+query, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rr := httptest.NewRecorder()
+	require.NoError(t, handler.GetProtocols(rr, req))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var response struct {
+		Data GetProtocolsPayload `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	names := make([]string, 0, len(response.Data.Protocols))
+	for _, p := range response.Data.Protocols {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+func TestGetProtocols_Filters(t *testing.T) {
+	t.Parallel()
+
+	catalog := testdataCatalog(t)
+	// Blend is on PUBLIC only; the others list no networks, so are on all.
+	catalog.Entries[0].Networks = []string{types.PUBLIC}
+	handler := NewProtocolsHandler(catalog)
+
+	for _, tc := range []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"Blend", "Phoenix", "Allbridge Core"}},
+		{"?tag=DEX", []string{"Phoenix"}},
+		{"?tag=lending&tag=bridge", []string{"Blend", "Allbridge Core"}},
+		{"?trending=true", []string{"Blend"}},
+		{"?exclude_blacklisted=true", []string{"Blend", "Allbridge Core"}},
+		{"?network=TESTNET", []string{"Phoenix", "Allbridge Core"}},
+		{"?network=PUBLIC&exclude_blacklisted=1&tag=Bridge", []string{"Allbridge Core"}},
+		{"?tag=NFT", []string{}},
+	} {
+		assert.Equal(t, tc.want, getProtocolNames(t, handler, tc.query, nil), tc.query)
+	}
+
+	for _, query := range []string{"?trending=yes", "?exclude_blacklisted=maybe", "?network=MAINNET"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/protocols"+query, nil)
+		err := handler.GetProtocols(httptest.NewRecorder(), req)
+		var httpErr *httperror.HttpError
+		require.ErrorAs(t, err, &httpErr, query)
+		assert.Equal(t, http.StatusBadRequest, httpErr.HttpStatus(), query)
+	}
+}
+
+func TestGetProtocols_LocalizesFromAcceptLanguage(t *testing.T) {
+	t.Parallel()
+
+	handler := NewProtocolsHandler(&utils.MockProtocolCatalog{Entries: []types.Protocol{{
+		Name:        "Blend",
+		Description: "Lending markets.",
+		Translations: map[string]types.ProtocolTranslation{
+			"es":    {Description: "Mercados de préstamos."},
+			"pt-BR": {Name: "Blend BR", Description: "Mercados de empréstimos."},
+		},
+	}}})
+
+	get := func(acceptLanguage string) map[string]any {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/protocols", nil)
+		if acceptLanguage != "" {
+			req.Header.Set("Accept-Language", acceptLanguage)
+		}
+		rr := httptest.NewRecorder()
+		require.NoError(t, handler.GetProtocols(rr, req))
+		assert.Equal(t, "Accept-Language", rr.Header().Get("Vary"))
+		var raw struct {
+			Data struct {
+				Protocols []map[string]any `json:"protocols"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &raw))
+		require.Len(t, raw.Data.Protocols, 1)
+		_, hasTranslations := raw.Data.Protocols[0]["translations"]
+		assert.False(t, hasTranslations, "the translations map is never returned")
+		return raw.Data.Protocols[0]
+	}
+
+	for _, tc := range []struct {
+		acceptLanguage, name, description string
+	}{
+		{"", "Blend", "Lending markets."},
+		{"fr", "Blend", "Lending markets."},
+		{"es-AR,en;q=0.5", "Blend", "Mercados de préstamos."},
+		{"en;q=0.2, pt-br;q=0.9, es;q=0.8", "Blend BR", "Mercados de empréstimos."},
+		{"es;q=0, pt-BR;q=0.1", "Blend BR", "Mercados de empréstimos."},
+	} {
+		p := get(tc.acceptLanguage)
+		assert.Equal(t, tc.name, p["name"], tc.acceptLanguage)
+		assert.Equal(t, tc.description, p["description"], tc.acceptLanguage)
+	}
+}
+
+func TestLocalizeProtocol_PrefersExactTag(t *testing.T) {
+	t.Parallel()
+
+	p := types.Protocol{
+		Name: "Blend",
+		Translations: map[string]types.ProtocolTranslation{
+			"pt-br": {Name: "lower"},
+			"pt-BR": {Name: "exact"},
+			"PT-BR": {Name: "upper"},
+		},
+	}
+	for range 20 {
+		assert.Equal(t, "exact", localizeProtocol(p, []string{"pt-BR"}).Name)
+		assert.Equal(t, "upper", localizeProtocol(p, []string{"Pt-Br"}).Name, "case-insensitive matches take the first key in sorted order")
+	}
+}
+
+func TestGetProtocols_ETag(t *testing.T) {
+	t.Parallel()
+
+	catalog := testdataCatalog(t)
+	handler := NewProtocolsHandler(catalog)
+
+	rr := httptest.NewRecorder()
+	require.NoError(t, handler.GetProtocols(rr, httptest.NewRequest(http.MethodGet, "/api/v1/protocols", nil)))
+	etag := rr.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/protocols", nil)
+	req.Header.Set("If-None-Match", etag)
+	rr = httptest.NewRecorder()
+	require.NoError(t, handler.GetProtocols(rr, req))
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Empty(t, rr.Body.Bytes())
+
+	// Filters change the body, and so the ETag.
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/protocols?tag=DEX", nil)
+	req.Header.Set("If-None-Match", etag)
+	rr = httptest.NewRecorder()
+	require.NoError(t, handler.GetProtocols(rr, req))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// So does a catalog change.
+	catalog.Entries = catalog.Entries[:2]
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/protocols", nil)
+	req.Header.Set("If-None-Match", etag)
+	rr = httptest.NewRecorder()
+	require.NoError(t, handler.GetProtocols(rr, req))
+	assert.Equal(t, http.StatusOK, rr.Code)
 }
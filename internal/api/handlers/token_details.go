@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/stellar/freighter-backend-v2/internal/api/httperror"
+	response "github.com/stellar/freighter-backend-v2/internal/api/httpresponse"
+	"github.com/stellar/freighter-backend-v2/internal/logger"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils"
+)
+
+const (
+	TokenDetailsContextTimeout = 8 * time.Second
+
+	msgTokenDetailsFetchFailed = "Unable to fetch token details."
+)
+
+type TokenDetailsHandler struct {
+	TokenDetailsService types.TokenDetailsService
+	MaxContracts        int
+	maxConcurrent       int
+}
+
+// NewTokenDetailsHandler bounds each request's fan-out to maxConcurrent
+// tokens in flight; each token issues up to four simulations in parallel.
+func NewTokenDetailsHandler(svc types.TokenDetailsService, maxContracts int, maxConcurrent int) *TokenDetailsHandler {
+	return &TokenDetailsHandler{TokenDetailsService: svc, MaxContracts: maxContracts, maxConcurrent: maxConcurrent}
+}
+
+type TokenDetailsRequest struct {
+	ContractIDs []string `json:"contract_ids"`
+	// Owner is optional; when set, each token's balance for it is included.
+	Owner string `json:"owner"`
+}
+
+// TokenDetailsResult carries either the token or an error, so one bad
+// contract does not fail the batch.
+type TokenDetailsResult struct {
+	ContractID string              `json:"contract_id"`
+	Token      *types.TokenDetails `json:"token,omitempty"`
+	Error      string              `json:"error,omitempty"`
+}
+
+type TokenDetailsPayload struct {
+	Tokens []TokenDetailsResult `json:"tokens"`
+}
+
+func validateTokenDetailsRequest(r *http.Request, maxContracts int) (*TokenDetailsRequest, *httperror.HttpError) {
+	var req TokenDetailsRequest
+	if decodeErr := decodeJSONBody(r, &req); decodeErr != nil {
+		return nil, decodeErr
+	}
+
+	req.Owner = strings.TrimSpace(req.Owner)
+	if req.Owner != "" && !utils.IsValidAccount(req.Owner) {
+		errStr := "invalid owner: must be a Stellar account (G...) or contract (C...) address"
+		return nil, httperror.BadRequest(errStr, errors.New(errStr))
+	}
+
+	if len(req.ContractIDs) == 0 {
+		errStr := "contract_ids array cannot be empty"
+		return nil, httperror.BadRequest(errStr, errors.New(errStr))
+	}
+	contractIDs := utils.DedupePreserveOrder(req.ContractIDs)
+	if len(contractIDs) > maxContracts {
+		errStr := fmt.Sprintf("too many contract_ids: maximum is %d, got %d unique", maxContracts, len(contractIDs))
+		return nil, httperror.BadRequest(errStr, errors.New(errStr))
+	}
+	for _, id := range contractIDs {
+		if !utils.IsValidContractID(id) {
+			errStr := fmt.Sprintf("invalid contract ID: %s", id)
+			return nil, httperror.BadRequest(errStr, errors.New(errStr))
+		}
+	}
+	req.ContractIDs = contractIDs
+	return &req, nil
+}
+
+// GetTokenDetails handles POST /api/v1/token-details. Results follow the
+// order of the (deduplicated) request; per-token failures are logged and
+// reported in that token's entry.
+func (h *TokenDetailsHandler) GetTokenDetails(w http.ResponseWriter, r *http.Request) error {
+	ctx, cancel := context.WithTimeout(r.Context(), TokenDetailsContextTimeout)
+	defer cancel()
+
+	network := r.URL.Query().Get("network")
+	if !isValidNetwork(network) {
+		return httperror.BadRequest(fmt.Sprintf("invalid network: network must be %s, %s or %s", types.PUBLIC, types.TESTNET, types.FUTURENET), errors.New("invalid network"))
+	}
+
+	req, validationErr := validateTokenDetailsRequest(r, h.MaxContracts)
+	if validationErr != nil {
+		return validationErr
+	}
+
+	results := make([]TokenDetailsResult, len(req.ContractIDs))
+	var g errgroup.Group
+	g.SetLimit(h.maxConcurrent)
+	for i, contractID := range req.ContractIDs {
+		g.Go(func() error {
+			results[i] = TokenDetailsResult{ContractID: contractID}
+			details, err := h.TokenDetailsService.GetTokenDetails(ctx, network, contractID, req.Owner)
+			if err != nil {
+				logger.ErrorWithContext(ctx, "fetching token details", "contract_id", contractID, "network", network, "error", err)
+				results[i].Error = msgTokenDetailsFetchFailed
+				return nil
+			}
+			results[i].Token = details
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	w.Header().Set("Content-Type", "application/json")
+	return response.OK(w, HttpResponse{Data: TokenDetailsPayload{Tokens: results}})
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils"
+)
+
+const (
+	testTokenContractA = "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC"
+	testTokenContractB = "CAS3J7GYLGXMF6TDJBBYYSE3HQ6BBSMLNUQ34T6TZMYMW2EVH34XOWMA"
+)
+
+func newTokenDetailsRequest(network, body string) *http.Request {
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/token-details?network="+network, strings.NewReader(body))
+	return req
+}
+
+func TestTokenDetails_GetTokenDetails_PartialFailure(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu     sync.Mutex
+		owners []string
+	)
+	svc := &utils.MockTokenDetailsService{GetTokenDetailsFunc: func(network, contractID, owner string) (*types.TokenDetails, error) {
+		mu.Lock()
+		owners = append(owners, owner)
+		mu.Unlock()
+		if contractID == testTokenContractB {
+			return nil, errors.New("simulation failed")
+		}
+		return &types.TokenDetails{Name: "USD Coin", Symbol: "USDC", Decimals: 7, Balance: "100"}, nil
+	}}
+	rr := httptest.NewRecorder()
+
+	body := `{"owner":"` + validIssuer + `","contract_ids":["` + testTokenContractA + `","` + testTokenContractB + `","` + testTokenContractA + `"]}`
+	require.NoError(t, NewTokenDetailsHandler(svc, 10, 2).GetTokenDetails(rr, newTokenDetailsRequest(types.PUBLIC, body)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp struct {
+		Data TokenDetailsPayload `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, []TokenDetailsResult{
+		{ContractID: testTokenContractA, Token: &types.TokenDetails{Name: "USD Coin", Symbol: "USDC", Decimals: 7, Balance: "100"}},
+		{ContractID: testTokenContractB, Error: msgTokenDetailsFetchFailed},
+	}, resp.Data.Tokens)
+	assert.Equal(t, []string{validIssuer, validIssuer}, owners, "duplicates are fetched once")
+}
+
+func TestTokenDetails_GetTokenDetails_BadRequests(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct{ network, body string }{
+		"invalid network":    {"MAINNET", `{"contract_ids":["` + testTokenContractA + `"]}`},
+		"malformed body":     {types.PUBLIC, `{"contract_ids":`},
+		"no contracts":       {types.PUBLIC, `{"contract_ids":[]}`},
+		"invalid contract":   {types.PUBLIC, `{"contract_ids":["` + validIssuer + `"]}`},
+		"invalid owner":      {types.PUBLIC, `{"owner":"nope","contract_ids":["` + testTokenContractA + `"]}`},
+		"too many contracts": {types.PUBLIC, `{"contract_ids":["` + testTokenContractA + `","` + testTokenContractB + `"]}`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := NewTokenDetailsHandler(&utils.MockTokenDetailsService{}, 1, 2).GetTokenDetails(httptest.NewRecorder(), newTokenDetailsRequest(tc.network, tc.body))
+			require.Error(t, err)
+			assert.Equal(t, http.StatusBadRequest, unwrapHttpStatus(t, err))
+		})
+	}
+}
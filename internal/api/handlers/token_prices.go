@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
+	"strings"
 
 	"github.com/stellar/freighter-backend-v2/internal/api/httperror"
 	response "github.com/stellar/freighter-backend-v2/internal/api/httpresponse"
@@ -17,13 +19,19 @@ import (
 
 type TokenPricesHandler struct {
 	PricesService types.PricesService
-	MaxTokens     int
+	// FXRates converts prices for the `currency` param; nil serves USD only.
+	FXRates   types.FXRatesService
+	MaxTokens int
 }
 
-func NewTokenPricesHandler(svc types.PricesService, maxTokens int) *TokenPricesHandler {
-	return &TokenPricesHandler{PricesService: svc, MaxTokens: maxTokens}
+func NewTokenPricesHandler(svc types.PricesService, fxRates types.FXRatesService, maxTokens int) *TokenPricesHandler {
+	return &TokenPricesHandler{PricesService: svc, FXRates: fxRates, MaxTokens: maxTokens}
 }
 
+// currencyCodePattern matches the shape of an ISO 4217 code; whether the code
+// is supported is up to the FX rates source.
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
 type TokenPricesRequest struct {
 	Tokens []string `json:"tokens"`
 }
@@ -80,14 +88,33 @@ func validateTokenPricesRequest(r *http.Request, maxTokens int) (*validatedToken
 	}, nil
 }
 
-// GetPrices handles POST /api/v1/token-prices.
+// GetPrices handles POST /api/v1/token-prices. Prices are in USD unless the
+// `currency` query param names another ISO 4217 code.
 func (h *TokenPricesHandler) GetPrices(w http.ResponseWriter, r *http.Request) error {
-	network := r.URL.Query().Get("network")
-	if !isValidNetwork(network) {
-		return httperror.BadRequest(fmt.Sprintf("invalid network: network must be %s or %s", types.PUBLIC, types.TESTNET), errors.New("invalid network"))
+	network, netErr := validatePricesNetwork(r)
+	if netErr != nil {
+		return netErr
 	}
-	if network == types.FUTURENET {
-		return httperror.BadRequest("token prices are not available on FUTURENET", errors.New("futurenet not supported"))
+	currency := strings.ToUpper(r.URL.Query().Get("currency"))
+	if currency == "" {
+		currency = types.BaseCurrency
+	}
+	if !currencyCodePattern.MatchString(currency) || (currency != types.BaseCurrency && h.FXRates == nil) {
+		errStr := fmt.Sprintf("unsupported currency: %s", currency)
+		return httperror.BadRequest(errStr, types.ErrUnsupportedCurrency)
+	}
+	// Check the code against the rates table before pricing, so an
+	// unsupported one is a 400 that costs no price fetches.
+	if currency != types.BaseCurrency {
+		supported, err := h.FXRates.SupportsCurrency(r.Context(), currency)
+		if err != nil {
+			logger.ErrorWithContext(r.Context(), "loading fx rates", "currency", currency, "error", err)
+			return httperror.ServiceUnavailable("currency conversion temporarily unavailable", err)
+		}
+		if !supported {
+			errStr := fmt.Sprintf("unsupported currency: %s", currency)
+			return httperror.BadRequest(errStr, types.ErrUnsupportedCurrency)
+		}
 	}
 
 	req, validationErr := validateTokenPricesRequest(r, h.MaxTokens)
@@ -103,6 +130,16 @@ func (h *TokenPricesHandler) GetPrices(w http.ResponseWriter, r *http.Request) e
 		}
 		return httperror.InternalServerError("Failed to get token prices", err)
 	}
+	if currency != types.BaseCurrency {
+		prices, err = h.FXRates.ConvertPrices(r.Context(), prices, currency)
+		if err != nil {
+			if errors.Is(err, types.ErrUnsupportedCurrency) {
+				return httperror.BadRequest(fmt.Sprintf("unsupported currency: %s", currency), err)
+			}
+			logger.ErrorWithContext(r.Context(), "converting token prices", "currency", currency, "error", err)
+			return httperror.ServiceUnavailable("currency conversion temporarily unavailable", err)
+		}
+	}
 
 	// Build response keyed by the *original* client input, preserving v1's
 	// echo behavior (so a request for "native" returns "native": ...). The
@@ -115,3 +152,50 @@ func (h *TokenPricesHandler) GetPrices(w http.ResponseWriter, r *http.Request) e
 	w.Header().Set("Content-Type", "application/json")
 	return response.OK(w, HttpResponse{Data: out})
 }
+
+// GetPriceHistory handles GET /api/v1/token-prices/{token}/history. range
+// defaults to 1d when omitted.
+func (h *TokenPricesHandler) GetPriceHistory(w http.ResponseWriter, r *http.Request) error {
+	network, netErr := validatePricesNetwork(r)
+	if netErr != nil {
+		return netErr
+	}
+
+	token := r.PathValue("token")
+	canonical, err := assetid.Normalize(token)
+	if err != nil {
+		return httperror.BadRequest("invalid token id", err)
+	}
+
+	priceRange := r.URL.Query().Get("range")
+	if priceRange == "" {
+		priceRange = types.PriceRange1D
+	}
+	if !types.IsValidPriceRange(priceRange) {
+		errStr := fmt.Sprintf("invalid range: must be one of %s", strings.Join(types.PriceRanges, ", "))
+		return httperror.BadRequest(errStr, errors.New(errStr))
+	}
+
+	history, err := h.PricesService.GetPriceHistory(r.Context(), canonical, network, priceRange)
+	if err != nil {
+		if errors.Is(err, types.ErrAssetNotPriced) {
+			return httperror.NotFound("price history not found", err)
+		}
+		return translateUpstreamError(r.Context(), "stellar-expert", err, "price history", canonical, network)
+	}
+
+	return response.OK(w, HttpResponse{Data: history})
+}
+
+// validatePricesNetwork reads the network query param shared by the price
+// endpoints. Prices exist only on PUBLIC and TESTNET.
+func validatePricesNetwork(r *http.Request) (string, *httperror.HttpError) {
+	network := r.URL.Query().Get("network")
+	if !isValidNetwork(network) {
+		return "", httperror.BadRequest(fmt.Sprintf("invalid network: network must be %s or %s", types.PUBLIC, types.TESTNET), errors.New("invalid network"))
+	}
+	if network == types.FUTURENET {
+		return "", httperror.BadRequest("token prices are not available on FUTURENET", errors.New("futurenet not supported"))
+	}
+	return network, nil
+}
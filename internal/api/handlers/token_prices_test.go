@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -29,7 +30,7 @@ func TestTokenPrices_Success(t *testing.T) {
 			"USDC:" + validIssuer: {CurrentPrice: "1", PercentagePriceChange24h: ptr("0")},
 		},
 	}
-	handler := NewTokenPricesHandler(mock, 1000)
+	handler := NewTokenPricesHandler(mock, nil, 1000)
 
 	body := `{"tokens":["XLM","USDC:` + validIssuer + `"]}`
 	req, _ := http.NewRequest(http.MethodPost, "/api/v1/token-prices?network=PUBLIC", strings.NewReader(body))
@@ -57,7 +58,7 @@ func TestTokenPrices_NullableEntry(t *testing.T) {
 			"BOGUS:" + validIssuer: nil,
 		},
 	}
-	handler := NewTokenPricesHandler(mock, 1000)
+	handler := NewTokenPricesHandler(mock, nil, 1000)
 
 	body := `{"tokens":["BOGUS:` + validIssuer + `"]}`
 	req, _ := http.NewRequest(http.MethodPost, "/api/v1/token-prices?network=PUBLIC", strings.NewReader(body))
@@ -77,7 +78,7 @@ func TestTokenPrices_PreservesOriginalInputKeys(t *testing.T) {
 			"XLM": {CurrentPrice: "0.16", PercentagePriceChange24h: ptr("1.27")},
 		},
 	}
-	handler := NewTokenPricesHandler(mock, 1000)
+	handler := NewTokenPricesHandler(mock, nil, 1000)
 
 	// Client sends "native"; response must echo "native", not "XLM".
 	body := `{"tokens":["native"]}`
@@ -94,6 +95,31 @@ func TestTokenPrices_PreservesOriginalInputKeys(t *testing.T) {
 	assert.Equal(t, "0.16", resp.Data["native"].CurrentPrice)
 }
 
+func TestTokenPrices_AcceptsContractTokens(t *testing.T) {
+	t.Parallel()
+
+	const contract = "CCW67TSZV3SSS2HXMBQ5JFGCKJNXKZM7UQUWUZPUTHXSTZLEO7SJMI75"
+	mock := &utils.MockPricesService{
+		GetPricesOverride: map[string]*types.PriceEntry{
+			contract: {CurrentPrice: "1"},
+		},
+	}
+	handler := NewTokenPricesHandler(mock, nil, 1000)
+
+	// Lower-case input normalizes to the canonical C-address.
+	body := `{"tokens":["` + strings.ToLower(contract) + `"]}`
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/token-prices?network=PUBLIC", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	require.NoError(t, handler.GetPrices(rr, req))
+	var resp struct {
+		Data map[string]*types.PriceEntry `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Data[strings.ToLower(contract)])
+	assert.Equal(t, "1", resp.Data[strings.ToLower(contract)].CurrentPrice)
+}
+
 func TestTokenPrices_BadRequests(t *testing.T) {
 	t.Parallel()
 
@@ -116,7 +142,7 @@ func TestTokenPrices_BadRequests(t *testing.T) {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			handler := NewTokenPricesHandler(&utils.MockPricesService{}, 2)
+			handler := NewTokenPricesHandler(&utils.MockPricesService{}, nil, 2)
 			req, _ := http.NewRequest(http.MethodPost, tc.url, strings.NewReader(tc.body))
 			rr := httptest.NewRecorder()
 
@@ -137,7 +163,7 @@ func TestTokenPrices_DuplicatesCollapseUnderCap(t *testing.T) {
 	t.Parallel()
 
 	mock := &utils.MockPricesService{}
-	handler := NewTokenPricesHandler(mock, 2)
+	handler := NewTokenPricesHandler(mock, nil, 2)
 
 	body := `{"tokens":["XLM","native","xlm"]}`
 	req, _ := http.NewRequest(http.MethodPost, "/api/v1/token-prices?network=PUBLIC", strings.NewReader(body))
@@ -152,7 +178,7 @@ func TestTokenPrices_ServiceError(t *testing.T) {
 	t.Parallel()
 
 	mock := &utils.MockPricesService{GetPricesError: errors.New("boom")}
-	handler := NewTokenPricesHandler(mock, 1000)
+	handler := NewTokenPricesHandler(mock, nil, 1000)
 
 	body := `{"tokens":["XLM"]}`
 	req, _ := http.NewRequest(http.MethodPost, "/api/v1/token-prices?network=PUBLIC", strings.NewReader(body))
@@ -178,7 +204,7 @@ func TestTokenPrices_ContextErrorReturns503(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 			mock := &utils.MockPricesService{GetPricesError: tc.err}
-			handler := NewTokenPricesHandler(mock, 1000)
+			handler := NewTokenPricesHandler(mock, nil, 1000)
 
 			body := `{"tokens":["XLM"]}`
 			req, _ := http.NewRequest(http.MethodPost, "/api/v1/token-prices?network=PUBLIC", strings.NewReader(body))
@@ -198,3 +224,163 @@ func unwrapHttpStatus(t *testing.T, err error) int {
 	require.True(t, errors.As(err, &hs), "expected HttpError-typed error, got %T", err)
 	return hs.HttpStatus()
 }
+
+func newPriceHistoryRequest(token, query string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/token-prices/"+token+"/history?"+query, nil)
+	req.SetPathValue("token", token)
+	return req
+}
+
+func TestTokenPriceHistory_Success(t *testing.T) {
+	t.Parallel()
+
+	mock := &utils.MockPricesService{
+		GetPriceHistoryResult: &types.PriceHistory{
+			Range:         types.PriceRange7D,
+			ResolutionSec: 14400,
+			Points:        []types.PricePoint{{Timestamp: 14400, Open: "1", High: "1.2", Low: "0.9", Close: "1.1"}},
+		},
+	}
+	handler := NewTokenPricesHandler(mock, nil, 1000)
+
+	rr := httptest.NewRecorder()
+	require.NoError(t, handler.GetPriceHistory(rr, newPriceHistoryRequest("native", "network=PUBLIC&range=7d")))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp struct {
+		Data types.PriceHistory `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, *mock.GetPriceHistoryResult, resp.Data)
+	assert.Equal(t, "XLM", mock.LastToken)
+	assert.Equal(t, types.PUBLIC, mock.LastNetwork)
+	assert.Equal(t, types.PriceRange7D, mock.LastRange)
+}
+
+func TestTokenPriceHistory_DefaultsToOneDay(t *testing.T) {
+	t.Parallel()
+
+	mock := &utils.MockPricesService{}
+	handler := NewTokenPricesHandler(mock, nil, 1000)
+
+	rr := httptest.NewRecorder()
+	require.NoError(t, handler.GetPriceHistory(rr, newPriceHistoryRequest("USDC:"+validIssuer, "network=TESTNET")))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, types.PriceRange1D, mock.LastRange)
+	assert.Contains(t, rr.Body.String(), `"points":[]`)
+}
+
+func TestTokenPriceHistory_RejectsBadInput(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name  string
+		token string
+		query string
+	}{
+		{"invalid range", "XLM", "network=PUBLIC&range=5y"},
+		{"invalid token", "not-a-token", "network=PUBLIC&range=1d"},
+		{"invalid network", "XLM", "network=MAINNET&range=1d"},
+		{"futurenet", "XLM", "network=FUTURENET&range=1d"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &utils.MockPricesService{}
+			handler := NewTokenPricesHandler(mock, nil, 1000)
+
+			err := handler.GetPriceHistory(httptest.NewRecorder(), newPriceHistoryRequest(tc.token, tc.query))
+			assert.Equal(t, http.StatusBadRequest, unwrapHttpStatus(t, err))
+			assert.Empty(t, mock.LastRange, "service must not be called")
+		})
+	}
+}
+
+func TestTokenPriceHistory_MapsServiceErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"not priced", fmt.Errorf("%w: XLM", types.ErrAssetNotPriced), http.StatusNotFound},
+		{"timeout", context.DeadlineExceeded, http.StatusGatewayTimeout},
+		{"other", errors.New("boom"), http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := NewTokenPricesHandler(&utils.MockPricesService{GetPriceHistoryError: tc.err}, nil, 1000)
+
+			err := handler.GetPriceHistory(httptest.NewRecorder(), newPriceHistoryRequest("XLM", "network=PUBLIC&range=30d"))
+			assert.Equal(t, tc.status, unwrapHttpStatus(t, err))
+		})
+	}
+}
+
+func TestTokenPrices_ConvertsCurrency(t *testing.T) {
+	t.Parallel()
+
+	mock := &utils.MockPricesService{
+		GetPricesOverride: map[string]*types.PriceEntry{
+			"XLM": {CurrentPrice: "0.2", PercentagePriceChange24h: ptr("1.27")},
+		},
+	}
+	fx := &utils.MockFXRatesService{Rates: map[string]float64{"EUR": 0.5}}
+	handler := NewTokenPricesHandler(mock, fx, 1000)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/token-prices?network=PUBLIC&currency=eur", strings.NewReader(`{"tokens":["native"]}`))
+	rr := httptest.NewRecorder()
+	require.NoError(t, handler.GetPrices(rr, req))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp struct {
+		Data map[string]*types.PriceEntry `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, &types.PriceEntry{CurrentPrice: "0.1", PercentagePriceChange24h: ptr("1.27")}, resp.Data["native"])
+	assert.Equal(t, "EUR", fx.LastCurrency)
+}
+
+func TestTokenPrices_USDSkipsConversion(t *testing.T) {
+	t.Parallel()
+
+	fx := &utils.MockFXRatesService{Err: errors.New("must not be called")}
+	handler := NewTokenPricesHandler(&utils.MockPricesService{}, fx, 1000)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/token-prices?network=PUBLIC&currency=USD", strings.NewReader(`{"tokens":["XLM"]}`))
+	require.NoError(t, handler.GetPrices(httptest.NewRecorder(), req))
+	assert.Empty(t, fx.LastCurrency)
+}
+
+func TestTokenPrices_CurrencyErrors(t *testing.T) {
+	t.Parallel()
+
+	fx := &utils.MockFXRatesService{Rates: map[string]float64{"EUR": 0.5}}
+	cases := []struct {
+		name     string
+		fx       types.FXRatesService
+		currency string
+		status   int
+	}{
+		{"malformed code", fx, "EURO", http.StatusBadRequest},
+		{"unknown code", fx, "XYZ", http.StatusBadRequest},
+		{"no fx service", nil, "EUR", http.StatusBadRequest},
+		{"fx unavailable", &utils.MockFXRatesService{Err: errors.New("down")}, "EUR", http.StatusServiceUnavailable},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			prices := &utils.MockPricesService{}
+			handler := NewTokenPricesHandler(prices, tc.fx, 1000)
+			req, _ := http.NewRequest(http.MethodPost, "/api/v1/token-prices?network=PUBLIC&currency="+tc.currency, strings.NewReader(`{"tokens":["XLM"]}`))
+			err := handler.GetPrices(httptest.NewRecorder(), req)
+			assert.Equal(t, tc.status, unwrapHttpStatus(t, err))
+			assert.Nil(t, prices.LastTokens, "the currency is checked before pricing")
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+
+	"github.com/stellar/freighter-backend-v2/internal/api/httperror"
+	response "github.com/stellar/freighter-backend-v2/internal/api/httpresponse"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils"
+)
+
+const (
+	// SubmitTxContextTimeout bounds submission plus polling. It stays under the
+	// server's 10s WriteTimeout so a slow ledger yields a PENDING response the
+	// client can follow up on, rather than a dropped connection.
+	SubmitTxContextTimeout   = 8 * time.Second
+	TxStatusContextTimeout   = 5 * time.Second
+	SimulateTxContextTimeout = 5 * time.Second
+)
+
+type TransactionsHandler struct {
+	RPCService types.RPCService
+}
+
+func NewTransactionsHandler(rpcService types.RPCService) *TransactionsHandler {
+	return &TransactionsHandler{RPCService: rpcService}
+}
+
+type SubmitTxRequest struct {
+	TxXDR string `json:"tx_xdr"`
+}
+
+// validateSubmitTxRequest requires a decodable envelope carrying at least one
+// signature; an unsigned envelope can only fail with tx_bad_auth, so it is
+// rejected before spending an RPC call on it.
+func validateSubmitTxRequest(r *http.Request) (string, *httperror.HttpError) {
+	var req SubmitTxRequest
+	if decodeErr := decodeJSONBody(r, &req); decodeErr != nil {
+		return "", decodeErr
+	}
+	txXDR := strings.TrimSpace(req.TxXDR)
+	if txXDR == "" {
+		errStr := "tx_xdr cannot be empty"
+		return "", httperror.BadRequest(errStr, errors.New(errStr))
+	}
+	var envelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(txXDR, &envelope); err != nil {
+		return "", httperror.BadRequest("invalid tx_xdr: must be a base64 TransactionEnvelope", err)
+	}
+	signatures := envelope.Signatures()
+	if envelope.IsFeeBump() {
+		signatures = envelope.FeeBumpSignatures()
+	}
+	if len(signatures) == 0 {
+		errStr := "tx_xdr must be signed"
+		return "", httperror.BadRequest(errStr, errors.New(errStr))
+	}
+	return txXDR, nil
+}
+
+type SimulateTxRequest struct {
+	TxXDR string `json:"tx_xdr"`
+}
+
+// SimulateTxResponse carries the transaction ready to sign alongside the raw
+// simulation, so callers can show fees and auth or act on a restore preamble.
+type SimulateTxResponse struct {
+	TransactionXDR string                  `json:"transaction_xdr"`
+	Simulation     *types.SimulationResult `json:"simulation"`
+}
+
+// SimulateTx handles POST /api/v1/simulate-tx. A simulation the host rejects
+// is the caller's transaction failing, so it is a 422 carrying the host's
+// message rather than an upstream error.
+func (h *TransactionsHandler) SimulateTx(w http.ResponseWriter, r *http.Request) error {
+	ctx, cancel := context.WithTimeout(r.Context(), SimulateTxContextTimeout)
+	defer cancel()
+
+	network := r.URL.Query().Get("network")
+	if !isValidNetwork(network) {
+		return httperror.BadRequest(fmt.Sprintf("invalid network: network must be %s, %s or %s", types.PUBLIC, types.TESTNET, types.FUTURENET), errors.New("invalid network"))
+	}
+
+	var req SimulateTxRequest
+	if decodeErr := decodeJSONBody(r, &req); decodeErr != nil {
+		return decodeErr
+	}
+	txXDR := strings.TrimSpace(req.TxXDR)
+	if txXDR == "" {
+		errStr := "tx_xdr cannot be empty"
+		return httperror.BadRequest(errStr, errors.New(errStr))
+	}
+	envelope, err := utils.SorobanEnvelope(txXDR)
+	if err != nil {
+		return httperror.BadRequest(fmt.Sprintf("invalid tx_xdr: %v", err), err)
+	}
+
+	sim, err := h.RPCService.SimulateTransaction(ctx, txXDR, network)
+	if err != nil {
+		return translateUpstreamError(r.Context(), "rpc", err, "transaction simulation", "", network)
+	}
+	if sim.Error != "" {
+		return httperror.WithExtras(
+			httperror.UnprocessableEntity("transaction simulation failed", errors.New(sim.Error)),
+			map[string]interface{}{"simulation_error": sim.Error},
+		)
+	}
+
+	assembled, err := utils.AssembleTransaction(envelope, sim)
+	if err != nil {
+		return httperror.InternalServerError("Failed to assemble simulated transaction", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return response.OK(w, HttpResponse{Data: SimulateTxResponse{TransactionXDR: assembled, Simulation: sim}})
+}
+
+// SubmitTx handles POST /api/v1/submit-tx.
+func (h *TransactionsHandler) SubmitTx(w http.ResponseWriter, r *http.Request) error {
+	ctx, cancel := context.WithTimeout(r.Context(), SubmitTxContextTimeout)
+	defer cancel()
+
+	network := r.URL.Query().Get("network")
+	if !isValidNetwork(network) {
+		return httperror.BadRequest(fmt.Sprintf("invalid network: network must be %s, %s or %s", types.PUBLIC, types.TESTNET, types.FUTURENET), errors.New("invalid network"))
+	}
+
+	txXDR, validationErr := validateSubmitTxRequest(r)
+	if validationErr != nil {
+		return validationErr
+	}
+
+	status, err := h.RPCService.SubmitTransaction(ctx, txXDR, network)
+	if err != nil {
+		return translateUpstreamError(r.Context(), "rpc", err, "transaction submission", "", network)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return response.OK(w, HttpResponse{Data: status})
+}
+
+// GetTxStatus handles GET /api/v1/tx/{hash}/status.
+func (h *TransactionsHandler) GetTxStatus(w http.ResponseWriter, r *http.Request) error {
+	ctx, cancel := context.WithTimeout(r.Context(), TxStatusContextTimeout)
+	defer cancel()
+
+	network := r.URL.Query().Get("network")
+	if !isValidNetwork(network) {
+		return httperror.BadRequest(fmt.Sprintf("invalid network: network must be %s, %s or %s", types.PUBLIC, types.TESTNET, types.FUTURENET), errors.New("invalid network"))
+	}
+
+	hash := strings.ToLower(r.PathValue("hash"))
+	if decoded, err := hex.DecodeString(hash); err != nil || len(decoded) != 32 {
+		errStr := "invalid hash: must be a 64-character hex transaction hash"
+		return httperror.BadRequest(errStr, errors.New(errStr))
+	}
+
+	status, err := h.RPCService.GetTransactionStatus(ctx, hash, network)
+	if err != nil {
+		return translateUpstreamError(r.Context(), "rpc", err, "transaction status", "", network)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return response.OK(w, HttpResponse{Data: status})
+}
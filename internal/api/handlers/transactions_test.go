@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/txnbuild"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/api/httperror"
+	"github.com/stellar/freighter-backend-v2/internal/metrics"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils"
+)
+
+const testTxHash = "b9d0b2292c4e09e8eb22d036171491e87b8d2086bf8b265874c8d182cb9c9020"
+
+func unsignedTestTx(t *testing.T) string {
+	t.Helper()
+	kp := keypair.MustRandom()
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &txnbuild.SimpleAccount{AccountID: kp.Address(), Sequence: 1},
+		IncrementSequenceNum: true,
+		Operations:           []txnbuild.Operation{&txnbuild.BumpSequence{BumpTo: 10}},
+		BaseFee:              txnbuild.MinBaseFee,
+		Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()},
+	})
+	require.NoError(t, err)
+	encoded, err := tx.Base64()
+	require.NoError(t, err)
+	return encoded
+}
+
+func newSubmitTxRequest(network, body string) *http.Request {
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/submit-tx?network="+network, strings.NewReader(body))
+	return req
+}
+
+func TestTransactions_SubmitTx_Success(t *testing.T) {
+	t.Parallel()
+
+	txXDR, _ := testTxEnvelope(t)
+	mock := &utils.MockRPCService{SubmitTxResult: &types.TxStatus{Hash: testTxHash, Status: types.TxStatusSuccess, ResultCode: "tx_success", Ledger: 11}}
+	rr := httptest.NewRecorder()
+
+	require.NoError(t, NewTransactionsHandler(mock).SubmitTx(rr, newSubmitTxRequest(types.TESTNET, `{"tx_xdr":"`+txXDR+`"}`)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"data":{"hash":"`+testTxHash+`","status":"SUCCESS","result_code":"tx_success","ledger":11}}`, rr.Body.String())
+	assert.Equal(t, txXDR, mock.LastSubmittedXDR)
+}
+
+func TestTransactions_SubmitTx_BadRequests(t *testing.T) {
+	t.Parallel()
+
+	signed, _ := testTxEnvelope(t)
+	unsigned := unsignedTestTx(t)
+	for name, tc := range map[string]struct{ network, body string }{
+		"invalid network": {"MAINNET", `{"tx_xdr":"` + signed + `"}`},
+		"malformed body":  {types.PUBLIC, `{"tx_xdr":`},
+		"empty xdr":       {types.PUBLIC, `{"tx_xdr":" "}`},
+		"not an envelope": {types.PUBLIC, `{"tx_xdr":"AAAA"}`},
+		"unsigned":        {types.PUBLIC, `{"tx_xdr":"` + unsigned + `"}`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			mock := &utils.MockRPCService{}
+			err := NewTransactionsHandler(mock).SubmitTx(httptest.NewRecorder(), newSubmitTxRequest(tc.network, tc.body))
+			require.Error(t, err)
+			assert.Equal(t, http.StatusBadRequest, unwrapHttpStatus(t, err))
+			assert.Empty(t, mock.LastSubmittedXDR, "nothing reaches RPC")
+		})
+	}
+}
+
+func TestTransactions_SubmitTx_UpstreamErrors(t *testing.T) {
+	t.Parallel()
+
+	txXDR, _ := testTxEnvelope(t)
+	for name, tc := range map[string]struct {
+		err  error
+		want int
+	}{
+		"deadline": {context.DeadlineExceeded, http.StatusGatewayTimeout},
+		"upstream": {&metrics.UpstreamError{Kind: "rpc_error", Err: errors.New("bad status")}, http.StatusBadGateway},
+		"other":    {errors.New("boom"), http.StatusInternalServerError},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := NewTransactionsHandler(&utils.MockRPCService{SubmitTxError: tc.err}).SubmitTx(httptest.NewRecorder(), newSubmitTxRequest(types.PUBLIC, `{"tx_xdr":"`+txXDR+`"}`))
+			require.Error(t, err)
+			assert.Equal(t, tc.want, unwrapHttpStatus(t, err))
+		})
+	}
+}
+
+func TestTransactions_GetTxStatus(t *testing.T) {
+	t.Parallel()
+
+	newReq := func(hash, network string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tx/"+hash+"/status?network="+network, nil)
+		req.SetPathValue("hash", hash)
+		return req
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		mock := &utils.MockRPCService{TxStatusResult: &types.TxStatus{Hash: testTxHash, Status: types.TxStatusPending}}
+		rr := httptest.NewRecorder()
+		require.NoError(t, NewTransactionsHandler(mock).GetTxStatus(rr, newReq(strings.ToUpper(testTxHash), types.PUBLIC)))
+		assert.JSONEq(t, `{"data":{"hash":"`+testTxHash+`","status":"PENDING"}}`, rr.Body.String())
+	})
+
+	t.Run("invalid hash", func(t *testing.T) {
+		t.Parallel()
+		err := NewTransactionsHandler(&utils.MockRPCService{}).GetTxStatus(httptest.NewRecorder(), newReq("abc", types.PUBLIC))
+		assert.Equal(t, http.StatusBadRequest, unwrapHttpStatus(t, err))
+	})
+
+	t.Run("invalid network", func(t *testing.T) {
+		t.Parallel()
+		err := NewTransactionsHandler(&utils.MockRPCService{}).GetTxStatus(httptest.NewRecorder(), newReq(testTxHash, ""))
+		assert.Equal(t, http.StatusBadRequest, unwrapHttpStatus(t, err))
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		t.Parallel()
+		mock := &utils.MockRPCService{TxStatusError: &metrics.UpstreamError{Kind: "rpc_error", Err: errors.New("bad status")}}
+		err := NewTransactionsHandler(mock).GetTxStatus(httptest.NewRecorder(), newReq(testTxHash, types.PUBLIC))
+		assert.Equal(t, http.StatusBadGateway, unwrapHttpStatus(t, err))
+	})
+}
+
+func unsignedInvokeTx(t *testing.T) string {
+	t.Helper()
+	kp := keypair.MustRandom()
+	contractID := xdr.ContractId{1}
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &txnbuild.SimpleAccount{AccountID: kp.Address(), Sequence: 1},
+		IncrementSequenceNum: true,
+		Operations: []txnbuild.Operation{&txnbuild.InvokeHostFunction{
+			HostFunction: xdr.HostFunction{
+				Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+				InvokeContract: &xdr.InvokeContractArgs{
+					ContractAddress: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID},
+					FunctionName:    "transfer",
+					Args:            xdr.ScVec{},
+				},
+			},
+		}},
+		BaseFee:       txnbuild.MinBaseFee,
+		Preconditions: txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()},
+	})
+	require.NoError(t, err)
+	encoded, err := tx.Base64()
+	require.NoError(t, err)
+	return encoded
+}
+
+func newSimulateTxRequest(network, body string) *http.Request {
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/simulate-tx?network="+network, strings.NewReader(body))
+	return req
+}
+
+func TestTransactions_SimulateTx_Success(t *testing.T) {
+	t.Parallel()
+
+	data, err := xdr.MarshalBase64(xdr.SorobanTransactionData{ResourceFee: 700})
+	require.NoError(t, err)
+	sim := &types.SimulationResult{TransactionData: data, MinResourceFee: 700, Auth: []string{}, Events: []string{}, LatestLedger: 9}
+	txXDR := unsignedInvokeTx(t)
+	mock := &utils.MockRPCService{SimulateTxResult: sim}
+	rr := httptest.NewRecorder()
+
+	require.NoError(t, NewTransactionsHandler(mock).SimulateTx(rr, newSimulateTxRequest(types.TESTNET, `{"tx_xdr":"`+txXDR+`"}`)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, txXDR, mock.LastSimulatedXDR)
+
+	var body struct {
+		Data SimulateTxResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Equal(t, sim, body.Data.Simulation)
+	var assembled xdr.TransactionEnvelope
+	require.NoError(t, xdr.SafeUnmarshalBase64(body.Data.TransactionXDR, &assembled))
+	assert.EqualValues(t, txnbuild.MinBaseFee+700, assembled.V1.Tx.Fee)
+	assert.NotNil(t, assembled.V1.Tx.Ext.SorobanData)
+}
+
+func TestTransactions_SimulateTx_BadRequests(t *testing.T) {
+	t.Parallel()
+
+	classic, _ := testTxEnvelope(t)
+	for name, tc := range map[string]struct{ network, body string }{
+		"invalid network":   {"MAINNET", `{"tx_xdr":"` + unsignedInvokeTx(t) + `"}`},
+		"malformed body":    {types.PUBLIC, `{"tx_xdr":`},
+		"empty xdr":         {types.PUBLIC, `{"tx_xdr":""}`},
+		"not an envelope":   {types.PUBLIC, `{"tx_xdr":"AAAA"}`},
+		"classic operation": {types.PUBLIC, `{"tx_xdr":"` + classic + `"}`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			mock := &utils.MockRPCService{}
+			err := NewTransactionsHandler(mock).SimulateTx(httptest.NewRecorder(), newSimulateTxRequest(tc.network, tc.body))
+			require.Error(t, err)
+			assert.Equal(t, http.StatusBadRequest, unwrapHttpStatus(t, err))
+			assert.Empty(t, mock.LastSimulatedXDR, "nothing reaches RPC")
+		})
+	}
+}
+
+func TestTransactions_SimulateTx_Failures(t *testing.T) {
+	t.Parallel()
+
+	txXDR := unsignedInvokeTx(t)
+
+	t.Run("host failure is 422", func(t *testing.T) {
+		t.Parallel()
+		mock := &utils.MockRPCService{SimulateTxResult: &types.SimulationResult{Error: "HostError: contract panicked"}}
+		err := NewTransactionsHandler(mock).SimulateTx(httptest.NewRecorder(), newSimulateTxRequest(types.PUBLIC, `{"tx_xdr":"`+txXDR+`"}`))
+		require.Error(t, err)
+		assert.Equal(t, http.StatusUnprocessableEntity, unwrapHttpStatus(t, err))
+		var httpErr *httperror.HttpError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, "HostError: contract panicked", httpErr.Extras["simulation_error"])
+	})
+
+	t.Run("upstream error is 502", func(t *testing.T) {
+		t.Parallel()
+		mock := &utils.MockRPCService{SimulateTxError: &metrics.UpstreamError{Kind: "http_error", Err: errors.New("down")}}
+		err := NewTransactionsHandler(mock).SimulateTx(httptest.NewRecorder(), newSimulateTxRequest(types.PUBLIC, `{"tx_xdr":"`+txXDR+`"}`))
+		assert.Equal(t, http.StatusBadGateway, unwrapHttpStatus(t, err))
+	})
+}
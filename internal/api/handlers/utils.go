@@ -2,22 +2,27 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"net/url"
 	"strconv"
 
 	"github.com/alitto/pond/v2"
+	"github.com/creachadair/jrpc2"
 	"github.com/stellar/go-stellar-sdk/txnbuild"
 	"github.com/stellar/go-stellar-sdk/xdr"
 	"github.com/stellar/wallet-backend/pkg/wbclient"
 
 	"github.com/stellar/freighter-backend-v2/internal/api/httperror"
+	"github.com/stellar/freighter-backend-v2/internal/api/middleware"
 	"github.com/stellar/freighter-backend-v2/internal/logger"
 	"github.com/stellar/freighter-backend-v2/internal/metrics"
 	"github.com/stellar/freighter-backend-v2/internal/types"
 	"github.com/stellar/freighter-backend-v2/internal/utils"
+	"github.com/stellar/freighter-backend-v2/internal/utils/scval"
 )
 
 // isValidWalletBackendNetwork reports whether network is one of the values
@@ -40,40 +45,65 @@ func isValidNetwork(network string) bool {
 	return network == types.PUBLIC || network == types.TESTNET || network == types.FUTURENET
 }
 
-// translateServiceError maps a service-layer error to a typed HttpError per
-// the spec's REST-honest mapping. Logs all non-404 errors with context;
-// account-not-found is a normal client outcome and is not logged.
+// decodeJSONBody decodes a JSON request body into dest, mapping a body over the
+// size limit to 413 and anything else malformed to 400.
+func decodeJSONBody(r *http.Request, dest any) *httperror.HttpError {
+	if err := json.NewDecoder(r.Body).Decode(dest); err != nil {
+		if middleware.IsMaxBytesError(err) {
+			return httperror.RequestEntityTooLarge("Request body too large", err)
+		}
+		return httperror.BadRequest("invalid request body", err)
+	}
+	return nil
+}
+
+// translateServiceError maps a wallet-backend service error to a typed
+// HttpError; see translateUpstreamError for the status mapping.
+func translateServiceError(ctx context.Context, err error, resource, address, network string) *httperror.HttpError {
+	return translateUpstreamError(ctx, "wallet-backend", err, resource, address, network)
+}
+
+// translateUpstreamError maps a service-layer error to a typed HttpError per
+// the spec's REST-honest mapping. Logs all non-404 errors with context, naming
+// the upstream that failed; account-not-found is a normal client outcome and
+// is not logged.
 //
 // Status mapping:
 //   - wbclient.ErrAccountNotFound       -> 404
 //   - context.DeadlineExceeded          -> 504 (server-side timeout)
 //   - context.Canceled                  -> 503 (client disconnect / parent abort)
-//   - *metrics.UpstreamError (any Kind) -> 502 (graphql_error, http_error)
+//   - *metrics.UpstreamError (any Kind) -> 502 (graphql_error, http_error, simulation_error)
+//   - *jrpc2.Error                      -> 502 (stellar-rpc rejected the call)
 //   - *url.Error / *net.OpError         -> 502 (transport / DNS / dial)
 //   - anything else                     -> 500
-func translateServiceError(ctx context.Context, err error, resource, address, network string) *httperror.HttpError {
+func translateUpstreamError(ctx context.Context, upstream string, err error, resource, address, network string) *httperror.HttpError {
 	switch {
 	case errors.Is(err, wbclient.ErrAccountNotFound):
 		return httperror.NotFound(fmt.Sprintf("%s not found", resource), err)
 	case errors.Is(err, context.DeadlineExceeded):
-		logger.ErrorWithContext(ctx, "wallet-backend call timed out", "resource", resource, "address", address, "network", network, "error", err)
+		logger.ErrorWithContext(ctx, upstream+" call timed out", "resource", resource, "address", address, "network", network, "error", err)
 		return httperror.GatewayTimeout(fmt.Sprintf("Failed to get %s", resource), err)
 	case errors.Is(err, context.Canceled):
-		logger.ErrorWithContext(ctx, "wallet-backend call canceled by client", "resource", resource, "address", address, "network", network, "error", err)
+		logger.ErrorWithContext(ctx, upstream+" call canceled by client", "resource", resource, "address", address, "network", network, "error", err)
 		return httperror.ServiceUnavailable(fmt.Sprintf("Failed to get %s", resource), err)
 	}
 	var upErr *metrics.UpstreamError
 	if errors.As(err, &upErr) {
-		logger.ErrorWithContext(ctx, "wallet-backend upstream error", "resource", resource, "address", address, "network", network, "kind", upErr.Kind, "code", upErr.Code, "error", err)
+		logger.ErrorWithContext(ctx, upstream+" upstream error", "resource", resource, "address", address, "network", network, "kind", upErr.Kind, "code", upErr.Code, "error", err)
+		return httperror.BadGateway(fmt.Sprintf("Failed to get %s", resource), err)
+	}
+	var rpcErr *jrpc2.Error
+	if errors.As(err, &rpcErr) {
+		logger.ErrorWithContext(ctx, upstream+" rpc error", "resource", resource, "address", address, "network", network, "code", rpcErr.Code, "error", err)
 		return httperror.BadGateway(fmt.Sprintf("Failed to get %s", resource), err)
 	}
 	var urlErr *url.Error
 	var netErr *net.OpError
 	if errors.As(err, &urlErr) || errors.As(err, &netErr) {
-		logger.ErrorWithContext(ctx, "wallet-backend transport error", "resource", resource, "address", address, "network", network, "error", err)
+		logger.ErrorWithContext(ctx, upstream+" transport error", "resource", resource, "address", address, "network", network, "error", err)
 		return httperror.BadGateway(fmt.Sprintf("Failed to get %s", resource), err)
 	}
-	logger.ErrorWithContext(ctx, "wallet-backend call failed", "resource", resource, "address", address, "network", network, "error", err)
+	logger.ErrorWithContext(ctx, upstream+" call failed", "resource", resource, "address", address, "network", network, "error", err)
 	return httperror.InternalServerError(fmt.Sprintf("Failed to get %s", resource), err)
 }
 
@@ -83,9 +113,10 @@ type collection struct {
 }
 
 type Collectible struct {
-	Owner    string `json:"owner"`
-	TokenUri string `json:"token_uri"`
-	TokenId  string `json:"token_id"`
+	Owner    string                     `json:"owner"`
+	TokenUri string                     `json:"token_uri"`
+	TokenId  string                     `json:"token_id"`
+	Metadata *types.CollectibleMetadata `json:"metadata,omitempty"`
 }
 
 func FetchCollection(
@@ -118,7 +149,12 @@ func FetchCollection(
 			nameCh <- result{"", err}
 			return
 		}
-		nameCh <- result{res.String(), nil}
+		name, err := scval.Text(*res)
+		if err != nil {
+			nameCh <- result{"", fmt.Errorf("name: %w", err)}
+			return
+		}
+		nameCh <- result{name, nil}
 	})
 
 	group.Submit(func() {
@@ -127,7 +163,12 @@ func FetchCollection(
 			symbolCh <- result{"", err}
 			return
 		}
-		symbolCh <- result{res.String(), nil}
+		symbol, err := scval.Text(*res)
+		if err != nil {
+			symbolCh <- result{"", fmt.Errorf("symbol: %w", err)}
+			return
+		}
+		symbolCh <- result{symbol, nil}
 	})
 
 	if err := group.Wait(); err != nil {
@@ -240,32 +281,49 @@ func fetchCollectible(
 		return nil, tokenURIRes.err
 	}
 
+	owner, err := scval.Text(ownerRes.val)
+	if err != nil {
+		return nil, fmt.Errorf("owner_of: %w", err)
+	}
+	tokenURI, err := scval.Text(tokenURIRes.val)
+	if err != nil {
+		return nil, fmt.Errorf("token_uri: %w", err)
+	}
+
 	return &Collectible{
-		Owner:    ownerRes.val.String(),
-		TokenUri: tokenURIRes.val.String(),
+		Owner:    owner,
+		TokenUri: tokenURI,
 		TokenId:  tokenId,
 	}, nil
 }
 
-/*
-NOTE: This function is not part of SEP-50 and should only be used with Meridian Pay NFTs or contracts that extend the standard.
-*/
+// DefaultMaxDiscoveredTokens is the default cap on the tokens enumerated for
+// one owner in one registered collection (--max-discovered-tokens), bounding
+// the RPC fan-out of discovery.
+const DefaultMaxDiscoveredTokens = 100
+
+// fetchOwnerTokens lists up to limit of the token IDs owner holds in a
+// registered collection, using the enumeration the collection declares, and
+// reports whether the owner holds more. Neither method is part of SEP-50, so
+// only registered collections are enumerated.
 func fetchOwnerTokens(
 	rpc types.RPCService,
 	ctx context.Context,
 	accountId *txnbuild.SimpleAccount,
-	contractID string,
+	collection types.RegisteredCollection,
 	owner string,
 	network string,
-) ([]string, error) {
-	id, err := utils.ScAddressFromString(contractID)
+	rpcPool pond.Pool,
+	limit int,
+) (tokenIDs []string, truncated bool, err error) {
+	id, err := utils.ScAddressFromString(collection.ContractID)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	ownerAddress, err := utils.ScAddressFromString(owner)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	ownerVal := xdr.ScVal{
@@ -273,21 +331,90 @@ func fetchOwnerTokens(
 		Address: ownerAddress,
 	}
 
+	switch collection.Enumeration {
+	case types.CollectionEnumerationOwnerTokens:
+		return fetchOwnerTokensVec(rpc, ctx, accountId, *id, ownerVal, network, limit)
+	case types.CollectionEnumerationOwnerIndex:
+		return fetchOwnerTokensByIndex(rpc, ctx, accountId, *id, ownerVal, network, rpcPool, limit)
+	}
+	return nil, false, fmt.Errorf("unsupported enumeration %q", collection.Enumeration)
+}
+
+// fetchOwnerTokensVec reads get_owner_tokens(owner) -> Vec<u32>.
+func fetchOwnerTokensVec(
+	rpc types.RPCService,
+	ctx context.Context,
+	accountId *txnbuild.SimpleAccount,
+	id xdr.ScAddress,
+	ownerVal xdr.ScVal,
+	network string,
+	limit int,
+) ([]string, bool, error) {
 	// Make direct RPC call (already running within a pool task from caller)
-	res, err := rpc.SimulateInvocation(ctx, *id, accountId, "get_owner_tokens", []xdr.ScVal{ownerVal}, txnbuild.NewTimeout(300), network)
+	res, err := rpc.SimulateInvocation(ctx, id, accountId, "get_owner_tokens", []xdr.ScVal{ownerVal}, txnbuild.NewTimeout(300), network)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	vec, ok := res.GetVec()
 	if !ok {
-		return nil, fmt.Errorf("expected SCV_VEC result, got %v", res.Type)
+		return nil, false, fmt.Errorf("expected SCV_VEC result, got %v", res.Type)
 	}
 
 	tokenIDs, err := utils.ScVecToStrings(vec)
 	if err != nil {
-		return nil, err
+		return nil, false, err
+	}
+	if len(tokenIDs) > limit {
+		return tokenIDs[:limit], true, nil
+	}
+
+	return tokenIDs, false, nil
+}
+
+// fetchOwnerTokensByIndex reads balance(owner) -> u32, then
+// get_owner_token_id(owner, index) -> u32 for every index below it.
+func fetchOwnerTokensByIndex(
+	rpc types.RPCService,
+	ctx context.Context,
+	accountId *txnbuild.SimpleAccount,
+	id xdr.ScAddress,
+	ownerVal xdr.ScVal,
+	network string,
+	rpcPool pond.Pool,
+	limit int,
+) ([]string, bool, error) {
+	res, err := rpc.SimulateInvocation(ctx, id, accountId, "balance", []xdr.ScVal{ownerVal}, txnbuild.NewTimeout(300), network)
+	if err != nil {
+		return nil, false, err
+	}
+	balance, ok := res.GetU32()
+	if !ok {
+		return nil, false, fmt.Errorf("balance: expected SCV_U32 result, got %v", res.Type)
+	}
+	count := min(int(balance), limit)
+
+	tokenIDs := make([]string, count)
+	group := rpcPool.NewGroupContext(ctx)
+	for i := range count {
+		index := xdr.Uint32(i) //nolint:gosec // bounded by balance
+		group.SubmitErr(func() error {
+			indexVal := xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &index}
+			res, err := rpc.SimulateInvocation(ctx, id, accountId, "get_owner_token_id", []xdr.ScVal{ownerVal, indexVal}, txnbuild.NewTimeout(300), network)
+			if err != nil {
+				return err
+			}
+			tokenID, ok := res.GetU32()
+			if !ok {
+				return fmt.Errorf("get_owner_token_id(%d): expected SCV_U32 result, got %v", i, res.Type)
+			}
+			tokenIDs[i] = strconv.FormatUint(uint64(tokenID), 10)
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, false, err
 	}
 
-	return tokenIDs, nil
+	return tokenIDs, count < int(balance), nil
 }
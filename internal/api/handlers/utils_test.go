@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net"
 	"net/http"
 	"net/http/httptest"
@@ -11,6 +12,7 @@ import (
 	"testing"
 
 	"github.com/alitto/pond/v2"
+	"github.com/creachadair/jrpc2"
 	"github.com/stellar/go-stellar-sdk/txnbuild"
 	"github.com/stellar/go-stellar-sdk/xdr"
 	"github.com/stellar/wallet-backend/pkg/wbclient"
@@ -18,6 +20,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/stellar/freighter-backend-v2/internal/metrics"
+	"github.com/stellar/freighter-backend-v2/internal/types"
 	"github.com/stellar/freighter-backend-v2/internal/utils"
 )
 
@@ -110,9 +113,10 @@ func TestFetchOwnerTokens_Success(t *testing.T) {
 	owner := "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"
 	contractID := "CBIELTK6YBZJU5UP2WWQEUCYKLPU6AUNZ2BQ4WWFEIE3USCIHMXQDAMA"
 
-	tokens, err := fetchOwnerTokens(mockRPC, context.Background(), account, contractID, owner, "PUBLIC")
+	tokens, truncated, err := fetchOwnerTokens(mockRPC, context.Background(), account, types.RegisteredCollection{ContractID: contractID, Enumeration: types.CollectionEnumerationOwnerTokens}, owner, "PUBLIC", pond.NewPool(2), DefaultMaxDiscoveredTokens)
 	assert.NoError(t, err)
 	assert.Equal(t, []string{}, tokens)
+	assert.False(t, truncated)
 }
 
 func TestFetchOwnerTokens_InvalidContractID(t *testing.T) {
@@ -120,7 +124,7 @@ func TestFetchOwnerTokens_InvalidContractID(t *testing.T) {
 	account := &txnbuild.SimpleAccount{AccountID: "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"}
 	owner := "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"
 
-	_, err := fetchOwnerTokens(mockRPC, context.Background(), account, "INVALID", owner, "PUBLIC")
+	_, _, err := fetchOwnerTokens(mockRPC, context.Background(), account, types.RegisteredCollection{ContractID: "INVALID", Enumeration: types.CollectionEnumerationOwnerTokens}, owner, "PUBLIC", pond.NewPool(2), DefaultMaxDiscoveredTokens)
 	assert.Error(t, err)
 }
 
@@ -129,7 +133,7 @@ func TestFetchOwnerTokens_InvalidOwnerAddress(t *testing.T) {
 	account := &txnbuild.SimpleAccount{AccountID: "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"}
 	contractID := "CBIELTK6YBZJU5UP2WWQEUCYKLPU6AUNZ2BQ4WWFEIE3USCIHMXQDAMA"
 
-	_, err := fetchOwnerTokens(mockRPC, context.Background(), account, contractID, "INVALID", "PUBLIC")
+	_, _, err := fetchOwnerTokens(mockRPC, context.Background(), account, types.RegisteredCollection{ContractID: contractID, Enumeration: types.CollectionEnumerationOwnerTokens}, "INVALID", "PUBLIC", pond.NewPool(2), DefaultMaxDiscoveredTokens)
 	assert.Error(t, err)
 }
 
@@ -141,7 +145,7 @@ func TestFetchOwnerTokens_SimulateInvocationError(t *testing.T) {
 	owner := "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"
 	contractID := "CBIELTK6YBZJU5UP2WWQEUCYKLPU6AUNZ2BQ4WWFEIE3USCIHMXQDAMA"
 
-	_, err := fetchOwnerTokens(mockRPC, context.Background(), account, contractID, owner, "PUBLIC")
+	_, _, err := fetchOwnerTokens(mockRPC, context.Background(), account, types.RegisteredCollection{ContractID: contractID, Enumeration: types.CollectionEnumerationOwnerTokens}, owner, "PUBLIC", pond.NewPool(2), DefaultMaxDiscoveredTokens)
 	assert.Error(t, err)
 }
 
@@ -157,7 +161,7 @@ func TestFetchOwnerTokens_NonVecResponse(t *testing.T) {
 	owner := "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"
 	contractID := "CBIELTK6YBZJU5UP2WWQEUCYKLPU6AUNZ2BQ4WWFEIE3USCIHMXQDAMA"
 
-	_, err := fetchOwnerTokens(mockRPC, context.Background(), account, contractID, owner, "PUBLIC")
+	_, _, err := fetchOwnerTokens(mockRPC, context.Background(), account, types.RegisteredCollection{ContractID: contractID, Enumeration: types.CollectionEnumerationOwnerTokens}, owner, "PUBLIC", pond.NewPool(2), DefaultMaxDiscoveredTokens)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "expected SCV_VEC result")
 }
@@ -197,6 +201,7 @@ func TestTranslateServiceError(t *testing.T) {
 		{"http_error -> 502", &metrics.UpstreamError{Kind: "http_error", Code: 503, Err: errors.New("upstream down")}, http.StatusBadGateway},
 		{"url.Error -> 502", &url.Error{Op: "Post", URL: "http://wb/graphql", Err: errors.New("dial tcp: connection refused")}, http.StatusBadGateway},
 		{"net.OpError -> 502", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, http.StatusBadGateway},
+		{"jrpc2 error -> 502", fmt.Errorf("sendTransaction RPC failed: %w", &jrpc2.Error{Code: -32602, Message: "invalid params"}), http.StatusBadGateway},
 		{"generic -> 500", errors.New("anything else"), http.StatusInternalServerError},
 	}
 	for _, tt := range tests {
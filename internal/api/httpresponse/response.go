@@ -1,8 +1,12 @@
 package response
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
+	"strings"
 
 	"github.com/stellar/freighter-backend-v2/internal/logger"
 )
@@ -24,6 +28,46 @@ func OK(w http.ResponseWriter, data interface{}) error {
 	return JSON(w, http.StatusOK, data)
 }
 
+// OKWithETag writes a 200 OK JSON response carrying a strong ETag derived from
+// the encoded body, or a bodiless 304 Not Modified when r's If-None-Match
+// already names that ETag. Use it for responses clients poll.
+func OKWithETag(w http.ResponseWriter, r *http.Request, data interface{}) error {
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(data); err != nil {
+		logger.Error("Failed to encode JSON response", "error", err)
+		return err
+	}
+	sum := sha256.Sum256(body.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	w.Header().Set("ETag", etag)
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body.Bytes()); err != nil {
+		logger.Error("Failed to write JSON response", "error", err)
+		return err
+	}
+	return nil
+}
+
+// etagMatches applies If-None-Match's weak comparison: any listed tag, with or
+// without a W/ prefix, or "*".
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
 // Created writes a 201 Created JSON response
 func Created(w http.ResponseWriter, data interface{}) error {
 	return JSON(w, http.StatusCreated, data)
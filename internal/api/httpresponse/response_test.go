@@ -64,6 +64,35 @@ func TestOK(t *testing.T) {
 	assert.Empty(t, w.Header().Get("Content-Type"))
 }
 
+func TestOKWithETag(t *testing.T) {
+	data := testData{Message: "success", Count: 1}
+
+	w := httptest.NewRecorder()
+	require.NoError(t, OKWithETag(w, httptest.NewRequest(http.MethodGet, "/", nil), data))
+	assert.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.JSONEq(t, `{"message":"success","count":1}`, w.Body.String())
+
+	for _, ifNoneMatch := range []string{etag, "W/" + etag, `"other", ` + etag, "*"} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("If-None-Match", ifNoneMatch)
+		w = httptest.NewRecorder()
+		require.NoError(t, OKWithETag(w, r, data))
+		assert.Equal(t, http.StatusNotModified, w.Code, ifNoneMatch)
+		assert.Equal(t, etag, w.Header().Get("ETag"))
+		assert.Empty(t, w.Body.Bytes())
+	}
+
+	// A different body gets a different ETag, so a stale one no longer matches.
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	require.NoError(t, OKWithETag(w, r, testData{Message: "changed", Count: 1}))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+}
+
 func TestCreated(t *testing.T) {
 	w := httptest.NewRecorder()
 	data := map[string]string{"id": "123"}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/stellar/freighter-backend-v2/internal/api/httperror"
+	"github.com/stellar/freighter-backend-v2/internal/auth"
+	"github.com/stellar/freighter-backend-v2/internal/logger"
+)
+
+// AdminAuth returns middleware that admits requests bearing one of the admin
+// credentials, a map of actor name to token, and rejects everything else with
+// 401. The matching actor is attached to the request context (retrieve it with
+// auth.AdminActorFromContext) and is what the audit log records.
+//
+// These are not the user JWTs of the public API: a user token never
+// authenticates here, and an admin token never authenticates there.
+func AdminAuth(credentials map[string]string) Middleware {
+	// Compare digests so the comparison time depends on neither the token's
+	// length nor how much of it matches.
+	digests := make(map[string][sha256.Size]byte, len(credentials))
+	for actor, token := range credentials {
+		digests[actor] = sha256.Sum256([]byte(token))
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || token == "" {
+				httperror.Unauthorized("unauthorized", nil).Render(w)
+				return
+			}
+			presented := sha256.Sum256([]byte(token))
+			actor := ""
+			for name, digest := range digests {
+				// No early exit, so the matching entry's position isn't timed.
+				if subtle.ConstantTimeCompare(presented[:], digest[:]) == 1 {
+					actor = name
+				}
+			}
+			if actor == "" {
+				logger.Warn("admin: rejected request with an unknown credential", "method", r.Method, "path", r.URL.Path)
+				httperror.Unauthorized("unauthorized", nil).Render(w)
+				return
+			}
+			logger.FieldsFromContext(r.Context()).Set("admin_actor", actor)
+			next.ServeHTTP(w, r.WithContext(auth.ContextWithAdminActor(r.Context(), actor)))
+		})
+	}
+}
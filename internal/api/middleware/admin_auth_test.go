@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/freighter-backend-v2/internal/auth"
+)
+
+func TestAdminAuth(t *testing.T) {
+	t.Parallel()
+
+	var gotActor string
+	h := AdminAuth(map[string]string{"alice": "alice-token", "deploy-bot": "bot-token"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotActor, _ = auth.AdminActorFromContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	for _, tc := range []struct {
+		header    string
+		wantCode  int
+		wantActor string
+	}{
+		{"Bearer alice-token", http.StatusNoContent, "alice"},
+		{"Bearer bot-token", http.StatusNoContent, "deploy-bot"},
+		{"", http.StatusUnauthorized, ""},
+		{"Bearer ", http.StatusUnauthorized, ""},
+		{"Bearer alice-token2", http.StatusUnauthorized, ""},
+		{"alice-token", http.StatusUnauthorized, ""},
+	} {
+		gotActor = ""
+		r := httptest.NewRequest(http.MethodGet, "/admin/v1/feature-flags", nil)
+		if tc.header != "" {
+			r.Header.Set("Authorization", tc.header)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assert.Equal(t, tc.wantCode, w.Code, tc.header)
+		assert.Equal(t, tc.wantActor, gotActor, tc.header)
+	}
+}
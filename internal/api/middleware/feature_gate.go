@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/stellar/freighter-backend-v2/internal/auth"
+	"github.com/stellar/freighter-backend-v2/internal/logger"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+// FeatureGate returns middleware that serves a request only while the feature
+// flag key is on for its caller, and otherwise answers 404 exactly as an
+// unregistered route would. The flag is evaluated against the authenticated
+// user ID, so the gate must run inside Auth for percentage rollouts to see it,
+// and against the network query param when there is one.
+func FeatureGate(flags types.FeatureFlagsService, key string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fc := types.FlagContext{Network: r.URL.Query().Get("network")}
+			fc.UserID, _ = auth.UserIDFromContext(r.Context())
+			if !flags.IsEnabled(r.Context(), key, fc) {
+				logger.FieldsFromContext(r.Context()).Set("feature_gate", key)
+				http.NotFound(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/freighter-backend-v2/internal/auth"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+// stubFlags answers IsEnabled from a function of the flag context.
+type stubFlags func(key string, fc types.FlagContext) bool
+
+func (f stubFlags) Evaluate(context.Context, types.FlagContext) map[string]bool { return nil }
+
+func (f stubFlags) IsEnabled(_ context.Context, key string, fc types.FlagContext) bool {
+	return f(key, fc)
+}
+
+func TestFeatureGate(t *testing.T) {
+	t.Parallel()
+
+	var seen types.FlagContext
+	flags := stubFlags(func(key string, fc types.FlagContext) bool {
+		seen = fc
+		return key == "route_on" || fc.UserID == "beta-user"
+	})
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	serve := func(key, userID string) int {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/token-prices?network=TESTNET", nil)
+		if userID != "" {
+			r = r.WithContext(auth.ContextWithUserID(r.Context(), userID))
+		}
+		w := httptest.NewRecorder()
+		FeatureGate(flags, key)(ok).ServeHTTP(w, r)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve("route_on", ""))
+	assert.Equal(t, http.StatusNotFound, serve("route_off", ""))
+	assert.Equal(t, types.TESTNET, seen.Network)
+	assert.Equal(t, http.StatusOK, serve("route_off", "beta-user"), "the user ID reaches the flag")
+	assert.Equal(t, "beta-user", seen.UserID)
+}
@@ -11,7 +11,8 @@ func ResponseHeader() Middleware {
 			w.Header().Set("X-Frame-Options", "DENY")
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-None-Match")
+			w.Header().Set("Access-Control-Expose-Headers", "ETag")
 
 			// Handle preflight requests (OPTIONS method)
 			if r.Method == http.MethodOptions {
@@ -100,6 +100,9 @@ func TestMiddleware_ResponseHeader(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rec.Code)
 	assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
 	assert.Equal(t, "DENY", rec.Header().Get("X-Frame-Options"))
+	// Browser clients can revalidate polled responses by ETag.
+	assert.Contains(t, rec.Header().Get("Access-Control-Allow-Headers"), "If-None-Match")
+	assert.Equal(t, "ETag", rec.Header().Get("Access-Control-Expose-Headers"))
 }
 
 func TestMiddleware_Recover(t *testing.T) {
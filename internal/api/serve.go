@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
@@ -44,10 +45,30 @@ type ApiServer struct {
 	dbPool               *pgxpool.Pool
 	rpcService           types.RPCService
 	walletBackendService types.WalletBackendService
+	horizonService       types.HorizonService
+	balanceSources       services.NetworkBalanceSources
 	pricesService        types.PricesService
+	fxRatesService       types.FXRatesService
+	blockaidService      types.BlockaidService
+	coinbaseService      types.CoinbaseService
+	tokenDetailsService  types.TokenDetailsService
 	registry             *prometheus.Registry
 	appMetrics           *metrics.Metrics
 	authMode             auth.Mode
+	// collectibleMetadata stays nil unless --resolve-collectible-metadata is
+	// set, which leaves collectibles' token_uri unresolved.
+	collectibleMetadata types.CollectibleMetadataService
+	// collectionRegistry stays nil with neither --collections-registry-path nor
+	// a database, which turns collection discovery off.
+	collectionRegistry types.CollectionRegistry
+	// protocolCatalog is set in Start, once the database is up.
+	protocolCatalog types.ProtocolCatalog
+	// featureFlags is set in Start, once the database is up. It stays nil for
+	// --feature-flags-source=builtin.
+	featureFlags types.FeatureFlagsService
+	// background tracks the loops Start runs, so shutdown waits for them
+	// before closing the services they use.
+	background sync.WaitGroup
 }
 
 func NewApiServer(cfg *config.Config) *ApiServer {
@@ -89,13 +110,41 @@ func (s *ApiServer) Start() error {
 	}
 	defer s.closeServices()
 
+	// Background loops (registry refreshes, price refreshes) stop when Start
+	// returns, and are waited for before closeServices runs.
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	defer func() {
+		stopBackground()
+		s.background.Wait()
+	}()
+	s.startCollectionRegistry(bgCtx)
+	s.startProtocolCatalog(bgCtx)
+	s.startFeatureFlags(bgCtx)
+	s.startPriceRefresh(bgCtx)
+
 	mux, err := s.initHandlers()
 	if err != nil {
 		return fmt.Errorf("initializing handlers: %w", err)
 	}
 	apiHandler := s.initMiddleware(mux)
 	metricsHandler := middleware.Chain(s.initMetricsHandler(), middleware.Recover())
-	return s.startServers(apiHandler, metricsHandler)
+	return s.startServers(apiHandler, metricsHandler, s.initAdminHandler())
+}
+
+// priceProviders builds the spot price providers named by --price-providers,
+// in priority order. Names are validated by the serve command.
+func (s *ApiServer) priceProviders(stellarExpert types.StellarExpertService) []types.PriceProvider {
+	c := s.cfg.PricesConfig
+	providers := make([]types.PriceProvider, 0, len(c.PriceProviders))
+	for _, name := range c.PriceProviders {
+		switch name {
+		case services.PriceProviderStellarExpert:
+			providers = append(providers, services.NewStellarExpertPriceProvider(stellarExpert))
+		case services.PriceProviderFeed:
+			providers = append(providers, services.NewPriceFeedProvider(c.PriceFeedPubnetURL, c.PriceFeedTestnetURL, c.PriceFeedAPIKey, s.appMetrics.Service))
+		}
+	}
+	return providers
 }
 
 func (s *ApiServer) initServices() error {
@@ -106,6 +155,12 @@ func (s *ApiServer) initServices() error {
 	s.redis = store.NewRedisStore(s.cfg.RedisConfig.Host, s.cfg.RedisConfig.Port, s.cfg.RedisConfig.Password)
 
 	s.rpcService = services.NewRPCService(s.cfg.RpcConfig.PubnetRpcUrl, s.cfg.RpcConfig.TestnetRpcUrl, s.cfg.RpcConfig.FuturenetRpcUrl, s.appMetrics.Service)
+	s.tokenDetailsService = services.NewTokenDetailsService(
+		s.rpcService,
+		s.redis,
+		time.Duration(s.cfg.AppConfig.TokenBalanceCacheTTLSeconds)*time.Second,
+		s.appMetrics.Service,
+	)
 
 	// Initialize wallet backend service if configured
 	walletBackendService, err := services.NewWalletBackendService(
@@ -122,6 +177,14 @@ func (s *ApiServer) initServices() error {
 	}
 	s.walletBackendService = walletBackendService
 
+	s.horizonService = services.NewHorizonService(s.cfg.HorizonConfig.HorizonPubnetURL, s.cfg.HorizonConfig.HorizonTestnetURL, s.appMetrics.Service)
+	horizonBalances, err := services.NewHorizonBalanceSource(s.horizonService, s.cfg.AppConfig.WalletBackendBalanceConcurrency)
+	if err != nil {
+		logger.Error("Failed to initialize horizon balance source", "error", err)
+		return err
+	}
+	s.balanceSources = s.selectBalanceSources(horizonBalances)
+
 	stellarExpert := services.NewStellarExpertService(
 		s.cfg.PricesConfig.StellarExpertPubnetURL,
 		s.cfg.PricesConfig.StellarExpertTestnetURL,
@@ -130,14 +193,178 @@ func (s *ApiServer) initServices() error {
 		s.appMetrics.Service,
 	)
 	s.pricesService = services.NewPricesService(stellarExpert, s.redis, services.PricesServiceConfig{
-		CacheTTL:         time.Duration(s.cfg.PricesConfig.PriceCacheTTLSeconds) * time.Second,
-		MissFetchTimeout: time.Duration(s.cfg.PricesConfig.PriceFetchTimeoutSeconds) * time.Second,
-		MaxConcurrent:    s.cfg.PricesConfig.MaxConcurrentPriceFetches,
+		CacheTTL:           time.Duration(s.cfg.PricesConfig.PriceCacheTTLSeconds) * time.Second,
+		StaleTTL:           time.Duration(s.cfg.PricesConfig.PriceStaleTTLSeconds) * time.Second,
+		MissFetchTimeout:   time.Duration(s.cfg.PricesConfig.PriceFetchTimeoutSeconds) * time.Second,
+		MaxConcurrent:      s.cfg.PricesConfig.MaxConcurrentPriceFetches,
+		HotTokens:          s.cfg.PricesConfig.HotPriceTokens,
+		HotRefreshInterval: s.cfg.PricesConfig.HotPriceRefreshInterval,
+		Providers:          s.priceProviders(stellarExpert),
+		Aggregation:        s.cfg.PricesConfig.PriceAggregation,
+		Contracts:          services.NewContractAssetResolver(s.rpcService, s.redis, s.appMetrics.Service),
 	}, s.appMetrics.Service, s.appMetrics.Prices)
+	s.fxRatesService = services.NewFXRatesService(
+		services.NewFrankfurterProvider(s.cfg.PricesConfig.FXRatesURL, s.appMetrics.Service),
+		s.redis,
+		time.Duration(s.cfg.PricesConfig.FXRatesCacheTTLSeconds)*time.Second,
+	)
+
+	s.blockaidService = services.NewBlockaidService(services.BlockaidServiceConfig{
+		BaseURL:  s.cfg.BlockaidConfig.BlockaidBaseURL,
+		APIKey:   s.cfg.BlockaidConfig.BlockaidAPIKey,
+		CacheTTL: time.Duration(s.cfg.BlockaidConfig.BlockaidCacheTTLSeconds) * time.Second,
+	}, s.redis, s.appMetrics.Service)
+
+	if c := s.cfg.CollectiblesConfig; c.ResolveMetadata {
+		s.collectibleMetadata = services.NewCollectibleMetadataService(services.CollectibleMetadataServiceConfig{
+			IPFSGatewayURL: c.IPFSGatewayURL,
+			CacheTTL:       time.Duration(c.MetadataCacheTTLSeconds) * time.Second,
+			MaxBytes:       c.MetadataMaxBytes,
+			FetchTimeout:   c.MetadataFetchTimeout,
+		}, s.redis, s.appMetrics.Service)
+	}
+
+	if path := s.cfg.CollectiblesConfig.CollectionsRegistryPath; path != "" {
+		registry, err := store.NewFileCollectionRegistry(path)
+		if err != nil {
+			logger.Error("Failed to load collections registry", "error", err)
+			return err
+		}
+		s.collectionRegistry = registry
+	} else if addresses := s.cfg.CollectiblesConfig.MeridianPayAddresses(); len(addresses) > 0 {
+		logger.Warn("The --meridian-pay-* flags are deprecated; list those collections in --collections-registry-path or the collections table", "addresses", addresses)
+		registry, err := store.NewStaticCollectionRegistry(meridianPayCollections(addresses))
+		if err != nil {
+			logger.Error("Failed to register the --meridian-pay-* collections", "error", err)
+			return err
+		}
+		s.collectionRegistry = registry
+	}
+
+	coinbaseService, err := services.NewCoinbaseService(
+		s.cfg.CoinbaseConfig.CoinbaseBaseURL,
+		s.cfg.CoinbaseConfig.CoinbaseAPIKey,
+		s.cfg.CoinbaseConfig.CoinbaseAPISecret,
+		s.appMetrics.Service,
+	)
+	if err != nil {
+		logger.Error("Failed to initialize coinbase service", "error", err)
+		return err
+	}
+	s.coinbaseService = coinbaseService
 
 	return nil
 }
 
+// meridianPayCollections registers the deprecated --meridian-pay-* addresses
+// the way they were queried before the registry existed: through
+// get_owner_tokens, whatever the network.
+func meridianPayCollections(addresses []string) []types.RegisteredCollection {
+	var collections []types.RegisteredCollection
+	for _, network := range []string{types.PUBLIC, types.TESTNET, types.FUTURENET} {
+		for _, address := range addresses {
+			collections = append(collections, types.RegisteredCollection{
+				Network:     network,
+				ContractID:  address,
+				Enumeration: types.CollectionEnumerationOwnerTokens,
+				Label:       "meridian-pay",
+			})
+		}
+	}
+	return collections
+}
+
+// startCollectionRegistry serves collection discovery from the collections
+// table when no registry file was configured. A failed initial load is only
+// logged: discovery starts empty and the next refresh retries.
+func (s *ApiServer) startCollectionRegistry(ctx context.Context) {
+	if s.collectionRegistry != nil || s.dbPool == nil {
+		return
+	}
+	registry := store.NewDBCollectionRegistry(store.NewCollectionsStore(s.dbPool), s.cfg.CollectiblesConfig.CollectionsRefreshInterval)
+	s.collectionRegistry = registry
+	s.startRefresher(ctx, registry, "Failed to load collections from the database; discovery starts empty")
+}
+
+// startProtocolCatalog loads the catalog GET /api/v1/protocols serves from
+// --protocols-source and keeps reloading it. A failed initial load is only
+// logged: the endpoint answers 503 until a reload succeeds.
+func (s *ApiServer) startProtocolCatalog(ctx context.Context) {
+	var refresher types.Refresher
+	c := s.cfg.AppConfig
+	switch {
+	case c.ProtocolsSource == config.ProtocolsSourceDatabase && s.dbPool != nil:
+		catalog := store.NewDBProtocolCatalog(store.NewProtocolsStore(s.dbPool), c.ProtocolsRefreshInterval)
+		s.protocolCatalog, refresher = catalog, catalog
+	case c.ProtocolsSource == config.ProtocolsSourceFile:
+		catalog := store.NewFileProtocolCatalog(c.ProtocolsConfigPath, c.ProtocolsRefreshInterval, s.protocolRules(), s.appMetrics.Protocols)
+		s.protocolCatalog, refresher = catalog, catalog
+	default:
+		return
+	}
+	s.startRefresher(ctx, refresher, "Failed to load the protocols catalog; serving 503 until a reload succeeds")
+}
+
+// protocolRules are the optional protocol checks set by
+// --protocols-allowed-tags and --protocols-strict-validation.
+func (s *ApiServer) protocolRules() types.ProtocolRules {
+	return types.ProtocolRules{
+		AllowedTags: s.cfg.AppConfig.ProtocolsAllowedTags,
+		Strict:      s.cfg.AppConfig.ProtocolsStrictValidation,
+	}
+}
+
+// startFeatureFlags loads the flags GET /api/v1/feature-flags evaluates from
+// --feature-flags-source and keeps reloading them. Until a load succeeds the
+// built-in defaults are evaluated.
+func (s *ApiServer) startFeatureFlags(ctx context.Context) {
+	var (
+		flags     types.FeatureFlagSource
+		refresher types.Refresher
+	)
+	c := s.cfg.AppConfig
+	switch {
+	case c.FeatureFlagsSource == config.FeatureFlagsSourceDatabase && s.dbPool != nil:
+		source := store.NewDBFeatureFlagSource(store.NewFeatureFlagsStore(s.dbPool), c.FeatureFlagsRefreshInterval)
+		flags, refresher = source, source
+	case c.FeatureFlagsSource == config.FeatureFlagsSourceFile:
+		source := store.NewFileFeatureFlagSource(c.FeatureFlagsPath, c.FeatureFlagsRefreshInterval)
+		flags, refresher = source, source
+	default:
+		return
+	}
+	s.featureFlags = services.NewFeatureFlagsService(flags)
+	s.startRefresher(ctx, refresher, "Failed to load feature flags; serving built-in defaults until a reload succeeds")
+}
+
+// startRefresher loads r once, logging failureMessage if that fails, and
+// keeps it refreshing until ctx is cancelled.
+func (s *ApiServer) startRefresher(ctx context.Context, r types.Refresher, failureMessage string) {
+	if err := r.Refresh(ctx); err != nil {
+		logger.Warn(failureMessage, "error", err)
+	}
+	s.background.Go(func() { r.Run(ctx) })
+}
+
+// startPriceRefresh runs the prices service's background work: keeping the
+// most requested token prices fresh ahead of expiry (--hot-price-tokens) and
+// the fetches that outlive their request.
+func (s *ApiServer) startPriceRefresh(ctx context.Context) {
+	if s.pricesService == nil {
+		return
+	}
+	s.background.Go(func() { s.pricesService.Run(ctx) })
+}
+
+// featureFlagsService returns the service started by startFeatureFlags, or
+// one over the built-in flags for --feature-flags-source=builtin.
+func (s *ApiServer) featureFlagsService() types.FeatureFlagsService {
+	if s.featureFlags == nil {
+		return services.NewFeatureFlagsService(nil)
+	}
+	return s.featureFlags
+}
+
 // initDatabase opens the long-lived connection pool and pings it so a
 // misconfigured or unreachable database aborts startup. Migrations are NOT run
 // here: they are applied out-of-band via the `migrate` subcommand (a deploy Job
@@ -175,6 +402,26 @@ func (s *ApiServer) closeServices() {
 	}
 }
 
+// selectBalanceSources picks the balances source per network: wallet-backend
+// where the wallet-backend routes are enabled and that network has a URL and
+// signing key, Horizon everywhere else.
+func (s *ApiServer) selectBalanceSources(horizonBalances types.AccountBalancesSource) services.NetworkBalanceSources {
+	wb := s.cfg.WalletBackendConfig
+	configured := map[string]bool{
+		types.PUBLIC:  wb.PubnetUrl != "" && wb.PubnetSigningKey != "",
+		types.TESTNET: wb.TestnetUrl != "" && wb.TestnetSigningKey != "",
+	}
+	sources := services.NetworkBalanceSources{}
+	for network, ok := range configured {
+		if s.cfg.AppConfig.WalletBackendRoutesEnabled && ok {
+			sources[network] = s.walletBackendService
+		} else {
+			sources[network] = horizonBalances
+		}
+	}
+	return sources
+}
+
 // route describes a single registered API endpoint. It is the single source of
 // truth consumed by both initHandlers (which registers it) and the strict-mode
 // gating guard test (which enumerates it): gated routes are wrapped in the Auth
@@ -193,6 +440,13 @@ type route struct {
 	// on config) but is skipped by initHandlers, leaving its path to 404. Config-
 	// driven toggles belong here; everything permanently registered declares true.
 	enabled bool
+	// flag names the feature flag that switches a registered route on and off
+	// per request, for percentage rollouts and kill switches that take no
+	// restart. While the flag is off for a caller the route 404s, as if
+	// unregistered. Routes stay on until their flag is defined. The gate runs
+	// inside Auth, so it sees the user ID and leaves strict-mode 401s as they
+	// are. Empty for routes that must never be switched off this way.
+	flag string
 }
 
 // routes builds the full endpoint table. It constructs each handler with its
@@ -204,20 +458,24 @@ func (s *ApiServer) routes() ([]route, error) {
 	// boxed in the interface is not == nil, which would defeat the handler's
 	// disabled check.
 	var dbPinger handlers.DBPinger
+	var blockaidReports types.BlockaidReportStore
 	if s.dbPool != nil {
 		dbPinger = s.dbPool
+		blockaidReports = store.NewBlockaidReportsStore(s.dbPool)
 	}
 
 	healthHandler := handlers.NewHealthHandler()
 	rpcHealthHandler := handlers.NewRPCHealthHandler(s.rpcService)
 	dbHealthHandler := handlers.NewDBHealthHandler(dbPinger)
 
-	protocolsHandler := handlers.NewProtocolsHandler(s.cfg.AppConfig.ProtocolsConfigPath)
-	collectiblesHandler := handlers.NewCollectiblesHandler(s.rpcService, s.cfg.AppConfig.MeridianPayTreasureHuntAddress, s.cfg.AppConfig.MeridianPayTreasurePoapAddress, s.cfg.AppConfig.MeridianPayStellarHouseAddress, s.cfg.RpcConfig.MaxConcurrentRPCCalls)
+	protocolsHandler := handlers.NewProtocolsHandler(s.protocolCatalog)
+	collectiblesHandler := handlers.NewCollectiblesHandler(s.rpcService, s.collectionRegistry, s.cfg.RpcConfig.MaxConcurrentRPCCalls)
+	collectiblesHandler.MetadataService = s.collectibleMetadata
+	collectiblesHandler.MaxDiscoveredTokens = s.cfg.CollectiblesConfig.MaxDiscoveredTokens
 	ledgerKeyAccountsHandler := handlers.NewLedgerKeyAccountHandler(s.rpcService, s.cfg.AppConfig.MaxLedgerKeyAddresses)
-	featureFlagsHandler := handlers.NewFeatureFlagsHandler()
-	accountBalancesHandler := handlers.NewAccountBalancesHandler(s.walletBackendService, s.cfg.AppConfig.MaxBalanceAddresses)
-	tokenPricesHandler := handlers.NewTokenPricesHandler(s.pricesService, s.cfg.PricesConfig.MaxTokensPerRequest)
+	featureFlagsHandler := handlers.NewFeatureFlagsHandler(s.featureFlagsService())
+	accountBalancesHandler := handlers.NewAccountBalancesHandler(s.balanceSources, s.cfg.AppConfig.MaxBalanceAddresses)
+	tokenPricesHandler := handlers.NewTokenPricesHandler(s.pricesService, s.fxRatesService, s.cfg.PricesConfig.MaxTokensPerRequest)
 	accountHistoryHandler, err := handlers.NewAccountHistoryHandler(
 		s.walletBackendService,
 		s.cfg.AppConfig.AccountHistoryDefaultLimit,
@@ -227,6 +485,11 @@ func (s *ApiServer) routes() ([]route, error) {
 		return nil, fmt.Errorf("init account-history handler: %w", err)
 	}
 	whoamiHandler := handlers.NewWhoamiHandler()
+	blockaidHandler := handlers.NewBlockaidHandler(s.blockaidService, blockaidReports)
+	onrampHandler := handlers.NewOnrampHandler(s.coinbaseService)
+	transactionsHandler := handlers.NewTransactionsHandler(s.rpcService)
+	contractCallsHandler := handlers.NewContractCallsHandler(s.rpcService, s.cfg.AppConfig.MaxContractCalls, s.cfg.RpcConfig.MaxConcurrentRPCCalls)
+	tokenDetailsHandler := handlers.NewTokenDetailsHandler(s.tokenDetailsService, s.cfg.AppConfig.MaxTokenDetailsContracts, s.cfg.RpcConfig.MaxConcurrentRPCCalls)
 
 	return []route{
 		// Health/liveness/readiness probes: gated=false, registered BARE — never
@@ -234,35 +497,49 @@ func (s *ApiServer) routes() ([]route, error) {
 		// per-request JWTs, and db-health is designed never to fail the request;
 		// gating any of these would 401 probes under `--auth-mode strict` and cause
 		// pod churn.
-		{http.MethodGet, "/api/v1/ping", handlers.CustomHandler(healthHandler.CheckHealth), false, true},
-		{http.MethodGet, "/api/v1/rpc-health", handlers.CustomHandler(rpcHealthHandler.CheckRPCHealth), false, true},
-		{http.MethodGet, "/api/v1/db-health", handlers.CustomHandler(dbHealthHandler.CheckDBHealth), false, true},
+		{http.MethodGet, "/api/v1/ping", handlers.CustomHandler(healthHandler.CheckHealth), false, true, ""},
+		{http.MethodGet, "/api/v1/rpc-health", handlers.CustomHandler(rpcHealthHandler.CheckRPCHealth), false, true, ""},
+		{http.MethodGet, "/api/v1/db-health", handlers.CustomHandler(dbHealthHandler.CheckDBHealth), false, true, ""},
 
 		// User-facing routes: gated=true, wrapped in the shared Auth middleware.
 		// Flipping --auth-mode permissive<->strict moves all of these together.
 		// whoami reads the user ID from context and reports authenticated:false when
-		// absent (permissive anonymous).
-		{http.MethodGet, "/api/v1/protocols", handlers.CustomHandler(protocolsHandler.GetProtocols), true, true},
-		{http.MethodPost, "/api/v1/collectibles", handlers.CustomHandler(collectiblesHandler.GetCollectibles), true, true},
-		{http.MethodPost, "/api/v1/ledger-key/accounts", handlers.CustomHandler(ledgerKeyAccountsHandler.GetLedgerKeyAccounts), true, true},
-		{http.MethodGet, "/api/v1/feature-flags", handlers.CustomHandler(featureFlagsHandler.GetFeatureFlags), true, true},
-		// The wallet-backend-fronted routes, config-gated together by
-		// --wallet-backend-routes-enabled. These are the ONLY two routes that touch
-		// walletBackendService, and both fail identically when it is unconfigured:
-		// configureNetworkClient returns nil and the handler errors before any network
-		// call, so every request 500s. wallet-backend is configured only in dev, so
-		// they are disabled in production until that upstream is wired up.
-		// enabled=false leaves both paths 404ing.
-		//
-		// They share one flag deliberately: they share one dependency and one failure
-		// mode, so there is no state where enabling exactly one is correct. If a route
-		// is ever added here that can work without wallet-backend, give it its own
-		// gate rather than widening this one.
-		{http.MethodPost, "/api/v1/accounts/balances", handlers.CustomHandler(accountBalancesHandler.GetAccountBalances), true, s.cfg.AppConfig.WalletBackendRoutesEnabled},
-		{http.MethodGet, "/api/v1/accounts/{address}/transactions", handlers.CustomHandler(accountHistoryHandler.GetAccountTransactions), true, s.cfg.AppConfig.WalletBackendRoutesEnabled},
-
-		{http.MethodPost, "/api/v1/token-prices", handlers.CustomHandler(tokenPricesHandler.GetPrices), true, true},
-		{http.MethodGet, "/api/v1/auth/whoami", handlers.CustomHandler(whoamiHandler.Whoami), true, true},
+		// absent (permissive anonymous). Each carries a route_* flag except
+		// feature-flags, which clients need to learn what else is off, and whoami.
+		{http.MethodGet, "/api/v1/protocols", handlers.CustomHandler(protocolsHandler.GetProtocols), true, true, "route_protocols"},
+		{http.MethodPost, "/api/v1/collectibles", handlers.CustomHandler(collectiblesHandler.GetCollectibles), true, true, "route_collectibles"},
+		{http.MethodPost, "/api/v1/ledger-key/accounts", handlers.CustomHandler(ledgerKeyAccountsHandler.GetLedgerKeyAccounts), true, true, "route_ledger_key_accounts"},
+		{http.MethodGet, "/api/v1/feature-flags", handlers.CustomHandler(featureFlagsHandler.GetFeatureFlags), true, true, ""},
+		// Balances always register: each network reads from wallet-backend when it
+		// is enabled and configured for that network, and from Horizon otherwise
+		// (see balanceSources).
+		{http.MethodPost, "/api/v1/accounts/balances", handlers.CustomHandler(accountBalancesHandler.GetAccountBalances), true, true, "route_account_balances"},
+		// Account history has no Horizon equivalent, so it is config-gated by
+		// --wallet-backend-routes-enabled. Without a wallet-backend client
+		// configureNetworkClient returns nil and every request 500s, so production
+		// leaves it disabled (404) until that upstream is wired up.
+		{http.MethodGet, "/api/v1/accounts/{address}/transactions", handlers.CustomHandler(accountHistoryHandler.GetAccountTransactions), true, s.cfg.AppConfig.WalletBackendRoutesEnabled, "route_account_history"},
+
+		{http.MethodPost, "/api/v1/token-prices", handlers.CustomHandler(tokenPricesHandler.GetPrices), true, true, "route_token_prices"},
+		{http.MethodGet, "/api/v1/token-prices/{token}/history", handlers.CustomHandler(tokenPricesHandler.GetPriceHistory), true, true, "route_token_price_history"},
+		{http.MethodPost, "/api/v1/token-details", handlers.CustomHandler(tokenDetailsHandler.GetTokenDetails), true, true, "route_token_details"},
+		{http.MethodPost, "/api/v1/contract-calls", handlers.CustomHandler(contractCallsHandler.GetContractCalls), true, true, "route_contract_calls"},
+		{http.MethodGet, "/api/v1/auth/whoami", handlers.CustomHandler(whoamiHandler.Whoami), true, true, ""},
+		{http.MethodPost, "/api/v1/simulate-tx", handlers.CustomHandler(transactionsHandler.SimulateTx), true, true, "route_simulate_tx"},
+		{http.MethodPost, "/api/v1/submit-tx", handlers.CustomHandler(transactionsHandler.SubmitTx), true, true, "route_submit_tx"},
+		{http.MethodGet, "/api/v1/tx/{hash}/status", handlers.CustomHandler(transactionsHandler.GetTxStatus), true, true, "route_tx_status"},
+
+		// Blockaid-backed routes, each switched on by its own --use-blockaid-* flag
+		// (all default off). serve refuses to boot with any of them on and no
+		// --blockaid-api-key, so an enabled route always has a usable client.
+		{http.MethodPost, "/api/v1/scan-tx", handlers.CustomHandler(blockaidHandler.ScanTx), true, s.cfg.BlockaidConfig.UseBlockaidTxScanning, "route_scan_tx"},
+		{http.MethodPost, "/api/v1/scan-dapp", handlers.CustomHandler(blockaidHandler.ScanDapp), true, s.cfg.BlockaidConfig.UseBlockaidDappScanning, "route_scan_dapp"},
+		{http.MethodPost, "/api/v1/scan-assets", handlers.CustomHandler(blockaidHandler.ScanAssets), true, s.cfg.BlockaidConfig.UseBlockaidAssetScanning, "route_scan_assets"},
+		{http.MethodPost, "/api/v1/report-asset-warning", handlers.CustomHandler(blockaidHandler.ReportAssetWarning), true, s.cfg.BlockaidConfig.UseBlockaidAssetWarningReporting, "route_report_asset_warning"},
+		{http.MethodPost, "/api/v1/report-transaction-warning", handlers.CustomHandler(blockaidHandler.ReportTransactionWarning), true, s.cfg.BlockaidConfig.UseBlockaidTransactionWarningReporting, "route_report_transaction_warning"},
+
+		// Registered only with both CDP credentials set; serve rejects half a pair.
+		{http.MethodPost, "/api/v1/onramp/token", handlers.CustomHandler(onrampHandler.GetSessionToken), true, s.cfg.CoinbaseConfig.Configured(), "route_onramp_token"},
 	}, nil
 }
 
@@ -277,6 +554,7 @@ func (s *ApiServer) initHandlers() (*http.ServeMux, error) {
 	// to routes() with gated=true.
 	verifier := auth.NewVerifier(s.cfg.AppConfig.AuthClockSkewLeeway)
 	authed := middleware.Auth(verifier, s.authMode, s.appMetrics.Auth)
+	featureFlags := s.featureFlagsService()
 
 	mux := http.NewServeMux()
 	for _, rt := range rts {
@@ -287,6 +565,9 @@ func (s *ApiServer) initHandlers() (*http.ServeMux, error) {
 			continue
 		}
 		h := rt.handler
+		if rt.flag != "" {
+			h = middleware.FeatureGate(featureFlags, rt.flag)(h)
+		}
 		if rt.gated {
 			h = authed(h)
 		}
@@ -302,6 +583,29 @@ func (s *ApiServer) initMetricsHandler() http.Handler {
 	return mux
 }
 
+// initAdminHandler builds the admin API, or returns nil when --admin-port is
+// unset. Every route requires an admin credential; user JWTs are not accepted.
+func (s *ApiServer) initAdminHandler() http.Handler {
+	if s.cfg.AppConfig.AdminPort == 0 || s.dbPool == nil {
+		return nil
+	}
+	admin := handlers.NewAdminHandler(store.NewAdminStore(s.dbPool), s.protocolRules())
+	mux := http.NewServeMux()
+	mux.Handle("GET /admin/v1/feature-flags", handlers.CustomHandler(admin.ListFeatureFlags))
+	mux.Handle("PUT /admin/v1/feature-flags/{key}", handlers.CustomHandler(admin.PutFeatureFlag))
+	mux.Handle("DELETE /admin/v1/feature-flags/{key}", handlers.CustomHandler(admin.DeleteFeatureFlag))
+	mux.Handle("GET /admin/v1/protocols", handlers.CustomHandler(admin.ListProtocols))
+	mux.Handle("PUT /admin/v1/protocols/{name}", handlers.CustomHandler(admin.PutProtocol))
+	mux.Handle("DELETE /admin/v1/protocols/{name}", handlers.CustomHandler(admin.DeleteProtocol))
+	mux.Handle("GET /admin/v1/audit-log", handlers.CustomHandler(admin.ListAuditLog))
+	return middleware.Chain(mux,
+		middleware.Recover(),
+		middleware.BodySizeLimit(s.cfg.AppConfig.MaxRequestBodySize),
+		middleware.Logging(),
+		middleware.AdminAuth(s.cfg.AppConfig.AdminCredentials),
+	)
+}
+
 func (s *ApiServer) initMiddleware(mux *http.ServeMux) http.Handler {
 	middlewares := []middleware.Middleware{
 		middleware.Recover(),
@@ -316,7 +620,9 @@ func (s *ApiServer) initMiddleware(mux *http.ServeMux) http.Handler {
 	return handler
 }
 
-func (s *ApiServer) startServers(apiHandler http.Handler, metricsHandler http.Handler) error {
+// startServers runs the API and metrics servers, and the admin server when
+// adminHandler is not nil, until a signal or the first server failure.
+func (s *ApiServer) startServers(apiHandler, metricsHandler, adminHandler http.Handler) error {
 	apiServer := &http.Server{
 		Addr:         fmt.Sprintf("%s:%d", s.cfg.AppConfig.FreighterBackendHost, s.cfg.AppConfig.FreighterBackendPort),
 		Handler:      apiHandler,
@@ -332,8 +638,19 @@ func (s *ApiServer) startServers(apiHandler http.Handler, metricsHandler http.Ha
 		IdleTimeout:  DefaultIdleTimeout,
 	}
 
-	// errgroup: if either ListenAndServe returns an unexpected error, ctx is
-	// canceled so we tear down the surviving server and surface the failure.
+	var adminServer *http.Server
+	if adminHandler != nil {
+		adminServer = &http.Server{
+			Addr:         fmt.Sprintf("%s:%d", s.cfg.AppConfig.AdminHost, s.cfg.AppConfig.AdminPort),
+			Handler:      adminHandler,
+			ReadTimeout:  DefaultReadTimeout,
+			WriteTimeout: DefaultWriteTimeout,
+			IdleTimeout:  DefaultIdleTimeout,
+		}
+	}
+
+	// errgroup: if any ListenAndServe returns an unexpected error, ctx is
+	// canceled so we tear down the surviving servers and surface the failure.
 	g, ctx := errgroup.WithContext(context.Background())
 	g.Go(func() error {
 		logger.Info("Starting API server", "address", apiServer.Addr)
@@ -349,6 +666,15 @@ func (s *ApiServer) startServers(apiHandler http.Handler, metricsHandler http.Ha
 		}
 		return nil
 	})
+	if adminServer != nil {
+		g.Go(func() error {
+			logger.Info("Starting admin server", "address", adminServer.Addr)
+			if err := adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				return fmt.Errorf("admin server: %w", err)
+			}
+			return nil
+		})
+	}
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -369,6 +695,11 @@ func (s *ApiServer) startServers(apiHandler http.Handler, metricsHandler http.Ha
 	if err := metricsServer.Shutdown(shutdownCtx); err != nil {
 		logger.Error("Metrics server forced to shutdown", "error", err)
 	}
+	if adminServer != nil {
+		if err := adminServer.Shutdown(shutdownCtx); err != nil {
+			logger.Error("Admin server forced to shutdown", "error", err)
+		}
+	}
 
 	if err := g.Wait(); err != nil {
 		return err
@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/testutil"
@@ -24,26 +25,46 @@ import (
 	"github.com/stellar/freighter-backend-v2/internal/auth/authtest"
 	"github.com/stellar/freighter-backend-v2/internal/config"
 	"github.com/stellar/freighter-backend-v2/internal/metrics"
+	"github.com/stellar/freighter-backend-v2/internal/services"
 	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils"
 )
 
 // testCfg returns the standard handler-test config, varying only the auth mode.
 // Most initHandlers tests differ from each other only in AuthMode, so they share
 // this literal rather than each rebuilding it inline.
 func testCfg(authMode string) *config.Config {
-	return &config.Config{AppConfig: config.AppConfig{
-		ProtocolsConfigPath:        "testdata/protocols.json",
-		AccountHistoryDefaultLimit: 20,
-		AccountHistoryMaxLimit:     100,
-		AuthMode:                   authMode,
-		// Mirrors the --wallet-backend-routes-enabled default (true). Without this the
-		// zero-value false would leave the balances and account-history routes
-		// unregistered, and AllUserFacingRoutesGatedInStrict — which probes every
-		// gated route in routes() for a 401 — would see a 404 and fail in a way that
-		// looks like an auth regression. Tests that want the off state set it false
-		// explicitly (see WalletBackendRoutesDisabledNotRegistered).
-		WalletBackendRoutesEnabled: true,
-	}}
+	return &config.Config{
+		AppConfig: config.AppConfig{
+			ProtocolsConfigPath:        "testdata/protocols.json",
+			AccountHistoryDefaultLimit: 20,
+			AccountHistoryMaxLimit:     100,
+			AuthMode:                   authMode,
+			// Mirrors the --wallet-backend-routes-enabled default (true). Without this the
+			// zero-value false would leave the account-history route unregistered, and
+			// AllUserFacingRoutesGatedInStrict — which probes every gated route in
+			// routes() for a 401 — would see a 404 and fail in a way that looks like an
+			// auth regression. Tests that want the off state set it false explicitly
+			// (see WalletBackendRoutesDisabledNotRegistered).
+			WalletBackendRoutesEnabled: true,
+		},
+		// The --use-blockaid-* flags default off; they are on here for the same
+		// reason, so the strict-mode guard probes the scan routes too (see
+		// BlockaidRoutesDisabledNotRegistered for the off state).
+		BlockaidConfig: config.BlockaidConfig{
+			UseBlockaidTxScanning:                  true,
+			UseBlockaidDappScanning:                true,
+			UseBlockaidAssetScanning:               true,
+			UseBlockaidAssetWarningReporting:       true,
+			UseBlockaidTransactionWarningReporting: true,
+		},
+		// Likewise for the onramp route, which registers only with CDP
+		// credentials. initHandlers never parses them, so placeholders suffice.
+		CoinbaseConfig: config.CoinbaseConfig{
+			CoinbaseAPIKey:    "test-key",
+			CoinbaseAPISecret: "test-secret",
+		},
+	}
 }
 
 // wildcardSegment matches a ServeMux path wildcard like {address} so tests can
@@ -98,6 +119,28 @@ func TestApiServer_initServices_AcceptsAPIKey(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestApiServer_initServices_RegistersDeprecatedMeridianPayAddresses(t *testing.T) {
+	const contract = "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC"
+	s := &ApiServer{
+		cfg: &config.Config{
+			PricesConfig:       config.PricesConfig{StellarExpertAPIKey: "test-key"},
+			AppConfig:          config.AppConfig{WalletBackendBalanceConcurrency: 10},
+			CollectiblesConfig: config.CollectiblesConfig{MeridianPayPoapAddress: contract, MeridianPayStellarHouseAddress: contract},
+		},
+		appMetrics: metrics.NewMetrics(prometheus.NewRegistry()),
+	}
+	require.NoError(t, s.initServices())
+	require.NotNil(t, s.collectionRegistry)
+
+	for _, network := range []string{types.PUBLIC, types.TESTNET, types.FUTURENET} {
+		got, err := s.collectionRegistry.Collections(context.Background(), network)
+		require.NoError(t, err)
+		require.Len(t, got, 1, network)
+		assert.Equal(t, contract, got[0].ContractID)
+		assert.Equal(t, types.CollectionEnumerationOwnerTokens, got[0].Enumeration)
+	}
+}
+
 func TestApiServer_initServices_RejectsNonPositiveBalanceConcurrency(t *testing.T) {
 	for _, n := range []int{0, -1} {
 		s := &ApiServer{
@@ -164,6 +207,97 @@ func TestApiServer_initMetricsHandler_RejectsOtherPaths(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, rec.Code, "metrics server must only expose /metrics")
 }
 
+func TestApiServer_initAdminHandler(t *testing.T) {
+	s := newTestAPIServer(t, testCfg("permissive"))
+	assert.Nil(t, s.initAdminHandler(), "no admin listener without --admin-port")
+
+	pool, err := pgxpool.New(context.Background(), "postgres://localhost:1/unused")
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+	s.dbPool = pool
+	s.cfg.AppConfig.AdminPort = 9091
+	s.cfg.AppConfig.AdminCredentials = map[string]string{"alice": "alice-token"}
+	handler := s.initAdminHandler()
+	require.NotNil(t, handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/v1/feature-flags", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, "admin routes require an admin credential")
+
+	// The admin API is never reachable on the public listener.
+	mux, err := s.initHandlers()
+	require.NoError(t, err)
+	req = httptest.NewRequest(http.MethodGet, "/admin/v1/feature-flags", nil)
+	req.Header.Set("Authorization", "Bearer alice-token")
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+// TestApiServer_routes_FlagKeys checks every route flag is a valid flag key and
+// names one route only, so defining a flag can never switch off a route it
+// wasn't meant for.
+func TestApiServer_routes_FlagKeys(t *testing.T) {
+	rts, err := newTestAPIServer(t, testCfg("permissive")).routes()
+	require.NoError(t, err)
+
+	seen := map[string]string{}
+	for _, rt := range rts {
+		if rt.flag == "" {
+			continue
+		}
+		require.NoError(t, (&types.FeatureFlag{Key: rt.flag}).Validate(), rt.pattern)
+		assert.Empty(t, seen[rt.flag], "%s is also the flag of %s", rt.flag, seen[rt.flag])
+		seen[rt.flag] = rt.pattern
+		assert.True(t, rt.gated, "%s: a flag gate needs Auth in front of it to see the user ID", rt.pattern)
+	}
+	assert.Contains(t, seen, "route_account_balances")
+}
+
+// TestApiServer_initHandlers_RouteFlagsSwitchRoutesPerRequest flips a route
+// through its flag without rebuilding the mux: a kill switch 404s everyone, and
+// a percentage rollout serves only the users in it.
+func TestApiServer_initHandlers_RouteFlagsSwitchRoutesPerRequest(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	userID := hex.EncodeToString(pub)
+
+	source := &utils.MockFeatureFlagSource{}
+	s := newTestAPIServer(t, testCfg("permissive"))
+	s.featureFlags = services.NewFeatureFlagsService(source)
+	mux, err := s.initHandlers()
+	require.NoError(t, err)
+
+	const path = "/api/v1/tx/probe/status"
+	probe := func(authenticated bool) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if authenticated {
+			req.Header.Set("Authorization", "Bearer "+authtest.MintToken(t, priv, userID, "GET "+path, auth.MaxTokenLifetime, time.Now(), nil))
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	// The handler 400s on the missing network, which proves the request got
+	// past the gate.
+	const served, gatedOff = http.StatusBadRequest, http.StatusNotFound
+
+	assert.Equal(t, served, probe(false), "an undefined flag leaves the route on")
+
+	source.Flags = []types.FeatureFlag{{Key: "route_tx_status", Enabled: false}}
+	assert.Equal(t, gatedOff, probe(false), "kill switch")
+	assert.Equal(t, gatedOff, probe(true), "kill switch")
+
+	// Roll out to exactly the buckets below and including this user's.
+	pct := types.RolloutBucket("route_tx_status", userID) + 1
+	source.Flags = []types.FeatureFlag{{Key: "route_tx_status", Rules: []types.FlagRule{{Percentage: &pct, Enabled: true}}}}
+	assert.Equal(t, served, probe(true), "a user inside the rollout is served")
+	assert.Equal(t, gatedOff, probe(false), "anonymous callers are outside a partial rollout")
+	pct--
+	assert.Equal(t, gatedOff, probe(true), "a user outside the rollout is not")
+}
+
 func TestApiServer_initMiddleware(t *testing.T) {
 	s := &ApiServer{
 		cfg:        &config.Config{},
@@ -187,7 +321,7 @@ func TestApiServer_initHandlers_RegistersAccountHistoryRoutes(t *testing.T) {
 }
 
 // walletBackendRoutes is every route gated by --wallet-backend-routes-enabled.
-// Both tests below iterate it, so adding a third wallet-backend-fronted route
+// Both tests below iterate it, so adding another wallet-backend-only route
 // extends the on/off coverage by one line here rather than being silently missed.
 // The {address} wildcard is pre-substituted: auth and registration both run before
 // path-parameter validation, so any non-empty segment reaches the assertion.
@@ -196,7 +330,6 @@ var walletBackendRoutes = []struct {
 	method string
 	path   string
 }{
-	{"balances", http.MethodPost, "/api/v1/accounts/balances"},
 	{"account-history", http.MethodGet, "/api/v1/accounts/GBTYAFHGNZSTE4VBWZYAGB3SRGJEPTI5I4Y22KZ4JTVAN56LESB6JZOF/transactions"},
 }
 
@@ -249,11 +382,11 @@ func TestApiServer_initHandlers_WalletBackendRoutesEnabledStayGated(t *testing.T
 	}
 }
 
-// TestApiServer_initHandlers_WalletBackendRoutesGatedTogether pins the "one flag,
-// both routes" decision. The two share a dependency and a failure mode, so a change
-// that gated only one — leaving the other publicly 500ing in prd, which is the exact
-// bug this flag exists to close — would otherwise pass every test above.
-func TestApiServer_initHandlers_WalletBackendRoutesGatedTogether(t *testing.T) {
+// TestApiServer_initHandlers_WalletBackendFlagGatesOnlyHistory pins which routes
+// the flag removes. Balances fall back to Horizon without wallet-backend, so
+// gating them too would put prd back to 404ing the route this fallback exists
+// to serve; account history has no fallback and must stay gated.
+func TestApiServer_initHandlers_WalletBackendFlagGatesOnlyHistory(t *testing.T) {
 	cfg := testCfg("permissive")
 	cfg.AppConfig.WalletBackendRoutesEnabled = false
 
@@ -269,9 +402,81 @@ func TestApiServer_initHandlers_WalletBackendRoutesGatedTogether(t *testing.T) {
 	}
 
 	assert.Equal(t, map[string]bool{
-		"POST /api/v1/accounts/balances":              true,
 		"GET /api/v1/accounts/{address}/transactions": true,
-	}, disabled, "exactly the wallet-backend-fronted routes must be disabled by the flag")
+	}, disabled, "only account history may be disabled by the flag")
+
+	mux, err := s.initHandlers()
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/accounts/balances", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "balances must stay registered (and reject the missing network) with the flag off")
+}
+
+func TestApiServer_selectBalanceSources(t *testing.T) {
+	horizon := &utils.MockWalletBackendService{}
+	wb := &utils.MockWalletBackendService{}
+
+	tests := []struct {
+		name        string
+		enabled     bool
+		wbConfig    config.WalletBackendConfig
+		wantPubnet  types.AccountBalancesSource
+		wantTestnet types.AccountBalancesSource
+	}{
+		{"flag off uses horizon everywhere", false, config.WalletBackendConfig{PubnetUrl: "u", PubnetSigningKey: "k", TestnetUrl: "u", TestnetSigningKey: "k"}, horizon, horizon},
+		{"per-network wallet-backend", true, config.WalletBackendConfig{TestnetUrl: "u", TestnetSigningKey: "k"}, horizon, wb},
+		{"url without key falls back", true, config.WalletBackendConfig{PubnetUrl: "u"}, horizon, horizon},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := testCfg("permissive")
+			cfg.AppConfig.WalletBackendRoutesEnabled = tc.enabled
+			cfg.WalletBackendConfig = tc.wbConfig
+			s := newTestAPIServer(t, cfg)
+			s.walletBackendService = wb
+
+			sources := s.selectBalanceSources(horizon)
+			assert.Same(t, tc.wantPubnet, sources[types.PUBLIC])
+			assert.Same(t, tc.wantTestnet, sources[types.TESTNET])
+		})
+	}
+}
+
+// TestApiServer_initHandlers_BlockaidRoutesDisabledNotRegistered pins the default
+// off state of each --use-blockaid-* flag: a disabled scan route is absent from
+// the mux and 404s, rather than reaching a client with no API key.
+func TestApiServer_initHandlers_BlockaidRoutesDisabledNotRegistered(t *testing.T) {
+	cfg := testCfg("permissive")
+	cfg.BlockaidConfig = config.BlockaidConfig{}
+
+	mux, err := newTestAPIServer(t, cfg).initHandlers()
+	require.NoError(t, err)
+
+	for _, path := range []string{
+		"/api/v1/scan-tx",
+		"/api/v1/scan-dapp",
+		"/api/v1/scan-assets",
+		"/api/v1/report-asset-warning",
+		"/api/v1/report-transaction-warning",
+	} {
+		t.Run(path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+			assert.Equal(t, http.StatusNotFound, rec.Code, "a disabled blockaid route must 404")
+		})
+	}
+}
+
+func TestApiServer_initHandlers_OnrampRouteRequiresCredentials(t *testing.T) {
+	cfg := testCfg("permissive")
+	cfg.CoinbaseConfig = config.CoinbaseConfig{}
+
+	mux, err := newTestAPIServer(t, cfg).initHandlers()
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/onramp/token", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code, "the onramp route must not register without CDP credentials")
 }
 
 func TestApiServer_initHandlers_WhoamiRouteRespectsAuthMode(t *testing.T) {
@@ -363,6 +568,18 @@ func (stubRPCService) GetLedgerEntries(ctx context.Context, keys []string, netwo
 	return nil, nil
 }
 
+func (stubRPCService) SimulateTransaction(ctx context.Context, envelopeXDR string, network string) (*types.SimulationResult, error) {
+	return nil, nil
+}
+
+func (stubRPCService) SubmitTransaction(ctx context.Context, envelopeXDR string, network string) (*types.TxStatus, error) {
+	return nil, nil
+}
+
+func (stubRPCService) GetTransactionStatus(ctx context.Context, hash string, network string) (*types.TxStatus, error) {
+	return nil, nil
+}
+
 func TestApiServer_initHandlers_HealthRoutesAnonymousInStrict(t *testing.T) {
 	s := newTestAPIServer(t, testCfg("strict"))
 	s.rpcService = stubRPCService{} // so /rpc-health can be invoked without a live RPC
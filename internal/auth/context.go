@@ -21,3 +21,18 @@ func UserIDFromContext(ctx context.Context) (string, bool) {
 	id, ok := ctx.Value(userIDKey).(string)
 	return id, ok
 }
+
+var adminActorKey = contextKey{name: "adminActor"}
+
+// ContextWithAdminActor returns a child context carrying the name of the admin
+// credential that authenticated the request.
+func ContextWithAdminActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, adminActorKey, actor)
+}
+
+// AdminActorFromContext returns the authenticated admin actor and whether one
+// was set.
+func AdminActorFromContext(ctx context.Context) (string, bool) {
+	actor, ok := ctx.Value(adminActorKey).(string)
+	return actor, ok
+}
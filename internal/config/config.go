@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"slices"
 	"time"
 )
 
@@ -16,15 +17,38 @@ type Config struct {
 	PricesConfig        PricesConfig
 	BlockaidConfig      BlockaidConfig
 	CoinbaseConfig      CoinbaseConfig
+	CollectiblesConfig  CollectiblesConfig
 	WalletBackendConfig WalletBackendConfig
 }
 
+// Values of AppConfig.ProtocolsSource.
+const (
+	ProtocolsSourceFile     = "file"
+	ProtocolsSourceDatabase = "database"
+)
+
+// Values of AppConfig.FeatureFlagsSource.
+const (
+	FeatureFlagsSourceBuiltin  = "builtin"
+	FeatureFlagsSourceFile     = "file"
+	FeatureFlagsSourceDatabase = "database"
+)
+
 type AppConfig struct {
 	FreighterBackendHost string
 	FreighterBackendPort int
 	MetricsHost          string
 	MetricsPort          int
-	Mode                 string
+	// AdminHost and AdminPort bind the admin API listener, which edits feature
+	// flags and protocols in the database. AdminPort 0 (the default) disables
+	// it.
+	AdminHost string
+	AdminPort int
+	// AdminCredentials maps an actor name to the bearer token it authenticates
+	// with on the admin listener (--admin-credentials name=token,...). The name
+	// is what the audit log records.
+	AdminCredentials map[string]string
+	Mode             string
 	// AuthMode selects JWT enforcement for gated routes: "permissive" (allow
 	// requests with no token through, reject invalid tokens) or "strict"
 	// (require a valid token). Parsed via auth.ParseMode; validated at startup.
@@ -33,30 +57,47 @@ type AppConfig struct {
 	// validation (--auth-clock-skew-leeway). Wider values tolerate more device
 	// clock drift but proportionally widen the token replay window. It does not
 	// affect signature verification. Defaults to auth.ClockSkewLeeway.
-	AuthClockSkewLeeway            time.Duration
-	SentryKey                      string
-	ProtocolsConfigPath            string
-	MeridianPayTreasureHuntAddress string
-	MeridianPayTreasurePoapAddress string
-	MeridianPayStellarHouseAddress string
-	MaxRequestBodySize             int64
-	MaxBalanceAddresses            int
-	MaxLedgerKeyAddresses          int
-	// WalletBackendRoutesEnabled controls whether the wallet-backend-fronted routes
-	// are registered (--wallet-backend-routes-enabled / env
-	// WALLET_BACKEND_ROUTES_ENABLED, default true):
-	//
-	//	POST /api/v1/accounts/balances
-	//	GET  /api/v1/accounts/{address}/transactions
+	AuthClockSkewLeeway time.Duration
+	SentryKey           string
+	ProtocolsConfigPath string
+	// ProtocolsSource selects where GET /api/v1/protocols reads the catalog
+	// (--protocols-source): ProtocolsSourceFile reads ProtocolsConfigPath,
+	// ProtocolsSourceDatabase serves a snapshot of the protocols table
+	// refreshed every ProtocolsRefreshInterval.
+	ProtocolsSource          string
+	ProtocolsRefreshInterval time.Duration
+	// ProtocolsAllowedTags is the tag vocabulary a protocols file must stick
+	// to (--protocols-allowed-tags); empty accepts any tag.
+	ProtocolsAllowedTags []string
+	// ProtocolsStrictValidation also requires each protocol to have a
+	// description and https URLs (--protocols-strict-validation).
+	ProtocolsStrictValidation bool
+	// FeatureFlagsSource selects where GET /api/v1/feature-flags reads its
+	// flags (--feature-flags-source): FeatureFlagsSourceBuiltin serves
+	// types.DefaultFeatureFlags, FeatureFlagsSourceFile reads
+	// FeatureFlagsPath and FeatureFlagsSourceDatabase the feature_flags table,
+	// both re-read every FeatureFlagsRefreshInterval.
+	FeatureFlagsSource          string
+	FeatureFlagsPath            string
+	FeatureFlagsRefreshInterval time.Duration
+	MaxRequestBodySize          int64
+	MaxBalanceAddresses         int
+	MaxLedgerKeyAddresses       int
+	// WalletBackendRoutesEnabled controls whether wallet-backend is used at all
+	// (--wallet-backend-routes-enabled / env WALLET_BACKEND_ROUTES_ENABLED, default
+	// true):
 	//
-	// When false neither is added to the mux, so both paths 404 exactly as an unknown
-	// path would: no handler runs and no wallet-backend call is attempted. Production
-	// sets it false while wallet-backend is unconfigured there — without a client both
-	// routes 500 on every request. Flipping it back on is an env-var change and a
-	// restart, not a release.
+	//	GET  /api/v1/accounts/{address}/transactions is registered only when true;
+	//	     when false the path 404s exactly as an unknown path would.
+	//	POST /api/v1/accounts/balances is always registered, and reads from
+	//	     wallet-backend only when this is true and the network has a
+	//	     wallet-backend URL and signing key; otherwise it is built from Horizon.
 	//
-	// One flag covers both because they share one dependency and one failure mode;
-	// enabling exactly one is never the correct state.
+	// Production sets it false while wallet-backend is unconfigured there — without
+	// a client account history 500s on every request. Flipping it back on is an
+	// env-var change and a restart, not a release. Once registered, the route can
+	// also be rolled out or killed per request, with no restart, through its
+	// route_account_history feature flag.
 	WalletBackendRoutesEnabled bool
 	// WalletBackendBalanceConcurrency caps the number of concurrent wallet-backend
 	// fetches per single /api/v1/accounts/balances request. The handler fans out to
@@ -72,6 +113,16 @@ type AppConfig struct {
 	// endpoint. Requests above it are rejected with 400. Must be > 0 and
 	// <= 100 (the wallet-backend upstream page-size cap).
 	AccountHistoryMaxLimit int
+	// MaxTokenDetailsContracts caps the contract IDs accepted in one
+	// POST /api/v1/token-details request (--max-token-details-contracts).
+	MaxTokenDetailsContracts int
+	// TokenBalanceCacheTTLSeconds bounds how long simulated SEP-41 balances
+	// are reused from Redis (--token-balance-cache-ttl-seconds). Zero disables
+	// balance caching; token metadata is always cached, without expiry.
+	TokenBalanceCacheTTLSeconds int
+	// MaxContractCalls caps the calls accepted in one
+	// POST /api/v1/contract-calls request (--max-contract-calls).
+	MaxContractCalls int
 }
 
 type RPCConfig struct {
@@ -145,10 +196,43 @@ type PricesConfig struct {
 	PriceFetchTimeoutSeconds  int
 	MaxTokensPerRequest       int
 	MaxConcurrentPriceFetches int
+	// PriceStaleTTLSeconds is how long past PriceCacheTTLSeconds a cached
+	// price is still served while it refreshes in the background; 0 disables
+	// stale serving.
+	PriceStaleTTLSeconds int
+	// HotPriceTokens is how many of each network's most requested tokens are
+	// refreshed ahead of expiry, checked every HotPriceRefreshInterval; 0
+	// disables the refresher.
+	HotPriceTokens          int
+	HotPriceRefreshInterval time.Duration
+	// PriceProviders are the spot price sources in priority order
+	// (--price-providers): services.PriceProviderStellarExpert and/or
+	// services.PriceProviderFeed.
+	PriceProviders []string
+	// PriceAggregation combines provider quotes (--price-aggregation):
+	// services.PriceAggregationFirstSuccess or services.PriceAggregationMedian.
+	PriceAggregation string
+	// PriceFeedPubnetURL / PriceFeedTestnetURL are the per-network endpoints
+	// of the HTTP price feed provider; PriceFeedAPIKey, when set, is sent as
+	// a bearer token.
+	PriceFeedPubnetURL  string
+	PriceFeedTestnetURL string
+	PriceFeedAPIKey     string
+	// FXRatesURL is the Frankfurter API used to convert prices for the
+	// `currency` param; FXRatesCacheTTLSeconds is how long its rates table
+	// is cached.
+	FXRatesURL             string
+	FXRatesCacheTTLSeconds int
 }
 
 type BlockaidConfig struct {
-	BlockaidAPIKey                         string
+	// BlockaidBaseURL is the Blockaid API host (--blockaid-base-url). Empty
+	// falls back to services.DefaultBlockaidBaseURL.
+	BlockaidBaseURL string
+	BlockaidAPIKey  string
+	// BlockaidCacheTTLSeconds bounds how long dApp and asset verdicts are
+	// reused from Redis (--blockaid-cache-ttl-seconds). Zero disables caching.
+	BlockaidCacheTTLSeconds                int
 	UseBlockaidDappScanning                bool
 	UseBlockaidTxScanning                  bool
 	UseBlockaidAssetScanning               bool
@@ -156,11 +240,87 @@ type BlockaidConfig struct {
 	UseBlockaidTransactionWarningReporting bool
 }
 
+// ReportingEnabled reports whether either warning-reporting route is switched
+// on. Reports are persisted, so these routes need the database.
+func (c BlockaidConfig) ReportingEnabled() bool {
+	return c.UseBlockaidAssetWarningReporting || c.UseBlockaidTransactionWarningReporting
+}
+
+// AnyEnabled reports whether any Blockaid-backed route is switched on, in which
+// case an API key is required at boot.
+func (c BlockaidConfig) AnyEnabled() bool {
+	return c.UseBlockaidDappScanning || c.UseBlockaidTxScanning || c.UseBlockaidAssetScanning ||
+		c.UseBlockaidAssetWarningReporting || c.UseBlockaidTransactionWarningReporting
+}
+
 type CoinbaseConfig struct {
+	// CoinbaseBaseURL is the CDP API host (--coinbase-base-url). Empty falls
+	// back to services.DefaultCoinbaseBaseURL.
+	CoinbaseBaseURL   string
 	CoinbaseAPIKey    string
 	CoinbaseAPISecret string
 }
 
+// Configured reports whether both CDP credentials are set. The onramp route
+// is registered only when they are.
+func (c CoinbaseConfig) Configured() bool {
+	return c.CoinbaseAPIKey != "" && c.CoinbaseAPISecret != ""
+}
+
+// CollectiblesConfig controls collection discovery and SEP-50 metadata
+// resolution for POST /api/v1/collectibles.
+type CollectiblesConfig struct {
+	// CollectionsRegistryPath is a JSON file listing the collections whose
+	// tokens are discovered for the owner (--collections-registry-path). When
+	// empty, the collections table is used instead if the database is enabled;
+	// otherwise discovery is off and only the contracts a client lists are
+	// fetched.
+	CollectionsRegistryPath string
+	// MeridianPayTreasureHuntAddress, MeridianPayPoapAddress and
+	// MeridianPayStellarHouseAddress are deprecated (--meridian-pay-*). Each
+	// one set is discovered through get_owner_tokens on every network, as it
+	// was before the registry existed. They can't be combined with
+	// CollectionsRegistryPath and take precedence over the collections table.
+	MeridianPayTreasureHuntAddress string
+	MeridianPayPoapAddress         string
+	MeridianPayStellarHouseAddress string
+	// MaxDiscoveredTokens caps the tokens discovered for the owner in one
+	// registered collection (--max-discovered-tokens); a capped collection is
+	// returned with truncated set.
+	MaxDiscoveredTokens int
+	// CollectionsRefreshInterval is how often the collections table is
+	// re-read (--collections-refresh-interval).
+	CollectionsRefreshInterval time.Duration
+	// ResolveMetadata fetches each collectible's token_uri and returns the
+	// validated metadata inline (--resolve-collectible-metadata). Off by
+	// default: clients then fetch token_uri themselves.
+	ResolveMetadata bool
+	// IPFSGatewayURL is the path-style HTTPS gateway ipfs:// URIs are fetched
+	// through (--ipfs-gateway-url).
+	IPFSGatewayURL string
+	// MetadataCacheTTLSeconds bounds how long resolved metadata is reused from
+	// Redis (--collectible-metadata-cache-ttl-seconds). Zero disables caching.
+	MetadataCacheTTLSeconds int
+	// MetadataMaxBytes caps the size of one metadata document
+	// (--collectible-metadata-max-bytes).
+	MetadataMaxBytes int64
+	// MetadataFetchTimeout bounds one metadata fetch, redirects included
+	// (--collectible-metadata-fetch-timeout).
+	MetadataFetchTimeout time.Duration
+}
+
+// MeridianPayAddresses returns the deprecated --meridian-pay-* addresses that
+// are set, without duplicates.
+func (c CollectiblesConfig) MeridianPayAddresses() []string {
+	var addresses []string
+	for _, address := range []string{c.MeridianPayTreasureHuntAddress, c.MeridianPayPoapAddress, c.MeridianPayStellarHouseAddress} {
+		if address != "" && !slices.Contains(addresses, address) {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses
+}
+
 type WalletBackendConfig struct {
 	PubnetUrl         string
 	TestnetUrl        string
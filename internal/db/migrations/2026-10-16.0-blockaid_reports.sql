@@ -0,0 +1,25 @@
+-- Copies of the false-positive / false-negative reports users send about
+-- Blockaid warnings. Each row is written after the report is forwarded, so
+-- forward_error records reports Blockaid never received.
+
+-- +migrate Up
+CREATE TABLE blockaid_reports (
+    id            BIGSERIAL PRIMARY KEY,
+    kind          TEXT NOT NULL CHECK (kind IN ('asset', 'transaction')),
+    event         TEXT NOT NULL CHECK (event IN ('FALSE_POSITIVE', 'FALSE_NEGATIVE')),
+    network       TEXT NOT NULL,
+    -- The reported asset id (CODE:ISSUER) or transaction envelope XDR.
+    subject       TEXT NOT NULL,
+    origin_url    TEXT,
+    details       TEXT NOT NULL DEFAULT '',
+    -- NULL for anonymous requests (permissive auth mode).
+    user_id       TEXT,
+    forward_error TEXT,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX blockaid_reports_kind_subject_idx ON blockaid_reports (kind, subject);
+CREATE INDEX blockaid_reports_created_at_idx ON blockaid_reports (created_at);
+
+-- +migrate Down
+DROP TABLE blockaid_reports;
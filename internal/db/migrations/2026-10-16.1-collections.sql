@@ -0,0 +1,18 @@
+-- Collection contracts whose tokens POST /api/v1/collectibles discovers for
+-- the owner. Managed with the `collections` subcommand; serve reloads the
+-- table on an interval, so edits take effect without a restart.
+
+-- +migrate Up
+CREATE TABLE collections (
+    network     TEXT NOT NULL CHECK (network IN ('PUBLIC', 'TESTNET', 'FUTURENET')),
+    contract_id TEXT NOT NULL,
+    -- How the contract lists an owner's tokens; see types.CollectionEnumeration.
+    enumeration TEXT NOT NULL CHECK (enumeration IN ('owner_tokens', 'owner_index')),
+    label       TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (network, contract_id)
+);
+
+-- +migrate Down
+DROP TABLE collections;
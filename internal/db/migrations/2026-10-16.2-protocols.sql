@@ -0,0 +1,26 @@
+-- The Discover catalog served by GET /api/v1/protocols when serve runs with
+-- --protocols-source=database. Loaded from a protocols.json file with the
+-- `protocols import` subcommand; serve reloads the table on an interval.
+
+-- +migrate Up
+CREATE TABLE protocols (
+    name                TEXT PRIMARY KEY,
+    -- Display order, ascending.
+    position            INTEGER NOT NULL,
+    tags                TEXT[] NOT NULL DEFAULT '{}',
+    website_url         TEXT NOT NULL,
+    icon_url            TEXT NOT NULL,
+    background_url      TEXT NOT NULL DEFAULT '',
+    description         TEXT NOT NULL DEFAULT '',
+    is_blacklisted      BOOLEAN NOT NULL DEFAULT false,
+    -- NULL when the catalog doesn't say either way; omitted from responses.
+    is_trending         BOOLEAN,
+    is_wc_not_supported BOOLEAN NOT NULL DEFAULT false,
+    -- Networks the protocol is available on.
+    networks            TEXT[] NOT NULL CHECK (networks <@ ARRAY['PUBLIC', 'TESTNET', 'FUTURENET']),
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- +migrate Down
+DROP TABLE protocols;
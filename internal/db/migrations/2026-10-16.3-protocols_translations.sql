@@ -0,0 +1,8 @@
+-- Localized name/description per protocol, keyed by BCP 47 language tag:
+-- {"es": {"name": "...", "description": "..."}}. See types.ProtocolTranslation.
+
+-- +migrate Up
+ALTER TABLE protocols ADD COLUMN translations JSONB NOT NULL DEFAULT '{}';
+
+-- +migrate Down
+ALTER TABLE protocols DROP COLUMN translations;
@@ -0,0 +1,16 @@
+-- Feature flags served by GET /api/v1/feature-flags when serve runs with
+-- --feature-flags-source=database. rules holds a JSON array of
+-- types.FlagRule, tried in order; enabled applies when none matches.
+
+-- +migrate Up
+CREATE TABLE feature_flags (
+    key         TEXT PRIMARY KEY CHECK (key ~ '^[a-z][a-z0-9_]*$'),
+    description TEXT NOT NULL DEFAULT '',
+    enabled     BOOLEAN NOT NULL DEFAULT false,
+    rules       JSONB NOT NULL DEFAULT '[]' CHECK (jsonb_typeof(rules) = 'array'),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- +migrate Down
+DROP TABLE feature_flags;
@@ -0,0 +1,21 @@
+-- One row per change to data-driven config (feature flags, protocols), written
+-- in the same transaction as the change. before is NULL for a create and after
+-- is NULL for a delete.
+
+-- +migrate Up
+CREATE TABLE audit_log (
+    id          BIGSERIAL PRIMARY KEY,
+    -- Who made the change: the admin credential's name, or the CLI user.
+    actor       TEXT NOT NULL,
+    action      TEXT NOT NULL CHECK (action IN ('create', 'update', 'delete', 'replace')),
+    entity_type TEXT NOT NULL,
+    entity_key  TEXT NOT NULL,
+    before      JSONB,
+    after       JSONB,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX audit_log_entity_idx ON audit_log (entity_type, entity_key, created_at DESC);
+
+-- +migrate Down
+DROP TABLE audit_log;
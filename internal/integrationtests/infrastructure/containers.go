@@ -392,6 +392,8 @@ func createFreighterContainer(ctx context.Context, name string, imageName string
 			// Token-prices endpoint isn't exercised by integration tests;
 			// the server still requires a non-empty key at startup.
 			"STELLAR_EXPERT_API_KEY": "integration-test-placeholder",
+			// Protocol tests swap the catalog file and wait for the reload.
+			"PROTOCOLS_REFRESH_INTERVAL": "1s",
 		},
 		Networks:   []string{testNetwork.Name},
 		WaitingFor: wait.ForHTTP("/api/v1/ping"),
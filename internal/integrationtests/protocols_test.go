@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -15,12 +16,9 @@ import (
 	"github.com/stellar/freighter-backend-v2/internal/integrationtests/infrastructure"
 )
 
-// testHTTPError is a local struct for unmarshaling HTTP error responses in tests.
-type testHTTPError struct {
-	Message    string         `json:"message"`
-	StatusCode int            `json:"statusCode"`
-	Extras     map[string]any `json:"extras,omitempty"`
-}
+// protocolsReloadWait bounds how long a copied protocols file takes to be
+// served: the container re-reads it every PROTOCOLS_REFRESH_INTERVAL (1s).
+const protocolsReloadWait = 10 * time.Second
 
 type ProtocolsTestSuite struct {
 	suite.Suite
@@ -40,27 +38,20 @@ func (s *ProtocolsTestSuite) TestGetProtocolsReturns200StatusCodeForValidProtoco
 	t := s.T()
 	ctx := context.Background()
 
-	err := s.freighterContainer.CopyFileToContainer(
-		ctx,
-		"../../internal/integrationtests/infrastructure/testdata/protocols.json",
-		"/app/config/protocols.json",
-		0o644,
-	)
-	require.NoError(t, err)
+	s.copyProtocolsFile(ctx, "protocols.json")
 
-	resp, err := http.Get(fmt.Sprintf("%s/api/v1/protocols", s.connectionString))
-	require.NoError(t, err)
-	require.Equal(t, http.StatusOK, resp.StatusCode)
-
-	body, err := io.ReadAll(resp.Body)
-	require.NoError(t, err)
-	require.NotNil(t, body)
+	var body []byte
+	require.Eventually(t, func() bool {
+		var status int
+		status, body = s.getProtocols()
+		return status == http.StatusOK
+	}, protocolsReloadWait, 250*time.Millisecond)
 
 	type expectedResponse struct {
 		Data handlers.GetProtocolsPayload `json:"data"`
 	}
 	var response expectedResponse
-	err = json.Unmarshal(body, &response)
+	err := json.Unmarshal(body, &response)
 	require.NoError(t, err)
 	require.NotNil(t, response)
 
@@ -99,28 +90,44 @@ func (s *ProtocolsTestSuite) TestGetProtocolsReturns200StatusCodeForValidProtoco
 	assert.False(t, allbridgeHasTrending, "is_trending key should be absent for protocols without a trending flag")
 }
 
-func (s *ProtocolsTestSuite) TestGetProtocolsReturns500StatusCodeForInvalidProtocols() {
+func (s *ProtocolsTestSuite) TestGetProtocolsServesLastGoodVersionForInvalidProtocols() {
 	t := s.T()
 	ctx := context.Background()
+
+	s.copyProtocolsFile(ctx, "protocols.json")
+	require.Eventually(t, func() bool {
+		status, _ := s.getProtocols()
+		return status == http.StatusOK
+	}, protocolsReloadWait, 250*time.Millisecond)
+
+	s.copyProtocolsFile(ctx, "invalid_protocols.json")
+	// Give the server a few polls to pick the bad version up and reject it.
+	time.Sleep(3 * time.Second)
+
+	status, body := s.getProtocols()
+	require.Equal(t, http.StatusOK, status)
+	var response struct {
+		Data handlers.GetProtocolsPayload `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &response))
+	assert.Len(t, response.Data.Protocols, 3, "the last good catalog keeps being served")
+}
+
+func (s *ProtocolsTestSuite) copyProtocolsFile(ctx context.Context, name string) {
 	err := s.freighterContainer.CopyFileToContainer(
 		ctx,
-		"../../internal/integrationtests/infrastructure/testdata/invalid_protocols.json",
+		"../../internal/integrationtests/infrastructure/testdata/"+name,
 		"/app/config/protocols.json",
 		0o644,
 	)
-	require.NoError(t, err)
+	s.Require().NoError(err)
+}
 
+func (s *ProtocolsTestSuite) getProtocols() (int, []byte) {
 	resp, err := http.Get(fmt.Sprintf("%s/api/v1/protocols", s.connectionString))
-	require.NoError(t, err)
-	require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
-
+	s.Require().NoError(err)
+	defer resp.Body.Close()
 	body, err := io.ReadAll(resp.Body)
-	require.NoError(t, err)
-	require.NotNil(t, body)
-
-	var errorResponse testHTTPError
-	err = json.Unmarshal(body, &errorResponse)
-	require.NoError(t, err)
-	require.NotNil(t, errorResponse)
-	assert.Equal(t, "An error occurred while processing protocol configurations.", errorResponse.Message)
+	s.Require().NoError(err)
+	return resp.StatusCode, body
 }
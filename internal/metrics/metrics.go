@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net"
 	"net/url"
+	"time"
 
 	"github.com/creachadair/jrpc2"
 	"github.com/prometheus/client_golang/prometheus"
@@ -50,19 +51,21 @@ func (e *UpstreamError) Unwrap() error { return e.Err }
 
 // Metrics groups all Prometheus metrics. Must be created via NewMetrics.
 type Metrics struct {
-	HTTP    *HTTP
-	Service *Service
-	Auth    *Auth
-	Prices  *Prices
+	HTTP      *HTTP
+	Service   *Service
+	Auth      *Auth
+	Prices    *Prices
+	Protocols *Protocols
 }
 
 // NewMetrics creates and registers all application metrics with the given registerer.
 func NewMetrics(reg prometheus.Registerer) *Metrics {
 	return &Metrics{
-		HTTP:    NewHTTP(reg),
-		Service: NewService(reg),
-		Auth:    NewAuth(reg),
-		Prices:  NewPrices(reg),
+		HTTP:      NewHTTP(reg),
+		Service:   NewService(reg),
+		Auth:      NewAuth(reg),
+		Prices:    NewPrices(reg),
+		Protocols: NewProtocols(reg),
 	}
 }
 
@@ -229,18 +232,25 @@ func NewService(reg prometheus.Registerer) *Service {
 // degraded-mode signals (miss-budget exhaustion), and
 // Redis-from-this-service-POV errors.
 type Prices struct {
-	// CacheOutcomes counts per-token cache outcomes by network and outcome:
-	// "hit" (live entry within --price-cache-ttl-seconds) or "miss" (no
-	// entry, expired, or upstream-only path).
+	// CacheOutcomes counts per-token cache outcomes by network, outcome and
+	// provider. outcome is "hit" (live entry within --price-cache-ttl-seconds),
+	// "stale" (entry served past it, within --price-stale-ttl-seconds, while
+	// it refreshes) or "miss" (no entry, expired, or upstream-only path).
+	// provider is the price provider that produced the entry, "median" when
+	// several quotes were aggregated, or "none" for a miss no provider priced.
 	CacheOutcomes *prometheus.CounterVec
 	// MissBudgetExhausted counts requests whose miss-fetch budget
 	// (--price-fetch-timeout-seconds) tripped before all misses resolved.
 	// Labeled by network.
 	MissBudgetExhausted *prometheus.CounterVec
 	// RedisErrors counts Redis operations from the prices service that
-	// failed (and were silently fallen-through). Labeled by op: "mget" or
-	// "set".
+	// failed (and were silently fallen-through). Labeled by op: "mget",
+	// "set", "zincrby" or "ztop".
 	RedisErrors *prometheus.CounterVec
+	// BackgroundRefreshes counts price fetches started off the request path,
+	// by network and trigger: "stale" (a stale entry was served) or "hot"
+	// (a most-requested token was refreshed ahead of expiry).
+	BackgroundRefreshes *prometheus.CounterVec
 }
 
 // NewPrices creates and registers prices-service metrics with the given registerer.
@@ -249,7 +259,7 @@ func NewPrices(reg prometheus.Registerer) *Prices {
 		CacheOutcomes: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name: "freighter_prices_cache_outcomes_total",
 			Help: "Per-token cache outcomes for the token-prices endpoint.",
-		}, []string{"network", "outcome"}),
+		}, []string{"network", "outcome", "provider"}),
 		MissBudgetExhausted: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name: "freighter_prices_miss_budget_exhausted_total",
 			Help: "Requests whose miss-fetch budget elapsed before all misses resolved.",
@@ -258,11 +268,67 @@ func NewPrices(reg prometheus.Registerer) *Prices {
 			Name: "freighter_prices_redis_errors_total",
 			Help: "Redis operation failures observed by the prices service.",
 		}, []string{"op"}),
+		BackgroundRefreshes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "freighter_prices_background_refreshes_total",
+			Help: "Token price fetches started off the request path, by trigger.",
+		}, []string{"network", "trigger"}),
 	}
-	reg.MustRegister(p.CacheOutcomes, p.MissBudgetExhausted, p.RedisErrors)
+	reg.MustRegister(p.CacheOutcomes, p.MissBudgetExhausted, p.RedisErrors, p.BackgroundRefreshes)
 	return p
 }
 
+// Protocols holds metrics for the hot-reloaded protocols catalog file.
+type Protocols struct {
+	// CatalogInfo is 1 for the sha256 of the catalog currently served, and
+	// has no other series, so a dashboard shows which version every pod runs.
+	CatalogInfo *prometheus.GaugeVec
+	// LastReloadTimestamp is when a new catalog version was last swapped in.
+	LastReloadTimestamp prometheus.Gauge
+	// ReloadFailures counts failed reloads by reason: "read" (every poll that
+	// could not read the file), "parse" or "invalid" (once per rejected
+	// version).
+	ReloadFailures *prometheus.CounterVec
+}
+
+// NewProtocols creates and registers protocols-catalog metrics with the given registerer.
+func NewProtocols(reg prometheus.Registerer) *Protocols {
+	p := &Protocols{
+		CatalogInfo: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "freighter_protocols_catalog_info",
+			Help: "The protocols catalog version currently served, by sha256 of the file.",
+		}, []string{"sha256"}),
+		LastReloadTimestamp: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "freighter_protocols_catalog_last_reload_timestamp_seconds",
+			Help: "Unix time a new protocols catalog version was last loaded.",
+		}),
+		ReloadFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "freighter_protocols_catalog_reload_failures_total",
+			Help: "Protocols catalog versions rejected while reloading, by reason.",
+		}, []string{"reason"}),
+	}
+	reg.MustRegister(p.CatalogInfo, p.LastReloadTimestamp, p.ReloadFailures)
+	return p
+}
+
+// RecordProtocolsCatalogLoaded marks version as the catalog being served. It
+// is nil-safe so catalogs can run without a metrics registry in tests.
+func RecordProtocolsCatalogLoaded(p *Protocols, version string, at time.Time) {
+	if p == nil {
+		return
+	}
+	p.CatalogInfo.Reset()
+	p.CatalogInfo.WithLabelValues(version).Set(1)
+	p.LastReloadTimestamp.Set(float64(at.Unix()))
+}
+
+// RecordProtocolsReloadFailure counts a rejected catalog version. Nil-safe.
+func RecordProtocolsReloadFailure(p *Protocols, reason string) {
+	if p == nil {
+		return
+	}
+	p.ReloadFailures.WithLabelValues(reason).Inc()
+}
+
 // Record records call metrics for a service method invocation.
 // It is nil-safe: if m is nil, it is a no-op, allowing services to work without metrics in tests.
 func Record(m *Service, service, method, network string, duration float64, err error) {
@@ -8,6 +8,7 @@ import (
 	"net"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/creachadair/jrpc2"
 	"github.com/prometheus/client_golang/prometheus"
@@ -24,6 +25,7 @@ func TestNewMetrics_RegistersWithoutPanic(t *testing.T) {
 		require.NotNil(t, m.HTTP)
 		require.NotNil(t, m.Service)
 		require.NotNil(t, m.Prices)
+		require.NotNil(t, m.Protocols)
 	})
 }
 
@@ -53,6 +55,33 @@ func TestNewService_LintPasses(t *testing.T) {
 	assert.Empty(t, problems, "lint problems: %v", problems)
 }
 
+func TestNewProtocols_LintPasses(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	NewProtocols(reg)
+
+	problems, err := testutil.GatherAndLint(reg)
+	require.NoError(t, err)
+	assert.Empty(t, problems, "lint problems: %v", problems)
+}
+
+func TestRecordProtocolsCatalogLoaded_KeepsOnlyCurrentVersion(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	p := NewProtocols(reg)
+
+	RecordProtocolsCatalogLoaded(p, "aaa", time.Unix(100, 0))
+	RecordProtocolsCatalogLoaded(p, "bbb", time.Unix(200, 0))
+	RecordProtocolsReloadFailure(p, "invalid")
+
+	assert.Equal(t, 1, testutil.CollectAndCount(p.CatalogInfo))
+	assert.Equal(t, float64(1), testutil.ToFloat64(p.CatalogInfo.WithLabelValues("bbb")))
+	assert.Equal(t, float64(200), testutil.ToFloat64(p.LastReloadTimestamp))
+	assert.Equal(t, float64(1), testutil.ToFloat64(p.ReloadFailures.WithLabelValues("invalid")))
+
+	// Nil-safe.
+	RecordProtocolsCatalogLoaded(nil, "ccc", time.Now())
+	RecordProtocolsReloadFailure(nil, "read")
+}
+
 func TestNewPrices_LintPasses(t *testing.T) {
 	reg := prometheus.NewRegistry()
 	NewPrices(reg)
@@ -0,0 +1,205 @@
+// ABOUTME: Horizon-backed balance source for /api/v1/accounts/balances, used where wallet-backend is not configured.
+// ABOUTME: Maps Horizon account balances into the same NativeBalance/TrustlineBalance/LiquidityPoolBalance shapes.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	hProtocol "github.com/stellar/go-stellar-sdk/protocols/horizon"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stellar/go/amount"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/stellar/freighter-backend-v2/internal/logger"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils"
+)
+
+// baseReserveStroops is the per-entry base reserve (0.5 XLM) on pubnet and
+// testnet. Horizon's account resource does not expose it, and it has not
+// changed since protocol 10.
+const baseReserveStroops = 5_000_000
+
+// NetworkBalanceSources routes each balances request to the source configured
+// for its network, so one network can read from wallet-backend while another
+// falls back to Horizon.
+type NetworkBalanceSources map[string]types.AccountBalancesSource
+
+func (n NetworkBalanceSources) GetBalancesByAccountAddresses(ctx context.Context, addresses []string, network string) (interface{}, error) {
+	source, ok := n[network]
+	if !ok || source == nil {
+		return nil, fmt.Errorf("no balance source configured for network: %s", network)
+	}
+	return source.GetBalancesByAccountAddresses(ctx, addresses, network)
+}
+
+type horizonBalanceSource struct {
+	horizon        types.HorizonService
+	maxConcurrency int
+}
+
+// NewHorizonBalanceSource builds account balances from Horizon account details.
+// It covers the classic ledger only: native, trustline and liquidity-pool share
+// balances. Soroban token balances (SAC contract balances, SEP-41) have no
+// Horizon representation and are omitted, and pool shares carry no reserves.
+func NewHorizonBalanceSource(horizon types.HorizonService, maxConcurrency int) (types.AccountBalancesSource, error) {
+	if maxConcurrency <= 0 {
+		return nil, fmt.Errorf("maxConcurrency must be > 0, got %d", maxConcurrency)
+	}
+	return &horizonBalanceSource{horizon: horizon, maxConcurrency: maxConcurrency}, nil
+}
+
+// GetBalancesByAccountAddresses mirrors walletBackendService's semantics: one
+// result per unique address in first-seen order, an account Horizon reports
+// not_found is unfunded (is_funded=false, empty balances), and any other
+// failure aborts the whole request as a systemic error. The returned
+// interface{} is a []*types.AccountBalances.
+func (h *horizonBalanceSource) GetBalancesByAccountAddresses(ctx context.Context, addresses []string, network string) (interface{}, error) {
+	passphrase, err := utils.NetworkPassphrase(network)
+	if err != nil {
+		return nil, err
+	}
+
+	unique := utils.DedupePreserveOrder(addresses)
+	results := make([]*types.AccountBalances, len(unique))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(h.maxConcurrency)
+
+	for i, addr := range unique {
+		g.Go(func() error {
+			ab := &types.AccountBalances{
+				Address:  addr,
+				Balances: []types.Balance{},
+			}
+			account, fetchErr := h.horizon.GetAccountDetails(gctx, network, addr)
+			switch {
+			case errors.Is(fetchErr, ErrHorizonNotFound):
+				logger.InfoWithContext(gctx, "account not found", "address", addr)
+			case fetchErr != nil:
+				return fetchErr
+			default:
+				balances, mapErr := mapHorizonBalances(account, passphrase)
+				if mapErr != nil {
+					return mapErr
+				}
+				// A Horizon account always has a native balance, but derive
+				// IsFunded from it anyway so the rule stays the one
+				// wallet-backend results follow.
+				for _, b := range balances {
+					if _, ok := b.(*types.NativeBalance); ok {
+						ab.IsFunded = true
+						ab.SubentryCount = uint32(account.SubentryCount) //nolint:gosec // Horizon never reports a negative count
+					}
+				}
+				if ab.IsFunded {
+					ab.Balances = balances
+				}
+			}
+			results[i] = ab
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// mapHorizonBalances converts each Horizon balance line to the matching
+// freighter variant, following mapBalance's key/token/available conventions.
+// Classic trustlines use their SAC contract id as token_id, which is what
+// wallet-backend reports for the same line.
+func mapHorizonBalances(account *hProtocol.Account, passphrase string) ([]types.Balance, error) {
+	out := make([]types.Balance, 0, len(account.Balances))
+	for _, b := range account.Balances {
+		switch b.Type {
+		case "native":
+			minimum := nativeMinimumBalance(account)
+			out = append(out, &types.NativeBalance{
+				BalanceBase: types.BalanceBase{
+					Key:       "native",
+					Token:     &types.Token{Type: "native", Code: "XLM"},
+					Total:     b.Balance,
+					Available: spendable(b.Balance, minimum, b.SellingLiabilities),
+					TokenID:   "native",
+					TokenType: "NATIVE",
+				},
+				MinimumBalance:     minimum,
+				BuyingLiabilities:  b.BuyingLiabilities,
+				SellingLiabilities: b.SellingLiabilities,
+				LastModifiedLedger: account.LastModifiedLedger,
+			})
+		case "liquidity_pool_shares":
+			out = append(out, &types.LiquidityPoolBalance{
+				BalanceBase: types.BalanceBase{
+					Key:       b.LiquidityPoolId + ":lp",
+					Total:     b.Balance,
+					Available: b.Balance,
+					TokenID:   b.LiquidityPoolId,
+					TokenType: "LIQUIDITY_POOL",
+				},
+				LiquidityPoolID:    b.LiquidityPoolId,
+				Reserves:           []types.LiquidityPoolReserve{},
+				LastModifiedLedger: b.LastModifiedLedger,
+			})
+		default:
+			tokenID, err := sacContractID(b.Type, b.Code, b.Issuer, passphrase)
+			if err != nil {
+				return nil, err
+			}
+			code, issuer := b.Code, b.Issuer
+			out = append(out, &types.TrustlineBalance{
+				BalanceBase: types.BalanceBase{
+					Key:       code + ":" + issuer,
+					Token:     &types.Token{Type: b.Type, Code: code, Issuer: &types.TokenIssuer{Key: issuer}},
+					Total:     b.Balance,
+					Available: spendable(b.Balance, b.SellingLiabilities),
+					TokenID:   tokenID,
+					TokenType: "CLASSIC",
+				},
+				Code:                              &code,
+				Issuer:                            &issuer,
+				Type:                              b.Type,
+				Limit:                             b.Limit,
+				BuyingLiabilities:                 b.BuyingLiabilities,
+				SellingLiabilities:                b.SellingLiabilities,
+				LastModifiedLedger:                b.LastModifiedLedger,
+				IsAuthorized:                      derefBool(b.IsAuthorized),
+				IsAuthorizedToMaintainLiabilities: derefBool(b.IsAuthorizedToMaintainLiabilities),
+			})
+		}
+	}
+	return out, nil
+}
+
+// nativeMinimumBalance is the account's base reserve requirement,
+// (2 + subentries + sponsoring - sponsored) * base reserve, matching
+// wallet-backend's minimum_balance (liabilities excluded).
+func nativeMinimumBalance(account *hProtocol.Account) string {
+	entries := 2 + int64(account.SubentryCount) + int64(account.NumSponsoring) - int64(account.NumSponsored)
+	if entries < 0 {
+		entries = 0
+	}
+	return amount.StringFromInt64(entries * baseReserveStroops)
+}
+
+// sacContractID derives the Stellar Asset Contract address for a classic asset
+// on the network identified by passphrase.
+func sacContractID(assetType, code, issuer, passphrase string) (string, error) {
+	asset, err := xdr.BuildAsset(assetType, issuer, code)
+	if err != nil {
+		return "", fmt.Errorf("building asset %s:%s: %w", code, issuer, err)
+	}
+	id, err := asset.ContractID(passphrase)
+	if err != nil {
+		return "", fmt.Errorf("deriving contract id for %s:%s: %w", code, issuer, err)
+	}
+	return strkey.Encode(strkey.VersionByteContract, id[:])
+}
+
+func derefBool(b *bool) bool {
+	return b != nil && *b
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/network"
+	hProtocol "github.com/stellar/go-stellar-sdk/protocols/horizon"
+	"github.com/stellar/go-stellar-sdk/protocols/horizon/base"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils"
+)
+
+const (
+	testUSDCIssuer  = "GA5ZSEJYB37JRC5AVCIA5MOP4RHTM335X2KGX3IHOJAPP5RE34K4KZVN"
+	testUnfundedAcc = "GBTYAFHGNZSTE4VBWZYAGB3SRGJEPTI5I4Y22KZ4JTVAN56LESB6JZOF"
+)
+
+func testHorizonAccountDetails() *hProtocol.Account {
+	yes := true
+	return &hProtocol.Account{
+		AccountID:          testHorizonAccount,
+		SubentryCount:      3,
+		NumSponsoring:      1,
+		NumSponsored:       2,
+		LastModifiedLedger: 900,
+		Balances: []hProtocol.Balance{
+			{
+				Balance:            "100.0000000",
+				BuyingLiabilities:  "0.0000000",
+				SellingLiabilities: "1.0000000",
+				Asset:              base.Asset{Type: "native"},
+			},
+			{
+				Balance:            "50.0000000",
+				Limit:              "922337203685.4775807",
+				BuyingLiabilities:  "0.0000000",
+				SellingLiabilities: "10.0000000",
+				LastModifiedLedger: 800,
+				IsAuthorized:       &yes,
+				Asset:              base.Asset{Type: "credit_alphanum4", Code: "USDC", Issuer: testUSDCIssuer},
+			},
+			{
+				Balance:            "2.5000000",
+				LiquidityPoolId:    "abcd",
+				LastModifiedLedger: 700,
+				Asset:              base.Asset{Type: "liquidity_pool_shares"},
+			},
+		},
+	}
+}
+
+func newTestHorizonBalanceSource(t *testing.T, mock *utils.MockHorizonService) types.AccountBalancesSource {
+	t.Helper()
+	src, err := NewHorizonBalanceSource(mock, 4)
+	require.NoError(t, err)
+	return src
+}
+
+func TestHorizonBalanceSource_MapsClassicBalances(t *testing.T) {
+	t.Parallel()
+
+	src := newTestHorizonBalanceSource(t, &utils.MockHorizonService{
+		GetAccountDetailsFunc: func(string, string) (*hProtocol.Account, error) {
+			return testHorizonAccountDetails(), nil
+		},
+	})
+
+	out, err := src.GetBalancesByAccountAddresses(context.Background(), []string{testHorizonAccount}, types.PUBLIC)
+	require.NoError(t, err)
+	results := out.([]*types.AccountBalances)
+	require.Len(t, results, 1)
+	ab := results[0]
+	assert.True(t, ab.IsFunded)
+	assert.Equal(t, uint32(3), ab.SubentryCount)
+	require.Len(t, ab.Balances, 3)
+
+	// (2 + 3 subentries + 1 sponsoring - 2 sponsored) * 0.5 XLM = 2 XLM.
+	native := ab.Balances[0].(*types.NativeBalance)
+	assert.Equal(t, "native", native.Key)
+	assert.Equal(t, "NATIVE", native.TokenType)
+	assert.Equal(t, "2.0000000", native.MinimumBalance)
+	assert.Equal(t, "97.0000000", native.Available)
+	assert.Equal(t, uint32(900), native.LastModifiedLedger)
+
+	usdc, err := xdr.NewCreditAsset("USDC", testUSDCIssuer)
+	require.NoError(t, err)
+	contractID, err := usdc.ContractID(network.PublicNetworkPassphrase)
+	require.NoError(t, err)
+	trustline := ab.Balances[1].(*types.TrustlineBalance)
+	assert.Equal(t, "USDC:"+testUSDCIssuer, trustline.Key)
+	assert.Equal(t, strkey.MustEncode(strkey.VersionByteContract, contractID[:]), trustline.TokenID)
+	assert.Equal(t, "CLASSIC", trustline.TokenType)
+	assert.Equal(t, "credit_alphanum4", trustline.Type)
+	assert.Equal(t, "40.0000000", trustline.Available)
+	assert.True(t, trustline.IsAuthorized)
+	assert.False(t, trustline.IsAuthorizedToMaintainLiabilities)
+
+	pool := ab.Balances[2].(*types.LiquidityPoolBalance)
+	assert.Equal(t, "abcd:lp", pool.Key)
+	assert.Equal(t, "LIQUIDITY_POOL", pool.TokenType)
+	assert.NotNil(t, pool.Reserves)
+}
+
+func TestHorizonBalanceSource_NotFoundIsUnfunded(t *testing.T) {
+	t.Parallel()
+
+	src := newTestHorizonBalanceSource(t, &utils.MockHorizonService{
+		GetAccountDetailsFunc: func(_, accountID string) (*hProtocol.Account, error) {
+			if accountID == testUnfundedAcc {
+				return nil, fmt.Errorf("%w: account", ErrHorizonNotFound)
+			}
+			return testHorizonAccountDetails(), nil
+		},
+	})
+
+	out, err := src.GetBalancesByAccountAddresses(context.Background(), []string{testUnfundedAcc, testHorizonAccount, testUnfundedAcc}, types.TESTNET)
+	require.NoError(t, err)
+	results := out.([]*types.AccountBalances)
+	require.Len(t, results, 2, "duplicates collapse")
+	assert.Equal(t, testUnfundedAcc, results[0].Address)
+	assert.False(t, results[0].IsFunded)
+	assert.NotNil(t, results[0].Balances)
+	assert.Empty(t, results[0].Balances)
+	assert.True(t, results[1].IsFunded)
+}
+
+func TestHorizonBalanceSource_SystemicErrorFailsRequest(t *testing.T) {
+	t.Parallel()
+
+	boom := errors.New("horizon down")
+	src := newTestHorizonBalanceSource(t, &utils.MockHorizonService{Error: boom})
+
+	_, err := src.GetBalancesByAccountAddresses(context.Background(), []string{testHorizonAccount}, types.PUBLIC)
+	assert.ErrorIs(t, err, boom)
+}
+
+func TestNewHorizonBalanceSource_RejectsNonPositiveConcurrency(t *testing.T) {
+	t.Parallel()
+	_, err := NewHorizonBalanceSource(&utils.MockHorizonService{}, 0)
+	assert.Error(t, err)
+}
+
+type recordingBalanceSource struct {
+	mu       sync.Mutex
+	networks []string
+}
+
+func (r *recordingBalanceSource) GetBalancesByAccountAddresses(_ context.Context, _ []string, network string) (interface{}, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.networks = append(r.networks, network)
+	return []*types.AccountBalances{}, nil
+}
+
+func TestNetworkBalanceSources_RoutesByNetwork(t *testing.T) {
+	t.Parallel()
+
+	pubnet, testnet := &recordingBalanceSource{}, &recordingBalanceSource{}
+	sources := NetworkBalanceSources{types.PUBLIC: pubnet, types.TESTNET: testnet}
+
+	_, err := sources.GetBalancesByAccountAddresses(context.Background(), nil, types.TESTNET)
+	require.NoError(t, err)
+	assert.Empty(t, pubnet.networks)
+	assert.Equal(t, []string{types.TESTNET}, testnet.networks)
+
+	_, err = sources.GetBalancesByAccountAddresses(context.Background(), nil, types.FUTURENET)
+	assert.Error(t, err)
+}
@@ -0,0 +1,521 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/stellar/freighter-backend-v2/internal/logger"
+	"github.com/stellar/freighter-backend-v2/internal/metrics"
+	"github.com/stellar/freighter-backend-v2/internal/store"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils/assetid"
+)
+
+const (
+	blockaidServiceName = "blockaid"
+
+	blockaidHTTPTimeout = 15 * time.Second
+
+	// DefaultBlockaidBaseURL is the production Blockaid API host. Overridable so
+	// tests (and a future staging key) can point at a different host.
+	DefaultBlockaidBaseURL = "https://api.blockaid.io"
+
+	blockaidTxScanPath        = "/v0/stellar/transaction/scan"
+	blockaidSiteScanPath      = "/v0/site/scan"
+	blockaidTokenBulkScanPath = "/v0/token/bulk/scan"
+	blockaidTokenReportPath   = "/v0/token/report"
+	blockaidTxReportPath      = "/v0/stellar/transaction/report"
+
+	// blockaidTokenChain is the chain id for Stellar token scans. Blockaid only
+	// indexes pubnet tokens, so there is no testnet equivalent.
+	blockaidTokenChain = "stellar"
+
+	blockaidCacheKeyPrefix = "blockaid:v1"
+
+	// blockaidStatusError is the status Blockaid reports inside a 200 response
+	// when a validation or simulation step could not run.
+	blockaidStatusError = "Error"
+)
+
+// ErrBlockaidNetworkNotSupported indicates the requested Stellar network has
+// no Blockaid chain equivalent.
+var ErrBlockaidNetworkNotSupported = errors.New("network not supported by blockaid")
+
+// BlockaidServiceConfig is the tunable surface of the Blockaid client.
+type BlockaidServiceConfig struct {
+	// BaseURL defaults to DefaultBlockaidBaseURL when empty.
+	BaseURL string
+	// APIKey is sent as the X-API-Key header on every request.
+	APIKey string
+	// CacheTTL bounds how long dApp and asset verdicts are reused from Redis.
+	// Zero disables caching. Transaction scans are never cached.
+	CacheTTL time.Duration
+}
+
+type blockaidService struct {
+	baseURL    string
+	apiKey     string
+	cacheTTL   time.Duration
+	redis      *store.RedisStore
+	httpClient *http.Client
+	svcMetrics *metrics.Service
+}
+
+// NewBlockaidService constructs a thin HTTP client for the Blockaid scanning
+// API. redis may be nil; if so, every dApp and asset scan hits Blockaid.
+func NewBlockaidService(cfg BlockaidServiceConfig, redis *store.RedisStore, metricsService *metrics.Service) types.BlockaidService {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBlockaidBaseURL
+	}
+	httpClient := &http.Client{
+		Timeout: blockaidHTTPTimeout,
+		Transport: &http.Transport{
+			MaxIdleConns:          100,
+			MaxIdleConnsPerHost:   10,
+			MaxConnsPerHost:       50,
+			IdleConnTimeout:       90 * time.Second,
+			ResponseHeaderTimeout: 10 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+			ForceAttemptHTTP2:     true,
+		},
+	}
+	return &blockaidService{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     cfg.APIKey,
+		cacheTTL:   cfg.CacheTTL,
+		redis:      redis,
+		httpClient: httpClient,
+		svcMetrics: metricsService,
+	}
+}
+
+func (b *blockaidService) Name() string {
+	return blockaidServiceName
+}
+
+// blockaidMetadata tells Blockaid where a request originated. Type "wallet"
+// carries the dApp URL; "in_app" is used for transactions the wallet built
+// itself, which have no origin to score.
+type blockaidMetadata struct {
+	Type string `json:"type"`
+	URL  string `json:"url,omitempty"`
+}
+
+type blockaidTxScanRequest struct {
+	Chain          string           `json:"chain"`
+	Options        []string         `json:"options"`
+	AccountAddress string           `json:"account_address"`
+	Transaction    string           `json:"transaction"`
+	Metadata       blockaidMetadata `json:"metadata"`
+}
+
+// blockaidValidation is the validation block shared by Blockaid's scan
+// responses.
+type blockaidValidation struct {
+	Status      string                  `json:"status"`
+	ResultType  string                  `json:"result_type"`
+	Description string                  `json:"description"`
+	Reason      string                  `json:"reason"`
+	Features    []types.BlockaidFeature `json:"features"`
+	Error       string                  `json:"error"`
+}
+
+type blockaidSimulationStatus struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+}
+
+type blockaidTxScanResponse struct {
+	Validation *blockaidValidation `json:"validation"`
+	Simulation json.RawMessage     `json:"simulation"`
+}
+
+// ScanTx submits a transaction envelope to Blockaid for validation and
+// simulation and returns the normalized verdict.
+func (b *blockaidService) ScanTx(ctx context.Context, network, accountAddress, txXDR, originURL string) (_ *types.TxScanResult, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.Record(b.svcMetrics, blockaidServiceName, "ScanTx", network, time.Since(start).Seconds(), err)
+	}()
+
+	chain, err := blockaidChain(network)
+	if err != nil {
+		return nil, err
+	}
+
+	body := blockaidTxScanRequest{
+		Chain:          chain,
+		Options:        []string{"validation", "simulation"},
+		AccountAddress: accountAddress,
+		Transaction:    txXDR,
+		Metadata:       newBlockaidMetadata(originURL),
+	}
+
+	var resp blockaidTxScanResponse
+	if err := b.doJSON(ctx, blockaidTxScanPath, "transaction scan", body, &resp); err != nil {
+		return nil, err
+	}
+
+	result := &types.TxScanResult{ScanVerdict: normalizeValidation(resp.Validation)}
+	if len(resp.Simulation) > 0 && !bytes.Equal(resp.Simulation, []byte("null")) {
+		result.Simulation = resp.Simulation
+		var sim blockaidSimulationStatus
+		if err := json.Unmarshal(resp.Simulation, &sim); err == nil && sim.Status == blockaidStatusError {
+			result.SimulationError = sim.Error
+		}
+	}
+	return result, nil
+}
+
+type blockaidSiteScanRequest struct {
+	URL string `json:"url"`
+}
+
+// blockaidAttackType is one entry of a site or token scan's attack_types map.
+type blockaidAttackType struct {
+	Score     float64 `json:"score"`
+	Threshold float64 `json:"threshold"`
+}
+
+type blockaidSiteScanResponse struct {
+	Status      string                        `json:"status"`
+	IsMalicious bool                          `json:"is_malicious"`
+	AttackTypes map[string]blockaidAttackType `json:"attack_types"`
+}
+
+// ScanDapp scores a dApp origin. Only "hit" verdicts are cached: a "miss"
+// means Blockaid has queued the origin for crawling, and caching it would pin
+// RiskUnknown for the whole TTL after a real verdict exists.
+func (b *blockaidService) ScanDapp(ctx context.Context, origin string) (_ *types.DappScanResult, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.Record(b.svcMetrics, blockaidServiceName, "ScanDapp", "", time.Since(start).Seconds(), err)
+	}()
+
+	key := blockaidCacheKey("site", origin)
+	if cached, ok := b.loadCached(ctx, []string{key}, func() any { return new(types.DappScanResult) })[key].(*types.DappScanResult); ok {
+		return cached, nil
+	}
+
+	var resp blockaidSiteScanResponse
+	if err := b.doJSON(ctx, blockaidSiteScanPath, "site scan", blockaidSiteScanRequest{URL: origin}, &resp); err != nil {
+		return nil, err
+	}
+
+	result := &types.DappScanResult{
+		ScanVerdict: types.ScanVerdict{ResultType: types.RiskUnknown, Features: []types.BlockaidFeature{}},
+		URL:         origin,
+		Status:      types.DappScanMiss,
+	}
+	if resp.Status == types.DappScanHit {
+		result.Status = types.DappScanHit
+		result.ResultType = types.RiskBenign
+		if resp.IsMalicious {
+			result.ResultType = types.RiskMalicious
+			result.IsMalicious = true
+		}
+		result.Features = attackTypeFeatures(resp.AttackTypes)
+		b.storeCached(ctx, key, result)
+	}
+	return result, nil
+}
+
+type blockaidTokenBulkScanRequest struct {
+	Chain  string   `json:"chain"`
+	Tokens []string `json:"tokens"`
+}
+
+type blockaidTokenScanResult struct {
+	ResultType     string                  `json:"result_type"`
+	MaliciousScore string                  `json:"malicious_score"`
+	Features       []types.BlockaidFeature `json:"features"`
+}
+
+type blockaidTokenBulkScanResponse struct {
+	Results map[string]blockaidTokenScanResult `json:"results"`
+}
+
+// ScanAssets scores canonical asset ids on PUBLIC, serving what it can from
+// Redis and sending only the misses to Blockaid in one bulk call. Native XLM
+// is benign by definition and never sent; an asset Blockaid omits from its
+// response comes back RiskUnknown and is not cached.
+func (b *blockaidService) ScanAssets(ctx context.Context, network string, assets []string) (_ map[string]*types.AssetScanResult, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.Record(b.svcMetrics, blockaidServiceName, "ScanAssets", network, time.Since(start).Seconds(), err)
+	}()
+
+	if network != types.PUBLIC {
+		return nil, fmt.Errorf("%w: %s", ErrBlockaidNetworkNotSupported, network)
+	}
+
+	result := make(map[string]*types.AssetScanResult, len(assets))
+	keys := make([]string, 0, len(assets))
+	assetByKey := make(map[string]string, len(assets))
+	for _, asset := range assets {
+		if asset == assetid.NativeCanonical {
+			result[asset] = &types.AssetScanResult{ScanVerdict: types.ScanVerdict{ResultType: types.RiskBenign, Features: []types.BlockaidFeature{}}}
+			continue
+		}
+		key := blockaidCacheKey("asset", asset)
+		keys = append(keys, key)
+		assetByKey[key] = asset
+	}
+
+	cached := b.loadCached(ctx, keys, func() any { return new(types.AssetScanResult) })
+	misses := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if hit, ok := cached[key].(*types.AssetScanResult); ok {
+			result[assetByKey[key]] = hit
+			continue
+		}
+		misses = append(misses, assetByKey[key])
+	}
+	if len(misses) == 0 {
+		return result, nil
+	}
+
+	tokens := make([]string, len(misses))
+	for i, asset := range misses {
+		tokens[i] = assetid.ToBlockaid(asset)
+	}
+	var resp blockaidTokenBulkScanResponse
+	if err := b.doJSON(ctx, blockaidTokenBulkScanPath, "token bulk scan", blockaidTokenBulkScanRequest{Chain: blockaidTokenChain, Tokens: tokens}, &resp); err != nil {
+		return nil, err
+	}
+
+	for i, asset := range misses {
+		scan, ok := resp.Results[tokens[i]]
+		if !ok {
+			result[asset] = &types.AssetScanResult{ScanVerdict: types.ScanVerdict{ResultType: types.RiskUnknown, Features: []types.BlockaidFeature{}}}
+			continue
+		}
+		entry := &types.AssetScanResult{
+			ScanVerdict:    normalizeValidation(&blockaidValidation{ResultType: scan.ResultType, Features: scan.Features}),
+			MaliciousScore: scan.MaliciousScore,
+		}
+		result[asset] = entry
+		b.storeCached(ctx, blockaidCacheKey("asset", asset), entry)
+	}
+	return result, nil
+}
+
+// blockaidReportRequest is the envelope shared by Blockaid's report
+// endpoints: the disputed event plus the parameters of the original scan.
+type blockaidReportRequest struct {
+	Event   string               `json:"event"`
+	Details string               `json:"details"`
+	Report  blockaidReportParams `json:"report"`
+}
+
+type blockaidReportParams struct {
+	Type   string `json:"type"`
+	Params any    `json:"params"`
+}
+
+type blockaidTokenReportParams struct {
+	Address string `json:"address"`
+	Chain   string `json:"chain"`
+}
+
+type blockaidTxReportParams struct {
+	Chain          string           `json:"chain"`
+	AccountAddress string           `json:"account_address"`
+	Transaction    string           `json:"transaction"`
+	Metadata       blockaidMetadata `json:"metadata"`
+}
+
+// ReportAsset forwards a dispute of an asset verdict. Like ScanAssets it is
+// PUBLIC-only, since Blockaid has no verdicts for other networks' tokens.
+func (b *blockaidService) ReportAsset(ctx context.Context, network string, report types.AssetWarningReport) (err error) {
+	start := time.Now()
+	defer func() {
+		metrics.Record(b.svcMetrics, blockaidServiceName, "ReportAsset", network, time.Since(start).Seconds(), err)
+	}()
+
+	if network != types.PUBLIC {
+		return fmt.Errorf("%w: %s", ErrBlockaidNetworkNotSupported, network)
+	}
+
+	body := blockaidReportRequest{
+		Event:   report.Event,
+		Details: report.Details,
+		Report: blockaidReportParams{
+			Type:   "params",
+			Params: blockaidTokenReportParams{Address: assetid.ToBlockaid(report.Asset), Chain: blockaidTokenChain},
+		},
+	}
+	return b.doJSON(ctx, blockaidTokenReportPath, "token report", body, nil)
+}
+
+// ReportTransaction forwards a dispute of a transaction verdict.
+func (b *blockaidService) ReportTransaction(ctx context.Context, network string, report types.TransactionWarningReport) (err error) {
+	start := time.Now()
+	defer func() {
+		metrics.Record(b.svcMetrics, blockaidServiceName, "ReportTransaction", network, time.Since(start).Seconds(), err)
+	}()
+
+	chain, err := blockaidChain(network)
+	if err != nil {
+		return err
+	}
+
+	body := blockaidReportRequest{
+		Event:   report.Event,
+		Details: report.Details,
+		Report: blockaidReportParams{
+			Type: "params",
+			Params: blockaidTxReportParams{
+				Chain:          chain,
+				AccountAddress: report.AccountAddress,
+				Transaction:    report.TxXDR,
+				Metadata:       newBlockaidMetadata(report.OriginURL),
+			},
+		},
+	}
+	return b.doJSON(ctx, blockaidTxReportPath, "transaction report", body, nil)
+}
+
+// loadCached returns the Redis hits among keys. A Redis failure is logged and
+// treated as all-miss so a cache outage degrades to direct Blockaid calls.
+func (b *blockaidService) loadCached(ctx context.Context, keys []string, makeDest func() any) map[string]any {
+	if b.redis == nil || b.cacheTTL <= 0 || len(keys) == 0 {
+		return nil
+	}
+	hits, err := b.redis.MGetJSON(ctx, keys, makeDest)
+	if err != nil {
+		logger.Warn("blockaid: redis MGet failed; bypassing cache", "error", err)
+		return nil
+	}
+	return hits
+}
+
+func (b *blockaidService) storeCached(ctx context.Context, key string, value any) {
+	if b.redis == nil || b.cacheTTL <= 0 {
+		return
+	}
+	if err := b.redis.SetJSON(ctx, key, value, b.cacheTTL); err != nil {
+		logger.Warn("blockaid: redis SET failed", "key", key, "error", err)
+	}
+}
+
+func blockaidCacheKey(kind, id string) string {
+	return blockaidCacheKeyPrefix + ":" + kind + ":" + id
+}
+
+// attackTypeFeatures flattens a site scan's attack_types map into features,
+// sorted by id so cached and fresh responses are byte-identical.
+func attackTypeFeatures(attackTypes map[string]blockaidAttackType) []types.BlockaidFeature {
+	features := make([]types.BlockaidFeature, 0, len(attackTypes))
+	for id := range attackTypes {
+		features = append(features, types.BlockaidFeature{Type: "Malicious", FeatureID: id})
+	}
+	slices.SortFunc(features, func(a, b types.BlockaidFeature) int { return strings.Compare(a.FeatureID, b.FeatureID) })
+	return features
+}
+
+// doJSON POSTs payload to path and decodes a 200 response into dest; a nil
+// dest discards the body (the report endpoints have nothing to return). Any
+// other status is an UpstreamError so metrics classify it as http_error:<code> and
+// handlers render it as a 502. label names the endpoint in error messages.
+func (b *blockaidService) doJSON(ctx context.Context, path, label string, payload, dest any) error {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encoding blockaid %s request: %w", label, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.baseURL+path, bytes.NewReader(encoded))
+	if err != nil {
+		return fmt.Errorf("building blockaid %s request: %w", label, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", b.apiKey)
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return &metrics.UpstreamError{Kind: "http_error", Err: err}
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return &metrics.UpstreamError{Kind: "http_error", Code: resp.StatusCode, Err: fmt.Errorf("blockaid %s status %d", label, resp.StatusCode)}
+	}
+	if dest == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(dest); err != nil {
+		return fmt.Errorf("decoding blockaid %s response: %w", label, err)
+	}
+	return nil
+}
+
+// normalizeValidation maps a Blockaid validation block onto ScanVerdict. A
+// missing block or one Blockaid could not complete yields RiskUnknown rather
+// than an error: the scan itself succeeded, and the client decides how to
+// present an unscored payload.
+func normalizeValidation(v *blockaidValidation) types.ScanVerdict {
+	verdict := types.ScanVerdict{ResultType: types.RiskUnknown, Features: []types.BlockaidFeature{}}
+	if v == nil {
+		return verdict
+	}
+	if v.Status == blockaidStatusError {
+		verdict.ValidationError = v.Error
+		return verdict
+	}
+	verdict.ResultType = normalizeResultType(v.ResultType)
+	verdict.IsMalicious = verdict.ResultType == types.RiskMalicious
+	verdict.IsSuspicious = verdict.ResultType == types.RiskWarning
+	verdict.Description = v.Description
+	verdict.Reason = v.Reason
+	if v.Features != nil {
+		verdict.Features = v.Features
+	}
+	return verdict
+}
+
+func normalizeResultType(resultType string) string {
+	switch strings.ToLower(resultType) {
+	case types.RiskBenign:
+		return types.RiskBenign
+	case types.RiskWarning, "spam":
+		// Blockaid flags airdropped spam tokens as "Spam"; clients surface it
+		// the same way as a warning.
+		return types.RiskWarning
+	case types.RiskMalicious:
+		return types.RiskMalicious
+	default:
+		return types.RiskUnknown
+	}
+}
+
+func newBlockaidMetadata(originURL string) blockaidMetadata {
+	if originURL == "" {
+		return blockaidMetadata{Type: "in_app"}
+	}
+	return blockaidMetadata{Type: "wallet", URL: originURL}
+}
+
+// blockaidChain maps a Stellar network onto Blockaid's chain identifier.
+func blockaidChain(network string) (string, error) {
+	switch network {
+	case types.PUBLIC:
+		return "pubnet", nil
+	case types.TESTNET:
+		return "testnet", nil
+	case types.FUTURENET:
+		return "futurenet", nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrBlockaidNetworkNotSupported, network)
+	}
+}
@@ -0,0 +1,331 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/metrics"
+	"github.com/stellar/freighter-backend-v2/internal/store"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+const testScanAccount = "GBTYAFHGNZSTE4VBWZYAGB3SRGJEPTI5I4Y22KZ4JTVAN56LESB6JZOF"
+
+func newTestBlockaid(t *testing.T, handler http.Handler) types.BlockaidService {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewBlockaidService(BlockaidServiceConfig{BaseURL: server.URL, APIKey: "test-key"}, nil, nil)
+}
+
+func TestBlockaid_ScanTx_Success(t *testing.T) {
+	t.Parallel()
+
+	var gotPath, gotKey string
+	var gotBody blockaidTxScanRequest
+	svc := newTestBlockaid(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotKey = r.Header.Get("X-API-Key")
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		_, _ = w.Write([]byte(`{
+			"validation": {
+				"status": "Success",
+				"result_type": "Malicious",
+				"description": "drainer",
+				"reason": "known_attacker",
+				"features": [{"type": "Malicious", "feature_id": "KNOWN_MALICIOUS", "description": "bad"}]
+			},
+			"simulation": {"status": "Success", "assets_diffs": {}}
+		}`))
+	}))
+
+	res, err := svc.ScanTx(context.Background(), types.PUBLIC, testScanAccount, "AAAA", "https://dapp.example")
+	require.NoError(t, err)
+
+	assert.Equal(t, blockaidTxScanPath, gotPath)
+	assert.Equal(t, "test-key", gotKey)
+	assert.Equal(t, "pubnet", gotBody.Chain)
+	assert.Equal(t, testScanAccount, gotBody.AccountAddress)
+	assert.Equal(t, "AAAA", gotBody.Transaction)
+	assert.Equal(t, blockaidMetadata{Type: "wallet", URL: "https://dapp.example"}, gotBody.Metadata)
+
+	assert.Equal(t, types.RiskMalicious, res.ResultType)
+	assert.True(t, res.IsMalicious)
+	assert.False(t, res.IsSuspicious)
+	assert.Equal(t, "known_attacker", res.Reason)
+	require.Len(t, res.Features, 1)
+	assert.Equal(t, "KNOWN_MALICIOUS", res.Features[0].FeatureID)
+	assert.JSONEq(t, `{"status":"Success","assets_diffs":{}}`, string(res.Simulation))
+	assert.Empty(t, res.SimulationError)
+}
+
+func TestBlockaid_ScanTx_InAppMetadataWithoutOrigin(t *testing.T) {
+	t.Parallel()
+
+	var gotBody blockaidTxScanRequest
+	svc := newTestBlockaid(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		_, _ = w.Write([]byte(`{"validation":{"status":"Success","result_type":"Benign"}}`))
+	}))
+
+	res, err := svc.ScanTx(context.Background(), types.TESTNET, testScanAccount, "AAAA", "")
+	require.NoError(t, err)
+	assert.Equal(t, "testnet", gotBody.Chain)
+	assert.Equal(t, blockaidMetadata{Type: "in_app"}, gotBody.Metadata)
+	assert.Equal(t, types.RiskBenign, res.ResultType)
+	assert.NotNil(t, res.Features, "features must be non-nil even when Blockaid omits them")
+	assert.Nil(t, res.Simulation)
+}
+
+func TestBlockaid_ScanTx_ValidationErrorIsUnknown(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestBlockaid(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{
+			"validation": {"status": "Error", "error": "could not parse"},
+			"simulation": {"status": "Error", "error": "simulation failed"}
+		}`))
+	}))
+
+	res, err := svc.ScanTx(context.Background(), types.PUBLIC, testScanAccount, "AAAA", "")
+	require.NoError(t, err)
+	assert.Equal(t, types.RiskUnknown, res.ResultType)
+	assert.False(t, res.IsMalicious)
+	assert.Equal(t, "could not parse", res.ValidationError)
+	assert.Equal(t, "simulation failed", res.SimulationError)
+}
+
+func TestBlockaid_ScanTx_UnrecognizedResultTypeIsUnknown(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestBlockaid(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"validation":{"status":"Success","result_type":"Bogus"}}`))
+	}))
+
+	res, err := svc.ScanTx(context.Background(), types.PUBLIC, testScanAccount, "AAAA", "")
+	require.NoError(t, err)
+	assert.Equal(t, types.RiskUnknown, res.ResultType)
+}
+
+func TestBlockaid_ScanTx_ServerError(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestBlockaid(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+	}))
+
+	_, err := svc.ScanTx(context.Background(), types.PUBLIC, testScanAccount, "AAAA", "")
+	require.Error(t, err)
+	var upstream *metrics.UpstreamError
+	require.True(t, errors.As(err, &upstream))
+	assert.Equal(t, http.StatusForbidden, upstream.Code)
+}
+
+func TestBlockaid_ScanTx_RejectsUnknownNetwork(t *testing.T) {
+	t.Parallel()
+
+	svc := NewBlockaidService(BlockaidServiceConfig{BaseURL: "https://example.invalid", APIKey: "test-key"}, nil, nil)
+	_, err := svc.ScanTx(context.Background(), "MAINNET", testScanAccount, "AAAA", "")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrBlockaidNetworkNotSupported))
+}
+
+func TestBlockaid_ScanDapp_Hit(t *testing.T) {
+	t.Parallel()
+
+	var gotPath string
+	var gotBody blockaidSiteScanRequest
+	svc := newTestBlockaid(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		_, _ = w.Write([]byte(`{
+			"status": "hit",
+			"is_malicious": true,
+			"attack_types": {"wallet_drainer": {"score": 1, "threshold": 0.5}, "approval_farming": {"score": 0.9, "threshold": 0.5}}
+		}`))
+	}))
+
+	res, err := svc.ScanDapp(context.Background(), "https://evil.example")
+	require.NoError(t, err)
+	assert.Equal(t, blockaidSiteScanPath, gotPath)
+	assert.Equal(t, "https://evil.example", gotBody.URL)
+	assert.Equal(t, types.DappScanHit, res.Status)
+	assert.Equal(t, "https://evil.example", res.URL)
+	assert.Equal(t, types.RiskMalicious, res.ResultType)
+	assert.True(t, res.IsMalicious)
+	require.Len(t, res.Features, 2)
+	assert.Equal(t, "approval_farming", res.Features[0].FeatureID, "features are sorted by id")
+	assert.Equal(t, "wallet_drainer", res.Features[1].FeatureID)
+}
+
+func TestBlockaid_ScanDapp_MissIsUnknown(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestBlockaid(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"miss"}`))
+	}))
+
+	res, err := svc.ScanDapp(context.Background(), "https://new.example")
+	require.NoError(t, err)
+	assert.Equal(t, types.DappScanMiss, res.Status)
+	assert.Equal(t, types.RiskUnknown, res.ResultType)
+	assert.NotNil(t, res.Features)
+}
+
+func TestBlockaid_ScanAssets_Success(t *testing.T) {
+	t.Parallel()
+
+	usdc := "USDC:" + testScanAccount
+	spam := "FREE:" + testScanAccount
+	gone := "GONE:" + testScanAccount
+
+	var gotPath string
+	var gotBody blockaidTokenBulkScanRequest
+	svc := newTestBlockaid(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		_, _ = w.Write([]byte(`{"results": {
+			"USDC-` + testScanAccount + `": {"result_type": "Benign", "malicious_score": "0.0"},
+			"FREE-` + testScanAccount + `": {"result_type": "Spam", "malicious_score": "0.8", "features": [{"type": "Warning", "feature_id": "AIRDROP_PATTERN", "description": "spam"}]}
+		}}`))
+	}))
+
+	res, err := svc.ScanAssets(context.Background(), types.PUBLIC, []string{"XLM", usdc, spam, gone})
+	require.NoError(t, err)
+
+	assert.Equal(t, blockaidTokenBulkScanPath, gotPath)
+	assert.Equal(t, "stellar", gotBody.Chain)
+	assert.Equal(t, []string{"USDC-" + testScanAccount, "FREE-" + testScanAccount, "GONE-" + testScanAccount}, gotBody.Tokens, "native must not be sent to Blockaid")
+
+	require.Len(t, res, 4)
+	assert.Equal(t, types.RiskBenign, res["XLM"].ResultType)
+	assert.Equal(t, types.RiskBenign, res[usdc].ResultType)
+	assert.Equal(t, "0.0", res[usdc].MaliciousScore)
+	assert.Equal(t, types.RiskWarning, res[spam].ResultType)
+	assert.True(t, res[spam].IsSuspicious)
+	require.Len(t, res[spam].Features, 1)
+	assert.Equal(t, "AIRDROP_PATTERN", res[spam].Features[0].FeatureID)
+	assert.Equal(t, types.RiskUnknown, res[gone].ResultType)
+}
+
+func TestBlockaid_ScanAssets_NativeOnlySkipsUpstream(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestBlockaid(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Blockaid must not be called for a native-only scan")
+	}))
+
+	res, err := svc.ScanAssets(context.Background(), types.PUBLIC, []string{"XLM"})
+	require.NoError(t, err)
+	assert.Equal(t, types.RiskBenign, res["XLM"].ResultType)
+}
+
+func TestBlockaid_ScanAssets_RejectsNonPublic(t *testing.T) {
+	t.Parallel()
+
+	svc := NewBlockaidService(BlockaidServiceConfig{BaseURL: "https://example.invalid", APIKey: "test-key"}, nil, nil)
+	_, err := svc.ScanAssets(context.Background(), types.TESTNET, []string{"XLM"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrBlockaidNetworkNotSupported))
+}
+
+// TestBlockaid_ScanAssets_RedisDownBypassesCache pins the degrade path: an
+// unreachable Redis must not fail the scan, only skip the cache.
+func TestBlockaid_ScanAssets_RedisDownBypassesCache(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{"results": {"USDC-` + testScanAccount + `": {"result_type": "Benign"}}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	redisStore := store.NewRedisStore("localhost", 1, "") // port 1 = no listener
+	svc := NewBlockaidService(BlockaidServiceConfig{BaseURL: server.URL, APIKey: "test-key", CacheTTL: time.Hour}, redisStore, nil)
+
+	res, err := svc.ScanAssets(context.Background(), types.PUBLIC, []string{"USDC:" + testScanAccount})
+	require.NoError(t, err)
+	assert.Equal(t, types.RiskBenign, res["USDC:"+testScanAccount].ResultType)
+	assert.Equal(t, 1, calls)
+}
+
+func TestBlockaid_ReportAsset(t *testing.T) {
+	t.Parallel()
+
+	var gotPath string
+	var gotBody map[string]any
+	svc := newTestBlockaid(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		_, _ = w.Write([]byte(`{"request_id":"abc"}`))
+	}))
+
+	err := svc.ReportAsset(context.Background(), types.PUBLIC, types.AssetWarningReport{
+		Asset: "USDC:" + testScanAccount, Event: types.ReportFalsePositive, Details: "legit",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, blockaidTokenReportPath, gotPath)
+	assert.Equal(t, map[string]any{
+		"event":   "FALSE_POSITIVE",
+		"details": "legit",
+		"report": map[string]any{
+			"type":   "params",
+			"params": map[string]any{"address": "USDC-" + testScanAccount, "chain": "stellar"},
+		},
+	}, gotBody)
+}
+
+func TestBlockaid_ReportTransaction(t *testing.T) {
+	t.Parallel()
+
+	var gotPath string
+	var gotBody struct {
+		Event  string `json:"event"`
+		Report struct {
+			Params blockaidTxReportParams `json:"params"`
+		} `json:"report"`
+	}
+	svc := newTestBlockaid(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	err := svc.ReportTransaction(context.Background(), types.TESTNET, types.TransactionWarningReport{
+		AccountAddress: testScanAccount, TxXDR: "AAAA", Event: types.ReportFalseNegative,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, blockaidTxReportPath, gotPath)
+	assert.Equal(t, "FALSE_NEGATIVE", gotBody.Event)
+	assert.Equal(t, blockaidTxReportParams{
+		Chain: "testnet", AccountAddress: testScanAccount, Transaction: "AAAA", Metadata: blockaidMetadata{Type: "in_app"},
+	}, gotBody.Report.Params)
+}
+
+func TestBlockaid_ReportAsset_ServerError(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestBlockaid(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+
+	err := svc.ReportAsset(context.Background(), types.PUBLIC, types.AssetWarningReport{Asset: "USDC:" + testScanAccount, Event: types.ReportFalsePositive})
+	var upstream *metrics.UpstreamError
+	require.True(t, errors.As(err, &upstream))
+	assert.Equal(t, http.StatusInternalServerError, upstream.Code)
+}
+
+func TestBlockaid_Name(t *testing.T) {
+	t.Parallel()
+	svc := NewBlockaidService(BlockaidServiceConfig{APIKey: "test-key"}, nil, nil)
+	assert.Equal(t, "blockaid", svc.Name())
+}
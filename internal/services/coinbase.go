@@ -0,0 +1,189 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	jwtgo "github.com/golang-jwt/jwt/v5"
+
+	"github.com/stellar/freighter-backend-v2/internal/metrics"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+const (
+	coinbaseServiceName = "coinbase"
+
+	coinbaseHTTPTimeout = 15 * time.Second
+
+	// DefaultCoinbaseBaseURL is the Coinbase Developer Platform API host.
+	DefaultCoinbaseBaseURL = "https://api.developer.coinbase.com"
+
+	coinbaseOnrampTokenPath = "/onramp/v1/token"
+
+	// coinbaseJWTLifetime is how long each request JWT is valid. CDP rejects
+	// tokens older than two minutes, and every request signs a fresh one.
+	coinbaseJWTLifetime = 2 * time.Minute
+
+	// coinbaseStellarBlockchain is the CDP network id for Stellar pubnet.
+	coinbaseStellarBlockchain = "stellar"
+)
+
+// ErrCoinbaseNotConfigured indicates the CDP key or secret is missing.
+var ErrCoinbaseNotConfigured = errors.New("coinbase onramp is not configured")
+
+type coinbaseService struct {
+	baseURL    string
+	apiKey     string
+	privateKey *ecdsa.PrivateKey
+	httpClient *http.Client
+	svcMetrics *metrics.Service
+	now        func() time.Time
+}
+
+// NewCoinbaseService constructs a Coinbase CDP client that signs each request
+// with an ES256 JWT. apiKey is the CDP key name ("organizations/.../apiKeys/...")
+// and apiSecret its EC private key in PEM form; literal "\n" sequences, as
+// secrets are often stored in single-line env vars, are expanded first. With
+// either empty the service is returned unconfigured and every call fails with
+// ErrCoinbaseNotConfigured; a secret that does not parse is a boot error.
+func NewCoinbaseService(baseURL, apiKey, apiSecret string, m *metrics.Service) (types.CoinbaseService, error) {
+	if baseURL == "" {
+		baseURL = DefaultCoinbaseBaseURL
+	}
+
+	var privateKey *ecdsa.PrivateKey
+	if apiKey != "" && apiSecret != "" {
+		key, err := jwtgo.ParseECPrivateKeyFromPEM([]byte(strings.ReplaceAll(apiSecret, `\n`, "\n")))
+		if err != nil {
+			return nil, fmt.Errorf("parsing coinbase API secret: %w", err)
+		}
+		privateKey = key
+	}
+
+	return &coinbaseService{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		privateKey: privateKey,
+		httpClient: &http.Client{Timeout: coinbaseHTTPTimeout},
+		svcMetrics: m,
+		now:        time.Now,
+	}, nil
+}
+
+func (c *coinbaseService) Name() string {
+	return coinbaseServiceName
+}
+
+type coinbaseOnrampAddress struct {
+	Address     string   `json:"address"`
+	Blockchains []string `json:"blockchains"`
+}
+
+type coinbaseOnrampTokenRequest struct {
+	Addresses []coinbaseOnrampAddress `json:"addresses"`
+	Assets    []string                `json:"assets,omitempty"`
+}
+
+type coinbaseOnrampTokenResponse struct {
+	Token     string `json:"token"`
+	ChannelID string `json:"channel_id"`
+}
+
+// GetOnrampSessionToken requests a one-time onramp session for a Stellar
+// destination. Coinbase onramp is pubnet-only, so calls are recorded under
+// PUBLIC.
+func (c *coinbaseService) GetOnrampSessionToken(ctx context.Context, req types.OnrampTokenRequest) (_ *types.OnrampSessionToken, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.Record(c.svcMetrics, coinbaseServiceName, "GetOnrampSessionToken", types.PUBLIC, time.Since(start).Seconds(), err)
+	}()
+
+	if c.privateKey == nil {
+		return nil, ErrCoinbaseNotConfigured
+	}
+
+	body, err := json.Marshal(coinbaseOnrampTokenRequest{
+		Addresses: []coinbaseOnrampAddress{{Address: req.Address, Blockchains: []string{coinbaseStellarBlockchain}}},
+		Assets:    req.Assets,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encoding coinbase onramp token request: %w", err)
+	}
+
+	endpoint := c.baseURL + coinbaseOnrampTokenPath
+	token, err := c.signRequest(http.MethodPost, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("building coinbase onramp token request: %w", err)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, &metrics.UpstreamError{Kind: "http_error", Err: err}
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, &metrics.UpstreamError{Kind: "http_error", Code: resp.StatusCode, Err: fmt.Errorf("coinbase onramp token status %d", resp.StatusCode)}
+	}
+
+	var decoded coinbaseOnrampTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("decoding coinbase onramp token response: %w", err)
+	}
+	if decoded.Token == "" {
+		return nil, errors.New("coinbase onramp token response has no token")
+	}
+	return &types.OnrampSessionToken{Token: decoded.Token, ChannelID: decoded.ChannelID}, nil
+}
+
+// signRequest builds the CDP bearer JWT for one request. CDP binds each token
+// to a single "METHOD host/path" uri claim, and requires the key name as both
+// kid and sub plus a random nonce header so tokens cannot be replayed.
+func (c *coinbaseService) signRequest(method, endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("parsing coinbase endpoint: %w", err)
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generating coinbase JWT nonce: %w", err)
+	}
+
+	now := c.now()
+	token := jwtgo.NewWithClaims(jwtgo.SigningMethodES256, jwtgo.MapClaims{
+		"sub": c.apiKey,
+		"iss": "cdp",
+		"nbf": now.Unix(),
+		"exp": now.Add(coinbaseJWTLifetime).Unix(),
+		"uri": method + " " + u.Host + u.Path,
+	})
+	token.Header["kid"] = c.apiKey
+	token.Header["nonce"] = hex.EncodeToString(nonce)
+
+	signed, err := token.SignedString(c.privateKey)
+	if err != nil {
+		return "", fmt.Errorf("signing coinbase JWT: %w", err)
+	}
+	return signed, nil
+}
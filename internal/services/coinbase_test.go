@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	jwtgo "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/metrics"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+const testCDPKeyName = "organizations/org-1/apiKeys/key-1"
+
+// newTestCDPSecret returns a fresh P-256 key and its SEC1 PEM encoding, the
+// format CDP hands out for ES256 API keys.
+func newTestCDPSecret(t *testing.T) (*ecdsa.PrivateKey, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return key, string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+}
+
+func newTestCoinbase(t *testing.T, secret string, handler http.Handler) (types.CoinbaseService, *httptest.Server) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	svc, err := NewCoinbaseService(server.URL, testCDPKeyName, secret, nil)
+	require.NoError(t, err)
+	return svc, server
+}
+
+func TestCoinbase_GetOnrampSessionToken_Success(t *testing.T) {
+	t.Parallel()
+
+	key, secret := newTestCDPSecret(t)
+	var gotAuth string
+	var gotBody coinbaseOnrampTokenRequest
+	svc, server := newTestCoinbase(t, secret, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		assert.Equal(t, coinbaseOnrampTokenPath, r.URL.Path)
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		_, _ = w.Write([]byte(`{"token":"session-token","channel_id":"chan"}`))
+	}))
+
+	token, err := svc.GetOnrampSessionToken(context.Background(), types.OnrampTokenRequest{
+		Address: testScanAccount,
+		Assets:  []string{"XLM", "USDC"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &types.OnrampSessionToken{Token: "session-token", ChannelID: "chan"}, token)
+
+	assert.Equal(t, []coinbaseOnrampAddress{{Address: testScanAccount, Blockchains: []string{"stellar"}}}, gotBody.Addresses)
+	assert.Equal(t, []string{"XLM", "USDC"}, gotBody.Assets)
+
+	// The bearer must be an ES256 JWT signed by the configured key and bound
+	// to this exact request.
+	require.True(t, strings.HasPrefix(gotAuth, "Bearer "))
+	claims := jwtgo.MapClaims{}
+	parsed, err := jwtgo.ParseWithClaims(strings.TrimPrefix(gotAuth, "Bearer "), claims,
+		func(*jwtgo.Token) (any, error) { return &key.PublicKey, nil },
+		jwtgo.WithValidMethods([]string{"ES256"}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, testCDPKeyName, parsed.Header["kid"])
+	assert.NotEmpty(t, parsed.Header["nonce"])
+	assert.Equal(t, testCDPKeyName, claims["sub"])
+	assert.Equal(t, "cdp", claims["iss"])
+	assert.Equal(t, "POST "+strings.TrimPrefix(server.URL, "http://")+coinbaseOnrampTokenPath, claims["uri"])
+}
+
+func TestCoinbase_SignRequest_Lifetime(t *testing.T) {
+	t.Parallel()
+
+	_, secret := newTestCDPSecret(t)
+	svc, err := NewCoinbaseService("", testCDPKeyName, secret, nil)
+	require.NoError(t, err)
+	cb := svc.(*coinbaseService)
+	fixed := time.Unix(1_700_000_000, 0)
+	cb.now = func() time.Time { return fixed }
+
+	signed, err := cb.signRequest(http.MethodPost, DefaultCoinbaseBaseURL+coinbaseOnrampTokenPath)
+	require.NoError(t, err)
+
+	claims := jwtgo.MapClaims{}
+	_, _, err = jwtgo.NewParser().ParseUnverified(signed, claims)
+	require.NoError(t, err)
+	assert.InDelta(t, float64(fixed.Unix()), claims["nbf"], 0)
+	assert.InDelta(t, float64(fixed.Add(coinbaseJWTLifetime).Unix()), claims["exp"], 0)
+	assert.Equal(t, "POST api.developer.coinbase.com/onramp/v1/token", claims["uri"])
+}
+
+func TestCoinbase_NewCoinbaseService_ExpandsEscapedNewlines(t *testing.T) {
+	t.Parallel()
+
+	_, secret := newTestCDPSecret(t)
+	_, err := NewCoinbaseService("", testCDPKeyName, strings.ReplaceAll(secret, "\n", `\n`), nil)
+	require.NoError(t, err)
+}
+
+func TestCoinbase_NewCoinbaseService_RejectsInvalidSecret(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewCoinbaseService("", testCDPKeyName, "not a pem", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "parsing coinbase API secret")
+}
+
+func TestCoinbase_GetOnrampSessionToken_NotConfigured(t *testing.T) {
+	t.Parallel()
+
+	svc, err := NewCoinbaseService("", "", "", nil)
+	require.NoError(t, err)
+	_, err = svc.GetOnrampSessionToken(context.Background(), types.OnrampTokenRequest{Address: testScanAccount})
+	assert.True(t, errors.Is(err, ErrCoinbaseNotConfigured))
+}
+
+func TestCoinbase_GetOnrampSessionToken_ServerError(t *testing.T) {
+	t.Parallel()
+
+	_, secret := newTestCDPSecret(t)
+	svc, _ := newTestCoinbase(t, secret, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"unauthorized"}`, http.StatusUnauthorized)
+	}))
+
+	_, err := svc.GetOnrampSessionToken(context.Background(), types.OnrampTokenRequest{Address: testScanAccount})
+	var upstream *metrics.UpstreamError
+	require.True(t, errors.As(err, &upstream))
+	assert.Equal(t, http.StatusUnauthorized, upstream.Code)
+}
+
+func TestCoinbase_GetOnrampSessionToken_EmptyToken(t *testing.T) {
+	t.Parallel()
+
+	_, secret := newTestCDPSecret(t)
+	svc, _ := newTestCoinbase(t, secret, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+
+	_, err := svc.GetOnrampSessionToken(context.Background(), types.OnrampTokenRequest{Address: testScanAccount})
+	require.Error(t, err)
+}
+
+func TestCoinbase_Name(t *testing.T) {
+	t.Parallel()
+	svc, err := NewCoinbaseService("", "", "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "coinbase", svc.Name())
+}
@@ -0,0 +1,348 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/stellar/freighter-backend-v2/internal/logger"
+	"github.com/stellar/freighter-backend-v2/internal/metrics"
+	"github.com/stellar/freighter-backend-v2/internal/store"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+const (
+	collectibleMetadataServiceName = "collectible-metadata"
+
+	// DefaultIPFSGatewayURL is the path-style gateway ipfs:// token URIs are
+	// fetched through when none is configured.
+	DefaultIPFSGatewayURL = "https://ipfs.io"
+
+	collectibleMetadataCacheKeyPrefix = "collectibles:v1:metadata"
+
+	collectibleMetadataMaxRedirects = 3
+)
+
+var (
+	// ErrUnsupportedTokenURI indicates a token_uri that is neither https:// nor
+	// ipfs://. Plain http and every other scheme are refused.
+	ErrUnsupportedTokenURI = errors.New("unsupported token_uri")
+	// ErrInvalidCollectibleMetadata indicates a document that does not match
+	// the SEP-50 metadata schema.
+	ErrInvalidCollectibleMetadata = errors.New("invalid SEP-50 metadata")
+)
+
+// CollectibleMetadataServiceConfig is the tunable surface of the metadata
+// resolver.
+type CollectibleMetadataServiceConfig struct {
+	// IPFSGatewayURL defaults to DefaultIPFSGatewayURL when empty.
+	IPFSGatewayURL string
+	// CacheTTL bounds how long a resolved document is reused from Redis. Zero
+	// disables caching.
+	CacheTTL time.Duration
+	// MaxBytes caps the size of a metadata document.
+	MaxBytes int64
+	// FetchTimeout bounds one fetch, redirects included.
+	FetchTimeout time.Duration
+}
+
+type collectibleMetadataService struct {
+	gatewayURL string
+	cacheTTL   time.Duration
+	maxBytes   int64
+	redis      *store.RedisStore
+	httpClient *http.Client
+	svcMetrics *metrics.Service
+}
+
+// NewCollectibleMetadataService constructs the SEP-50 metadata resolver. Token
+// URIs are chosen by whoever deployed the contract, so the client only dials
+// public addresses and follows a few HTTPS redirects. redis may be nil; if so,
+// every lookup fetches the document.
+func NewCollectibleMetadataService(cfg CollectibleMetadataServiceConfig, redis *store.RedisStore, m *metrics.Service) types.CollectibleMetadataService {
+	gatewayURL := cfg.IPFSGatewayURL
+	if gatewayURL == "" {
+		gatewayURL = DefaultIPFSGatewayURL
+	}
+	dialer := &net.Dialer{Timeout: cfg.FetchTimeout, Control: publicAddressOnly}
+	httpClient := &http.Client{
+		Timeout: cfg.FetchTimeout,
+		Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			MaxIdleConns:          100,
+			MaxIdleConnsPerHost:   10,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   cfg.FetchTimeout,
+			ResponseHeaderTimeout: cfg.FetchTimeout,
+			ForceAttemptHTTP2:     true,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= collectibleMetadataMaxRedirects {
+				return fmt.Errorf("stopped after %d redirects", collectibleMetadataMaxRedirects)
+			}
+			if req.URL.Scheme != "https" {
+				return fmt.Errorf("%w: redirect to %s", ErrUnsupportedTokenURI, req.URL.Scheme)
+			}
+			return nil
+		},
+	}
+	return &collectibleMetadataService{
+		gatewayURL: strings.TrimRight(gatewayURL, "/"),
+		cacheTTL:   cfg.CacheTTL,
+		maxBytes:   cfg.MaxBytes,
+		redis:      redis,
+		httpClient: httpClient,
+		svcMetrics: m,
+	}
+}
+
+func (c *collectibleMetadataService) Name() string {
+	return collectibleMetadataServiceName
+}
+
+// GetMetadata resolves tokenURI, serving from Redis when a validated copy is
+// cached. Only documents that pass validation are cached.
+func (c *collectibleMetadataService) GetMetadata(ctx context.Context, network, tokenURI string) (_ *types.CollectibleMetadata, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.Record(c.svcMetrics, collectibleMetadataServiceName, "GetMetadata", network, time.Since(start).Seconds(), err)
+	}()
+
+	fetchURL, err := c.resolveURI(tokenURI)
+	if err != nil {
+		return nil, err
+	}
+
+	key := collectibleMetadataCacheKey(tokenURI)
+	if hit := c.loadCached(ctx, key); hit != nil {
+		return hit, nil
+	}
+
+	raw, err := c.fetch(ctx, fetchURL)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := c.parseMetadata(raw)
+	if err != nil {
+		return nil, err
+	}
+	c.storeCached(ctx, key, metadata)
+	return metadata, nil
+}
+
+// resolveURI maps a token_uri to the HTTPS URL to fetch. ipfs://<cid>/<path>
+// (and the older ipfs://ipfs/<cid>/<path>) goes through the gateway.
+func (c *collectibleMetadataService) resolveURI(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnsupportedTokenURI, err)
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "https":
+		if u.Host == "" {
+			return "", fmt.Errorf("%w: missing host", ErrUnsupportedTokenURI)
+		}
+		return u.String(), nil
+	case "ipfs":
+		p := strings.TrimPrefix(u.Host+u.Path, "ipfs/")
+		if p == "" {
+			return "", fmt.Errorf("%w: missing CID", ErrUnsupportedTokenURI)
+		}
+		return c.gatewayURL + "/ipfs/" + p, nil
+	}
+	return "", fmt.Errorf("%w: scheme %q", ErrUnsupportedTokenURI, u.Scheme)
+}
+
+// fetch GETs a metadata document, refusing anything over maxBytes whether or
+// not the server declared its length.
+func (c *collectibleMetadataService) fetch(ctx context.Context, fetchURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fetchURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building metadata request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, &metrics.UpstreamError{Kind: "http_error", Err: err}
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, c.maxBytes))
+		return nil, &metrics.UpstreamError{Kind: "http_error", Code: resp.StatusCode, Err: fmt.Errorf("metadata status %d", resp.StatusCode)}
+	}
+	if resp.ContentLength > c.maxBytes {
+		return nil, fmt.Errorf("metadata is %d bytes, limit is %d", resp.ContentLength, c.maxBytes)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, c.maxBytes+1))
+	if err != nil {
+		return nil, &metrics.UpstreamError{Kind: "http_error", Err: err}
+	}
+	if int64(len(body)) > c.maxBytes {
+		return nil, fmt.Errorf("metadata exceeds %d bytes", c.maxBytes)
+	}
+	return body, nil
+}
+
+// sep50Document mirrors the SEP-50 metadata schema. Typed fields make
+// json.Unmarshal reject a document whose fields have the wrong JSON type;
+// unknown fields are ignored.
+type sep50Document struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Image       string           `json:"image"`
+	ExternalURL string           `json:"external_url"`
+	Attributes  []sep50Attribute `json:"attributes"`
+}
+
+type sep50Attribute struct {
+	TraitType   string          `json:"trait_type"`
+	Value       json.RawMessage `json:"value"`
+	DisplayType string          `json:"display_type"`
+}
+
+func (c *collectibleMetadataService) parseMetadata(raw []byte) (*types.CollectibleMetadata, error) {
+	var doc sep50Document
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCollectibleMetadata, err)
+	}
+	if strings.TrimSpace(doc.Name) == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidCollectibleMetadata)
+	}
+
+	metadata := &types.CollectibleMetadata{
+		Name:        doc.Name,
+		Description: doc.Description,
+	}
+	if doc.Image != "" {
+		image, err := c.resolveURI(doc.Image)
+		if err != nil {
+			return nil, fmt.Errorf("%w: image: %v", ErrInvalidCollectibleMetadata, err)
+		}
+		metadata.Image = image
+	}
+	if doc.ExternalURL != "" {
+		u, err := url.Parse(doc.ExternalURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return nil, fmt.Errorf("%w: external_url must be an https URL", ErrInvalidCollectibleMetadata)
+		}
+		metadata.ExternalURL = doc.ExternalURL
+	}
+	for i, attr := range doc.Attributes {
+		if strings.TrimSpace(attr.TraitType) == "" {
+			return nil, fmt.Errorf("%w: attributes[%d].trait_type is required", ErrInvalidCollectibleMetadata, i)
+		}
+		if !isScalarJSON(attr.Value) {
+			return nil, fmt.Errorf("%w: attributes[%d].value must be a string, number or boolean", ErrInvalidCollectibleMetadata, i)
+		}
+		metadata.Attributes = append(metadata.Attributes, types.CollectibleAttribute{
+			TraitType:   attr.TraitType,
+			Value:       attr.Value,
+			DisplayType: attr.DisplayType,
+		})
+	}
+	return metadata, nil
+}
+
+// isScalarJSON reports whether v is a JSON string, number or boolean.
+func isScalarJSON(v json.RawMessage) bool {
+	v = bytes.TrimSpace(v)
+	if len(v) == 0 {
+		return false
+	}
+	switch v[0] {
+	case '"', 't', 'f', '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return true
+	}
+	return false
+}
+
+// nonGlobalPrefixes are special-purpose ranges that the netip predicates used
+// by publicAddressOnly don't cover: the IANA special-purpose registries'
+// entries that aren't globally reachable, plus the translation prefixes that
+// embed an IPv4 address and could reach an internal one.
+var nonGlobalPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "this network"
+	netip.MustParsePrefix("100.64.0.0/10"),   // carrier-grade NAT (RFC 6598)
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // TEST-NET-1
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // TEST-NET-2
+	netip.MustParsePrefix("203.0.113.0/24"),  // TEST-NET-3
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved, broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // local-use NAT64
+	netip.MustParsePrefix("100::/64"),        // discard-only
+	netip.MustParsePrefix("2001::/23"),       // IETF protocol assignments
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation
+	netip.MustParsePrefix("2002::/16"),       // 6to4
+	netip.MustParsePrefix("3fff::/20"),       // documentation
+	netip.MustParsePrefix("5f00::/16"),       // segment routing
+}
+
+// publicAddressOnly is a net.Dialer Control hook that refuses to connect to
+// loopback, private, link-local, shared (CGNAT) and other addresses that are
+// not globally routable. It runs after DNS resolution, so a public hostname
+// that resolves to an internal address is refused too.
+func publicAddressOnly(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+		return fmt.Errorf("refusing to dial non-public address %s", ip)
+	}
+	for _, prefix := range nonGlobalPrefixes {
+		if prefix.Contains(ip) {
+			return fmt.Errorf("refusing to dial non-public address %s", ip)
+		}
+	}
+	return nil
+}
+
+func (c *collectibleMetadataService) loadCached(ctx context.Context, key string) *types.CollectibleMetadata {
+	if c.redis == nil || c.cacheTTL <= 0 {
+		return nil
+	}
+	hits, err := c.redis.MGetJSON(ctx, []string{key}, func() any { return &types.CollectibleMetadata{} })
+	if err != nil {
+		logger.Warn("collectible metadata: redis MGet failed; bypassing cache", "error", err)
+		return nil
+	}
+	hit, _ := hits[key].(*types.CollectibleMetadata)
+	return hit
+}
+
+func (c *collectibleMetadataService) storeCached(ctx context.Context, key string, value *types.CollectibleMetadata) {
+	if c.redis == nil || c.cacheTTL <= 0 {
+		return
+	}
+	if err := c.redis.SetJSON(ctx, key, value, c.cacheTTL); err != nil {
+		logger.Warn("collectible metadata: redis SET failed", "key", key, "error", err)
+	}
+}
+
+// collectibleMetadataCacheKey hashes the token_uri, which is contract-chosen
+// and unbounded in length, into a fixed-size key.
+func collectibleMetadataCacheKey(tokenURI string) string {
+	sum := sha256.Sum256([]byte(tokenURI))
+	return collectibleMetadataCacheKeyPrefix + ":" + hex.EncodeToString(sum[:])
+}
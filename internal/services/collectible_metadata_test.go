@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/metrics"
+	"github.com/stellar/freighter-backend-v2/internal/store"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+const testMetadataDoc = `{
+	"name": "Stellar House #1",
+	"description": "A key to the house",
+	"image": "ipfs://bafyimage/1.png",
+	"external_url": "https://example.com/1",
+	"attributes": [
+		{"trait_type": "level", "value": 3, "display_type": "number"},
+		{"trait_type": "color", "value": "blue"},
+		{"trait_type": "rare", "value": true}
+	],
+	"extra": {"ignored": true}
+}`
+
+// newTestMetadataService points the resolver at a TLS test server used as
+// both the HTTPS host and the IPFS gateway. The server's own client replaces
+// the public-address-only one, since httptest listens on loopback.
+func newTestMetadataService(t *testing.T, handler http.Handler, maxBytes int64) (*collectibleMetadataService, *httptest.Server) {
+	t.Helper()
+	server := httptest.NewTLSServer(handler)
+	t.Cleanup(server.Close)
+	svc := NewCollectibleMetadataService(CollectibleMetadataServiceConfig{
+		IPFSGatewayURL: server.URL + "/",
+		MaxBytes:       maxBytes,
+		FetchTimeout:   time.Second,
+	}, nil, nil).(*collectibleMetadataService)
+	svc.httpClient = server.Client()
+	return svc, server
+}
+
+func TestCollectibleMetadata_ResolvesIPFSThroughGateway(t *testing.T) {
+	t.Parallel()
+
+	var gotPath string
+	svc, server := newTestMetadataService(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		_, _ = w.Write([]byte(testMetadataDoc))
+	}), 1<<16)
+
+	got, err := svc.GetMetadata(context.Background(), types.PUBLIC, "ipfs://bafymeta/1.json")
+	require.NoError(t, err)
+	assert.Equal(t, "/ipfs/bafymeta/1.json", gotPath)
+	assert.Equal(t, "Stellar House #1", got.Name)
+	assert.Equal(t, "A key to the house", got.Description)
+	assert.Equal(t, server.URL+"/ipfs/bafyimage/1.png", got.Image)
+	assert.Equal(t, "https://example.com/1", got.ExternalURL)
+	require.Len(t, got.Attributes, 3)
+	assert.Equal(t, "level", got.Attributes[0].TraitType)
+	assert.JSONEq(t, `3`, string(got.Attributes[0].Value))
+	assert.Equal(t, "number", got.Attributes[0].DisplayType)
+	assert.JSONEq(t, `"blue"`, string(got.Attributes[1].Value))
+	assert.JSONEq(t, `true`, string(got.Attributes[2].Value))
+}
+
+func TestCollectibleMetadata_FetchesHTTPS(t *testing.T) {
+	t.Parallel()
+
+	svc, server := newTestMetadataService(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/meta/7", r.URL.Path)
+		_, _ = w.Write([]byte(`{"name":"Seven"}`))
+	}), 1<<16)
+
+	got, err := svc.GetMetadata(context.Background(), types.TESTNET, server.URL+"/meta/7")
+	require.NoError(t, err)
+	assert.Equal(t, &types.CollectibleMetadata{Name: "Seven"}, got)
+}
+
+func TestCollectibleMetadata_ResolveURI(t *testing.T) {
+	t.Parallel()
+
+	svc := NewCollectibleMetadataService(CollectibleMetadataServiceConfig{}, nil, nil).(*collectibleMetadataService)
+
+	for _, tc := range []struct {
+		uri  string
+		want string
+	}{
+		{"https://example.com/a.json", "https://example.com/a.json"},
+		{"ipfs://bafy/a.json", DefaultIPFSGatewayURL + "/ipfs/bafy/a.json"},
+		{"ipfs://ipfs/bafy/a.json", DefaultIPFSGatewayURL + "/ipfs/bafy/a.json"},
+		{"ipfs://bafy", DefaultIPFSGatewayURL + "/ipfs/bafy"},
+	} {
+		got, err := svc.resolveURI(tc.uri)
+		require.NoError(t, err, tc.uri)
+		assert.Equal(t, tc.want, got)
+	}
+
+	for _, uri := range []string{"http://example.com/a.json", "ftp://example.com/a", "data:application/json,{}", "ipfs://", "https:///path", "MNFT"} {
+		_, err := svc.resolveURI(uri)
+		assert.ErrorIs(t, err, ErrUnsupportedTokenURI, uri)
+	}
+}
+
+func TestCollectibleMetadata_RejectsInvalidDocuments(t *testing.T) {
+	t.Parallel()
+
+	for name, doc := range map[string]string{
+		"not json":            `<html></html>`,
+		"missing name":        `{"description":"x"}`,
+		"name wrong type":     `{"name": 7}`,
+		"http image":          `{"name":"a","image":"http://example.com/a.png"}`,
+		"non-https external":  `{"name":"a","external_url":"javascript:alert(1)"}`,
+		"attribute no trait":  `{"name":"a","attributes":[{"value":1}]}`,
+		"attribute obj value": `{"name":"a","attributes":[{"trait_type":"t","value":{"a":1}}]}`,
+		"attributes not list": `{"name":"a","attributes":{"trait_type":"t"}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			svc, _ := newTestMetadataService(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(doc))
+			}), 1<<16)
+
+			_, err := svc.GetMetadata(context.Background(), types.PUBLIC, "ipfs://bafy")
+			assert.ErrorIs(t, err, ErrInvalidCollectibleMetadata)
+		})
+	}
+}
+
+func TestCollectibleMetadata_EnforcesSizeLimit(t *testing.T) {
+	t.Parallel()
+
+	big := fmt.Sprintf(`{"name":"a","description":%q}`, strings.Repeat("x", 2048))
+
+	t.Run("declared length", func(t *testing.T) {
+		t.Parallel()
+		svc, _ := newTestMetadataService(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Length", fmt.Sprint(len(big)))
+			_, _ = w.Write([]byte(big))
+		}), 1024)
+
+		_, err := svc.GetMetadata(context.Background(), types.PUBLIC, "ipfs://bafy")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "limit is 1024")
+	})
+
+	t.Run("streamed body", func(t *testing.T) {
+		t.Parallel()
+		svc, _ := newTestMetadataService(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Flushing forces chunked encoding, so no Content-Length is sent.
+			_, _ = w.Write([]byte(big[:10]))
+			w.(http.Flusher).Flush()
+			_, _ = w.Write([]byte(big[10:]))
+		}), 1024)
+
+		_, err := svc.GetMetadata(context.Background(), types.PUBLIC, "ipfs://bafy")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "exceeds 1024 bytes")
+	})
+}
+
+func TestCollectibleMetadata_NonOKIsUpstreamError(t *testing.T) {
+	t.Parallel()
+
+	svc, _ := newTestMetadataService(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}), 1<<16)
+
+	_, err := svc.GetMetadata(context.Background(), types.PUBLIC, "ipfs://bafy")
+	var upErr *metrics.UpstreamError
+	require.True(t, errors.As(err, &upErr))
+	assert.Equal(t, http.StatusNotFound, upErr.Code)
+}
+
+func TestCollectibleMetadata_RefusesNonPublicAddresses(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached a loopback server")
+	}))
+	t.Cleanup(server.Close)
+
+	// The production client, not server.Client(): the dial guard must stop the
+	// connection before TLS is even attempted.
+	svc := NewCollectibleMetadataService(CollectibleMetadataServiceConfig{MaxBytes: 1024, FetchTimeout: time.Second}, nil, nil)
+	_, err := svc.GetMetadata(context.Background(), types.PUBLIC, server.URL)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "non-public address")
+
+	for _, addr := range []string{"127.0.0.1:443", "[::1]:443", "10.0.0.1:443", "192.168.1.1:443", "169.254.169.254:80", "[fe80::1]:443", "0.0.0.0:443", "[::ffff:127.0.0.1]:443",
+		"100.64.0.1:443", "100.127.255.254:443", "198.18.0.1:443", "192.0.2.10:443", "240.0.0.1:443", "255.255.255.255:443",
+		"[64:ff9b::a00:1]:443", "[2001:db8::1]:443", "[2002:a00:1::1]:443", "[::ffff:100.64.0.1]:443"} {
+		assert.Error(t, publicAddressOnly("tcp", addr, nil), addr)
+	}
+	assert.NoError(t, publicAddressOnly("tcp", "93.184.216.34:443", nil))
+	assert.NoError(t, publicAddressOnly("tcp", "100.128.0.1:443", nil))
+	assert.NoError(t, publicAddressOnly("tcp", "[2606:4700::1111]:443", nil))
+}
+
+func TestCollectibleMetadata_RedisUnavailableBypassesCache(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{"name":"a"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	redisStore := store.NewRedisStore("localhost", 1, "") // port 1 = no listener
+	svc := NewCollectibleMetadataService(CollectibleMetadataServiceConfig{
+		CacheTTL:     time.Hour,
+		MaxBytes:     1024,
+		FetchTimeout: time.Second,
+	}, redisStore, nil).(*collectibleMetadataService)
+	svc.httpClient = server.Client()
+
+	for range 2 {
+		got, err := svc.GetMetadata(context.Background(), types.PUBLIC, server.URL)
+		require.NoError(t, err)
+		assert.Equal(t, "a", got.Name)
+	}
+	assert.Equal(t, 2, calls)
+}
+
+func TestCollectibleMetadataCacheKey(t *testing.T) {
+	t.Parallel()
+
+	a := collectibleMetadataCacheKey("ipfs://bafy/1.json")
+	assert.True(t, strings.HasPrefix(a, collectibleMetadataCacheKeyPrefix+":"))
+	assert.Len(t, a, len(collectibleMetadataCacheKeyPrefix)+1+64)
+	assert.NotEqual(t, a, collectibleMetadataCacheKey("ipfs://bafy/2.json"))
+}
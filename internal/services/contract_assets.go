@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stellar/go-stellar-sdk/txnbuild"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/stellar/freighter-backend-v2/internal/logger"
+	"github.com/stellar/freighter-backend-v2/internal/metrics"
+	"github.com/stellar/freighter-backend-v2/internal/store"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils"
+	"github.com/stellar/freighter-backend-v2/internal/utils/assetid"
+	"github.com/stellar/freighter-backend-v2/internal/utils/scval"
+)
+
+const (
+	contractAssetsServiceName = "contract-assets"
+
+	// sacNativeName is what a SAC's name() returns for native XLM; credit
+	// assets return "CODE:ISSUER".
+	sacNativeName = "native"
+
+	// maxConcurrentResolutions bounds the simulations one ClassicAssets call
+	// runs at once.
+	maxConcurrentResolutions = 10
+)
+
+type contractAssetResolver struct {
+	rpc        types.RPCService
+	redis      *store.RedisStore
+	svcMetrics *metrics.Service
+}
+
+// cachedClassicAsset is the Redis shape of a resolution. Classic is empty for
+// a contract that is not a SAC.
+type cachedClassicAsset struct {
+	Classic string `json:"classic"`
+}
+
+// NewContractAssetResolver resolves SACs by simulating the contract's name()
+// through rpc. redis may be nil.
+func NewContractAssetResolver(rpc types.RPCService, redis *store.RedisStore, m *metrics.Service) types.ContractAssetResolver {
+	return &contractAssetResolver{rpc: rpc, redis: redis, svcMetrics: m}
+}
+
+func (r *contractAssetResolver) Name() string { return contractAssetsServiceName }
+
+// ClassicAsset asks the contract for its name(), which a SAC reports as
+// "native" or "CODE:ISSUER", and trusts the answer only if that asset's SAC
+// address is contractID — any contract can return such a name. The address
+// is a hash of the asset, so the answer can never change and is cached
+// without expiry. A contract whose simulation fails is reported as not a SAC
+// but left uncached: a SAC whose instance has been archived fails the same
+// way until it is restored.
+func (r *contractAssetResolver) ClassicAsset(ctx context.Context, network, contractID string) (_ string, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.Record(r.svcMetrics, contractAssetsServiceName, "ClassicAsset", network, time.Since(start).Seconds(), err)
+	}()
+
+	passphrase, err := utils.NetworkPassphrase(network)
+	if err != nil {
+		return "", err
+	}
+	if classic, ok := r.cached(ctx, network, []string{contractID})[contractID]; ok {
+		return classic, nil
+	}
+	return r.resolve(ctx, network, passphrase, contractID)
+}
+
+// ClassicAssets resolves contractIDs like ClassicAsset, reading the cache for
+// all of them in one round trip and simulating only those it doesn't hold.
+// A contract that fails to resolve, for example on an RPC error or once ctx
+// is done, is left out of the result.
+func (r *contractAssetResolver) ClassicAssets(ctx context.Context, network string, contractIDs []string) (_ map[string]string, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.Record(r.svcMetrics, contractAssetsServiceName, "ClassicAssets", network, time.Since(start).Seconds(), err)
+	}()
+
+	passphrase, err := utils.NetworkPassphrase(network)
+	if err != nil {
+		return nil, err
+	}
+	result := r.cached(ctx, network, contractIDs)
+
+	var (
+		mu sync.Mutex
+		g  errgroup.Group
+	)
+	g.SetLimit(maxConcurrentResolutions)
+	for _, contractID := range contractIDs {
+		if _, ok := result[contractID]; ok {
+			continue
+		}
+		g.Go(func() error {
+			classic, resolveErr := r.resolve(ctx, network, passphrase, contractID)
+			if resolveErr != nil {
+				if ctx.Err() == nil {
+					logger.Warn("contract-assets: resolving contract failed", "network", network, "contract", contractID, "error", resolveErr)
+				}
+				return nil
+			}
+			mu.Lock()
+			result[contractID] = classic
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return result, nil
+}
+
+// cached returns the cached resolutions of contractIDs. Contracts absent from
+// the cache, or every contract when Redis is unavailable, are left out.
+func (r *contractAssetResolver) cached(ctx context.Context, network string, contractIDs []string) map[string]string {
+	result := make(map[string]string, len(contractIDs))
+	if r.redis == nil || len(contractIDs) == 0 {
+		return result
+	}
+	keys := make([]string, len(contractIDs))
+	for i, contractID := range contractIDs {
+		keys[i] = tokenDetailsCacheKey(network, "sac", contractID)
+	}
+	cached, err := r.redis.MGetJSON(ctx, keys, func() any { return new(cachedClassicAsset) })
+	if err != nil {
+		logger.Warn("contract-assets: redis MGET failed", "network", network, "error", err)
+		return result
+	}
+	for i, contractID := range contractIDs {
+		if hit, ok := cached[keys[i]].(*cachedClassicAsset); ok {
+			result[contractID] = hit.Classic
+		}
+	}
+	return result
+}
+
+// resolve simulates contractID's name() and caches the answer.
+func (r *contractAssetResolver) resolve(ctx context.Context, network, passphrase, contractID string) (string, error) {
+	contract, err := utils.ScAddressFromContractString(contractID)
+	if err != nil {
+		return "", err
+	}
+
+	source := &txnbuild.SimpleAccount{AccountID: utils.SimulationSourceAccount}
+	v, err := r.rpc.SimulateInvocation(ctx, *contract, source, "name", []xdr.ScVal{}, txnbuild.NewTimeout(300), network)
+	if err != nil {
+		var upErr *metrics.UpstreamError
+		if errors.As(err, &upErr) && upErr.Kind == "simulation_error" {
+			return "", nil
+		}
+		return "", err
+	}
+
+	classic := ""
+	if v != nil {
+		if name, textErr := scval.Text(*v); textErr == nil {
+			classic = sacClassicAsset(name, contractID, passphrase)
+		}
+	}
+	if r.redis != nil {
+		key := tokenDetailsCacheKey(network, "sac", contractID)
+		if setErr := r.redis.SetJSON(ctx, key, cachedClassicAsset{Classic: classic}, 0); setErr != nil {
+			logger.Warn("contract-assets: redis SET failed", "contract", contractID, "error", setErr)
+		}
+	}
+	return classic, nil
+}
+
+// sacClassicAsset turns a SAC-style name() into a canonical classic asset id
+// and returns it if that asset's SAC is contractID, or "" otherwise.
+func sacClassicAsset(name, contractID, passphrase string) string {
+	candidate := assetid.NativeCanonical
+	if name != sacNativeName {
+		if !strings.Contains(name, ":") {
+			return ""
+		}
+		canonical, err := assetid.Normalize(name)
+		if err != nil {
+			return ""
+		}
+		candidate = canonical
+	}
+	sacID, err := assetid.SACContractID(candidate, passphrase)
+	if err != nil || sacID != contractID {
+		return ""
+	}
+	return candidate
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/metrics"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils"
+)
+
+// pubnetUSDCSAC is the SAC of USDC:testIssuer on pubnet.
+const pubnetUSDCSAC = "CCW67TSZV3SSS2HXMBQ5JFGCKJNXKZM7UQUWUZPUTHXSTZLEO7SJMI75"
+
+func scString(s string) *xdr.ScVal {
+	v := xdr.ScString(s)
+	return &xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &v}
+}
+
+func TestContractAssetResolver_ResolvesSACs(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		network  string
+		contract string
+		reported string
+		want     string
+	}{
+		{"credit SAC", types.PUBLIC, pubnetUSDCSAC, "USDC:" + testIssuer, "USDC:" + testIssuer},
+		{"native SAC", types.TESTNET, testTokenContract, sacNativeName, "XLM"},
+		{"name of another asset's SAC", types.PUBLIC, pubnetUSDCSAC, "EURC:" + testIssuer, ""},
+		{"SAC of another network", types.PUBLIC, testTokenContract, sacNativeName, ""},
+		{"SEP-41 token name", types.PUBLIC, pubnetUSDCSAC, "USD Coin", ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rpc := &utils.MockRPCService{SimulateResultOverride: scString(tc.reported)}
+			got, err := NewContractAssetResolver(rpc, nil, nil).ClassicAsset(context.Background(), tc.network, tc.contract)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestContractAssetResolver_SimulationErrorIsNotASAC(t *testing.T) {
+	t.Parallel()
+
+	rpc := &utils.MockRPCService{SimulateError: &metrics.UpstreamError{Kind: "simulation_error", Err: errors.New("no name")}}
+	got, err := NewContractAssetResolver(rpc, nil, nil).ClassicAsset(context.Background(), types.PUBLIC, pubnetUSDCSAC)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestContractAssetResolver_PropagatesRPCFailures(t *testing.T) {
+	t.Parallel()
+
+	rpc := &utils.MockRPCService{SimulateError: &metrics.UpstreamError{Kind: "http_error", Code: 503, Err: errors.New("down")}}
+	_, err := NewContractAssetResolver(rpc, nil, nil).ClassicAsset(context.Background(), types.PUBLIC, pubnetUSDCSAC)
+	require.Error(t, err)
+}
+
+func TestContractAssetResolver_RejectsBadInput(t *testing.T) {
+	t.Parallel()
+
+	resolver := NewContractAssetResolver(&utils.MockRPCService{}, nil, nil)
+	_, err := resolver.ClassicAsset(context.Background(), "BOGUS", pubnetUSDCSAC)
+	require.Error(t, err)
+	_, err = resolver.ClassicAsset(context.Background(), types.PUBLIC, "CNOTACONTRACT")
+	require.Error(t, err)
+}
+
+func TestContractAssetResolver_ClassicAssets(t *testing.T) {
+	t.Parallel()
+
+	rpc := &utils.MockRPCService{SimulateResultOverride: scString("USDC:" + testIssuer)}
+	got, err := NewContractAssetResolver(rpc, nil, nil).ClassicAssets(context.Background(), types.PUBLIC, []string{pubnetUSDCSAC, testTokenContract})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{pubnetUSDCSAC: "USDC:" + testIssuer, testTokenContract: ""}, got)
+}
+
+func TestContractAssetResolver_ClassicAssetsLeavesOutFailures(t *testing.T) {
+	t.Parallel()
+
+	rpc := &utils.MockRPCService{SimulateError: &metrics.UpstreamError{Kind: "http_error", Code: 503, Err: errors.New("down")}}
+	resolver := NewContractAssetResolver(rpc, nil, nil)
+	got, err := resolver.ClassicAssets(context.Background(), types.PUBLIC, []string{pubnetUSDCSAC})
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	_, err = resolver.ClassicAssets(context.Background(), "BOGUS", []string{pubnetUSDCSAC})
+	require.Error(t, err)
+}
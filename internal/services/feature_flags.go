@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+
+	"github.com/stellar/freighter-backend-v2/internal/logger"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+// FeatureFlagsService evaluates the flags of a types.FeatureFlagSource per
+// request. Flags are held in memory by the source, so evaluation does no I/O.
+type FeatureFlagsService struct {
+	source types.FeatureFlagSource
+}
+
+var _ types.FeatureFlagsService = (*FeatureFlagsService)(nil)
+
+// NewFeatureFlagsService evaluates source's flags, or
+// types.DefaultFeatureFlags when source is nil.
+func NewFeatureFlagsService(source types.FeatureFlagSource) *FeatureFlagsService {
+	return &FeatureFlagsService{source: source}
+}
+
+// Evaluate returns every flag's value for fc. While the source cannot supply
+// flags, the built-in defaults are evaluated instead, so clients keep getting
+// an answer.
+func (s *FeatureFlagsService) Evaluate(ctx context.Context, fc types.FlagContext) map[string]bool {
+	flags := s.flags(ctx)
+	values := make(map[string]bool, len(flags))
+	for _, f := range flags {
+		values[f.Key] = f.Evaluate(fc)
+	}
+	return values
+}
+
+// IsEnabled returns the value of the flag with key for fc, or true when no
+// such flag is defined.
+func (s *FeatureFlagsService) IsEnabled(ctx context.Context, key string, fc types.FlagContext) bool {
+	for _, f := range s.flags(ctx) {
+		if f.Key == key {
+			return f.Evaluate(fc)
+		}
+	}
+	return true
+}
+
+func (s *FeatureFlagsService) flags(ctx context.Context) []types.FeatureFlag {
+	if s.source == nil {
+		return types.DefaultFeatureFlags()
+	}
+	flags, err := s.source.FeatureFlags(ctx)
+	if err != nil {
+		// The source logs its own load failures; this would repeat them per request.
+		logger.Debug("feature flags: source unavailable; evaluating built-in defaults", "error", err)
+		return types.DefaultFeatureFlags()
+	}
+	return flags
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils"
+)
+
+func TestFeatureFlagsService_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ios := types.FlagContext{Platform: "ios", Version: "1.6.23"}
+	legacy := map[string]bool{types.FeatureFlagSwap: false, types.FeatureFlagDiscover: false, types.FeatureFlagOnramp: false}
+
+	assert.Equal(t, legacy, NewFeatureFlagsService(nil).Evaluate(ctx, ios), "no source evaluates the built-in defaults")
+
+	source := &utils.MockFeatureFlagSource{Err: types.ErrFeatureFlagsNotLoaded}
+	svc := NewFeatureFlagsService(source)
+	assert.Equal(t, legacy, svc.Evaluate(ctx, ios), "an unloaded source falls back to the built-in defaults")
+
+	source.Err = nil
+	source.Flags = []types.FeatureFlag{{Key: types.FeatureFlagSwap, Enabled: true}, {Key: "new_home"}}
+	assert.Equal(t, map[string]bool{types.FeatureFlagSwap: true, "new_home": false}, svc.Evaluate(ctx, ios))
+}
+
+func TestFeatureFlagsService_IsEnabled(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	svc := NewFeatureFlagsService(&utils.MockFeatureFlagSource{Flags: []types.FeatureFlag{
+		{Key: "route_token_prices", Enabled: false},
+	}})
+	assert.False(t, svc.IsEnabled(ctx, "route_token_prices", types.FlagContext{}))
+	assert.True(t, svc.IsEnabled(ctx, "route_token_details", types.FlagContext{}), "an undefined flag is enabled")
+}
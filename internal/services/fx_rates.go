@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/stellar/freighter-backend-v2/internal/logger"
+	"github.com/stellar/freighter-backend-v2/internal/store"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+const (
+	fxRatesServiceName = "fx-rates"
+
+	// DefaultFXRatesCacheTTL suits daily reference rates: a refresh every
+	// hour picks up a new publication well within the day it applies to.
+	DefaultFXRatesCacheTTL = time.Hour
+
+	fxRatesCacheKey     = "fx:v1:" + types.BaseCurrency
+	fxRatesFetchTimeout = 10 * time.Second
+)
+
+type fxRatesService struct {
+	provider types.FXRatesProvider
+	redis    *store.RedisStore
+	cacheTTL time.Duration
+	// fetchGroup coalesces concurrent cache misses into one provider call.
+	fetchGroup singleflight.Group
+}
+
+// NewFXRatesService wires an FX rates provider behind a Redis-cached rates
+// table. redis may be nil, in which case every conversion fetches fresh
+// rates. A non-positive cacheTTL falls back to DefaultFXRatesCacheTTL.
+func NewFXRatesService(provider types.FXRatesProvider, redis *store.RedisStore, cacheTTL time.Duration) types.FXRatesService {
+	if cacheTTL <= 0 {
+		cacheTTL = DefaultFXRatesCacheTTL
+	}
+	return &fxRatesService{provider: provider, redis: redis, cacheTTL: cacheTTL}
+}
+
+func (s *fxRatesService) Name() string { return fxRatesServiceName }
+
+// ConvertPrices restates BaseCurrency entries in currency. The current price
+// is multiplied by today's rate. The 24h change is recomputed against the
+// previous rate so it reflects what a holder of the local currency saw:
+// (1+c)·rate/previousRate − 1. Without a previous rate the currency is
+// assumed flat over the day and the change is kept as is.
+func (s *fxRatesService) ConvertPrices(ctx context.Context, entries map[string]*types.PriceEntry, currency string) (map[string]*types.PriceEntry, error) {
+	currency = strings.ToUpper(currency)
+	if currency == types.BaseCurrency {
+		return entries, nil
+	}
+	rates, err := s.rates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rate := rates.Rates[currency]
+	if rate <= 0 {
+		return nil, fmt.Errorf("%w: %s", types.ErrUnsupportedCurrency, currency)
+	}
+	previousRate := rates.PreviousRates[currency]
+
+	out := make(map[string]*types.PriceEntry, len(entries))
+	for token, entry := range entries {
+		out[token] = convertPriceEntry(entry, rate, previousRate)
+	}
+	return out, nil
+}
+
+// SupportsCurrency reports whether the rates table has a rate for currency.
+func (s *fxRatesService) SupportsCurrency(ctx context.Context, currency string) (bool, error) {
+	currency = strings.ToUpper(currency)
+	if currency == types.BaseCurrency {
+		return true, nil
+	}
+	rates, err := s.rates(ctx)
+	if err != nil {
+		return false, err
+	}
+	return rates.Rates[currency] > 0, nil
+}
+
+func convertPriceEntry(entry *types.PriceEntry, rate, previousRate float64) *types.PriceEntry {
+	if entry == nil {
+		return nil
+	}
+	price, err := strconv.ParseFloat(entry.CurrentPrice, 64)
+	if err != nil {
+		return nil
+	}
+	converted := &types.PriceEntry{CurrentPrice: formatPrice(price * rate)}
+	if entry.PercentagePriceChange24h == nil {
+		return converted
+	}
+	change, err := strconv.ParseFloat(*entry.PercentagePriceChange24h, 64)
+	if err != nil {
+		return converted
+	}
+	if previousRate > 0 {
+		change = ((1+change/100)*rate/previousRate - 1) * 100
+	}
+	converted.PercentagePriceChange24h = formatChange(&change)
+	return converted
+}
+
+// rates returns the cached rates table, fetching it from the provider on a
+// miss. Only a successful fetch is cached.
+func (s *fxRatesService) rates(ctx context.Context) (*types.FXRates, error) {
+	if s.redis != nil {
+		cached, err := s.redis.MGetJSON(ctx, []string{fxRatesCacheKey}, func() any { return new(types.FXRates) })
+		if err != nil {
+			logger.Warn("fx: redis MGet failed; bypassing cache", "error", err)
+		} else if rates, _ := cached[fxRatesCacheKey].(*types.FXRates); rates != nil {
+			return rates, nil
+		}
+	}
+
+	ch := s.fetchGroup.DoChan(fxRatesCacheKey, func() (any, error) {
+		fctx, cancel := context.WithTimeout(context.Background(), fxRatesFetchTimeout)
+		defer cancel()
+		rates, err := s.provider.GetRates(fctx)
+		if err != nil {
+			return nil, fmt.Errorf("fetching fx rates from %s: %w", s.provider.Name(), err)
+		}
+		if s.redis != nil {
+			if err := s.redis.SetJSON(fctx, fxRatesCacheKey, rates, s.cacheTTL); err != nil {
+				logger.Warn("fx: redis SET failed", "error", err)
+			}
+		}
+		return rates, nil
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		rates, _ := res.Val.(*types.FXRates)
+		return rates, nil
+	}
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/stellar/freighter-backend-v2/internal/logger"
+	"github.com/stellar/freighter-backend-v2/internal/metrics"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+const (
+	frankfurterServiceName = "frankfurter"
+
+	// DefaultFrankfurterBaseURL is the public Frankfurter API, which serves
+	// the European Central Bank's daily reference rates.
+	DefaultFrankfurterBaseURL = "https://api.frankfurter.app"
+
+	frankfurterHTTPTimeout = 10 * time.Second
+	frankfurterDateLayout  = "2006-01-02"
+)
+
+type frankfurterProvider struct {
+	baseURL    string
+	httpClient *http.Client
+	svcMetrics *metrics.Service
+}
+
+type frankfurterResponse struct {
+	Base  string             `json:"base"`
+	Date  string             `json:"date"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+// NewFrankfurterProvider constructs an FX rates provider for a Frankfurter
+// API at baseURL (DefaultFrankfurterBaseURL when empty).
+func NewFrankfurterProvider(baseURL string, metricsService *metrics.Service) types.FXRatesProvider {
+	if baseURL == "" {
+		baseURL = DefaultFrankfurterBaseURL
+	}
+	return &frankfurterProvider{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: frankfurterHTTPTimeout},
+		svcMetrics: metricsService,
+	}
+}
+
+func (f *frankfurterProvider) Name() string { return frankfurterServiceName }
+
+// GetRates fetches the latest reference rates and, best-effort, the ones
+// published before them. Reference rates are set once per business day, so
+// the previous table is the prior business day's; a failure to fetch it only
+// drops PreviousRates.
+func (f *frankfurterProvider) GetRates(ctx context.Context) (_ *types.FXRates, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.Record(f.svcMetrics, frankfurterServiceName, "GetRates", "", time.Since(start).Seconds(), err)
+	}()
+
+	latest, err := f.fetch(ctx, "latest")
+	if err != nil {
+		return nil, err
+	}
+	rates := &types.FXRates{Date: latest.Date, Rates: latest.Rates}
+
+	day, err := time.Parse(frankfurterDateLayout, latest.Date)
+	if err != nil {
+		logger.Warn("fx: unparseable rates date; previous rates unavailable", "date", latest.Date, "error", err)
+		return rates, nil
+	}
+	// Asking for the day before the latest date returns the closest earlier
+	// publication, skipping weekends and holidays.
+	previous, err := f.fetch(ctx, day.AddDate(0, 0, -1).Format(frankfurterDateLayout))
+	if err != nil {
+		logger.Warn("fx: previous rates fetch failed", "error", err)
+		return rates, nil
+	}
+	rates.PreviousRates = previous.Rates
+	return rates, nil
+}
+
+// fetch GETs one rates table quoted in types.BaseCurrency. Frankfurter omits
+// the base from its own table, so it is added at 1.
+func (f *frankfurterProvider) fetch(ctx context.Context, path string) (*frankfurterResponse, error) {
+	reqURL := fmt.Sprintf("%s/%s?%s", f.baseURL, path, url.Values{"from": {types.BaseCurrency}}.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building frankfurter request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, &metrics.UpstreamError{Kind: "http_error", Err: err}
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, &metrics.UpstreamError{Kind: "http_error", Code: resp.StatusCode, Err: fmt.Errorf("frankfurter status %d", resp.StatusCode)}
+	}
+	var body frankfurterResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decoding frankfurter response: %w", err)
+	}
+	if body.Base != types.BaseCurrency || len(body.Rates) == 0 {
+		return nil, fmt.Errorf("frankfurter returned base %q with %d rates", body.Base, len(body.Rates))
+	}
+	body.Rates[types.BaseCurrency] = 1
+	return &body, nil
+}
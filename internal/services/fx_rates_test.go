@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+type stubFXProvider struct {
+	rates *types.FXRates
+	err   error
+	calls atomic.Int64
+}
+
+func (s *stubFXProvider) Name() string { return "stub-fx" }
+
+func (s *stubFXProvider) GetRates(ctx context.Context) (*types.FXRates, error) {
+	s.calls.Add(1)
+	return s.rates, s.err
+}
+
+func TestFXRates_ConvertPrices(t *testing.T) {
+	t.Parallel()
+
+	provider := &stubFXProvider{rates: &types.FXRates{
+		Date:          "2026-10-16",
+		Rates:         map[string]float64{"USD": 1, "EUR": 0.5, "JPY": 150},
+		PreviousRates: map[string]float64{"EUR": 0.4},
+	}}
+	svc := NewFXRatesService(provider, nil, 0)
+
+	entries := map[string]*types.PriceEntry{
+		"XLM":  {CurrentPrice: "0.2", PercentagePriceChange24h: ptrStr("10")},
+		"USDC": {CurrentPrice: "1"},
+		"GONE": nil,
+	}
+
+	eur, err := svc.ConvertPrices(context.Background(), entries, "eur")
+	require.NoError(t, err)
+	// 1.1 × 0.5/0.4 = 1.375 → the token gained 37.5% for a EUR holder.
+	assert.Equal(t, &types.PriceEntry{CurrentPrice: "0.1", PercentagePriceChange24h: ptrStr("37.5")}, eur["XLM"])
+	assert.Equal(t, &types.PriceEntry{CurrentPrice: "0.5"}, eur["USDC"])
+	assert.Contains(t, eur, "GONE")
+	assert.Nil(t, eur["GONE"])
+
+	// No previous JPY rate: the currency is taken as flat over the day.
+	jpy, err := svc.ConvertPrices(context.Background(), entries, "JPY")
+	require.NoError(t, err)
+	assert.Equal(t, &types.PriceEntry{CurrentPrice: "30", PercentagePriceChange24h: ptrStr("10")}, jpy["XLM"])
+}
+
+func TestFXRates_BaseCurrencySkipsProvider(t *testing.T) {
+	t.Parallel()
+
+	provider := &stubFXProvider{err: errors.New("down")}
+	svc := NewFXRatesService(provider, nil, 0)
+
+	entries := map[string]*types.PriceEntry{"XLM": {CurrentPrice: "0.2"}}
+	got, err := svc.ConvertPrices(context.Background(), entries, "usd")
+	require.NoError(t, err)
+	assert.Equal(t, entries, got)
+	assert.Equal(t, int64(0), provider.calls.Load())
+}
+
+func TestFXRates_SupportsCurrency(t *testing.T) {
+	t.Parallel()
+
+	svc := NewFXRatesService(&stubFXProvider{rates: &types.FXRates{Rates: map[string]float64{"USD": 1, "EUR": 0.9}}}, nil, 0)
+	for currency, want := range map[string]bool{"EUR": true, "eur": true, "USD": true, "XYZ": false} {
+		got, err := svc.SupportsCurrency(context.Background(), currency)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, currency)
+	}
+
+	provider := &stubFXProvider{err: errors.New("down")}
+	svc = NewFXRatesService(provider, nil, 0)
+	_, err := svc.SupportsCurrency(context.Background(), "EUR")
+	require.Error(t, err)
+	supported, err := svc.SupportsCurrency(context.Background(), types.BaseCurrency)
+	require.NoError(t, err)
+	assert.True(t, supported)
+}
+
+func TestFXRates_Errors(t *testing.T) {
+	t.Parallel()
+
+	svc := NewFXRatesService(&stubFXProvider{rates: &types.FXRates{Rates: map[string]float64{"USD": 1, "EUR": 0.9}}}, nil, 0)
+	_, err := svc.ConvertPrices(context.Background(), nil, "XXX")
+	require.ErrorIs(t, err, types.ErrUnsupportedCurrency)
+
+	upstreamErr := errors.New("down")
+	svc = NewFXRatesService(&stubFXProvider{err: upstreamErr}, nil, 0)
+	_, err = svc.ConvertPrices(context.Background(), nil, "EUR")
+	require.ErrorIs(t, err, upstreamErr)
+	assert.NotErrorIs(t, err, types.ErrUnsupportedCurrency)
+}
+
+func TestFrankfurterProvider_GetRates(t *testing.T) {
+	t.Parallel()
+
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path+"?"+r.URL.RawQuery)
+		switch r.URL.Path {
+		case "/latest":
+			_, _ = w.Write([]byte(`{"amount":1.0,"base":"USD","date":"2026-10-12","rates":{"EUR":0.92,"JPY":149.5}}`))
+		case "/2026-10-11":
+			_, _ = w.Write([]byte(`{"amount":1.0,"base":"USD","date":"2026-10-09","rates":{"EUR":0.91,"JPY":148}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	rates, err := NewFrankfurterProvider(server.URL+"/", nil).GetRates(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/latest?from=USD", "/2026-10-11?from=USD"}, paths)
+	assert.Equal(t, "2026-10-12", rates.Date)
+	assert.Equal(t, map[string]float64{"USD": 1, "EUR": 0.92, "JPY": 149.5}, rates.Rates)
+	assert.Equal(t, 0.91, rates.PreviousRates["EUR"])
+}
+
+func TestFrankfurterProvider_PreviousRatesAreBestEffort(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/latest" {
+			_, _ = w.Write([]byte(`{"base":"USD","date":"2026-10-12","rates":{"EUR":0.92}}`))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+
+	rates, err := NewFrankfurterProvider(server.URL, nil).GetRates(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0.92, rates.Rates["EUR"])
+	assert.Empty(t, rates.PreviousRates)
+}
+
+func TestFrankfurterProvider_RejectsFailedLatest(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"base":"EUR","date":"2026-10-12","rates":{"USD":1.08}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	_, err := NewFrankfurterProvider(server.URL, nil).GetRates(context.Background())
+	require.Error(t, err)
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/stellar/go-stellar-sdk/clients/horizonclient"
+	hProtocol "github.com/stellar/go-stellar-sdk/protocols/horizon"
+	"github.com/stellar/go-stellar-sdk/protocols/horizon/operations"
+
+	"github.com/stellar/freighter-backend-v2/internal/metrics"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+const horizonServiceName = "horizon"
+
+var (
+	// ErrHorizonNotFound is returned when Horizon answers not_found, which for
+	// account-scoped reads means the account is unfunded. It is a normal client
+	// outcome and is not counted in the service error metric.
+	ErrHorizonNotFound = errors.New("resource not found in horizon")
+
+	// ErrHorizonNetworkNotConfigured indicates we have no Horizon URL for the
+	// requested Stellar network.
+	ErrHorizonNetworkNotConfigured = errors.New("horizon URL not configured for network")
+)
+
+type horizonService struct {
+	pubnetURL  string
+	testnetURL string
+	httpClient *http.Client
+	svcMetrics *metrics.Service
+}
+
+// NewHorizonService constructs a Horizon client for pubnet and testnet. Either
+// URL may be empty, in which case calls for that network fail with
+// ErrHorizonNetworkNotConfigured.
+func NewHorizonService(pubnetURL, testnetURL string, m *metrics.Service) types.HorizonService {
+	return &horizonService{
+		pubnetURL:  pubnetURL,
+		testnetURL: testnetURL,
+		httpClient: createDefaultClient(),
+		svcMetrics: m,
+	}
+}
+
+func (h *horizonService) Name() string {
+	return horizonServiceName
+}
+
+// contextDoer binds a caller's context to every request horizonclient makes.
+// horizonclient's request methods take no context and substitute their own
+// 60s timeout, so without this a cancelled handler would keep the upstream
+// call running.
+type contextDoer struct {
+	ctx    context.Context
+	client *http.Client
+}
+
+func (d contextDoer) Do(req *http.Request) (*http.Response, error) {
+	return d.client.Do(req.WithContext(d.ctx))
+}
+
+func (d contextDoer) Get(rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	return d.client.Do(req)
+}
+
+// PostForm is only used by horizonclient for transaction submission, which this
+// read-only service never does.
+func (d contextDoer) PostForm(string, url.Values) (*http.Response, error) {
+	return nil, errors.New("horizon writes are not supported")
+}
+
+// configureNetworkClient returns a client for network bound to ctx. Clients are
+// cheap (the pooled transport lives on httpClient) and horizonclient.Client
+// cannot be copied, so one is built per call rather than cached per network.
+func (h *horizonService) configureNetworkClient(ctx context.Context, network string) (*horizonclient.Client, error) {
+	var horizonURL string
+	switch network {
+	case types.PUBLIC:
+		horizonURL = h.pubnetURL
+	case types.TESTNET:
+		horizonURL = h.testnetURL
+	}
+	if horizonURL == "" {
+		return nil, fmt.Errorf("%w: %s", ErrHorizonNetworkNotConfigured, network)
+	}
+	return &horizonclient.Client{
+		HorizonURL: horizonURL,
+		HTTP:       contextDoer{ctx: ctx, client: h.httpClient},
+		AppName:    "freighter-backend",
+	}, nil
+}
+
+// record records call metrics with one carve-out: ErrHorizonNotFound is a
+// normal client outcome, so ErrorsTotal is not incremented for it.
+func (h *horizonService) record(method, network string, start time.Time, err error) {
+	if errors.Is(err, ErrHorizonNotFound) {
+		err = nil
+	}
+	metrics.Record(h.svcMetrics, horizonServiceName, method, network, time.Since(start).Seconds(), err)
+}
+
+func (h *horizonService) GetAccountDetails(ctx context.Context, network, accountID string) (_ *hProtocol.Account, err error) {
+	start := time.Now()
+	defer func() { h.record("GetAccountDetails", network, start, err) }()
+
+	client, err := h.configureNetworkClient(ctx, network)
+	if err != nil {
+		return nil, err
+	}
+	account, err := client.AccountDetail(horizonclient.AccountRequest{AccountID: accountID})
+	if err != nil {
+		return nil, translateHorizonError("account", err)
+	}
+	return &account, nil
+}
+
+func (h *horizonService) GetOffers(ctx context.Context, network, accountID string, params types.HorizonPageParams) (_ *hProtocol.OffersPage, err error) {
+	start := time.Now()
+	defer func() { h.record("GetOffers", network, start, err) }()
+
+	client, err := h.configureNetworkClient(ctx, network)
+	if err != nil {
+		return nil, err
+	}
+	page, err := client.Offers(horizonclient.OfferRequest{
+		ForAccount: accountID,
+		Cursor:     params.Cursor,
+		Limit:      params.Limit,
+		Order:      horizonOrder(params),
+	})
+	if err != nil {
+		return nil, translateHorizonError("offers", err)
+	}
+	return &page, nil
+}
+
+func (h *horizonService) GetPayments(ctx context.Context, network, accountID string, params types.HorizonPageParams) (_ *operations.OperationsPage, err error) {
+	start := time.Now()
+	defer func() { h.record("GetPayments", network, start, err) }()
+
+	client, err := h.configureNetworkClient(ctx, network)
+	if err != nil {
+		return nil, err
+	}
+	page, err := client.Payments(horizonclient.OperationRequest{
+		ForAccount: accountID,
+		Cursor:     params.Cursor,
+		Limit:      params.Limit,
+		Order:      horizonOrder(params),
+	})
+	if err != nil {
+		return nil, translateHorizonError("payments", err)
+	}
+	return &page, nil
+}
+
+func (h *horizonService) GetFeeStats(ctx context.Context, network string) (_ *hProtocol.FeeStats, err error) {
+	start := time.Now()
+	defer func() { h.record("GetFeeStats", network, start, err) }()
+
+	client, err := h.configureNetworkClient(ctx, network)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := client.FeeStats()
+	if err != nil {
+		return nil, translateHorizonError("fee stats", err)
+	}
+	return &stats, nil
+}
+
+func horizonOrder(params types.HorizonPageParams) horizonclient.Order {
+	if params.Descending {
+		return horizonclient.OrderDesc
+	}
+	return horizonclient.OrderAsc
+}
+
+// translateHorizonError maps horizonclient's problem errors onto the service
+// error vocabulary: not_found → ErrHorizonNotFound, any other problem response
+// → UpstreamError carrying the HTTP status. Transport and context errors pass
+// through so metrics.ClassifyError and handlers see them unchanged.
+func translateHorizonError(label string, err error) error {
+	hErr := horizonclient.GetError(err)
+	if hErr == nil {
+		return fmt.Errorf("horizon %s request: %w", label, err)
+	}
+	if horizonclient.IsNotFoundError(err) {
+		return fmt.Errorf("%w: %s", ErrHorizonNotFound, label)
+	}
+	code := 0
+	if hErr.Response != nil {
+		code = hErr.Response.StatusCode
+	}
+	return &metrics.UpstreamError{Kind: "http_error", Code: code, Err: fmt.Errorf("horizon %s: %s", label, hErr.Problem.Title)}
+}
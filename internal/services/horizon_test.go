@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/metrics"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+const testHorizonAccount = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+
+func newTestHorizon(t *testing.T, handler http.HandlerFunc) (types.HorizonService, *metrics.Service) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	m := metrics.NewMetrics(prometheus.NewRegistry()).Service
+	return NewHorizonService(server.URL, "", m), m
+}
+
+func writeHorizonProblem(w http.ResponseWriter, status int, problemType, title string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	_, _ = fmt.Fprintf(w, `{"type":"https://stellar.org/horizon-errors/%s","title":%q,"status":%d}`, problemType, title, status)
+}
+
+func TestHorizon_GetAccountDetails(t *testing.T) {
+	t.Parallel()
+
+	svc, _ := newTestHorizon(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/accounts/"+testHorizonAccount, r.URL.Path)
+		_, _ = w.Write([]byte(`{
+			"account_id": "` + testHorizonAccount + `",
+			"sequence": "123",
+			"subentry_count": 2,
+			"balances": [
+				{"balance": "100.0000000", "asset_type": "native"},
+				{"balance": "5.0000000", "asset_type": "credit_alphanum4", "asset_code": "USDC", "asset_issuer": "GA5ZSEJYB37JRC5AVCIA5MOP4RHTM335X2KGX3IHOJAPP5RE34K4KZVN"}
+			]
+		}`))
+	})
+
+	account, err := svc.GetAccountDetails(context.Background(), types.PUBLIC, testHorizonAccount)
+	require.NoError(t, err)
+	assert.Equal(t, testHorizonAccount, account.AccountID)
+	assert.Equal(t, int32(2), account.SubentryCount)
+	require.Len(t, account.Balances, 2)
+	assert.Equal(t, "USDC", account.Balances[1].Code)
+}
+
+func TestHorizon_GetAccountDetails_NotFound(t *testing.T) {
+	t.Parallel()
+
+	svc, m := newTestHorizon(t, func(w http.ResponseWriter, r *http.Request) {
+		writeHorizonProblem(w, http.StatusNotFound, "not_found", "Resource Missing")
+	})
+
+	_, err := svc.GetAccountDetails(context.Background(), types.PUBLIC, testHorizonAccount)
+	assert.True(t, errors.Is(err, ErrHorizonNotFound))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.CallsTotal.WithLabelValues(horizonServiceName, "GetAccountDetails", types.PUBLIC)))
+	assert.Equal(t, 0, testutil.CollectAndCount(m.ErrorsTotal), "an unfunded account is not a service error")
+}
+
+func TestHorizon_ServerErrorIsUpstreamError(t *testing.T) {
+	t.Parallel()
+
+	svc, m := newTestHorizon(t, func(w http.ResponseWriter, r *http.Request) {
+		writeHorizonProblem(w, http.StatusServiceUnavailable, "stale_history", "Historical DB Is Too Stale")
+	})
+
+	_, err := svc.GetFeeStats(context.Background(), types.PUBLIC)
+	var upstream *metrics.UpstreamError
+	require.True(t, errors.As(err, &upstream))
+	assert.Equal(t, http.StatusServiceUnavailable, upstream.Code)
+	assert.Equal(t, 1, testutil.CollectAndCount(m.ErrorsTotal))
+}
+
+func TestHorizon_GetOffers_PassesPaging(t *testing.T) {
+	t.Parallel()
+
+	svc, _ := newTestHorizon(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/accounts/"+testHorizonAccount+"/offers", r.URL.Path)
+		assert.Equal(t, "desc", r.URL.Query().Get("order"))
+		assert.Equal(t, "20", r.URL.Query().Get("limit"))
+		assert.Equal(t, "abc", r.URL.Query().Get("cursor"))
+		_, _ = w.Write([]byte(`{"_embedded":{"records":[{"id":"42","amount":"1.0000000","price":"0.5"}]}}`))
+	})
+
+	page, err := svc.GetOffers(context.Background(), types.PUBLIC, testHorizonAccount, types.HorizonPageParams{Cursor: "abc", Limit: 20, Descending: true})
+	require.NoError(t, err)
+	require.Len(t, page.Embedded.Records, 1)
+	assert.Equal(t, int64(42), page.Embedded.Records[0].ID)
+}
+
+func TestHorizon_GetPayments(t *testing.T) {
+	t.Parallel()
+
+	svc, _ := newTestHorizon(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/accounts/"+testHorizonAccount+"/payments", r.URL.Path)
+		assert.Equal(t, "asc", r.URL.Query().Get("order"))
+		_, _ = w.Write([]byte(`{"_embedded":{"records":[{"id":"1","type":"payment","type_i":1,"amount":"10.0000000","asset_type":"native"}]}}`))
+	})
+
+	page, err := svc.GetPayments(context.Background(), types.PUBLIC, testHorizonAccount, types.HorizonPageParams{})
+	require.NoError(t, err)
+	require.Len(t, page.Embedded.Records, 1)
+	assert.Equal(t, "payment", page.Embedded.Records[0].GetType())
+}
+
+func TestHorizon_GetFeeStats(t *testing.T) {
+	t.Parallel()
+
+	svc, _ := newTestHorizon(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/fee_stats", r.URL.Path)
+		_, _ = w.Write([]byte(`{"last_ledger":"100","last_ledger_base_fee":"100","ledger_capacity_usage":"0.5","fee_charged":{"p50":"100"},"max_fee":{"p50":"200"}}`))
+	})
+
+	stats, err := svc.GetFeeStats(context.Background(), types.PUBLIC)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), stats.LastLedgerBaseFee)
+	assert.Equal(t, int64(200), stats.MaxFee.P50)
+}
+
+func TestHorizon_UnconfiguredNetwork(t *testing.T) {
+	t.Parallel()
+
+	svc := NewHorizonService("http://localhost:8000", "", nil)
+	for _, network := range []string{types.TESTNET, types.FUTURENET} {
+		_, err := svc.GetFeeStats(context.Background(), network)
+		assert.True(t, errors.Is(err, ErrHorizonNetworkNotConfigured), network)
+	}
+}
+
+func TestHorizon_HonoursCallerContext(t *testing.T) {
+	t.Parallel()
+
+	svc, _ := newTestHorizon(t, func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := svc.GetAccountDetails(ctx, types.PUBLIC, testHorizonAccount)
+	assert.True(t, errors.Is(err, context.Canceled), "got %v", err)
+}
+
+func TestHorizon_Name(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "horizon", NewHorizonService("", "", nil).Name())
+}
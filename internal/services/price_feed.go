@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/stellar/freighter-backend-v2/internal/metrics"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+const priceFeedHTTPTimeout = 10 * time.Second
+
+type priceFeedProvider struct {
+	pubnetURL  string
+	testnetURL string
+	apiKey     string
+	httpClient *http.Client
+	svcMetrics *metrics.Service
+}
+
+// priceFeedResponse is the body a price feed returns for one asset.
+// PercentagePriceChange24h is optional.
+type priceFeedResponse struct {
+	Price                    float64  `json:"price"`
+	PercentagePriceChange24h *float64 `json:"percentagePriceChange24h"`
+}
+
+// NewPriceFeedProvider constructs a provider for a generic HTTP price feed.
+// For each asset it issues GET <network URL>?asset=<canonical id> and expects
+// {"price": <number>, "percentagePriceChange24h": <number|null>}; a 404 or a
+// zero price means the feed has no price for the asset. apiKey, when
+// non-empty, is sent as `Authorization: Bearer <apiKey>`.
+func NewPriceFeedProvider(pubnetURL, testnetURL, apiKey string, metricsService *metrics.Service) types.PriceProvider {
+	return &priceFeedProvider{
+		pubnetURL:  pubnetURL,
+		testnetURL: testnetURL,
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: priceFeedHTTPTimeout},
+		svcMetrics: metricsService,
+	}
+}
+
+func (f *priceFeedProvider) Name() string { return PriceProviderFeed }
+
+func (f *priceFeedProvider) GetQuote(ctx context.Context, network, canonical string) (_ *types.PriceQuote, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.Record(f.svcMetrics, PriceProviderFeed, "GetQuote", network, time.Since(start).Seconds(), err)
+	}()
+
+	baseURL, err := f.baseURLForNetwork(network)
+	if err != nil {
+		return nil, err
+	}
+	reqURL := baseURL + "?" + url.Values{"asset": {canonical}}.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building price feed request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if f.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+f.apiKey)
+	}
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, &metrics.UpstreamError{Kind: "http_error", Err: err}
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("%w: price feed has no %s", types.ErrAssetNotPriced, canonical)
+	default:
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, &metrics.UpstreamError{Kind: "http_error", Code: resp.StatusCode, Err: fmt.Errorf("price feed status %d", resp.StatusCode)}
+	}
+
+	var body priceFeedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decoding price feed response: %w", err)
+	}
+	if body.Price <= 0 {
+		return nil, fmt.Errorf("%w: price feed returned %v", types.ErrAssetNotPriced, body.Price)
+	}
+	return &types.PriceQuote{Price: body.Price, Change24h: body.PercentagePriceChange24h}, nil
+}
+
+func (f *priceFeedProvider) baseURLForNetwork(network string) (string, error) {
+	var baseURL string
+	switch network {
+	case types.PUBLIC:
+		baseURL = f.pubnetURL
+	case types.TESTNET:
+		baseURL = f.testnetURL
+	}
+	if baseURL == "" {
+		return "", fmt.Errorf("%w: price feed URL not configured for network %s", types.ErrAssetNotPriced, network)
+	}
+	return baseURL, nil
+}
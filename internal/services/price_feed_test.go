@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/metrics"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+func TestPriceFeedProvider_GetQuote(t *testing.T) {
+	t.Parallel()
+
+	var gotAsset, gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAsset = r.URL.Query().Get("asset")
+		gotAuth = r.Header.Get("Authorization")
+		switch gotAsset {
+		case "XLM":
+			_, _ = w.Write([]byte(`{"price":0.16,"percentagePriceChange24h":-1.5}`))
+		case "USDC:" + testIssuer:
+			_, _ = w.Write([]byte(`{"price":1}`))
+		case "ZERO:" + testIssuer:
+			_, _ = w.Write([]byte(`{"price":0}`))
+		case "DOWN:" + testIssuer:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	provider := NewPriceFeedProvider(server.URL, "", "secret", nil)
+	assert.Equal(t, PriceProviderFeed, provider.Name())
+	ctx := context.Background()
+
+	quote, err := provider.GetQuote(ctx, types.PUBLIC, "XLM")
+	require.NoError(t, err)
+	assert.Equal(t, &types.PriceQuote{Price: 0.16, Change24h: ptrFloat(-1.5)}, quote)
+	assert.Equal(t, "XLM", gotAsset)
+	assert.Equal(t, "Bearer secret", gotAuth)
+
+	quote, err = provider.GetQuote(ctx, types.PUBLIC, "USDC:"+testIssuer)
+	require.NoError(t, err)
+	assert.Nil(t, quote.Change24h)
+
+	_, err = provider.GetQuote(ctx, types.PUBLIC, "ZERO:"+testIssuer)
+	require.ErrorIs(t, err, types.ErrAssetNotPriced)
+
+	_, err = provider.GetQuote(ctx, types.PUBLIC, "GONE:"+testIssuer)
+	require.ErrorIs(t, err, types.ErrAssetNotPriced)
+
+	_, err = provider.GetQuote(ctx, types.PUBLIC, "DOWN:"+testIssuer)
+	var upErr *metrics.UpstreamError
+	require.ErrorAs(t, err, &upErr)
+	assert.Equal(t, http.StatusBadGateway, upErr.Code)
+
+	_, err = provider.GetQuote(ctx, types.TESTNET, "XLM")
+	require.ErrorIs(t, err, types.ErrAssetNotPriced, "no testnet URL configured")
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/stellar/freighter-backend-v2/internal/logger"
+	"github.com/stellar/freighter-backend-v2/internal/metrics"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils/assetid"
+)
+
+// priceHistorySpec pins, for one chart range, the upstream candle resolution,
+// the bucket width the series is downsampled to and how long the result is
+// cached. Every window stays under Stellar Expert's 200-record cap at its
+// resolution; wider ranges move slower, so they tolerate longer TTLs.
+type priceHistorySpec struct {
+	window        time.Duration
+	resolutionSec int
+	bucketSec     int
+	cacheTTL      time.Duration
+}
+
+var priceHistorySpecs = map[string]priceHistorySpec{
+	types.PriceRange1D:  {window: 24 * time.Hour, resolutionSec: 900, bucketSec: 1800, cacheTTL: 5 * time.Minute},
+	types.PriceRange7D:  {window: 7 * 24 * time.Hour, resolutionSec: 3600, bucketSec: 4 * 3600, cacheTTL: 30 * time.Minute},
+	types.PriceRange30D: {window: 30 * 24 * time.Hour, resolutionSec: 86400, bucketSec: 86400, cacheTTL: 2 * time.Hour},
+	types.PriceRange1Y:  {window: 365 * 24 * time.Hour, resolutionSec: 7 * 86400, bucketSec: 7 * 86400, cacheTTL: 12 * time.Hour},
+}
+
+// GetPriceHistory returns the downsampled OHLC series for one canonical token
+// id over priceRange. Unknown or malformed assets fail with
+// types.ErrAssetNotPriced; an asset that did not trade in the window yields
+// an empty series. A SAC contract charts the classic asset it wraps, and a
+// contract that can't be resolved fails the request rather than being charted
+// under its own id. Only
+// successful series are cached, and concurrent misses for the same (network,
+// range, token) share one upstream fetch.
+func (p *pricesService) GetPriceHistory(ctx context.Context, token, network, priceRange string) (_ *types.PriceHistory, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.Record(p.svcMetrics, pricesServiceName, "GetPriceHistory", network, time.Since(start).Seconds(), err)
+	}()
+
+	if network != types.PUBLIC && network != types.TESTNET {
+		return nil, fmt.Errorf("unsupported network for prices: %s", network)
+	}
+	spec, ok := priceHistorySpecs[priceRange]
+	if !ok {
+		return nil, fmt.Errorf("unsupported price range: %s", priceRange)
+	}
+	if p.cfg.Contracts != nil && assetid.IsContract(token) {
+		classic, resolveErr := p.cfg.Contracts.ClassicAsset(ctx, network, token)
+		if resolveErr != nil {
+			return nil, fmt.Errorf("resolving contract asset: %w", resolveErr)
+		}
+		if classic != "" {
+			token = classic
+		}
+	}
+	key := historyCacheKey(strings.ToLower(network), priceRange, token)
+
+	if cached := p.loadCachedHistory(ctx, key, network); cached != nil {
+		return cached, nil
+	}
+
+	ch := p.fetchGroup.DoChan(key, func() (any, error) {
+		bgCtx, release := p.background.acquire()
+		defer release()
+		fctx, cancel := context.WithTimeout(bgCtx, p.cfg.MissFetchTimeout)
+		defer cancel()
+		return p.fetchHistory(fctx, network, token, priceRange, spec, key)
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		history, _ := res.Val.(*types.PriceHistory)
+		return history, nil
+	}
+}
+
+func (p *pricesService) loadCachedHistory(ctx context.Context, key, network string) *types.PriceHistory {
+	if p.redis == nil {
+		p.recordCacheOutcome(network, PriceProviderStellarExpert, "miss", 1)
+		return nil
+	}
+	cached, err := p.redis.MGetJSON(ctx, []string{key}, func() any { return new(types.PriceHistory) })
+	if err != nil {
+		logger.Warn("prices: redis MGet failed; bypassing history cache", "error", err)
+		if p.pricesMetrics != nil {
+			p.pricesMetrics.RedisErrors.WithLabelValues("mget").Inc()
+		}
+		p.recordCacheOutcome(network, PriceProviderStellarExpert, "miss", 1)
+		return nil
+	}
+	history, _ := cached[key].(*types.PriceHistory)
+	if history == nil {
+		p.recordCacheOutcome(network, PriceProviderStellarExpert, "miss", 1)
+		return nil
+	}
+	p.recordCacheOutcome(network, PriceProviderStellarExpert, "hit", 1)
+	return history
+}
+
+// fetchHistory pulls candles for the range ending now and downsamples them.
+// `from` is aligned to the bucket width so every point but the newest covers
+// a whole bucket; the newest is the in-progress one, so the chart ends at the
+// current price rather than up to a bucket behind it.
+func (p *pricesService) fetchHistory(ctx context.Context, network, token, priceRange string, spec priceHistorySpec, key string) (*types.PriceHistory, error) {
+	to := time.Now().UTC()
+	from := to.Truncate(time.Duration(spec.bucketSec) * time.Second).Add(-spec.window)
+
+	candles, err := p.stellarExpert.GetAssetCandles(ctx, network, assetid.ToStellarExpert(token), from, to, spec.resolutionSec)
+	if err != nil {
+		if isTerminalAssetError(err) {
+			return nil, fmt.Errorf("%w: %s", types.ErrAssetNotPriced, token)
+		}
+		return nil, fmt.Errorf("fetching %s candles for %s: %w", priceRange, token, err)
+	}
+
+	history := &types.PriceHistory{
+		Range:         priceRange,
+		ResolutionSec: spec.bucketSec,
+		Points:        downsampleCandles(candles, spec.bucketSec),
+	}
+	if p.redis != nil {
+		if err := p.redis.SetJSON(ctx, key, history, spec.cacheTTL); err != nil {
+			logger.Warn("prices: redis SET failed", "asset", token, "range", priceRange, "error", err)
+			if p.pricesMetrics != nil {
+				p.pricesMetrics.RedisErrors.WithLabelValues("set").Inc()
+			}
+		}
+	}
+	return history, nil
+}
+
+// downsampleCandles merges ascending candles into bucketSec-wide points
+// aligned to the Unix epoch: open of the first candle, close of the last, and
+// the extremes in between. Candles without a close price carry no trade data
+// and are dropped. The result is never nil so it encodes as [].
+func downsampleCandles(candles []types.StellarExpertCandle, bucketSec int) []types.PricePoint {
+	type ohlc struct {
+		ts                     int64
+		open, high, low, close float64
+	}
+	points := make([]types.PricePoint, 0, len(candles))
+	flush := func(b ohlc) {
+		points = append(points, types.PricePoint{
+			Timestamp: b.ts,
+			Open:      formatPrice(b.open),
+			High:      formatPrice(b.high),
+			Low:       formatPrice(b.low),
+			Close:     formatPrice(b.close),
+		})
+	}
+
+	var (
+		cur     ohlc
+		started bool
+	)
+	for _, c := range candles {
+		if c.Close() == 0 {
+			continue
+		}
+		ts := c.TS() - c.TS()%int64(bucketSec)
+		if started && ts == cur.ts {
+			cur.high = max(cur.high, c.High())
+			cur.low = min(cur.low, c.Low())
+			cur.close = c.Close()
+			continue
+		}
+		if started {
+			flush(cur)
+		}
+		cur = ohlc{ts: ts, open: c.Open(), high: c.High(), low: c.Low(), close: c.Close()}
+		started = true
+	}
+	if started {
+		flush(cur)
+	}
+	return points
+}
+
+func historyCacheKey(cacheNet, priceRange, canonical string) string {
+	return cacheKeyPrefix + ":history:" + cacheNet + ":" + priceRange + ":" + canonical
+}
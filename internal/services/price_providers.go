@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/stellar/freighter-backend-v2/internal/logger"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils/assetid"
+)
+
+// Price provider names accepted by --price-providers. Each is also the
+// provider's Name() and its `provider` label on the prices cache metric.
+const (
+	PriceProviderStellarExpert = stellarExpertServiceName
+	PriceProviderFeed          = "price-feed"
+)
+
+const (
+	// candlesWindow / candlesResolutionSec define the rolling 24h window used
+	// to compute percentagePriceChange24h from /asset/{id}/candles. Hourly
+	// resolution yields ~25 records, well under Stellar Expert's 200-record cap.
+	candlesWindow        = 24 * time.Hour
+	candlesResolutionSec = 3600
+
+	// minCandleWindow / maxCandleWindow bound how far before `to` the
+	// oldest returned candle must open. With hourly resolution and a
+	// truncated `to`, an asset trading continuously yields candles[0] at
+	// exactly 24h ago; sparse trading or upstream truncation can shift it
+	// later (closer to now) or rarely earlier. ±1h around 24h covers
+	// normal bucket-boundary slack while rejecting sparse-data drift that
+	// would make the result not represent a 24h window.
+	minCandleWindow = 23 * time.Hour
+	maxCandleWindow = 25 * time.Hour
+)
+
+type stellarExpertPriceProvider struct {
+	stellarExpert types.StellarExpertService
+}
+
+// NewStellarExpertPriceProvider prices assets from Stellar Expert's
+// /asset/{id} snapshot, with the 24h change taken from hourly candles.
+func NewStellarExpertPriceProvider(stellarExpert types.StellarExpertService) types.PriceProvider {
+	return &stellarExpertPriceProvider{stellarExpert: stellarExpert}
+}
+
+func (s *stellarExpertPriceProvider) Name() string { return PriceProviderStellarExpert }
+
+// GetQuote fetches the asset snapshot and its candles concurrently. On a
+// terminal asset error the candles call is cancelled so unknown assets don't
+// double upstream load.
+func (s *stellarExpertPriceProvider) GetQuote(ctx context.Context, network, canonical string) (*types.PriceQuote, error) {
+	stellarExpertID := assetid.ToStellarExpert(canonical)
+
+	// Truncate to the candle resolution so `from` and `to` align to bucket
+	// boundaries; otherwise upstream may return a window 23–25h wide with
+	// no consistent rule. The current price is still as-of-now via
+	// /asset/{id}, so the actual price comparison is at most ~1h off 24h.
+	resolution := time.Duration(candlesResolutionSec) * time.Second
+	to := time.Now().UTC().Truncate(resolution)
+	from := to.Add(-candlesWindow)
+
+	fetchCtx, cancelFetch := context.WithCancel(ctx)
+	defer cancelFetch()
+
+	var (
+		asset      *types.StellarExpertAsset
+		assetErr   error
+		candles    []types.StellarExpertCandle
+		candlesErr error
+		wg         sync.WaitGroup
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		asset, assetErr = s.stellarExpert.GetAsset(fetchCtx, network, stellarExpertID)
+		if assetErr != nil && isTerminalAssetError(assetErr) {
+			cancelFetch()
+		}
+	}()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		candles, candlesErr = s.stellarExpert.GetAssetCandles(fetchCtx, network, stellarExpertID, from, to, candlesResolutionSec)
+	}()
+	wg.Wait()
+
+	if assetErr != nil {
+		if isTerminalAssetError(assetErr) {
+			return nil, fmt.Errorf("%w: %w", types.ErrAssetNotPriced, assetErr)
+		}
+		return nil, assetErr
+	}
+
+	// A zero price — whether Stellar Expert omitted the `price` field (a known
+	// but illiquid asset; JSON absence decodes to 0) or reported a genuine 0 —
+	// is unpriceable. Honor the documented null contract rather than leaking a
+	// "0" string.
+	if asset.Price == 0 {
+		return nil, fmt.Errorf("%w: zero price", types.ErrAssetNotPriced)
+	}
+
+	// The 24h change comes only from the hourly candles window, which can pin
+	// a true trailing 24h (±1h). When candles are unavailable or can't cover
+	// ~24h we return null rather than a mislabeled day-over-day delta from the
+	// daily price7d series.
+	quote := &types.PriceQuote{Price: asset.Price}
+	if candlesErr != nil {
+		if !errors.Is(candlesErr, context.DeadlineExceeded) && !errors.Is(candlesErr, context.Canceled) && !isTerminalAssetError(candlesErr) {
+			logger.Warn("prices: candles fetch failed; 24h change unavailable", "asset", stellarExpertID, "error", candlesErr)
+		}
+	} else {
+		quote.Change24h = change24hFromCandles(asset.Price, candles, to)
+	}
+	return quote, nil
+}
+
+// change24hFromCandles computes the 24h percentage delta between currentPrice
+// and the open of the oldest candle. Returns nil when the upstream is empty,
+// the open is zero, or the oldest returned candle is too far from 24h before
+// `to` to credibly represent a 24h window (sparse trading or anomalous
+// upstream return).
+func change24hFromCandles(currentPrice float64, candles []types.StellarExpertCandle, to time.Time) *float64 {
+	if len(candles) == 0 {
+		return nil
+	}
+	oldestAge := to.Sub(time.Unix(candles[0].TS(), 0))
+	if oldestAge < minCandleWindow || oldestAge > maxCandleWindow {
+		return nil
+	}
+	openPrice := candles[0].Open()
+	if openPrice == 0 {
+		return nil
+	}
+	percentChange := (currentPrice - openPrice) / openPrice * 100
+	return &percentChange
+}
+
+func isTerminalAssetError(err error) bool {
+	return errors.Is(err, ErrAssetNotFound) || errors.Is(err, ErrAssetMalformed)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -30,33 +31,53 @@ const (
 
 	cacheKeyPrefix = "prices:v1"
 
-	// candlesWindow / candlesResolutionSec define the rolling 24h window used
-	// to compute percentagePriceChange24h from /asset/{id}/candles. Hourly
-	// resolution yields ~25 records, well under Stellar Expert's 200-record cap.
-	candlesWindow        = 24 * time.Hour
-	candlesResolutionSec = 3600
-
-	// minCandleWindow / maxCandleWindow bound how far before `to` the
-	// oldest returned candle must open. With hourly resolution and a
-	// truncated `to`, an asset trading continuously yields candles[0] at
-	// exactly 24h ago; sparse trading or upstream truncation can shift it
-	// later (closer to now) or rarely earlier. ±1h around 24h covers
-	// normal bucket-boundary slack while rejecting sparse-data drift that
-	// would make the result not represent a 24h window.
-	minCandleWindow = 23 * time.Hour
-	maxCandleWindow = 25 * time.Hour
+	// providerLabelNone labels a miss no provider could price;
+	// providerLabelMedian labels an entry aggregated from several quotes.
+	providerLabelNone   = "none"
+	providerLabelMedian = "median"
+)
+
+// Aggregation policies for PricesServiceConfig.Aggregation
+// (--price-aggregation).
+const (
+	// PriceAggregationFirstSuccess asks providers in priority order and
+	// serves the first price found.
+	PriceAggregationFirstSuccess = "first-success"
+	// PriceAggregationMedian asks every provider concurrently and serves the
+	// median of the prices returned.
+	PriceAggregationMedian = "median"
 )
 
 // PricesServiceConfig tunes the orchestrator. Zero values fall back to safe
 // defaults so callers can construct a service with PricesServiceConfig{}.
 type PricesServiceConfig struct {
-	CacheTTL         time.Duration
+	// CacheTTL is how long a cached price is fresh.
+	CacheTTL time.Duration
+	// StaleTTL is how long past CacheTTL a cached price is still served while
+	// a background fetch refreshes it. Zero disables stale serving.
+	StaleTTL         time.Duration
 	MissFetchTimeout time.Duration
 	MaxConcurrent    int
+	// HotTokens is how many of each network's most requested tokens Run keeps
+	// fresh; HotRefreshInterval is how often it checks them. Zero HotTokens
+	// disables request tracking and Run.
+	HotTokens          int
+	HotRefreshInterval time.Duration
+	// Providers are the spot price sources in priority order. Empty means
+	// Stellar Expert alone.
+	Providers []types.PriceProvider
+	// Aggregation is PriceAggregationFirstSuccess (the default) or
+	// PriceAggregationMedian.
+	Aggregation string
+	// Contracts maps SAC contract ids to their classic assets. When nil,
+	// every contract token is priced under its own id.
+	Contracts types.ContractAssetResolver
 }
 
 type pricesService struct {
+	// stellarExpert serves price history; spot prices go through providers.
 	stellarExpert types.StellarExpertService
+	providers     []types.PriceProvider
 	redis         *store.RedisStore
 	cfg           PricesServiceConfig
 	svcMetrics    *metrics.Service
@@ -65,10 +86,15 @@ type pricesService struct {
 	// so a thundering herd on a hot token (e.g. XLM at TTL expiry) issues one
 	// Stellar Expert call instead of one per in-flight request.
 	fetchGroup singleflight.Group
+	// refreshSlots bounds the background refreshes of stale entries.
+	refreshSlots chan struct{}
+	// requested queues requests' tokens for Run's hot-count writer.
+	requested  chan requestedTokens
+	background backgroundWork
 }
 
 // NewPricesService wires the orchestrator. redis may be nil; if so, every
-// request bypasses the cache and hits Stellar Expert. pricesMetrics may be
+// request bypasses the cache and hits the providers. pricesMetrics may be
 // nil for tests; counters become no-ops in that case.
 func NewPricesService(stellarExpert types.StellarExpertService, redis *store.RedisStore, cfg PricesServiceConfig, metricsService *metrics.Service, pricesMetrics *metrics.Prices) types.PricesService {
 	if cfg.MaxConcurrent <= 0 {
@@ -80,23 +106,59 @@ func NewPricesService(stellarExpert types.StellarExpertService, redis *store.Red
 	if cfg.MissFetchTimeout <= 0 {
 		cfg.MissFetchTimeout = defaultMissFetchTTL
 	}
-	return &pricesService{stellarExpert: stellarExpert, redis: redis, cfg: cfg, svcMetrics: metricsService, pricesMetrics: pricesMetrics}
+	if cfg.Aggregation == "" {
+		cfg.Aggregation = PriceAggregationFirstSuccess
+	}
+	if cfg.StaleTTL < 0 {
+		cfg.StaleTTL = 0
+	}
+	if cfg.HotRefreshInterval <= 0 {
+		cfg.HotRefreshInterval = defaultHotRefreshInterval
+	}
+	providers := cfg.Providers
+	if len(providers) == 0 {
+		providers = []types.PriceProvider{NewStellarExpertPriceProvider(stellarExpert)}
+	}
+	return &pricesService{
+		stellarExpert: stellarExpert,
+		providers:     providers,
+		redis:         redis,
+		cfg:           cfg,
+		svcMetrics:    metricsService,
+		pricesMetrics: pricesMetrics,
+		refreshSlots:  make(chan struct{}, cfg.MaxConcurrent),
+		requested:     make(chan requestedTokens, hotRecordQueue),
+	}
 }
 
 func (p *pricesService) Name() string { return pricesServiceName }
 
 // cachedPriceEntry is the on-disk shape in Redis. Only positive results are
-// cached; Redis expiry (CacheTTL) alone governs freshness, so any entry that
-// MGET returns is a live hit.
+// cached, for CacheTTL+StaleTTL; FetchedAt (Unix milliseconds) tells a fresh
+// entry from a stale one. Entries written before FetchedAt existed live for
+// CacheTTL alone and are always fresh. Provider names the source for the
+// cache metric; entries written before providers existed lack it and came
+// from Stellar Expert.
 type cachedPriceEntry struct {
 	CurrentPrice             string  `json:"currentPrice,omitempty"`
 	PercentagePriceChange24h *string `json:"percentagePriceChange24h,omitempty"`
+	Provider                 string  `json:"provider,omitempty"`
+	FetchedAt                int64   `json:"fetchedAt,omitempty"`
+}
+
+// age is how long ago the entry was fetched, or zero when that is unknown.
+func (e *cachedPriceEntry) age(now time.Time) time.Duration {
+	if e.FetchedAt == 0 {
+		return 0
+	}
+	return now.Sub(time.UnixMilli(e.FetchedAt))
 }
 
 // GetPrices fetches a snapshot for each canonical token id. The returned map
 // is keyed by canonical id; nil values mean the token is unpriceable
 // (unknown to Stellar Expert, malformed, or unavailable within the request's
-// miss-fetch budget). The whole request only fails on caller context
+// miss-fetch budget). Stale cached prices are returned as is and refreshed in
+// the background. The whole request only fails on caller context
 // cancellation or unrecoverable system errors.
 func (p *pricesService) GetPrices(ctx context.Context, tokens []string, network string) (_ map[string]*types.PriceEntry, err error) {
 	start := time.Now()
@@ -107,9 +169,80 @@ func (p *pricesService) GetPrices(ctx context.Context, tokens []string, network
 	if network != types.PUBLIC && network != types.TESTNET {
 		return nil, fmt.Errorf("unsupported network for prices: %s", network)
 	}
-	cacheNet := strings.ToLower(network)
 
-	canonical := utils.DedupePreserveOrder(tokens)
+	// Resolving contracts and fetching misses share one budget, so a cold
+	// request waits at most MissFetchTimeout in all.
+	budgetCtx, cancel := context.WithTimeout(ctx, p.cfg.MissFetchTimeout)
+	defer cancel()
+
+	requested := utils.DedupePreserveOrder(tokens)
+	pricingIDs := p.pricingIDs(budgetCtx, network, requested)
+	toPrice := make([]string, 0, len(requested))
+	for _, token := range requested {
+		if id, ok := pricingIDs[token]; ok {
+			toPrice = append(toPrice, id)
+		}
+	}
+	priced, err := p.getPrices(ctx, budgetCtx, network, utils.DedupePreserveOrder(toPrice))
+
+	result := make(map[string]*types.PriceEntry, len(requested))
+	for _, token := range requested {
+		id, ok := pricingIDs[token]
+		if !ok {
+			// The contract couldn't be resolved, so it is neither priced nor
+			// cached under its own id this time.
+			if err == nil {
+				result[token] = nil
+			}
+			continue
+		}
+		if entry, ok := priced[id]; ok {
+			result[token] = entry
+		}
+	}
+	return result, err
+}
+
+// pricingIDs maps each token to the id it is priced under. A SAC is priced
+// as the classic asset it wraps, so the two share one cache entry and one
+// provider lookup; every other token, SEP-41 contracts included, is priced
+// as itself. A contract that can't be resolved within ctx is left out.
+func (p *pricesService) pricingIDs(ctx context.Context, network string, tokens []string) map[string]string {
+	ids := make(map[string]string, len(tokens))
+	var contracts []string
+	for _, token := range tokens {
+		if p.cfg.Contracts != nil && assetid.IsContract(token) {
+			contracts = append(contracts, token)
+			continue
+		}
+		ids[token] = token
+	}
+	if len(contracts) == 0 {
+		return ids
+	}
+
+	classic, err := p.cfg.Contracts.ClassicAssets(ctx, network, contracts)
+	if err != nil {
+		logger.Warn("prices: resolving contract assets failed", "network", network, "contracts", len(contracts), "error", err)
+		return ids
+	}
+	for _, contract := range contracts {
+		asset, ok := classic[contract]
+		if !ok {
+			continue
+		}
+		if asset == "" {
+			asset = contract
+		}
+		ids[contract] = asset
+	}
+	return ids
+}
+
+// getPrices prices deduplicated canonical ids from the cache and, for misses,
+// the providers within budgetCtx.
+func (p *pricesService) getPrices(ctx, budgetCtx context.Context, network string, canonical []string) (map[string]*types.PriceEntry, error) {
+	cacheNet := strings.ToLower(network)
 	result := make(map[string]*types.PriceEntry, len(canonical))
 	var resultMu sync.Mutex
 
@@ -119,22 +252,22 @@ func (p *pricesService) GetPrices(ctx context.Context, tokens []string, network
 		cacheKeys[i] = cacheKey(cacheNet, c)
 		tokenByCacheKey[cacheKeys[i]] = c
 	}
+	p.recordRequested(network, cacheNet, canonical)
 
-	for token, entry := range p.loadCachedPrices(ctx, cacheKeys, tokenByCacheKey, network) {
+	hits, stale := p.loadCachedPrices(ctx, cacheKeys, tokenByCacheKey, network)
+	for token, entry := range hits {
 		result[token] = entry
 	}
+	p.revalidate(network, cacheNet, stale)
 
 	misses := missingTokens(canonical, result)
 	if len(misses) == 0 {
 		return result, nil
 	}
 
-	fetchCtx, cancel := context.WithTimeout(ctx, p.cfg.MissFetchTimeout)
-	defer cancel()
-
-	p.resolveMisses(fetchCtx, network, cacheNet, misses, result, &resultMu)
+	p.resolveMisses(budgetCtx, network, cacheNet, misses, result, &resultMu)
 	unresolved := len(missingTokens(canonical, result))
-	if unresolved > 0 && fetchCtx.Err() != nil && ctx.Err() == nil {
+	if unresolved > 0 && budgetCtx.Err() != nil && ctx.Err() == nil {
 		logger.Warn("prices: miss fetch budget exhausted; returning best-effort results", "network", network, "misses", len(misses), "unresolved", unresolved)
 		if p.pricesMetrics != nil {
 			p.pricesMetrics.MissBudgetExhausted.WithLabelValues(network).Inc()
@@ -147,14 +280,14 @@ func (p *pricesService) GetPrices(ctx context.Context, tokens []string, network
 	return result, nil
 }
 
-// loadCachedPrices returns the cached entries for cacheKeys. Redis expiry
-// (CacheTTL) governs freshness, so every entry MGET returns is a live hit and
-// any absent key is a miss.
-func (p *pricesService) loadCachedPrices(ctx context.Context, cacheKeys []string, tokenByCacheKey map[string]string, network string) map[string]*types.PriceEntry {
-	hits := make(map[string]*types.PriceEntry, len(cacheKeys))
+// loadCachedPrices returns the cached entries for cacheKeys, stale ones
+// included, and the tokens whose entries are stale. Any absent key is a miss.
+// Only hits are counted here; misses are counted once resolveMisses knows
+// which provider, if any, priced them.
+func (p *pricesService) loadCachedPrices(ctx context.Context, cacheKeys []string, tokenByCacheKey map[string]string, network string) (hits map[string]*types.PriceEntry, stale []string) {
+	hits = make(map[string]*types.PriceEntry, len(cacheKeys))
 	if p.redis == nil {
-		p.recordCacheOutcome(network, "miss", len(cacheKeys))
-		return hits
+		return hits, nil
 	}
 
 	cached, mgetErr := p.redis.MGetJSON(ctx, cacheKeys, func() any { return new(cachedPriceEntry) })
@@ -163,31 +296,39 @@ func (p *pricesService) loadCachedPrices(ctx context.Context, cacheKeys []string
 		if p.pricesMetrics != nil {
 			p.pricesMetrics.RedisErrors.WithLabelValues("mget").Inc()
 		}
-		p.recordCacheOutcome(network, "miss", len(cacheKeys))
-		return hits
+		return hits, nil
 	}
 
+	now := time.Now()
 	for _, k := range cacheKeys {
 		v, present := cached[k]
 		entry, _ := v.(*cachedPriceEntry)
 		if !present || entry == nil {
-			p.recordCacheOutcome(network, "miss", 1)
 			continue
 		}
 		hits[tokenByCacheKey[k]] = &types.PriceEntry{
 			CurrentPrice:             entry.CurrentPrice,
 			PercentagePriceChange24h: entry.PercentagePriceChange24h,
 		}
-		p.recordCacheOutcome(network, "hit", 1)
+		provider := entry.Provider
+		if provider == "" {
+			provider = PriceProviderStellarExpert
+		}
+		outcome := "hit"
+		if entry.age(now) > p.cfg.CacheTTL {
+			outcome = "stale"
+			stale = append(stale, tokenByCacheKey[k])
+		}
+		p.recordCacheOutcome(network, provider, outcome, 1)
 	}
-	return hits
+	return hits, stale
 }
 
-func (p *pricesService) recordCacheOutcome(network, outcome string, n int) {
+func (p *pricesService) recordCacheOutcome(network, provider, outcome string, n int) {
 	if p.pricesMetrics == nil || n <= 0 {
 		return
 	}
-	p.pricesMetrics.CacheOutcomes.WithLabelValues(network, outcome).Add(float64(n))
+	p.pricesMetrics.CacheOutcomes.WithLabelValues(network, outcome, provider).Add(float64(n))
 }
 
 func (p *pricesService) resolveMisses(ctx context.Context, network, cacheNet string, misses []string, result map[string]*types.PriceEntry, resultMu *sync.Mutex) {
@@ -196,7 +337,11 @@ func (p *pricesService) resolveMisses(ctx context.Context, network, cacheNet str
 
 	for _, canonical := range misses {
 		g.Go(func() error {
-			entry, resolved := p.fetchAndCache(gctx, network, cacheNet, canonical)
+			entry, provider, resolved := p.fetchAndCache(gctx, network, cacheNet, canonical)
+			if provider == "" {
+				provider = providerLabelNone
+			}
+			p.recordCacheOutcome(network, provider, "miss", 1)
 			if resolved {
 				resultMu.Lock()
 				result[canonical] = entry
@@ -213,134 +358,171 @@ func (p *pricesService) resolveMisses(ctx context.Context, network, cacheNet str
 // fetchOutcome is the singleflight-shared result of one upstream fetch.
 type fetchOutcome struct {
 	entry    *types.PriceEntry
+	provider string
 	resolved bool
 }
 
 // fetchAndCache returns the priced entry for one canonical asset id, coalescing
 // concurrent requests for the same cache key through singleflight so a hot
-// token issues a single upstream fetch. The boolean reports whether the token
-// was authoritatively resolved for this request: not-found/malformed assets
-// resolve to (nil, true); transient failures and budget exhaustion return
-// (nil, false). The caller's ctx only bounds how long this request waits — the
-// shared fetch runs under its own budget so one caller's cancellation can't
-// poison other in-flight waiters.
-func (p *pricesService) fetchAndCache(ctx context.Context, network, cacheNet, canonical string) (*types.PriceEntry, bool) {
-	ch := p.fetchGroup.DoChan(cacheKey(cacheNet, canonical), func() (any, error) {
-		fctx, cancel := context.WithTimeout(context.Background(), p.cfg.MissFetchTimeout)
-		defer cancel()
-		entry, resolved := p.fetchFromUpstream(fctx, network, cacheNet, canonical)
-		return fetchOutcome{entry: entry, resolved: resolved}, nil
-	})
+// token issues a single round of provider calls. provider names the source of
+// a priced entry. The boolean reports whether the token was authoritatively
+// resolved for this request: assets no provider can price resolve to
+// (nil, true); transient failures and budget exhaustion return (nil, false).
+// The caller's ctx only bounds how long this request waits — the shared fetch
+// runs under its own budget so one caller's cancellation can't poison other
+// in-flight waiters.
+func (p *pricesService) fetchAndCache(ctx context.Context, network, cacheNet, canonical string) (_ *types.PriceEntry, provider string, resolved bool) {
 	select {
 	case <-ctx.Done():
-		return nil, false
-	case res := <-ch:
+		return nil, "", false
+	case res := <-p.sharedFetch(network, cacheNet, canonical):
 		out, _ := res.Val.(fetchOutcome)
-		return out.entry, out.resolved
+		return out.entry, out.provider, out.resolved
 	}
 }
 
-// fetchFromUpstream performs the actual Stellar Expert fetch for one canonical
-// asset id and writes a successful result to Redis. The asset and candles
-// calls run concurrently; on a terminal asset error the candles call is
-// cancelled so unknown assets don't double upstream load.
-func (p *pricesService) fetchFromUpstream(ctx context.Context, network, cacheNet, canonical string) (_ *types.PriceEntry, resolved bool) {
-	stellarExpertID := assetid.ToStellarExpert(canonical)
-
-	// Truncate to the candle resolution so `from` and `to` align to bucket
-	// boundaries; otherwise upstream may return a window 23–25h wide with
-	// no consistent rule. The current price is still as-of-now via
-	// /asset/{id}, so the actual price comparison is at most ~1h off 24h.
-	resolution := time.Duration(candlesResolutionSec) * time.Second
-	to := time.Now().UTC().Truncate(resolution)
-	from := to.Add(-candlesWindow)
+// sharedFetch starts, or joins, the singleflight fetch for one canonical
+// asset id. The fetch runs to completion whether or not anyone reads the
+// channel, unless Run's context is cancelled first.
+func (p *pricesService) sharedFetch(network, cacheNet, canonical string) <-chan singleflight.Result {
+	return p.fetchGroup.DoChan(cacheKey(cacheNet, canonical), func() (any, error) {
+		bgCtx, release := p.background.acquire()
+		defer release()
+		fctx, cancel := context.WithTimeout(bgCtx, p.cfg.MissFetchTimeout)
+		defer cancel()
+		entry, provider, resolved := p.fetchFromProviders(fctx, network, cacheNet, canonical)
+		return fetchOutcome{entry: entry, provider: provider, resolved: resolved}, nil
+	})
+}
 
-	fetchCtx, cancelFetch := context.WithCancel(ctx)
-	defer cancelFetch()
+// fetchFromProviders prices one canonical asset id under the configured
+// aggregation policy and writes a priced result to Redis.
+func (p *pricesService) fetchFromProviders(ctx context.Context, network, cacheNet, canonical string) (_ *types.PriceEntry, provider string, resolved bool) {
+	var quote *types.PriceQuote
+	if p.cfg.Aggregation == PriceAggregationMedian {
+		quote, provider, resolved = p.medianQuote(ctx, network, canonical)
+	} else {
+		quote, provider, resolved = p.firstSuccessQuote(ctx, network, canonical)
+	}
+	if quote == nil {
+		return nil, "", resolved
+	}
+	entry := &types.PriceEntry{
+		CurrentPrice:             formatPrice(quote.Price),
+		PercentagePriceChange24h: formatChange(quote.Change24h),
+	}
+	p.cachePositive(ctx, cacheNet, canonical, entry, provider)
+	return entry, provider, true
+}
 
-	var (
-		asset      *types.StellarExpertAsset
-		assetErr   error
-		candles    []types.StellarExpertCandle
-		candlesErr error
-		wg         sync.WaitGroup
-	)
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		asset, assetErr = p.stellarExpert.GetAsset(fetchCtx, network, stellarExpertID)
-		if assetErr != nil && (errors.Is(assetErr, ErrAssetNotFound) || errors.Is(assetErr, ErrAssetMalformed)) {
-			cancelFetch()
+// firstSuccessQuote asks providers in priority order and returns the first
+// price. A provider that has no price for the asset passes to the next one;
+// so does a failing one, which also makes a final miss non-authoritative.
+// Each attempt gets an even share of what is left of ctx's budget so a hung
+// provider can't starve its fallbacks.
+func (p *pricesService) firstSuccessQuote(ctx context.Context, network, canonical string) (*types.PriceQuote, string, bool) {
+	resolved := true
+	for i, provider := range p.providers {
+		attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+		if deadline, ok := ctx.Deadline(); ok {
+			share := time.Until(deadline) / time.Duration(len(p.providers)-i)
+			attemptCtx, cancel = context.WithTimeout(ctx, share)
 		}
-	}()
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		candles, candlesErr = p.stellarExpert.GetAssetCandles(fetchCtx, network, stellarExpertID, from, to, candlesResolutionSec)
-	}()
-	wg.Wait()
-
-	if assetErr != nil {
-		if errors.Is(assetErr, ErrAssetNotFound) || errors.Is(assetErr, ErrAssetMalformed) {
-			return nil, true
+		quote, err := provider.GetQuote(attemptCtx, network, canonical)
+		cancel()
+		if err == nil {
+			return quote, provider.Name(), true
 		}
-		if errors.Is(assetErr, context.DeadlineExceeded) || errors.Is(assetErr, context.Canceled) {
-			return nil, false
+		if errors.Is(err, types.ErrAssetNotPriced) {
+			continue
 		}
-		logger.Warn("prices: upstream fetch failed", "asset", canonical, "error", assetErr)
-		return nil, false
-	}
-
-	// A zero price — whether Stellar Expert omitted the `price` field (a known
-	// but illiquid asset; JSON absence decodes to 0) or reported a genuine 0 —
-	// is unpriceable. Honor the documented null contract rather than leaking a
-	// "0" string. Resolves to (nil, true) like not-found/malformed so it caches
-	// as an authoritative miss.
-	if asset.Price == 0 {
-		return nil, true
-	}
-
-	// The 24h change comes only from the hourly candles window, which can pin
-	// a true trailing 24h (±1h). When candles are unavailable or can't cover
-	// ~24h we return null rather than a mislabeled day-over-day delta from the
-	// daily price7d series.
-	var change24h *string
-	if candlesErr != nil {
-		if !errors.Is(candlesErr, context.DeadlineExceeded) && !errors.Is(candlesErr, context.Canceled) &&
-			!errors.Is(candlesErr, ErrAssetNotFound) && !errors.Is(candlesErr, ErrAssetMalformed) {
-			logger.Warn("prices: candles fetch failed; 24h change unavailable", "asset", stellarExpertID, "error", candlesErr)
+		resolved = false
+		if ctx.Err() != nil {
+			break
 		}
-	} else {
-		change24h = change24hFromCandles(asset.Price, candles, to)
+		logProviderError(provider.Name(), canonical, err)
+	}
+	return nil, "", resolved
+}
+
+// medianQuote asks every provider concurrently and returns the median price
+// and, separately, the median of the 24h changes reported. An entry built
+// from a single quote is labeled with that provider, otherwise with
+// providerLabelMedian. The miss is authoritative only when every provider
+// reported that it has no price.
+func (p *pricesService) medianQuote(ctx context.Context, network, canonical string) (*types.PriceQuote, string, bool) {
+	quotes := make([]*types.PriceQuote, len(p.providers))
+	errs := make([]error, len(p.providers))
+	var wg sync.WaitGroup
+	for i, provider := range p.providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			quotes[i], errs[i] = provider.GetQuote(ctx, network, canonical)
+		}()
 	}
+	wg.Wait()
 
-	entry := &types.PriceEntry{
-		CurrentPrice:             formatPrice(asset.Price),
-		PercentagePriceChange24h: change24h,
+	var (
+		prices, changes []float64
+		source          string
+		resolved        = true
+	)
+	for i, err := range errs {
+		if err != nil {
+			if errors.Is(err, types.ErrAssetNotPriced) {
+				continue
+			}
+			resolved = false
+			if ctx.Err() == nil {
+				logProviderError(p.providers[i].Name(), canonical, err)
+			}
+			continue
+		}
+		prices = append(prices, quotes[i].Price)
+		if quotes[i].Change24h != nil {
+			changes = append(changes, *quotes[i].Change24h)
+		}
+		source = p.providers[i].Name()
 	}
-	p.cachePositive(ctx, cacheNet, canonical, entry)
-	return entry, true
+	if len(prices) == 0 {
+		return nil, "", resolved
+	}
+	if len(prices) > 1 {
+		source = providerLabelMedian
+	}
+	quote := &types.PriceQuote{Price: median(prices)}
+	if len(changes) > 0 {
+		change := median(changes)
+		quote.Change24h = &change
+	}
+	return quote, source, true
 }
 
-// change24hFromCandles computes the 24h percentage delta between currentPrice
-// and the open of the oldest candle. Returns nil when the upstream is empty,
-// the open is zero, or the oldest returned candle is too far from 24h before
-// `to` to credibly represent a 24h window (sparse trading or anomalous
-// upstream return).
-func change24hFromCandles(currentPrice float64, candles []types.StellarExpertCandle, to time.Time) *string {
-	if len(candles) == 0 {
-		return nil
+func logProviderError(provider, canonical string, err error) {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return
 	}
-	oldestAge := to.Sub(time.Unix(candles[0].TS(), 0))
-	if oldestAge < minCandleWindow || oldestAge > maxCandleWindow {
-		return nil
+	logger.Warn("prices: upstream fetch failed", "provider", provider, "asset", canonical, "error", err)
+}
+
+// median returns the middle value of values, or the mean of the two middle
+// values for an even count. values is sorted in place.
+func median(values []float64) float64 {
+	slices.Sort(values)
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
 	}
-	openPrice := candles[0].Open()
-	if openPrice == 0 {
+	return values[mid]
+}
+
+// formatChange rounds a percentage change to two decimals.
+func formatChange(change *float64) *string {
+	if change == nil || math.IsNaN(*change) || math.IsInf(*change, 0) {
 		return nil
 	}
-	percentChange := (currentPrice - openPrice) / openPrice * 100
-	rounded := math.Round(percentChange*100) / 100
+	rounded := math.Round(*change*100) / 100
 	if rounded == 0 {
 		// Collapse negative zero to "0" so the JSON is byte-stable.
 		rounded = 0
@@ -349,15 +531,17 @@ func change24hFromCandles(currentPrice float64, candles []types.StellarExpertCan
 	return &formatted
 }
 
-func (p *pricesService) cachePositive(ctx context.Context, cacheNet, canonical string, entry *types.PriceEntry) {
+func (p *pricesService) cachePositive(ctx context.Context, cacheNet, canonical string, entry *types.PriceEntry, provider string) {
 	if p.redis == nil {
 		return
 	}
 	value := cachedPriceEntry{
 		CurrentPrice:             entry.CurrentPrice,
 		PercentagePriceChange24h: entry.PercentagePriceChange24h,
+		Provider:                 provider,
+		FetchedAt:                time.Now().UnixMilli(),
 	}
-	if err := p.redis.SetJSON(ctx, cacheKey(cacheNet, canonical), value, p.cfg.CacheTTL); err != nil {
+	if err := p.redis.SetJSON(ctx, cacheKey(cacheNet, canonical), value, p.cfg.CacheTTL+p.cfg.StaleTTL); err != nil {
 		logger.Warn("prices: redis SET failed", "asset", canonical, "error", err)
 		if p.pricesMetrics != nil {
 			p.pricesMetrics.RedisErrors.WithLabelValues("set").Inc()
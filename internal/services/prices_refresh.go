@@ -0,0 +1,300 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/stellar/freighter-backend-v2/internal/logger"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils/refresh"
+)
+
+const (
+	defaultHotRefreshInterval = 10 * time.Second
+
+	// hotWindow is the span request counts are bucketed by. The hot set sums
+	// the current and the previous window, so a token cools off between one
+	// and two windows after its requests stop.
+	hotWindow = time.Hour
+
+	hotRecordTimeout = 2 * time.Second
+
+	// hotRecordQueue is how many requests' tokens may wait for the hot-count
+	// writer. Requests arriving while it is full go uncounted.
+	hotRecordQueue = 1024
+)
+
+// Run is the lifetime of the service's background work. Until ctx is
+// cancelled it counts requested tokens and keeps the HotTokens most requested
+// prices of each network fresh, refreshing every HotRefreshInterval those
+// that would otherwise turn stale before the next pass. Fetches that outlive
+// the request that started them run under ctx, and Run returns only once they
+// have stopped.
+func (p *pricesService) Run(ctx context.Context) {
+	p.background.start(ctx)
+	defer p.background.stop()
+	if p.redis == nil || p.cfg.HotTokens <= 0 {
+		<-ctx.Done()
+		return
+	}
+
+	writerCtx, release := p.background.acquire()
+	go func() {
+		defer release()
+		p.writeRequested(writerCtx)
+	}()
+
+	refresh.Loop{
+		Interval:       p.cfg.HotRefreshInterval,
+		Refresh:        p.refreshHotTokens,
+		FailureMessage: "prices: hot token refresh failed",
+	}.Run(ctx)
+}
+
+// refreshHotTokens runs refreshHot for each network prices are served on.
+func (p *pricesService) refreshHotTokens(ctx context.Context) error {
+	var errs []error
+	for _, network := range []string{types.PUBLIC, types.TESTNET} {
+		if err := p.refreshHot(ctx, network); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", network, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// refreshHot re-fetches the network's hot tokens whose cached price would no
+// longer be fresh by the next pass. Tokens without a cached price are left to
+// the request path, so a hot but unpriceable token costs nothing here.
+func (p *pricesService) refreshHot(ctx context.Context, network string) error {
+	cacheNet := strings.ToLower(network)
+	tokens, err := p.hotTokens(ctx, cacheNet, time.Now())
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	cacheKeys := make([]string, len(tokens))
+	for i, token := range tokens {
+		cacheKeys[i] = cacheKey(cacheNet, token)
+	}
+	cached, err := p.redis.MGetJSON(ctx, cacheKeys, func() any { return new(cachedPriceEntry) })
+	if err != nil {
+		if p.pricesMetrics != nil {
+			p.pricesMetrics.RedisErrors.WithLabelValues("mget").Inc()
+		}
+		return err
+	}
+
+	var g errgroup.Group
+	g.SetLimit(p.cfg.MaxConcurrent)
+	now := time.Now()
+	for i, token := range tokens {
+		entry, _ := cached[cacheKeys[i]].(*cachedPriceEntry)
+		if entry == nil || !p.dueForRefresh(entry, now) {
+			continue
+		}
+		p.recordRefresh(network, "hot")
+		g.Go(func() error {
+			p.fetchAndCache(ctx, network, cacheNet, token)
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+// dueForRefresh reports whether entry turns stale before the next hot pass.
+// Entries of unknown age are refreshed so they gain one.
+func (p *pricesService) dueForRefresh(entry *cachedPriceEntry, now time.Time) bool {
+	return entry.FetchedAt == 0 || entry.age(now) > p.cfg.CacheTTL-p.cfg.HotRefreshInterval
+}
+
+// revalidate refreshes stale entries in the background. Refreshes share the
+// request path's singleflight, so a token already being fetched isn't fetched
+// twice, and at most MaxConcurrent run at once; a stale token that finds no
+// free slot stays stale for a later request to retry.
+func (p *pricesService) revalidate(network, cacheNet string, stale []string) {
+	for _, canonical := range stale {
+		select {
+		case p.refreshSlots <- struct{}{}:
+		default:
+			return
+		}
+		p.recordRefresh(network, "stale")
+		ctx, release := p.background.acquire()
+		go func() {
+			defer release()
+			defer func() { <-p.refreshSlots }()
+			p.fetchAndCache(ctx, network, cacheNet, canonical)
+		}()
+	}
+}
+
+func (p *pricesService) recordRefresh(network, trigger string) {
+	if p.pricesMetrics == nil {
+		return
+	}
+	p.pricesMetrics.BackgroundRefreshes.WithLabelValues(network, trigger).Inc()
+}
+
+// requestedTokens is one request's canonical ids, queued for the hot-count
+// writer under the hot key of the window it arrived in.
+type requestedTokens struct {
+	network   string
+	key       string
+	canonical []string
+}
+
+// recordRequested queues one request's canonical ids to be counted in the
+// current hot window. It never holds up the request: when the queue is full
+// the request goes uncounted, which only skews the hot set.
+func (p *pricesService) recordRequested(network, cacheNet string, canonical []string) {
+	if p.redis == nil || p.cfg.HotTokens <= 0 || len(canonical) == 0 {
+		return
+	}
+	select {
+	case p.requested <- requestedTokens{network: network, key: hotKey(cacheNet, hotWindowIndex(time.Now())), canonical: canonical}:
+	default:
+	}
+}
+
+// writeRequested counts queued requests until ctx is cancelled. Requests that
+// queue up while a write is in flight are summed into the next one, a single
+// ZINCRBY pipeline per hot key.
+func (p *pricesService) writeRequested(ctx context.Context) {
+	for {
+		var first requestedTokens
+		select {
+		case <-ctx.Done():
+			return
+		case first = <-p.requested:
+		}
+
+		counts := make(map[string]map[string]float64)
+		networks := make(map[string]string)
+		add := func(r requestedTokens) {
+			if counts[r.key] == nil {
+				counts[r.key] = make(map[string]float64, len(r.canonical))
+				networks[r.key] = r.network
+			}
+			for _, token := range r.canonical {
+				counts[r.key][token]++
+			}
+		}
+		add(first)
+		for n := len(p.requested); n > 0; n-- {
+			add(<-p.requested)
+		}
+
+		for key, incr := range counts {
+			writeCtx, cancel := context.WithTimeout(ctx, hotRecordTimeout)
+			err := p.redis.ZIncrBy(writeCtx, key, incr, 2*hotWindow)
+			cancel()
+			if err != nil && ctx.Err() == nil {
+				logger.Warn("prices: recording requested tokens failed", "network", networks[key], "error", err)
+				if p.pricesMetrics != nil {
+					p.pricesMetrics.RedisErrors.WithLabelValues("zincrby").Inc()
+				}
+			}
+		}
+	}
+}
+
+// hotTokens returns the network's HotTokens most requested canonical ids over
+// the current and previous windows, most requested first.
+func (p *pricesService) hotTokens(ctx context.Context, cacheNet string, now time.Time) ([]string, error) {
+	window := hotWindowIndex(now)
+	scores := make(map[string]float64, 2*p.cfg.HotTokens)
+	for _, w := range []int64{window - 1, window} {
+		top, err := p.redis.ZTop(ctx, hotKey(cacheNet, w), p.cfg.HotTokens)
+		if err != nil {
+			if p.pricesMetrics != nil {
+				p.pricesMetrics.RedisErrors.WithLabelValues("ztop").Inc()
+			}
+			return nil, err
+		}
+		for token, score := range top {
+			scores[token] += score
+		}
+	}
+	return topScored(scores, p.cfg.HotTokens), nil
+}
+
+// topScored returns up to n keys of scores, highest score first and ties
+// broken by key so the order is stable.
+func topScored(scores map[string]float64, n int) []string {
+	keys := make([]string, 0, len(scores))
+	for k := range scores {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		if c := cmp.Compare(scores[b], scores[a]); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+	if len(keys) > n {
+		keys = keys[:n]
+	}
+	return keys
+}
+
+func hotWindowIndex(now time.Time) int64 {
+	return now.Unix() / int64(hotWindow/time.Second)
+}
+
+func hotKey(cacheNet string, window int64) string {
+	return cacheKeyPrefix + ":hot:" + cacheNet + ":" + strconv.FormatInt(window, 10)
+}
+
+// backgroundWork tracks the work that outlives the request which started it:
+// shared fetches, stale revalidations and the hot-count writer. Until Run
+// starts, such work runs untracked under context.Background; from then on it
+// runs under Run's context, and Run waits for it before returning.
+type backgroundWork struct {
+	mu      sync.Mutex
+	ctx     context.Context
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+func (b *backgroundWork) start(ctx context.Context) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.ctx = ctx
+}
+
+// stop waits for the tracked work to finish. The context passed to start must
+// already be done, so the work is winding down.
+func (b *backgroundWork) stop() {
+	b.mu.Lock()
+	b.stopped = true
+	b.mu.Unlock()
+	b.wg.Wait()
+}
+
+// acquire returns the context one piece of background work runs under and
+// the func it calls once done. Work acquired after stop gets the cancelled
+// context and is not waited for.
+func (b *backgroundWork) acquire() (context.Context, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch {
+	case b.ctx == nil:
+		return context.Background(), func() {}
+	case b.stopped:
+		return b.ctx, func() {}
+	}
+	b.wg.Add(1)
+	return b.ctx, b.wg.Done
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/metrics"
+	"github.com/stellar/freighter-backend-v2/internal/store"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+func TestCachedPriceEntry_Age(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	assert.Equal(t, time.Duration(0), (&cachedPriceEntry{}).age(now), "legacy entries count as fresh")
+	entry := &cachedPriceEntry{FetchedAt: now.Add(-45 * time.Second).UnixMilli()}
+	assert.InDelta(t, float64(45*time.Second), float64(entry.age(now)), float64(time.Millisecond))
+}
+
+func TestPrices_DueForRefresh(t *testing.T) {
+	t.Parallel()
+
+	svc := NewPricesService(nil, nil, PricesServiceConfig{CacheTTL: 30 * time.Second, HotRefreshInterval: 10 * time.Second}, nil, nil).(*pricesService)
+	now := time.Now()
+	fetched := func(ago time.Duration) *cachedPriceEntry {
+		return &cachedPriceEntry{FetchedAt: now.Add(-ago).UnixMilli()}
+	}
+
+	assert.False(t, svc.dueForRefresh(fetched(5*time.Second), now))
+	assert.True(t, svc.dueForRefresh(fetched(25*time.Second), now), "stale before the next pass")
+	assert.True(t, svc.dueForRefresh(fetched(time.Minute), now))
+	assert.True(t, svc.dueForRefresh(&cachedPriceEntry{}, now), "entries of unknown age gain one")
+}
+
+func TestTopScored(t *testing.T) {
+	t.Parallel()
+
+	scores := map[string]float64{"AQUA": 2, "XLM": 9, "USDC": 5, "BTC": 5}
+	assert.Equal(t, []string{"XLM", "BTC", "USDC"}, topScored(scores, 3))
+	assert.Equal(t, []string{"XLM", "BTC", "USDC", "AQUA"}, topScored(scores, 10))
+	assert.Empty(t, topScored(nil, 3))
+}
+
+func TestPrices_RevalidateRefreshesInBackground(t *testing.T) {
+	t.Parallel()
+
+	provider := &stubPriceProvider{name: "primary", quote: &types.PriceQuote{Price: 1}}
+	pm := metrics.NewPrices(prometheus.NewRegistry())
+	svc := NewPricesService(nil, nil, PricesServiceConfig{Providers: []types.PriceProvider{provider}}, nil, pm).(*pricesService)
+
+	svc.revalidate(types.PUBLIC, "public", []string{"XLM"})
+
+	assert.Eventually(t, func() bool { return provider.calls.Load() == 1 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, float64(1), testutil.ToFloat64(pm.BackgroundRefreshes.WithLabelValues(types.PUBLIC, "stale")))
+}
+
+func TestPrices_RevalidateSkipsWhenSlotsAreTaken(t *testing.T) {
+	t.Parallel()
+
+	provider := &stubPriceProvider{name: "primary", delay: 100 * time.Millisecond, quote: &types.PriceQuote{Price: 1}}
+	pm := metrics.NewPrices(prometheus.NewRegistry())
+	svc := NewPricesService(nil, nil, PricesServiceConfig{Providers: []types.PriceProvider{provider}, MaxConcurrent: 1}, nil, pm).(*pricesService)
+
+	svc.revalidate(types.PUBLIC, "public", []string{"XLM", "USDC:" + testIssuer})
+
+	assert.Eventually(t, func() bool { return len(svc.refreshSlots) == 0 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, int64(1), provider.calls.Load())
+	assert.Equal(t, float64(1), testutil.ToFloat64(pm.BackgroundRefreshes.WithLabelValues(types.PUBLIC, "stale")))
+}
+
+func TestPrices_RunWaitsForBackgroundFetches(t *testing.T) {
+	t.Parallel()
+
+	provider := &stubPriceProvider{name: "primary", delay: time.Hour, quote: &types.PriceQuote{Price: 1}}
+	svc := NewPricesService(nil, nil, PricesServiceConfig{Providers: []types.PriceProvider{provider}, MissFetchTimeout: time.Hour}, nil, nil).(*pricesService)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		svc.Run(ctx)
+		close(done)
+	}()
+	require.Eventually(t, func() bool {
+		svc.background.mu.Lock()
+		defer svc.background.mu.Unlock()
+		return svc.background.ctx != nil
+	}, time.Second, 5*time.Millisecond)
+
+	svc.revalidate(types.PUBLIC, "public", []string{"XLM"})
+	require.Eventually(t, func() bool { return provider.calls.Load() == 1 }, time.Second, 5*time.Millisecond)
+	select {
+	case <-done:
+		require.FailNow(t, "Run returned before its context was cancelled")
+	default:
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		require.FailNow(t, "Run kept running after its context was cancelled")
+	}
+	assert.Empty(t, svc.refreshSlots, "the refresh finished before Run returned")
+}
+
+func TestPrices_RecordRequestedDropsWhenQueueIsFull(t *testing.T) {
+	t.Parallel()
+
+	redisStore := store.NewRedisStore("localhost", 1, "") // port 1 = no listener
+	svc := NewPricesService(nil, redisStore, PricesServiceConfig{HotTokens: 10}, nil, nil).(*pricesService)
+
+	for range hotRecordQueue + 5 {
+		svc.recordRequested(types.PUBLIC, "public", []string{"XLM"})
+	}
+	assert.Len(t, svc.requested, hotRecordQueue)
+}
+
+func TestHotKey_RotatesEachWindow(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_800_000_000, 0)
+	current := hotKey("public", hotWindowIndex(now))
+	assert.Equal(t, current, hotKey("public", hotWindowIndex(now.Add(time.Second))))
+	assert.NotEqual(t, current, hotKey("public", hotWindowIndex(now.Add(hotWindow))))
+	assert.Contains(t, current, cacheKeyPrefix+":hot:public:")
+}
@@ -520,8 +520,8 @@ func TestPrices_CacheOutcomes_NilRedisCountsAllAsMisses(t *testing.T) {
 	_, err := svc.GetPrices(context.Background(), []string{"XLM", "USDC:" + testIssuer}, types.PUBLIC)
 	require.NoError(t, err)
 
-	assert.Equal(t, float64(2), testutil.ToFloat64(pm.CacheOutcomes.WithLabelValues(types.PUBLIC, "miss")))
-	assert.Equal(t, float64(0), testutil.ToFloat64(pm.CacheOutcomes.WithLabelValues(types.PUBLIC, "hit")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(pm.CacheOutcomes.WithLabelValues(types.PUBLIC, "miss", PriceProviderStellarExpert)))
+	assert.Equal(t, float64(0), testutil.ToFloat64(pm.CacheOutcomes.WithLabelValues(types.PUBLIC, "hit", PriceProviderStellarExpert)))
 }
 
 func TestPrices_MissBudgetExhausted_EmitsMetric(t *testing.T) {
@@ -576,3 +576,390 @@ func TestPrices_NilMetrics_NoOps(t *testing.T) {
 	_, err := svc.GetPrices(context.Background(), []string{"XLM"}, types.PUBLIC)
 	require.NoError(t, err)
 }
+
+func TestPriceHistory_SpecsStayUnderUpstreamRecordCap(t *testing.T) {
+	t.Parallel()
+
+	for _, r := range types.PriceRanges {
+		spec, ok := priceHistorySpecs[r]
+		require.True(t, ok, "range %s has no spec", r)
+		resolution := time.Duration(spec.resolutionSec) * time.Second
+		bucket := time.Duration(spec.bucketSec) * time.Second
+		// +2: the aligned window can start up to one bucket early and ends
+		// on an in-progress candle.
+		records := int((spec.window+bucket)/resolution) + 2
+		assert.LessOrEqual(t, records, 200, "range %s", r)
+		assert.Zero(t, spec.bucketSec%spec.resolutionSec, "range %s bucket must be a multiple of the resolution", r)
+		assert.Positive(t, spec.cacheTTL, "range %s", r)
+	}
+}
+
+func TestDownsampleCandles(t *testing.T) {
+	t.Parallel()
+
+	candles := []types.StellarExpertCandle{
+		{3600, 1, 0.9, 1.2, 1.1, 0, 0, 0},
+		{5400, 1.1, 0.8, 1.5, 1.3, 0, 0, 0},
+		{7200, 1.3, 1.3, 1.3, 0, 0, 0, 0}, // no close: dropped
+		{9000, 1.3, 1.2, 1.4, 1.25, 0, 0, 0},
+	}
+	points := downsampleCandles(candles, 3600)
+	assert.Equal(t, []types.PricePoint{
+		{Timestamp: 3600, Open: "1", High: "1.5", Low: "0.8", Close: "1.3"},
+		{Timestamp: 7200, Open: "1.3", High: "1.4", Low: "1.2", Close: "1.25"},
+	}, points)
+
+	empty := downsampleCandles(nil, 3600)
+	require.NotNil(t, empty)
+	assert.Empty(t, empty)
+}
+
+func TestPriceHistory_ReturnsDownsampledSeries(t *testing.T) {
+	t.Parallel()
+
+	stellarExpert := newFakeStellarExpert()
+	stellarExpert.SetCandles("XLM", []types.StellarExpertCandle{
+		{14400, 0.1, 0.09, 0.12, 0.11, 0, 0, 0},
+		{18000, 0.11, 0.1, 0.13, 0.12, 0, 0, 0},
+		{28800, 0.12, 0.12, 0.14, 0.13, 0, 0, 0},
+	})
+	svc := NewPricesService(stellarExpert, nil, PricesServiceConfig{}, nil, nil)
+
+	history, err := svc.GetPriceHistory(context.Background(), "XLM", types.PUBLIC, types.PriceRange7D)
+	require.NoError(t, err)
+	assert.Equal(t, types.PriceRange7D, history.Range)
+	assert.Equal(t, 4*3600, history.ResolutionSec)
+	assert.Equal(t, []types.PricePoint{
+		{Timestamp: 14400, Open: "0.1", High: "0.13", Low: "0.09", Close: "0.12"},
+		{Timestamp: 28800, Open: "0.12", High: "0.14", Low: "0.12", Close: "0.13"},
+	}, history.Points)
+	assert.Equal(t, 1, stellarExpert.CandleCallCount("XLM"))
+}
+
+func TestPriceHistory_UsesStellarExpertAssetID(t *testing.T) {
+	t.Parallel()
+
+	stellarExpert := newFakeStellarExpert()
+	svc := NewPricesService(stellarExpert, nil, PricesServiceConfig{}, nil, nil)
+
+	history, err := svc.GetPriceHistory(context.Background(), "USDC:"+testIssuer, types.PUBLIC, types.PriceRange1D)
+	require.NoError(t, err)
+	assert.Empty(t, history.Points)
+	assert.Equal(t, 1, stellarExpert.CandleCallCount("USDC-"+testIssuer+"-1"))
+}
+
+func TestPriceHistory_UnknownAssetIsNotPriced(t *testing.T) {
+	t.Parallel()
+
+	stellarExpert := newFakeStellarExpert()
+	stellarExpert.SetCandleErr("XLM", ErrAssetNotFound)
+	svc := NewPricesService(stellarExpert, nil, PricesServiceConfig{}, nil, nil)
+
+	_, err := svc.GetPriceHistory(context.Background(), "XLM", types.PUBLIC, types.PriceRange30D)
+	require.ErrorIs(t, err, types.ErrAssetNotPriced)
+}
+
+func TestPriceHistory_PropagatesUpstreamErrors(t *testing.T) {
+	t.Parallel()
+
+	upstreamErr := &metrics.UpstreamError{Kind: "http_error", Code: 503, Err: errors.New("boom")}
+	stellarExpert := newFakeStellarExpert()
+	stellarExpert.SetCandleErr("XLM", upstreamErr)
+	svc := NewPricesService(stellarExpert, nil, PricesServiceConfig{}, nil, nil)
+
+	_, err := svc.GetPriceHistory(context.Background(), "XLM", types.PUBLIC, types.PriceRange1Y)
+	var upErr *metrics.UpstreamError
+	require.ErrorAs(t, err, &upErr)
+	assert.NotErrorIs(t, err, types.ErrAssetNotPriced)
+}
+
+func TestPriceHistory_RejectsUnsupportedInputs(t *testing.T) {
+	t.Parallel()
+
+	svc := NewPricesService(newFakeStellarExpert(), nil, PricesServiceConfig{}, nil, nil)
+
+	_, err := svc.GetPriceHistory(context.Background(), "XLM", types.FUTURENET, types.PriceRange1D)
+	require.Error(t, err)
+	_, err = svc.GetPriceHistory(context.Background(), "XLM", types.PUBLIC, "5y")
+	require.Error(t, err)
+}
+
+// stubPriceProvider is a programmable PriceProvider. A zero-value quote with
+// no err reports the asset as not priced; delay blocks until it elapses or
+// ctx is done.
+type stubPriceProvider struct {
+	name  string
+	quote *types.PriceQuote
+	err   error
+	delay time.Duration
+	calls atomic.Int64
+}
+
+func (s *stubPriceProvider) Name() string { return s.name }
+
+func (s *stubPriceProvider) GetQuote(ctx context.Context, network, canonical string) (*types.PriceQuote, error) {
+	s.calls.Add(1)
+	if s.delay > 0 {
+		select {
+		case <-time.After(s.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.quote == nil {
+		return nil, types.ErrAssetNotPriced
+	}
+	return s.quote, nil
+}
+
+func ptrFloat(f float64) *float64 { return &f }
+
+func TestPrices_FirstSuccess_UsesPriorityOrder(t *testing.T) {
+	t.Parallel()
+
+	primary := &stubPriceProvider{name: "primary", quote: &types.PriceQuote{Price: 0.16, Change24h: ptrFloat(1.234)}}
+	secondary := &stubPriceProvider{name: "secondary", quote: &types.PriceQuote{Price: 0.2}}
+	svc := NewPricesService(nil, nil, PricesServiceConfig{Providers: []types.PriceProvider{primary, secondary}}, nil, nil)
+
+	got, err := svc.GetPrices(context.Background(), []string{"XLM"}, types.PUBLIC)
+	require.NoError(t, err)
+	assert.Equal(t, &types.PriceEntry{CurrentPrice: "0.16", PercentagePriceChange24h: ptrStr("1.23")}, got["XLM"])
+	assert.Equal(t, int64(0), secondary.calls.Load())
+}
+
+func TestPrices_FirstSuccess_FallsBackPastNotPricedAndFailures(t *testing.T) {
+	t.Parallel()
+
+	for name, primary := range map[string]*stubPriceProvider{
+		"not priced": {name: "primary"},
+		"failing":    {name: "primary", err: &metrics.UpstreamError{Kind: "http_error", Code: 429, Err: errors.New("rate limited")}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			secondary := &stubPriceProvider{name: "secondary", quote: &types.PriceQuote{Price: 0.2}}
+			pm := metrics.NewPrices(prometheus.NewRegistry())
+			svc := NewPricesService(nil, nil, PricesServiceConfig{Providers: []types.PriceProvider{primary, secondary}}, nil, pm)
+
+			got, err := svc.GetPrices(context.Background(), []string{"XLM"}, types.PUBLIC)
+			require.NoError(t, err)
+			require.NotNil(t, got["XLM"])
+			assert.Equal(t, "0.2", got["XLM"].CurrentPrice)
+			assert.Nil(t, got["XLM"].PercentagePriceChange24h)
+			assert.Equal(t, float64(1), testutil.ToFloat64(pm.CacheOutcomes.WithLabelValues(types.PUBLIC, "miss", "secondary")))
+		})
+	}
+}
+
+func TestPrices_FirstSuccess_HungProviderDoesNotStarveFallback(t *testing.T) {
+	t.Parallel()
+
+	primary := &stubPriceProvider{name: "primary", delay: time.Hour}
+	secondary := &stubPriceProvider{name: "secondary", quote: &types.PriceQuote{Price: 0.2}}
+	svc := NewPricesService(nil, nil, PricesServiceConfig{
+		Providers:        []types.PriceProvider{primary, secondary},
+		MissFetchTimeout: 200 * time.Millisecond,
+	}, nil, nil)
+
+	got, err := svc.GetPrices(context.Background(), []string{"XLM"}, types.PUBLIC)
+	require.NoError(t, err)
+	require.NotNil(t, got["XLM"])
+	assert.Equal(t, "0.2", got["XLM"].CurrentPrice)
+}
+
+func TestPrices_NoProviderPrices_ReturnsNullLabeledNone(t *testing.T) {
+	t.Parallel()
+
+	pm := metrics.NewPrices(prometheus.NewRegistry())
+	providers := []types.PriceProvider{&stubPriceProvider{name: "a"}, &stubPriceProvider{name: "b"}}
+	for _, aggregation := range []string{PriceAggregationFirstSuccess, PriceAggregationMedian} {
+		svc := NewPricesService(nil, nil, PricesServiceConfig{Providers: providers, Aggregation: aggregation}, nil, pm)
+
+		got, err := svc.GetPrices(context.Background(), []string{"XLM"}, types.PUBLIC)
+		require.NoError(t, err)
+		require.Contains(t, got, "XLM")
+		assert.Nil(t, got["XLM"], aggregation)
+	}
+	assert.Equal(t, float64(2), testutil.ToFloat64(pm.CacheOutcomes.WithLabelValues(types.PUBLIC, "miss", providerLabelNone)))
+}
+
+func TestPrices_Median_AggregatesAllProviders(t *testing.T) {
+	t.Parallel()
+
+	pm := metrics.NewPrices(prometheus.NewRegistry())
+	svc := NewPricesService(nil, nil, PricesServiceConfig{
+		Providers: []types.PriceProvider{
+			&stubPriceProvider{name: "a", quote: &types.PriceQuote{Price: 1, Change24h: ptrFloat(-1)}},
+			&stubPriceProvider{name: "b", quote: &types.PriceQuote{Price: 10}},
+			&stubPriceProvider{name: "c", quote: &types.PriceQuote{Price: 2, Change24h: ptrFloat(2)}},
+			&stubPriceProvider{name: "d", err: errors.New("down")},
+		},
+		Aggregation: PriceAggregationMedian,
+	}, nil, pm)
+
+	got, err := svc.GetPrices(context.Background(), []string{"XLM"}, types.PUBLIC)
+	require.NoError(t, err)
+	assert.Equal(t, &types.PriceEntry{CurrentPrice: "2", PercentagePriceChange24h: ptrStr("0.5")}, got["XLM"])
+	assert.Equal(t, float64(1), testutil.ToFloat64(pm.CacheOutcomes.WithLabelValues(types.PUBLIC, "miss", providerLabelMedian)))
+}
+
+func TestPrices_Median_SingleQuoteKeepsProviderLabel(t *testing.T) {
+	t.Parallel()
+
+	pm := metrics.NewPrices(prometheus.NewRegistry())
+	svc := NewPricesService(nil, nil, PricesServiceConfig{
+		Providers: []types.PriceProvider{
+			&stubPriceProvider{name: "a"},
+			&stubPriceProvider{name: "b", quote: &types.PriceQuote{Price: 3}},
+		},
+		Aggregation: PriceAggregationMedian,
+	}, nil, pm)
+
+	got, err := svc.GetPrices(context.Background(), []string{"XLM"}, types.PUBLIC)
+	require.NoError(t, err)
+	assert.Equal(t, "3", got["XLM"].CurrentPrice)
+	assert.Equal(t, float64(1), testutil.ToFloat64(pm.CacheOutcomes.WithLabelValues(types.PUBLIC, "miss", "b")))
+}
+
+func TestMedian(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, 2.0, median([]float64{3, 1, 2}))
+	assert.Equal(t, 2.5, median([]float64{4, 1, 3, 2}))
+	assert.Equal(t, 7.0, median([]float64{7}))
+}
+
+// stubContractResolver maps contract ids to classic assets; unknown contracts
+// are not SACs. When err is set, every resolution fails.
+type stubContractResolver struct {
+	classic map[string]string
+	err     error
+	calls   atomic.Int64
+}
+
+func (s *stubContractResolver) Name() string { return "stub-contracts" }
+
+func (s *stubContractResolver) ClassicAsset(ctx context.Context, network, contractID string) (string, error) {
+	s.calls.Add(1)
+	if s.err != nil {
+		return "", s.err
+	}
+	return s.classic[contractID], nil
+}
+
+func (s *stubContractResolver) ClassicAssets(ctx context.Context, network string, contractIDs []string) (map[string]string, error) {
+	result := make(map[string]string, len(contractIDs))
+	for _, contractID := range contractIDs {
+		if classic, err := s.ClassicAsset(ctx, network, contractID); err == nil {
+			result[contractID] = classic
+		}
+	}
+	return result, nil
+}
+
+func TestPrices_SACPricedAsItsClassicAsset(t *testing.T) {
+	t.Parallel()
+
+	usdc := "USDC:" + testIssuer
+	provider := &stubPriceProvider{name: "primary", quote: &types.PriceQuote{Price: 1}}
+	resolver := &stubContractResolver{classic: map[string]string{pubnetUSDCSAC: usdc}}
+	svc := NewPricesService(nil, nil, PricesServiceConfig{Providers: []types.PriceProvider{provider}, Contracts: resolver}, nil, nil)
+
+	got, err := svc.GetPrices(context.Background(), []string{pubnetUSDCSAC, usdc}, types.PUBLIC)
+	require.NoError(t, err)
+	assert.Equal(t, &types.PriceEntry{CurrentPrice: "1"}, got[pubnetUSDCSAC])
+	assert.Equal(t, got[usdc], got[pubnetUSDCSAC])
+	assert.Equal(t, int64(1), provider.calls.Load(), "SAC and classic asset share one lookup")
+	assert.Equal(t, int64(1), resolver.calls.Load())
+}
+
+func TestPrices_SEP41ContractPricedByItsOwnID(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeStellarExpert()
+	fake.Set(pubnetUSDCSAC, &types.StellarExpertAsset{Price: 0.5})
+	svc := NewPricesService(fake, nil, PricesServiceConfig{Contracts: &stubContractResolver{}}, nil, nil)
+
+	got, err := svc.GetPrices(context.Background(), []string{pubnetUSDCSAC}, types.PUBLIC)
+	require.NoError(t, err)
+	require.NotNil(t, got[pubnetUSDCSAC])
+	assert.Equal(t, "0.5", got[pubnetUSDCSAC].CurrentPrice)
+}
+
+func TestPrices_UnresolvedContractIsNotPriced(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeStellarExpert()
+	fake.Set(pubnetUSDCSAC, &types.StellarExpertAsset{Price: 0.5})
+	fake.Set("XLM", &types.StellarExpertAsset{Price: 0.1})
+	resolver := &stubContractResolver{err: errors.New("rpc down")}
+	svc := NewPricesService(fake, nil, PricesServiceConfig{Contracts: resolver}, nil, nil)
+
+	got, err := svc.GetPrices(context.Background(), []string{pubnetUSDCSAC, "XLM"}, types.PUBLIC)
+	require.NoError(t, err)
+	require.Contains(t, got, pubnetUSDCSAC)
+	assert.Nil(t, got[pubnetUSDCSAC], "a failed resolution must not price the contract by its own id")
+	require.NotNil(t, got["XLM"])
+	assert.Equal(t, 0, fake.CallCount(pubnetUSDCSAC))
+}
+
+func TestPrices_ContractResolutionSharesTheMissBudget(t *testing.T) {
+	t.Parallel()
+
+	budget := 200 * time.Millisecond
+	resolver := &slowContractResolver{delay: 150 * time.Millisecond}
+	provider := &stubPriceProvider{name: "primary", delay: 150 * time.Millisecond, quote: &types.PriceQuote{Price: 1}}
+	svc := NewPricesService(nil, nil, PricesServiceConfig{
+		Providers:        []types.PriceProvider{provider},
+		Contracts:        resolver,
+		MissFetchTimeout: budget,
+	}, nil, nil)
+
+	start := time.Now()
+	got, err := svc.GetPrices(context.Background(), []string{pubnetUSDCSAC}, types.PUBLIC)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), budget+100*time.Millisecond)
+	assert.Nil(t, got[pubnetUSDCSAC])
+}
+
+// slowContractResolver reports every contract as not a SAC after delay.
+type slowContractResolver struct {
+	stubContractResolver
+	delay time.Duration
+}
+
+func (s *slowContractResolver) ClassicAssets(ctx context.Context, network string, contractIDs []string) (map[string]string, error) {
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+		return map[string]string{}, nil
+	}
+	return s.stubContractResolver.ClassicAssets(ctx, network, contractIDs)
+}
+
+func TestPriceHistory_SACChartsItsClassicAsset(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeStellarExpert()
+	resolver := &stubContractResolver{classic: map[string]string{pubnetUSDCSAC: "USDC:" + testIssuer}}
+	svc := NewPricesService(fake, nil, PricesServiceConfig{Contracts: resolver}, nil, nil)
+
+	_, err := svc.GetPriceHistory(context.Background(), pubnetUSDCSAC, types.PUBLIC, types.PriceRange1D)
+	require.NoError(t, err)
+	assert.Equal(t, 1, fake.CandleCallCount("USDC-"+testIssuer+"-1"))
+}
+
+func TestPriceHistory_UnresolvedContractFails(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeStellarExpert()
+	resolver := &stubContractResolver{err: errors.New("rpc down")}
+	svc := NewPricesService(fake, nil, PricesServiceConfig{Contracts: resolver}, nil, nil)
+
+	_, err := svc.GetPriceHistory(context.Background(), pubnetUSDCSAC, types.PUBLIC, types.PriceRange1D)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, types.ErrAssetNotPriced)
+	assert.Equal(t, 0, fake.CandleCallCount(pubnetUSDCSAC))
+}
@@ -3,9 +3,12 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/stellar/go-stellar-sdk/clients/rpcclient"
 	rpc "github.com/stellar/go-stellar-sdk/protocols/rpc"
@@ -25,6 +28,17 @@ const (
 	// getLedgerEntries calls with more than 200 keys. Enforced here so a future caller that
 	// bypasses handler-level caps still fails fast locally instead of round-tripping to RPC.
 	MaxLedgerEntryKeys = 200
+
+	// defaultTxPollInterval is how often SubmitTransaction polls getTransaction
+	// while waiting for a submitted transaction to land. Ledgers close about
+	// every 5s, so this sees the result within a second of it being ingested.
+	defaultTxPollInterval = time.Second
+
+	// sendTransaction statuses (stellar-rpc has no exported constants for them).
+	sendStatusPending       = "PENDING"
+	sendStatusDuplicate     = "DUPLICATE"
+	sendStatusTryAgainLater = "TRY_AGAIN_LATER"
+	sendStatusError         = "ERROR"
 )
 
 type rpcService struct {
@@ -33,6 +47,7 @@ type rpcService struct {
 	futurenetClient *rpcclient.Client
 	httpClient      *http.Client
 	svcMetrics      *metrics.Service
+	txPollInterval  time.Duration
 }
 
 func createDefaultClient() *http.Client {
@@ -66,6 +81,7 @@ func NewRPCService(rpcURL string, testnetRPCURL string, futurenetRPCURL string,
 		testnetClient:   rpcclient.NewClient(testnetRPCURL, httpClient),
 		futurenetClient: rpcclient.NewClient(futurenetRPCURL, httpClient),
 		svcMetrics:      m,
+		txPollInterval:  defaultTxPollInterval,
 	}
 }
 
@@ -146,6 +162,54 @@ func (r *rpcService) SimulateTx(
 	return &retval, nil
 }
 
+func (r *rpcService) SimulateTransaction(ctx context.Context, envelopeXDR, network string) (_ *types.SimulationResult, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.Record(r.svcMetrics, serviceName, "SimulateTransaction", network, time.Since(start).Seconds(), err)
+	}()
+
+	networkclient := r.configureNetworkClient(network)
+	resp, err := networkclient.SimulateTransaction(ctx, rpc.SimulateTransactionRequest{
+		Transaction: envelopeXDR,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("simulateTransaction RPC failed: %w", err)
+	}
+
+	result := &types.SimulationResult{
+		Auth:         []string{},
+		Events:       []string{},
+		LatestLedger: resp.LatestLedger,
+	}
+	if resp.Error != "" {
+		result.Error = resp.Error
+		return result, nil
+	}
+
+	result.TransactionData = resp.TransactionDataXDR
+	result.MinResourceFee = resp.MinResourceFee
+	if resp.EventsXDR != nil {
+		result.Events = resp.EventsXDR
+	}
+	// Only InvokeHostFunction transactions produce a result; footprint
+	// operations (ExtendFootprintTTL, RestoreFootprint) simulate with none.
+	if len(resp.Results) > 0 {
+		if resp.Results[0].AuthXDR != nil {
+			result.Auth = *resp.Results[0].AuthXDR
+		}
+		if resp.Results[0].ReturnValueXDR != nil {
+			result.ReturnValue = *resp.Results[0].ReturnValueXDR
+		}
+	}
+	if resp.RestorePreamble != nil {
+		result.RestorePreamble = &types.RestorePreamble{
+			TransactionData: resp.RestorePreamble.TransactionDataXDR,
+			MinResourceFee:  resp.RestorePreamble.MinResourceFee,
+		}
+	}
+	return result, nil
+}
+
 func (r *rpcService) SimulateInvocation(
 	ctx context.Context,
 	contractId xdr.ScAddress,
@@ -226,3 +290,134 @@ func (r *rpcService) GetLedgerEntries(ctx context.Context, keys []string, networ
 
 	return entries, nil
 }
+
+// SubmitTransaction forwards a signed envelope to sendTransaction. A PENDING or
+// DUPLICATE submission is then polled via getTransaction until it reaches a
+// terminal status; if ctx's deadline passes first the result is
+// TxStatusPending with the hash, so the caller can keep polling through
+// GetTransactionStatus. Rejections (ERROR, TRY_AGAIN_LATER) are outcomes, not
+// errors: only a failed RPC call or a cancelled ctx returns err.
+func (r *rpcService) SubmitTransaction(ctx context.Context, envelopeXDR, network string) (_ *types.TxStatus, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.Record(r.svcMetrics, serviceName, "SubmitTransaction", network, time.Since(start).Seconds(), err)
+	}()
+
+	networkClient := r.configureNetworkClient(network)
+	resp, err := networkClient.SendTransaction(ctx, rpc.SendTransactionRequest{Transaction: envelopeXDR})
+	if err != nil {
+		return nil, fmt.Errorf("sendTransaction RPC failed: %w", err)
+	}
+
+	switch resp.Status {
+	case sendStatusError:
+		return &types.TxStatus{
+			Hash:       resp.Hash,
+			Status:     types.TxStatusError,
+			ResultCode: decodeResultCode(resp.ErrorResultXDR),
+		}, nil
+	case sendStatusTryAgainLater:
+		return &types.TxStatus{Hash: resp.Hash, Status: types.TxStatusTryAgainLater}, nil
+	case sendStatusPending, sendStatusDuplicate:
+		return r.awaitTransaction(ctx, networkClient, resp.Hash)
+	default:
+		return nil, &metrics.UpstreamError{
+			Kind: "rpc_error",
+			Err:  fmt.Errorf("sendTransaction returned unknown status %q", resp.Status),
+		}
+	}
+}
+
+// awaitTransaction polls getTransaction until the hash is found or ctx ends.
+func (r *rpcService) awaitTransaction(ctx context.Context, networkClient *rpcclient.Client, hash string) (*types.TxStatus, error) {
+	ticker := time.NewTicker(r.txPollInterval)
+	defer ticker.Stop()
+
+	for {
+		status, err := getTransactionStatus(ctx, networkClient, hash)
+		switch {
+		case err == nil && status.Status != types.TxStatusNotFound:
+			return status, nil
+		case err != nil && !errors.Is(err, context.DeadlineExceeded):
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return &types.TxStatus{Hash: hash, Status: types.TxStatusPending}, nil
+			}
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetTransactionStatus reports the current status of a transaction by hash.
+// A hash stellar-rpc does not know is TxStatusNotFound, not an error.
+func (r *rpcService) GetTransactionStatus(ctx context.Context, hash, network string) (_ *types.TxStatus, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.Record(r.svcMetrics, serviceName, "GetTransactionStatus", network, time.Since(start).Seconds(), err)
+	}()
+
+	return getTransactionStatus(ctx, r.configureNetworkClient(network), hash)
+}
+
+func getTransactionStatus(ctx context.Context, networkClient *rpcclient.Client, hash string) (*types.TxStatus, error) {
+	resp, err := networkClient.GetTransaction(ctx, rpc.GetTransactionRequest{Hash: hash})
+	if err != nil {
+		return nil, fmt.Errorf("getTransaction RPC failed: %w", err)
+	}
+
+	status := &types.TxStatus{Hash: hash, Status: resp.Status}
+	switch resp.Status {
+	case rpc.TransactionStatusSuccess, rpc.TransactionStatusFailed:
+		status.ResultCode = decodeResultCode(resp.ResultXDR)
+		status.Ledger = resp.Ledger
+		status.CreatedAt = resp.LedgerCloseTime
+	case rpc.TransactionStatusNotFound:
+	default:
+		return nil, &metrics.UpstreamError{
+			Kind: "rpc_error",
+			Err:  fmt.Errorf("getTransaction returned unknown status %q", resp.Status),
+		}
+	}
+	return status, nil
+}
+
+// decodeResultCode renders a base64 TransactionResult as the Horizon-style
+// result code clients already know ("tx_bad_seq"). A fee bump reports the
+// inner transaction's code, which is the one that explains the outcome. An
+// empty or undecodable result yields "".
+func decodeResultCode(resultXDR string) string {
+	if resultXDR == "" {
+		return ""
+	}
+	var result xdr.TransactionResult
+	if err := xdr.SafeUnmarshalBase64(resultXDR, &result); err != nil {
+		return ""
+	}
+	code := result.Result.Code
+	if inner, ok := result.Result.GetInnerResultPair(); ok {
+		code = inner.Result.Result.Code
+	}
+	return resultCodeString(code)
+}
+
+// resultCodeString converts an XDR enum name such as
+// TransactionResultCodeTxBadSeq to tx_bad_seq.
+func resultCodeString(code xdr.TransactionResultCode) string {
+	name := strings.TrimPrefix(code.String(), "TransactionResultCode")
+	var b strings.Builder
+	for i, c := range name {
+		if unicode.IsUpper(c) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			c = unicode.ToLower(c)
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
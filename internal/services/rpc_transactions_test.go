@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+const testTxHash = "b9d0b2292c4e09e8eb22d036171491e87b8d2086bf8b265874c8d182cb9c9020"
+
+// newJSONRPCServer answers each JSON-RPC call with results[method](n), where n
+// counts prior calls to that method, echoing the request id as jrpc2 requires.
+func newJSONRPCServer(t *testing.T, results map[string]func(n int) any) *httptest.Server {
+	t.Helper()
+	counts := map[string]*atomic.Int32{}
+	for method := range results {
+		counts[method] = &atomic.Int32{}
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		result, ok := results[req.Method]
+		require.True(t, ok, "unexpected method %s", req.Method)
+		n := int(counts[req.Method].Add(1)) - 1
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result(n)})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestTxRPCService(url string) *rpcService {
+	svc := NewRPCService(url, url, url, nil).(*rpcService)
+	svc.txPollInterval = time.Millisecond
+	return svc
+}
+
+func txResultXDR(t *testing.T, code xdr.TransactionResultCode) string {
+	t.Helper()
+	result := xdr.TransactionResult{FeeCharged: 100, Result: xdr.TransactionResultResult{Code: code}}
+	if code == xdr.TransactionResultCodeTxSuccess || code == xdr.TransactionResultCodeTxFailed {
+		result.Result.Results = &[]xdr.OperationResult{}
+	}
+	encoded, err := xdr.MarshalBase64(result)
+	require.NoError(t, err)
+	return encoded
+}
+
+func TestRPCService_SubmitTransaction_PollsUntilTerminal(t *testing.T) {
+	t.Parallel()
+
+	successXDR := txResultXDR(t, xdr.TransactionResultCodeTxSuccess)
+	server := newJSONRPCServer(t, map[string]func(int) any{
+		"sendTransaction": func(int) any {
+			return map[string]any{"status": "PENDING", "hash": testTxHash, "latestLedger": 10, "latestLedgerCloseTime": "1700000000"}
+		},
+		"getTransaction": func(n int) any {
+			if n < 2 {
+				return map[string]any{"status": "NOT_FOUND", "latestLedger": 10, "latestLedgerCloseTime": "1700000000"}
+			}
+			return map[string]any{"status": "SUCCESS", "txHash": testTxHash, "ledger": 11, "createdAt": "1700000005", "resultXdr": successXDR}
+		},
+	})
+
+	status, err := newTestTxRPCService(server.URL).SubmitTransaction(context.Background(), "AAAA", types.PUBLIC)
+	require.NoError(t, err)
+	assert.Equal(t, &types.TxStatus{Hash: testTxHash, Status: types.TxStatusSuccess, ResultCode: "tx_success", Ledger: 11, CreatedAt: 1700000005}, status)
+}
+
+func TestRPCService_SubmitTransaction_RejectedIsOutcome(t *testing.T) {
+	t.Parallel()
+
+	badSeqXDR := txResultXDR(t, xdr.TransactionResultCodeTxBadSeq)
+	server := newJSONRPCServer(t, map[string]func(int) any{
+		"sendTransaction": func(int) any {
+			return map[string]any{"status": "ERROR", "hash": testTxHash, "errorResultXdr": badSeqXDR, "latestLedger": 10, "latestLedgerCloseTime": "1700000000"}
+		},
+	})
+
+	status, err := newTestTxRPCService(server.URL).SubmitTransaction(context.Background(), "AAAA", types.PUBLIC)
+	require.NoError(t, err)
+	assert.Equal(t, &types.TxStatus{Hash: testTxHash, Status: types.TxStatusError, ResultCode: "tx_bad_seq"}, status)
+}
+
+func TestRPCService_SubmitTransaction_DeadlineYieldsPending(t *testing.T) {
+	t.Parallel()
+
+	server := newJSONRPCServer(t, map[string]func(int) any{
+		"sendTransaction": func(int) any {
+			return map[string]any{"status": "PENDING", "hash": testTxHash, "latestLedger": 10, "latestLedgerCloseTime": "1700000000"}
+		},
+		"getTransaction": func(int) any {
+			return map[string]any{"status": "NOT_FOUND", "latestLedger": 10, "latestLedgerCloseTime": "1700000000"}
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	status, err := newTestTxRPCService(server.URL).SubmitTransaction(ctx, "AAAA", types.PUBLIC)
+	require.NoError(t, err)
+	assert.Equal(t, &types.TxStatus{Hash: testTxHash, Status: types.TxStatusPending}, status)
+}
+
+func TestRPCService_SubmitTransaction_CancelIsError(t *testing.T) {
+	t.Parallel()
+
+	server := newJSONRPCServer(t, map[string]func(int) any{
+		"sendTransaction": func(int) any {
+			return map[string]any{"status": "PENDING", "hash": testTxHash, "latestLedger": 10, "latestLedgerCloseTime": "1700000000"}
+		},
+		"getTransaction": func(int) any {
+			return map[string]any{"status": "NOT_FOUND", "latestLedger": 10, "latestLedgerCloseTime": "1700000000"}
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	_, err := newTestTxRPCService(server.URL).SubmitTransaction(ctx, "AAAA", types.PUBLIC)
+	assert.True(t, errors.Is(err, context.Canceled), "got %v", err)
+}
+
+func TestRPCService_SubmitTransaction_RPCError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "error": map[string]any{"code": -32602, "message": "invalid transaction"}})
+	}))
+	defer server.Close()
+
+	_, err := newTestTxRPCService(server.URL).SubmitTransaction(context.Background(), "AAAA", types.PUBLIC)
+	var rpcErr *jrpc2.Error
+	require.True(t, errors.As(err, &rpcErr), "got %v", err)
+	assert.EqualValues(t, -32602, rpcErr.Code)
+}
+
+func TestRPCService_GetTransactionStatus(t *testing.T) {
+	t.Parallel()
+
+	failedXDR := txResultXDR(t, xdr.TransactionResultCodeTxFailed)
+	server := newJSONRPCServer(t, map[string]func(int) any{
+		"getTransaction": func(n int) any {
+			if n == 0 {
+				return map[string]any{"status": "NOT_FOUND", "latestLedger": 10, "latestLedgerCloseTime": "1700000000"}
+			}
+			return map[string]any{"status": "FAILED", "txHash": testTxHash, "ledger": 12, "createdAt": "1700000010", "resultXdr": failedXDR}
+		},
+	})
+	svc := newTestTxRPCService(server.URL)
+
+	status, err := svc.GetTransactionStatus(context.Background(), testTxHash, types.TESTNET)
+	require.NoError(t, err)
+	assert.Equal(t, &types.TxStatus{Hash: testTxHash, Status: types.TxStatusNotFound}, status)
+
+	status, err = svc.GetTransactionStatus(context.Background(), testTxHash, types.TESTNET)
+	require.NoError(t, err)
+	assert.Equal(t, &types.TxStatus{Hash: testTxHash, Status: types.TxStatusFailed, ResultCode: "tx_failed", Ledger: 12, CreatedAt: 1700000010}, status)
+}
+
+func TestResultCodeString(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "tx_bad_auth_extra", resultCodeString(xdr.TransactionResultCodeTxBadAuthExtra))
+	assert.Equal(t, "tx_fee_bump_inner_success", resultCodeString(xdr.TransactionResultCodeTxFeeBumpInnerSuccess))
+	assert.Equal(t, "", decodeResultCode("not base64"))
+}
+
+func TestRPCService_SimulateTransaction(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns the full result", func(t *testing.T) {
+		t.Parallel()
+		server := newJSONRPCServer(t, map[string]func(int) any{
+			"simulateTransaction": func(int) any {
+				return map[string]any{
+					"transactionData": "DATA",
+					"minResourceFee":  "12345",
+					"events":          []string{"EVENT"},
+					"results":         []map[string]any{{"auth": []string{"AUTH"}, "xdr": "RETVAL"}},
+					"restorePreamble": map[string]any{"transactionData": "RESTORE", "minResourceFee": "99"},
+					"latestLedger":    42,
+				}
+			},
+		})
+
+		result, err := newTestTxRPCService(server.URL).SimulateTransaction(context.Background(), "AAAA", types.TESTNET)
+		require.NoError(t, err)
+		assert.Equal(t, &types.SimulationResult{
+			TransactionData: "DATA",
+			MinResourceFee:  12345,
+			Auth:            []string{"AUTH"},
+			Events:          []string{"EVENT"},
+			ReturnValue:     "RETVAL",
+			RestorePreamble: &types.RestorePreamble{TransactionData: "RESTORE", MinResourceFee: 99},
+			LatestLedger:    42,
+		}, result)
+	})
+
+	t.Run("host failure is an outcome", func(t *testing.T) {
+		t.Parallel()
+		server := newJSONRPCServer(t, map[string]func(int) any{
+			"simulateTransaction": func(int) any {
+				return map[string]any{"error": "HostError: Error(Contract, #10)", "latestLedger": 42}
+			},
+		})
+
+		result, err := newTestTxRPCService(server.URL).SimulateTransaction(context.Background(), "AAAA", types.TESTNET)
+		require.NoError(t, err)
+		assert.Equal(t, &types.SimulationResult{Error: "HostError: Error(Contract, #10)", Auth: []string{}, Events: []string{}, LatestLedger: 42}, result)
+	})
+}
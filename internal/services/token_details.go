@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/stellar/go-stellar-sdk/txnbuild"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/stellar/freighter-backend-v2/internal/logger"
+	"github.com/stellar/freighter-backend-v2/internal/metrics"
+	"github.com/stellar/freighter-backend-v2/internal/store"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils"
+	"github.com/stellar/freighter-backend-v2/internal/utils/scval"
+)
+
+const (
+	tokenDetailsServiceName = "token-details"
+
+	tokenDetailsCacheKeyPrefix = "tokens:v1"
+)
+
+type tokenDetailsService struct {
+	rpc        types.RPCService
+	redis      *store.RedisStore
+	balanceTTL time.Duration
+	svcMetrics *metrics.Service
+}
+
+// NewTokenDetailsService reads SEP-41 tokens through rpc's contract
+// simulation. Decimals, name and symbol cannot change after deployment, so
+// they are cached in Redis without expiry; balances are cached for
+// balanceTTL. redis may be nil, and a zero balanceTTL disables balance
+// caching.
+func NewTokenDetailsService(rpc types.RPCService, redis *store.RedisStore, balanceTTL time.Duration, m *metrics.Service) types.TokenDetailsService {
+	return &tokenDetailsService{
+		rpc:        rpc,
+		redis:      redis,
+		balanceTTL: balanceTTL,
+		svcMetrics: m,
+	}
+}
+
+func (t *tokenDetailsService) Name() string {
+	return tokenDetailsServiceName
+}
+
+// GetTokenDetails serves metadata and balance from Redis where it can and
+// simulates only what is missing, the reads running concurrently. A Redis
+// failure degrades to simulating everything.
+func (t *tokenDetailsService) GetTokenDetails(ctx context.Context, network, contractID, owner string) (_ *types.TokenDetails, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.Record(t.svcMetrics, tokenDetailsServiceName, "GetTokenDetails", network, time.Since(start).Seconds(), err)
+	}()
+
+	contract, err := utils.ScAddressFromContractString(contractID)
+	if err != nil {
+		return nil, err
+	}
+	var ownerVal *xdr.ScVal
+	source := &txnbuild.SimpleAccount{AccountID: utils.SimulationSourceAccount}
+	if owner != "" {
+		ownerAddress, addrErr := utils.ScAddressFromString(owner)
+		if addrErr != nil {
+			return nil, fmt.Errorf("invalid owner: %w", addrErr)
+		}
+		ownerVal = &xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: ownerAddress}
+		if strings.HasPrefix(owner, "G") {
+			source.AccountID = owner
+		}
+	}
+
+	metaKey := tokenDetailsCacheKey(network, "meta", contractID)
+	balanceKey := tokenDetailsCacheKey(network, "balance", contractID, owner)
+	keys := []string{metaKey}
+	if ownerVal != nil {
+		keys = append(keys, balanceKey)
+	}
+	cached := t.loadCached(ctx, keys)
+
+	// Both entries are TokenDetails: the metadata entry without a balance,
+	// the balance entry with only one.
+	var meta types.TokenDetails
+	metaHit, haveMeta := cached[metaKey].(*types.TokenDetails)
+	if haveMeta {
+		meta = *metaHit
+	}
+	var balance string
+	balanceHit, haveBalance := cached[balanceKey].(*types.TokenDetails)
+	if haveBalance {
+		balance = balanceHit.Balance
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	if !haveMeta {
+		g.Go(func() error {
+			v, simErr := t.simulate(gctx, *contract, source, "decimals", network)
+			if simErr != nil {
+				return simErr
+			}
+			decimals, ok := v.GetU32()
+			if !ok {
+				return fmt.Errorf("decimals returned %s, want u32", v.Type)
+			}
+			meta.Decimals = uint32(decimals)
+			return nil
+		})
+		g.Go(func() error {
+			v, simErr := t.simulate(gctx, *contract, source, "name", network)
+			if simErr != nil {
+				return simErr
+			}
+			meta.Name, simErr = scval.Text(*v)
+			return simErr
+		})
+		g.Go(func() error {
+			v, simErr := t.simulate(gctx, *contract, source, "symbol", network)
+			if simErr != nil {
+				return simErr
+			}
+			meta.Symbol, simErr = scval.Text(*v)
+			return simErr
+		})
+	}
+	if ownerVal != nil && !haveBalance {
+		g.Go(func() error {
+			v, simErr := t.simulate(gctx, *contract, source, "balance", network, *ownerVal)
+			if simErr != nil {
+				return simErr
+			}
+			if v.Type != xdr.ScValTypeScvI128 {
+				return fmt.Errorf("balance returned %s, want i128", v.Type)
+			}
+			native, simErr := scval.ToNative(*v)
+			if simErr != nil {
+				return simErr
+			}
+			balance = native.(string)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	if !haveMeta {
+		t.storeCached(ctx, metaKey, meta, 0)
+	}
+	if ownerVal != nil && !haveBalance && t.balanceTTL > 0 {
+		t.storeCached(ctx, balanceKey, types.TokenDetails{Balance: balance}, t.balanceTTL)
+	}
+
+	meta.Balance = balance
+	return &meta, nil
+}
+
+func (t *tokenDetailsService) simulate(ctx context.Context, contract xdr.ScAddress, source *txnbuild.SimpleAccount, fn xdr.ScSymbol, network string, args ...xdr.ScVal) (*xdr.ScVal, error) {
+	if args == nil {
+		args = []xdr.ScVal{}
+	}
+	v, err := t.rpc.SimulateInvocation(ctx, contract, source, fn, args, txnbuild.NewTimeout(300), network)
+	if err != nil {
+		return nil, fmt.Errorf("simulating %s: %w", fn, err)
+	}
+	if v == nil {
+		return nil, fmt.Errorf("simulating %s: no return value", fn)
+	}
+	return v, nil
+}
+
+// loadCached returns the Redis hits among keys. A Redis failure is logged and
+// treated as all-miss so a cache outage degrades to direct simulation.
+func (t *tokenDetailsService) loadCached(ctx context.Context, keys []string) map[string]any {
+	if t.redis == nil {
+		return nil
+	}
+	hits, err := t.redis.MGetJSON(ctx, keys, func() any { return new(types.TokenDetails) })
+	if err != nil {
+		logger.Warn("token-details: redis MGet failed; bypassing cache", "error", err)
+		return nil
+	}
+	return hits
+}
+
+func (t *tokenDetailsService) storeCached(ctx context.Context, key string, value any, ttl time.Duration) {
+	if t.redis == nil {
+		return
+	}
+	if err := t.redis.SetJSON(ctx, key, value, ttl); err != nil {
+		logger.Warn("token-details: redis SET failed", "key", key, "error", err)
+	}
+}
+
+func tokenDetailsCacheKey(network, kind string, ids ...string) string {
+	return tokenDetailsCacheKeyPrefix + ":" + strings.ToLower(network) + ":" + kind + ":" + strings.Join(ids, ":")
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stellar/go-stellar-sdk/txnbuild"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/store"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils"
+)
+
+const (
+	testTokenContract = "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC"
+	testTokenOwner    = "GBTYAFHGNZSTE4VBWZYAGB3SRGJEPTI5I4Y22KZ4JTVAN56LESB6JZOF"
+)
+
+// sep41RPC answers SEP-41 reads with fixed values, recording each call.
+type sep41RPC struct {
+	utils.MockRPCService
+	mu      sync.Mutex
+	calls   []string
+	sources []string
+	fail    string
+}
+
+func (s *sep41RPC) SimulateInvocation(ctx context.Context, contractId xdr.ScAddress, sourceAccount *txnbuild.SimpleAccount, functionName xdr.ScSymbol, params []xdr.ScVal, timeout txnbuild.TimeBounds, network string) (types.SimulateTransactionResponse, error) {
+	s.mu.Lock()
+	s.calls = append(s.calls, string(functionName))
+	s.sources = append(s.sources, sourceAccount.AccountID)
+	s.mu.Unlock()
+
+	if string(functionName) == s.fail {
+		return nil, errors.New("simulation failed")
+	}
+	switch functionName {
+	case "decimals":
+		v := xdr.Uint32(7)
+		return &xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &v}, nil
+	case "name":
+		v := xdr.ScString("USD Coin")
+		return &xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &v}, nil
+	case "symbol":
+		v := xdr.ScSymbol("USDC")
+		return &xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &v}, nil
+	case "balance":
+		if len(params) != 1 || params[0].Type != xdr.ScValTypeScvAddress {
+			return nil, errors.New("balance takes one address")
+		}
+		v := xdr.Int128Parts{Hi: 1, Lo: 5}
+		return &xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &v}, nil
+	}
+	return nil, errors.New("unexpected function")
+}
+
+func TestTokenDetailsService_GetTokenDetails(t *testing.T) {
+	t.Parallel()
+
+	t.Run("metadata only without owner", func(t *testing.T) {
+		t.Parallel()
+		rpc := &sep41RPC{}
+		details, err := NewTokenDetailsService(rpc, nil, 0, nil).GetTokenDetails(context.Background(), types.PUBLIC, testTokenContract, "")
+		require.NoError(t, err)
+		assert.Equal(t, &types.TokenDetails{Name: "USD Coin", Symbol: "USDC", Decimals: 7}, details)
+		assert.ElementsMatch(t, []string{"decimals", "name", "symbol"}, rpc.calls)
+		assert.Equal(t, []string{utils.SimulationSourceAccount, utils.SimulationSourceAccount, utils.SimulationSourceAccount}, rpc.sources)
+	})
+
+	t.Run("includes owner balance", func(t *testing.T) {
+		t.Parallel()
+		rpc := &sep41RPC{}
+		details, err := NewTokenDetailsService(rpc, nil, 0, nil).GetTokenDetails(context.Background(), types.TESTNET, testTokenContract, testTokenOwner)
+		require.NoError(t, err)
+		// Hi=1, Lo=5 is 2^64 + 5.
+		assert.Equal(t, "18446744073709551621", details.Balance)
+		assert.ElementsMatch(t, []string{"decimals", "name", "symbol", "balance"}, rpc.calls)
+		for _, source := range rpc.sources {
+			assert.Equal(t, testTokenOwner, source, "a G-address owner is the simulation source")
+		}
+	})
+
+	t.Run("contract owner keeps the placeholder source", func(t *testing.T) {
+		t.Parallel()
+		rpc := &sep41RPC{}
+		_, err := NewTokenDetailsService(rpc, nil, 0, nil).GetTokenDetails(context.Background(), types.TESTNET, testTokenContract, testTokenContract)
+		require.NoError(t, err)
+		for _, source := range rpc.sources {
+			assert.Equal(t, utils.SimulationSourceAccount, source)
+		}
+	})
+
+	t.Run("any failed read fails the token", func(t *testing.T) {
+		t.Parallel()
+		_, err := NewTokenDetailsService(&sep41RPC{fail: "symbol"}, nil, 0, nil).GetTokenDetails(context.Background(), types.PUBLIC, testTokenContract, "")
+		assert.ErrorContains(t, err, "simulating symbol")
+	})
+
+	t.Run("invalid contract", func(t *testing.T) {
+		t.Parallel()
+		_, err := NewTokenDetailsService(&sep41RPC{}, nil, 0, nil).GetTokenDetails(context.Background(), types.PUBLIC, testTokenOwner, "")
+		assert.Error(t, err)
+	})
+
+	t.Run("redis outage degrades to simulation", func(t *testing.T) {
+		t.Parallel()
+		redisStore := store.NewRedisStore("localhost", 1, "") // port 1 = no listener
+		details, err := NewTokenDetailsService(&sep41RPC{}, redisStore, time.Minute, nil).GetTokenDetails(context.Background(), types.PUBLIC, testTokenContract, testTokenOwner)
+		require.NoError(t, err)
+		assert.Equal(t, "USDC", details.Symbol)
+	})
+}
+
+func TestTokenDetailsCacheKey(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "tokens:v1:public:meta:"+testTokenContract, tokenDetailsCacheKey(types.PUBLIC, "meta", testTokenContract))
+	assert.Equal(t, "tokens:v1:testnet:balance:"+testTokenContract+":"+testTokenOwner, tokenDetailsCacheKey(types.TESTNET, "balance", testTokenContract, testTokenOwner))
+}
@@ -0,0 +1,100 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+// AuditLogStore reads the audit_log table. Entries are written by the stores
+// whose changes they record, inside the same transaction.
+type AuditLogStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewAuditLogStore(pool *pgxpool.Pool) *AuditLogStore {
+	return &AuditLogStore{pool: pool}
+}
+
+// ListAuditLog returns up to limit entries, newest first. entityType and
+// entityKey narrow the result when not empty.
+func (s *AuditLogStore) ListAuditLog(ctx context.Context, entityType, entityKey string, limit int) ([]types.AuditEntry, error) {
+	const query = `
+		SELECT id, actor, action, entity_type, entity_key, before, after, created_at
+		FROM audit_log
+		WHERE ($1 = '' OR entity_type = $1) AND ($2 = '' OR entity_key = $2)
+		ORDER BY id DESC
+		LIMIT $3`
+	rows, err := s.pool.Query(ctx, query, entityType, entityKey, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing audit log: %w", err)
+	}
+	entries, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (types.AuditEntry, error) {
+		var e types.AuditEntry
+		var before, after []byte
+		err := row.Scan(&e.ID, &e.Actor, &e.Action, &e.EntityType, &e.EntityKey, &before, &after, &e.CreatedAt)
+		e.Before, e.After = before, after
+		return e, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// writeAuditEntry records a change within tx. A nil before or after, typed
+// nil pointers included, is stored as SQL NULL.
+func writeAuditEntry(ctx context.Context, tx pgx.Tx, actor, action, entityType, entityKey string, before, after any) error {
+	beforeJSON, err := auditJSON(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := auditJSON(after)
+	if err != nil {
+		return err
+	}
+	const insert = `
+		INSERT INTO audit_log (actor, action, entity_type, entity_key, before, after)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+	if _, err := tx.Exec(ctx, insert, actor, action, entityType, entityKey, beforeJSON, afterJSON); err != nil {
+		return fmt.Errorf("writing audit entry: %w", err)
+	}
+	return nil
+}
+
+func auditJSON(v any) ([]byte, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encoding audit entry: %w", err)
+	}
+	if string(data) == "null" {
+		return nil, nil
+	}
+	return data, nil
+}
+
+// AdminStore is the types.AdminStore over the feature_flags, protocols and
+// audit_log tables.
+type AdminStore struct {
+	*FeatureFlagsStore
+	*ProtocolsStore
+	*AuditLogStore
+}
+
+var _ types.AdminStore = (*AdminStore)(nil)
+
+func NewAdminStore(pool *pgxpool.Pool) *AdminStore {
+	return &AdminStore{
+		FeatureFlagsStore: NewFeatureFlagsStore(pool),
+		ProtocolsStore:    NewProtocolsStore(pool),
+		AuditLogStore:     NewAuditLogStore(pool),
+	}
+}
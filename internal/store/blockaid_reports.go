@@ -0,0 +1,50 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+// BlockaidReportsStore writes the audit copy of Blockaid warning reports to
+// the blockaid_reports table.
+type BlockaidReportsStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewBlockaidReportsStore(pool *pgxpool.Pool) *BlockaidReportsStore {
+	return &BlockaidReportsStore{pool: pool}
+}
+
+// InsertBlockaidReport stores one report. Empty optional fields are written
+// as NULL so "anonymous" and "forwarded successfully" are queryable with IS
+// NULL rather than by comparing against the empty string.
+func (s *BlockaidReportsStore) InsertBlockaidReport(ctx context.Context, report types.BlockaidReport) error {
+	const query = `
+		INSERT INTO blockaid_reports (kind, event, network, subject, origin_url, details, user_id, forward_error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err := s.pool.Exec(ctx, query,
+		report.Kind,
+		report.Event,
+		report.Network,
+		report.Subject,
+		nullIfEmpty(report.OriginURL),
+		report.Details,
+		nullIfEmpty(report.UserID),
+		nullIfEmpty(report.ForwardError),
+	)
+	if err != nil {
+		return fmt.Errorf("inserting blockaid report: %w", err)
+	}
+	return nil
+}
+
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,82 @@
+package store
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	migrate "github.com/rubenv/sql-migrate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+
+	"github.com/stellar/freighter-backend-v2/internal/db"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+// startMigratedPostgres spins up a throwaway PostgreSQL container, applies the
+// embedded migrations, and returns a pool on it. Gated behind
+// ENABLE_INTEGRATION_TESTS like the db package's integration tests.
+func startMigratedPostgres(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	if os.Getenv("ENABLE_INTEGRATION_TESTS") != "true" {
+		t.Skip("set ENABLE_INTEGRATION_TESTS=true to run DB integration tests (requires Docker)")
+	}
+
+	ctx := context.Background()
+	container, err := postgres.Run(ctx,
+		"postgres:16-alpine",
+		postgres.WithDatabase("freighter"),
+		postgres.WithUsername("freighter"),
+		postgres.WithPassword("freighter"),
+		postgres.BasicWaitStrategies(),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = container.Terminate(ctx) })
+
+	dsn, err := container.ConnectionString(ctx, "sslmode=disable")
+	require.NoError(t, err)
+	_, err = db.Migrate(ctx, dsn, migrate.Up, 0)
+	require.NoError(t, err)
+
+	pool, err := db.OpenDBConnectionPool(ctx, dsn)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func TestBlockaidReportsStore_InsertBlockaidReport(t *testing.T) {
+	pool := startMigratedPostgres(t)
+	ctx := context.Background()
+	s := NewBlockaidReportsStore(pool)
+
+	require.NoError(t, s.InsertBlockaidReport(ctx, types.BlockaidReport{
+		Kind:    types.ReportKindAsset,
+		Event:   types.ReportFalsePositive,
+		Network: types.PUBLIC,
+		Subject: "USDC:GA5ZSEJYB37JRC5AVCIA5MOP4RHTM335X2KGX3IHOJAPP5RE34K4KZVN",
+		UserID:  "user-1",
+	}))
+
+	var userID, originURL, forwardError *string
+	err := pool.QueryRow(ctx, `SELECT user_id, origin_url, forward_error FROM blockaid_reports`).Scan(&userID, &originURL, &forwardError)
+	require.NoError(t, err)
+	require.NotNil(t, userID)
+	assert.Equal(t, "user-1", *userID)
+	assert.Nil(t, originURL, "empty optional fields are stored as NULL")
+	assert.Nil(t, forwardError)
+}
+
+func TestBlockaidReportsStore_RejectsUnknownEvent(t *testing.T) {
+	pool := startMigratedPostgres(t)
+	s := NewBlockaidReportsStore(pool)
+
+	err := s.InsertBlockaidReport(context.Background(), types.BlockaidReport{
+		Kind:    types.ReportKindTransaction,
+		Event:   "MAYBE",
+		Network: types.PUBLIC,
+		Subject: "AAAA",
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,170 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils/refresh"
+)
+
+// CollectionsStore reads and edits the collections table.
+type CollectionsStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewCollectionsStore(pool *pgxpool.Pool) *CollectionsStore {
+	return &CollectionsStore{pool: pool}
+}
+
+// ListCollections returns the registered collections on network, or on every
+// network when network is empty, ordered by network then registration time.
+func (s *CollectionsStore) ListCollections(ctx context.Context, network string) ([]types.RegisteredCollection, error) {
+	const query = `
+		SELECT network, contract_id, enumeration, label
+		FROM collections
+		WHERE $1 = '' OR network = $1
+		ORDER BY network, created_at, contract_id`
+	rows, err := s.pool.Query(ctx, query, network)
+	if err != nil {
+		return nil, fmt.Errorf("listing collections: %w", err)
+	}
+	collections, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (types.RegisteredCollection, error) {
+		var c types.RegisteredCollection
+		err := row.Scan(&c.Network, &c.ContractID, &c.Enumeration, &c.Label)
+		return c, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing collections: %w", err)
+	}
+	return collections, nil
+}
+
+// UpsertCollection registers c, or updates the enumeration and label of an
+// existing entry for the same network and contract, and records the change
+// against actor. It reports whether c was newly registered.
+func (s *CollectionsStore) UpsertCollection(ctx context.Context, actor string, c types.RegisteredCollection) (bool, error) {
+	created := false
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		before, err := getCollection(ctx, tx, c.Network, c.ContractID)
+		if err != nil {
+			return err
+		}
+		const upsert = `
+			INSERT INTO collections (network, contract_id, enumeration, label)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (network, contract_id)
+			DO UPDATE SET enumeration = EXCLUDED.enumeration, label = EXCLUDED.label, updated_at = now()`
+		if _, err := tx.Exec(ctx, upsert, c.Network, c.ContractID, c.Enumeration, c.Label); err != nil {
+			return err
+		}
+		created = before == nil
+		action := types.AuditActionUpdate
+		if created {
+			action = types.AuditActionCreate
+		}
+		return writeAuditEntry(ctx, tx, actor, action, types.AuditEntityCollection, collectionAuditKey(c.Network, c.ContractID), before, c)
+	})
+	if err != nil {
+		return false, fmt.Errorf("upserting collection: %w", err)
+	}
+	return created, nil
+}
+
+// DeleteCollection unregisters a contract and records the change against
+// actor, reporting whether it was registered.
+func (s *CollectionsStore) DeleteCollection(ctx context.Context, actor, network, contractID string) (bool, error) {
+	deleted := false
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		before, err := getCollection(ctx, tx, network, contractID)
+		if err != nil || before == nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM collections WHERE network = $1 AND contract_id = $2`, network, contractID); err != nil {
+			return err
+		}
+		deleted = true
+		return writeAuditEntry(ctx, tx, actor, types.AuditActionDelete, types.AuditEntityCollection, collectionAuditKey(network, contractID), before, nil)
+	})
+	if err != nil {
+		return false, fmt.Errorf("deleting collection: %w", err)
+	}
+	return deleted, nil
+}
+
+// getCollection locks and returns the entry for contractID on network, or nil
+// when there is none.
+func getCollection(ctx context.Context, tx pgx.Tx, network, contractID string) (*types.RegisteredCollection, error) {
+	const query = `
+		SELECT network, contract_id, enumeration, label
+		FROM collections
+		WHERE network = $1 AND contract_id = $2
+		FOR UPDATE`
+	var c types.RegisteredCollection
+	err := tx.QueryRow(ctx, query, network, contractID).Scan(&c.Network, &c.ContractID, &c.Enumeration, &c.Label)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func collectionAuditKey(network, contractID string) string {
+	return network + "/" + contractID
+}
+
+// collectionLister is the read side of CollectionsStore.
+type collectionLister interface {
+	ListCollections(ctx context.Context, network string) ([]types.RegisteredCollection, error)
+}
+
+// DBCollectionRegistry serves the collection registry from an in-memory
+// snapshot of the collections table, reloaded by Run. Requests never touch the
+// database, and a failed reload keeps serving the previous snapshot.
+type DBCollectionRegistry struct {
+	store    collectionLister
+	interval time.Duration
+	snapshot atomic.Pointer[map[string][]types.RegisteredCollection]
+}
+
+func NewDBCollectionRegistry(store collectionLister, interval time.Duration) *DBCollectionRegistry {
+	r := &DBCollectionRegistry{store: store, interval: interval}
+	r.snapshot.Store(&map[string][]types.RegisteredCollection{})
+	return r
+}
+
+// Collections returns network's entries from the latest snapshot.
+func (r *DBCollectionRegistry) Collections(_ context.Context, network string) ([]types.RegisteredCollection, error) {
+	return (*r.snapshot.Load())[network], nil
+}
+
+// Refresh replaces the snapshot with the current table contents.
+func (r *DBCollectionRegistry) Refresh(ctx context.Context) error {
+	collections, err := r.store.ListCollections(ctx, "")
+	if err != nil {
+		return err
+	}
+	byNetwork := make(map[string][]types.RegisteredCollection)
+	for _, c := range collections {
+		byNetwork[c.Network] = append(byNetwork[c.Network], c)
+	}
+	r.snapshot.Store(&byNetwork)
+	return nil
+}
+
+// Run refreshes the snapshot every interval until ctx is done.
+func (r *DBCollectionRegistry) Run(ctx context.Context) {
+	refresh.Loop{
+		Interval:       r.interval,
+		Refresh:        r.Refresh,
+		FailureMessage: "collections: registry refresh failed; keeping previous snapshot",
+	}.Run(ctx)
+}
@@ -0,0 +1,59 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+// FileCollectionRegistry serves the collection registry from a JSON file
+// holding an array of types.RegisteredCollection. The file is read once, at
+// construction; editing it takes a restart.
+type FileCollectionRegistry struct {
+	byNetwork map[string][]types.RegisteredCollection
+}
+
+// NewFileCollectionRegistry reads and validates the registry at path. Any
+// invalid or duplicated entry fails the whole file, so a typo surfaces at boot
+// instead of as a collection that silently never shows up.
+func NewFileCollectionRegistry(path string) (*FileCollectionRegistry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading collections registry %s: %w", path, err)
+	}
+	var entries []types.RegisteredCollection
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parsing collections registry %s: %w", path, err)
+	}
+	return newFileCollectionRegistry(entries)
+}
+
+// NewStaticCollectionRegistry serves entries, validated like a registry file.
+func NewStaticCollectionRegistry(entries []types.RegisteredCollection) (*FileCollectionRegistry, error) {
+	return newFileCollectionRegistry(entries)
+}
+
+func newFileCollectionRegistry(entries []types.RegisteredCollection) (*FileCollectionRegistry, error) {
+	byNetwork := make(map[string][]types.RegisteredCollection)
+	seen := make(map[string]bool, len(entries))
+	for i, entry := range entries {
+		if err := entry.Validate(); err != nil {
+			return nil, fmt.Errorf("collections registry entry %d: %w", i, err)
+		}
+		key := entry.Network + ":" + entry.ContractID
+		if seen[key] {
+			return nil, fmt.Errorf("collections registry entry %d: %s is registered twice on %s", i, entry.ContractID, entry.Network)
+		}
+		seen[key] = true
+		byNetwork[entry.Network] = append(byNetwork[entry.Network], entry)
+	}
+	return &FileCollectionRegistry{byNetwork: byNetwork}, nil
+}
+
+// Collections returns network's entries in file order.
+func (r *FileCollectionRegistry) Collections(_ context.Context, network string) ([]types.RegisteredCollection, error) {
+	return r.byNetwork[network], nil
+}
@@ -0,0 +1,74 @@
+package store
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+const (
+	testCollectionA = "CBIELTK6YBZJU5UP2WWQEUCYKLPU6AUNZ2BQ4WWFEIE3USCIHMXQDAMA"
+	testCollectionB = "CDSN4MICK7U5XOP4DE6OIZQCRMYO3UTQ5VYZV7ZA7H63OICZPBLXYRGJ"
+)
+
+func writeRegistryFile(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "collections.json")
+	require.NoError(t, os.WriteFile(path, []byte(body), 0o600))
+	return path
+}
+
+func TestFileCollectionRegistry_GroupsByNetwork(t *testing.T) {
+	t.Parallel()
+
+	path := writeRegistryFile(t, `[
+		{"network": "PUBLIC", "contract_id": "`+testCollectionA+`", "enumeration": "owner_tokens", "label": "treasure hunt"},
+		{"network": "TESTNET", "contract_id": "`+testCollectionA+`", "enumeration": "owner_index"},
+		{"network": "PUBLIC", "contract_id": "`+testCollectionB+`", "enumeration": "owner_index"}
+	]`)
+
+	registry, err := NewFileCollectionRegistry(path)
+	require.NoError(t, err)
+
+	pubnet, err := registry.Collections(context.Background(), types.PUBLIC)
+	require.NoError(t, err)
+	require.Len(t, pubnet, 2)
+	assert.Equal(t, types.RegisteredCollection{Network: types.PUBLIC, ContractID: testCollectionA, Enumeration: types.CollectionEnumerationOwnerTokens, Label: "treasure hunt"}, pubnet[0])
+	assert.Equal(t, testCollectionB, pubnet[1].ContractID)
+
+	testnet, err := registry.Collections(context.Background(), types.TESTNET)
+	require.NoError(t, err)
+	require.Len(t, testnet, 1)
+	assert.Equal(t, types.CollectionEnumerationOwnerIndex, testnet[0].Enumeration)
+
+	futurenet, err := registry.Collections(context.Background(), types.FUTURENET)
+	require.NoError(t, err)
+	assert.Empty(t, futurenet)
+}
+
+func TestFileCollectionRegistry_RejectsInvalidFiles(t *testing.T) {
+	t.Parallel()
+
+	for name, body := range map[string]string{
+		"not json":             `{`,
+		"unknown network":      `[{"network": "MAINNET", "contract_id": "` + testCollectionA + `", "enumeration": "owner_tokens"}]`,
+		"account not contract": `[{"network": "PUBLIC", "contract_id": "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF", "enumeration": "owner_tokens"}]`,
+		"unknown enumeration":  `[{"network": "PUBLIC", "contract_id": "` + testCollectionA + `", "enumeration": "get_tokens"}]`,
+		"duplicate":            `[{"network": "PUBLIC", "contract_id": "` + testCollectionA + `", "enumeration": "owner_tokens"}, {"network": "PUBLIC", "contract_id": "` + testCollectionA + `", "enumeration": "owner_index"}]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := NewFileCollectionRegistry(writeRegistryFile(t, body))
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := NewFileCollectionRegistry(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
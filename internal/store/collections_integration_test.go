@@ -0,0 +1,62 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+func TestCollectionsStore_UpsertListDelete(t *testing.T) {
+	pool := startMigratedPostgres(t)
+	ctx := context.Background()
+	s := NewCollectionsStore(pool)
+	mustUpsert := func(c types.RegisteredCollection) bool {
+		created, err := s.UpsertCollection(ctx, "alice", c)
+		require.NoError(t, err)
+		return created
+	}
+
+	assert.True(t, mustUpsert(types.RegisteredCollection{
+		Network: types.PUBLIC, ContractID: testCollectionA, Enumeration: types.CollectionEnumerationOwnerTokens, Label: "treasure hunt",
+	}))
+	assert.True(t, mustUpsert(types.RegisteredCollection{
+		Network: types.TESTNET, ContractID: testCollectionB, Enumeration: types.CollectionEnumerationOwnerIndex,
+	}))
+	// Re-adding the same contract updates it in place.
+	assert.False(t, mustUpsert(types.RegisteredCollection{
+		Network: types.PUBLIC, ContractID: testCollectionA, Enumeration: types.CollectionEnumerationOwnerIndex, Label: "hunt",
+	}))
+
+	all, err := s.ListCollections(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+
+	pubnet, err := s.ListCollections(ctx, types.PUBLIC)
+	require.NoError(t, err)
+	require.Len(t, pubnet, 1)
+	assert.Equal(t, types.RegisteredCollection{Network: types.PUBLIC, ContractID: testCollectionA, Enumeration: types.CollectionEnumerationOwnerIndex, Label: "hunt"}, pubnet[0])
+
+	removed, err := s.DeleteCollection(ctx, "bob", types.PUBLIC, testCollectionA)
+	require.NoError(t, err)
+	assert.True(t, removed)
+	removed, err = s.DeleteCollection(ctx, "bob", types.PUBLIC, testCollectionA)
+	require.NoError(t, err)
+	assert.False(t, removed)
+
+	entries, err := NewAuditLogStore(pool).ListAuditLog(ctx, types.AuditEntityCollection, types.PUBLIC+"/"+testCollectionA, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 3, "the no-op delete is not audited")
+	assert.Equal(t, []string{types.AuditActionDelete, types.AuditActionUpdate, types.AuditActionCreate},
+		[]string{entries[0].Action, entries[1].Action, entries[2].Action})
+	assert.Equal(t, "bob", entries[0].Actor)
+	assert.Nil(t, entries[0].After)
+	assert.JSONEq(t, string(entries[1].Before), string(entries[2].After), "an update's before is the previous after")
+
+	// The table's CHECK constraints back up RegisteredCollection.Validate.
+	_, err = pool.Exec(ctx, `INSERT INTO collections (network, contract_id, enumeration) VALUES ('PUBLIC', $1, 'get_tokens')`, testCollectionB)
+	assert.Error(t, err)
+}
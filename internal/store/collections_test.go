@@ -0,0 +1,81 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+type fakeCollectionLister struct {
+	collections []types.RegisteredCollection
+	err         error
+}
+
+func (f *fakeCollectionLister) ListCollections(context.Context, string) ([]types.RegisteredCollection, error) {
+	return f.collections, f.err
+}
+
+func TestDBCollectionRegistry_RefreshSwapsSnapshot(t *testing.T) {
+	t.Parallel()
+
+	lister := &fakeCollectionLister{}
+	registry := NewDBCollectionRegistry(lister, time.Minute)
+	ctx := context.Background()
+
+	got, err := registry.Collections(ctx, types.PUBLIC)
+	require.NoError(t, err)
+	assert.Empty(t, got, "empty until the first refresh")
+
+	lister.collections = []types.RegisteredCollection{
+		{Network: types.PUBLIC, ContractID: testCollectionA, Enumeration: types.CollectionEnumerationOwnerTokens},
+		{Network: types.TESTNET, ContractID: testCollectionB, Enumeration: types.CollectionEnumerationOwnerIndex},
+	}
+	require.NoError(t, registry.Refresh(ctx))
+
+	got, err = registry.Collections(ctx, types.PUBLIC)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, testCollectionA, got[0].ContractID)
+
+	// A failed reload keeps the last good snapshot.
+	lister.err = errors.New("connection refused")
+	require.Error(t, registry.Refresh(ctx))
+	got, err = registry.Collections(ctx, types.TESTNET)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, testCollectionB, got[0].ContractID)
+}
+
+func TestDBCollectionRegistry_RunRefreshesUntilCancelled(t *testing.T) {
+	t.Parallel()
+
+	lister := &fakeCollectionLister{collections: []types.RegisteredCollection{
+		{Network: types.PUBLIC, ContractID: testCollectionA, Enumeration: types.CollectionEnumerationOwnerTokens},
+	}}
+	registry := NewDBCollectionRegistry(lister, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		registry.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		got, _ := registry.Collections(ctx, types.PUBLIC)
+		return len(got) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
@@ -0,0 +1,171 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/stellar/freighter-backend-v2/internal/logger"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils/refresh"
+)
+
+// FeatureFlagsStore reads and writes the feature_flags table.
+type FeatureFlagsStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewFeatureFlagsStore(pool *pgxpool.Pool) *FeatureFlagsStore {
+	return &FeatureFlagsStore{pool: pool}
+}
+
+// ListFeatureFlags returns every flag, ordered by key.
+func (s *FeatureFlagsStore) ListFeatureFlags(ctx context.Context) ([]types.FeatureFlag, error) {
+	const query = `SELECT key, description, enabled, rules FROM feature_flags ORDER BY key`
+	rows, err := s.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("listing feature flags: %w", err)
+	}
+	flags, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (types.FeatureFlag, error) {
+		var f types.FeatureFlag
+		err := row.Scan(&f.Key, &f.Description, &f.Enabled, &f.Rules)
+		return f, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing feature flags: %w", err)
+	}
+	return flags, nil
+}
+
+// UpsertFeatureFlag creates f, or replaces the flag with the same key, and
+// records the change against actor in the audit log. It reports whether the
+// flag was created.
+func (s *FeatureFlagsStore) UpsertFeatureFlag(ctx context.Context, actor string, f types.FeatureFlag) (bool, error) {
+	if f.Rules == nil {
+		f.Rules = []types.FlagRule{}
+	}
+	created := false
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		before, err := getFeatureFlag(ctx, tx, f.Key)
+		if err != nil {
+			return err
+		}
+		const upsert = `
+			INSERT INTO feature_flags (key, description, enabled, rules)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (key)
+			DO UPDATE SET description = EXCLUDED.description, enabled = EXCLUDED.enabled,
+			              rules = EXCLUDED.rules, updated_at = now()`
+		if _, err := tx.Exec(ctx, upsert, f.Key, f.Description, f.Enabled, f.Rules); err != nil {
+			return err
+		}
+		created = before == nil
+		action := types.AuditActionUpdate
+		if created {
+			action = types.AuditActionCreate
+		}
+		return writeAuditEntry(ctx, tx, actor, action, types.AuditEntityFeatureFlag, f.Key, before, f)
+	})
+	if err != nil {
+		return false, fmt.Errorf("upserting feature flag: %w", err)
+	}
+	return created, nil
+}
+
+// DeleteFeatureFlag removes a flag and records the change against actor,
+// reporting whether the flag existed.
+func (s *FeatureFlagsStore) DeleteFeatureFlag(ctx context.Context, actor, key string) (bool, error) {
+	deleted := false
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		before, err := getFeatureFlag(ctx, tx, key)
+		if err != nil || before == nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM feature_flags WHERE key = $1`, key); err != nil {
+			return err
+		}
+		deleted = true
+		return writeAuditEntry(ctx, tx, actor, types.AuditActionDelete, types.AuditEntityFeatureFlag, key, before, nil)
+	})
+	if err != nil {
+		return false, fmt.Errorf("deleting feature flag: %w", err)
+	}
+	return deleted, nil
+}
+
+// getFeatureFlag locks and returns the flag with key, or nil when
+// there is none.
+func getFeatureFlag(ctx context.Context, tx pgx.Tx, key string) (*types.FeatureFlag, error) {
+	const query = `SELECT key, description, enabled, rules FROM feature_flags WHERE key = $1 FOR UPDATE`
+	var f types.FeatureFlag
+	err := tx.QueryRow(ctx, query, key).Scan(&f.Key, &f.Description, &f.Enabled, &f.Rules)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// featureFlagLister is the read side of FeatureFlagsStore.
+type featureFlagLister interface {
+	ListFeatureFlags(ctx context.Context) ([]types.FeatureFlag, error)
+}
+
+// DBFeatureFlagSource serves feature flags from an in-memory snapshot of the
+// feature_flags table, reloaded by Run, so requests do no I/O. A failed reload
+// keeps serving the previous snapshot.
+type DBFeatureFlagSource struct {
+	store    featureFlagLister
+	interval time.Duration
+	snapshot atomic.Pointer[[]types.FeatureFlag]
+}
+
+func NewDBFeatureFlagSource(store featureFlagLister, interval time.Duration) *DBFeatureFlagSource {
+	return &DBFeatureFlagSource{store: store, interval: interval}
+}
+
+// FeatureFlags returns the latest snapshot, or types.ErrFeatureFlagsNotLoaded
+// until the first successful Refresh.
+func (s *DBFeatureFlagSource) FeatureFlags(_ context.Context) ([]types.FeatureFlag, error) {
+	flags := s.snapshot.Load()
+	if flags == nil {
+		return nil, types.ErrFeatureFlagsNotLoaded
+	}
+	return *flags, nil
+}
+
+// Refresh replaces the snapshot with the current table contents. Rows that
+// fail validation (rules edited by hand) are skipped and logged rather than
+// failing the whole reload.
+func (s *DBFeatureFlagSource) Refresh(ctx context.Context) error {
+	flags, err := s.store.ListFeatureFlags(ctx)
+	if err != nil {
+		return err
+	}
+	valid := flags[:0]
+	for _, f := range flags {
+		if err := f.Validate(); err != nil {
+			logger.Warn("feature flags: skipping invalid flag", "key", f.Key, "error", err)
+			continue
+		}
+		valid = append(valid, f)
+	}
+	s.snapshot.Store(&valid)
+	return nil
+}
+
+// Run refreshes the snapshot every interval until ctx is done.
+func (s *DBFeatureFlagSource) Run(ctx context.Context) {
+	refresh.Loop{
+		Interval:       s.interval,
+		Refresh:        s.Refresh,
+		FailureMessage: "feature flags: refresh failed; keeping previous snapshot",
+	}.Run(ctx)
+}
@@ -0,0 +1,80 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/stellar/freighter-backend-v2/internal/logger"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils/refresh"
+)
+
+// FileFeatureFlagSource serves feature flags from a JSON file holding an array
+// of types.FeatureFlag, which Run polls for changes. A new version is swapped
+// in only when every flag in it validates; otherwise the last good version
+// keeps being served.
+type FileFeatureFlagSource struct {
+	path     string
+	interval time.Duration
+
+	snapshot atomic.Pointer[[]types.FeatureFlag]
+	// mu serializes Refresh, which owns last.
+	mu   sync.Mutex
+	last []byte
+}
+
+func NewFileFeatureFlagSource(path string, interval time.Duration) *FileFeatureFlagSource {
+	return &FileFeatureFlagSource{path: path, interval: interval}
+}
+
+// FeatureFlags returns the last good version, or
+// types.ErrFeatureFlagsNotLoaded until a valid file has been read.
+func (s *FileFeatureFlagSource) FeatureFlags(_ context.Context) ([]types.FeatureFlag, error) {
+	flags := s.snapshot.Load()
+	if flags == nil {
+		return nil, types.ErrFeatureFlagsNotLoaded
+	}
+	return *flags, nil
+}
+
+// Refresh re-reads the file and swaps it in when its content changed and
+// validates. A rejected version is not retried until the file changes again.
+func (s *FileFeatureFlagSource) Refresh(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("reading feature flags file %s: %w", s.path, err)
+	}
+	if s.last != nil && bytes.Equal(data, s.last) {
+		return nil
+	}
+	s.last = data
+
+	var flags []types.FeatureFlag
+	if err := json.Unmarshal(data, &flags); err != nil {
+		return fmt.Errorf("parsing feature flags file %s: %w", s.path, err)
+	}
+	if err := types.ValidateFeatureFlags(flags); err != nil {
+		return fmt.Errorf("validating feature flags file %s: %w", s.path, err)
+	}
+	s.snapshot.Store(&flags)
+	logger.Info("feature flags: loaded flags", "path", s.path, "count", len(flags))
+	return nil
+}
+
+// Run refreshes the snapshot every interval until ctx is done.
+func (s *FileFeatureFlagSource) Run(ctx context.Context) {
+	refresh.Loop{
+		Interval:       s.interval,
+		Refresh:        s.Refresh,
+		FailureMessage: "feature flags: reload failed; keeping last good version",
+	}.Run(ctx)
+}
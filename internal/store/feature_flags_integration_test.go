@@ -0,0 +1,56 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+func TestFeatureFlagsStore_UpsertListDelete(t *testing.T) {
+	pool := startMigratedPostgres(t)
+	ctx := context.Background()
+	s := NewFeatureFlagsStore(pool)
+	half := 50
+
+	created, err := s.UpsertFeatureFlag(ctx, "alice", types.FeatureFlag{Key: "swap", Enabled: true})
+	require.NoError(t, err)
+	assert.True(t, created)
+	_, err = s.UpsertFeatureFlag(ctx, "alice", types.FeatureFlag{
+		Key: "new_home", Description: "Redesigned home screen",
+		Rules: []types.FlagRule{{Platforms: []string{"ios"}, Versions: ">=1.8.0", Networks: []string{types.PUBLIC}, Percentage: &half, Enabled: true}},
+	})
+	require.NoError(t, err)
+	// Upserting an existing key replaces it.
+	created, err = s.UpsertFeatureFlag(ctx, "bob", types.FeatureFlag{Key: "swap", Description: "Swaps"})
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	got, err := s.ListFeatureFlags(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "new_home", got[0].Key)
+	require.Len(t, got[0].Rules, 1)
+	require.NotNil(t, got[0].Rules[0].Percentage)
+	assert.Equal(t, 50, *got[0].Rules[0].Percentage)
+	assert.Equal(t, types.FeatureFlag{Key: "swap", Description: "Swaps", Rules: []types.FlagRule{}}, got[1])
+
+	removed, err := s.DeleteFeatureFlag(ctx, "alice", "swap")
+	require.NoError(t, err)
+	assert.True(t, removed)
+	removed, err = s.DeleteFeatureFlag(ctx, "alice", "swap")
+	require.NoError(t, err)
+	assert.False(t, removed)
+
+	entries, err := NewAuditLogStore(pool).ListAuditLog(ctx, types.AuditEntityFeatureFlag, "swap", 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, types.AuditActionDelete, entries[0].Action)
+	assert.Equal(t, "bob", entries[1].Actor)
+	assert.JSONEq(t, `{"key": "swap", "enabled": true}`, string(entries[1].Before))
+	assert.JSONEq(t, `{"key": "swap", "description": "Swaps", "enabled": false}`, string(entries[1].After))
+	assert.Nil(t, entries[2].Before)
+}
@@ -0,0 +1,83 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+type fakeFeatureFlagLister struct {
+	flags []types.FeatureFlag
+	err   error
+}
+
+func (f *fakeFeatureFlagLister) ListFeatureFlags(context.Context) ([]types.FeatureFlag, error) {
+	return f.flags, f.err
+}
+
+func TestDBFeatureFlagSource_RefreshSwapsSnapshot(t *testing.T) {
+	t.Parallel()
+
+	lister := &fakeFeatureFlagLister{}
+	source := NewDBFeatureFlagSource(lister, time.Minute)
+	ctx := context.Background()
+
+	_, err := source.FeatureFlags(ctx)
+	assert.ErrorIs(t, err, types.ErrFeatureFlagsNotLoaded)
+
+	lister.flags = []types.FeatureFlag{
+		{Key: "swap", Enabled: true},
+		{Key: "broken", Rules: []types.FlagRule{{Versions: "~1"}}},
+	}
+	require.NoError(t, source.Refresh(ctx))
+	got, err := source.FeatureFlags(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []types.FeatureFlag{{Key: "swap", Enabled: true}}, got, "an invalid row is skipped, not fatal")
+
+	// A failed reload keeps the last good snapshot.
+	lister.err = errors.New("connection refused")
+	require.Error(t, source.Refresh(ctx))
+	got, err = source.FeatureFlags(ctx)
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+}
+
+func TestFileFeatureFlagSource_KeepsLastGoodVersion(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "feature-flags.json")
+	source := NewFileFeatureFlagSource(path, time.Minute)
+	ctx := context.Background()
+
+	require.ErrorIs(t, source.Refresh(ctx), os.ErrNotExist)
+	_, err := source.FeatureFlags(ctx)
+	require.ErrorIs(t, err, types.ErrFeatureFlagsNotLoaded)
+
+	require.NoError(t, os.WriteFile(path, []byte(`[
+		{"key": "swap", "enabled": true, "rules": [{"platforms": ["ios"], "versions": "1.6.23", "enabled": false}]},
+		{"key": "new_home", "rules": [{"percentage": 10, "enabled": true}]}
+	]`), 0o600))
+	require.NoError(t, source.Refresh(ctx))
+	got, err := source.FeatureFlags(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "1.6.23", got[0].Rules[0].Versions)
+	assert.False(t, got[0].Evaluate(types.FlagContext{Platform: "ios", Version: "1.6.23"}), "loading parses the version range")
+
+	for _, body := range []string{`[{"key": "swap"`, `[{"key": "swap", "rules": [{"percentage": 150}]}]`} {
+		require.NoError(t, os.WriteFile(path, []byte(body), 0o600))
+		require.Error(t, source.Refresh(ctx))
+		require.NoError(t, source.Refresh(ctx), "a rejected version is not retried until the file changes")
+		got, err = source.FeatureFlags(ctx)
+		require.NoError(t, err)
+		assert.Len(t, got, 2)
+	}
+}
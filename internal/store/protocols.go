@@ -0,0 +1,232 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils/refresh"
+)
+
+// ProtocolsStore reads and replaces the protocols table.
+type ProtocolsStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewProtocolsStore(pool *pgxpool.Pool) *ProtocolsStore {
+	return &ProtocolsStore{pool: pool}
+}
+
+// ListProtocols returns the whole catalog in display order.
+func (s *ProtocolsStore) ListProtocols(ctx context.Context) ([]types.Protocol, error) {
+	protocols, err := listProtocols(ctx, s.pool, false)
+	if err != nil {
+		return nil, fmt.Errorf("listing protocols: %w", err)
+	}
+	return protocols, nil
+}
+
+// protocolColumns are read into a types.Protocol by scanProtocol.
+const protocolColumns = `name, tags, website_url, icon_url, background_url, description,
+	is_blacklisted, is_trending, is_wc_not_supported, networks, translations`
+
+func scanProtocol(row pgx.Row) (types.Protocol, error) {
+	var p types.Protocol
+	err := row.Scan(&p.Name, &p.Tags, &p.URL, &p.IconURL, &p.BackgroundURL, &p.Description,
+		&p.IsBlacklisted, &p.IsTrending, &p.IsWalletConnectNotSupported, &p.Networks, &p.Translations)
+	return p, err
+}
+
+// listProtocols reads the catalog through q, a pool or a transaction, locking
+// the rows when forUpdate is set.
+func listProtocols(ctx context.Context, q interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}, forUpdate bool) ([]types.Protocol, error) {
+	query := `SELECT ` + protocolColumns + ` FROM protocols ORDER BY position, name`
+	if forUpdate {
+		query += ` FOR UPDATE`
+	}
+	rows, err := q.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (types.Protocol, error) {
+		return scanProtocol(row)
+	})
+}
+
+// ReplaceProtocols swaps the whole catalog for protocols in one transaction,
+// so readers see either the old catalog or the new one, and records the
+// change against actor in the audit log. Slice order becomes display order.
+// Every entry must carry its networks.
+func (s *ProtocolsStore) ReplaceProtocols(ctx context.Context, actor string, protocols []types.Protocol) error {
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		before, err := listProtocols(ctx, tx, true)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM protocols`); err != nil {
+			return err
+		}
+		batch := &pgx.Batch{}
+		for i, p := range protocols {
+			queueProtocolUpsert(batch, p, i)
+		}
+		if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+			return err
+		}
+		return writeAuditEntry(ctx, tx, actor, types.AuditActionReplace, types.AuditEntityProtocols, "", before, protocols)
+	})
+	if err != nil {
+		return fmt.Errorf("replacing protocols: %w", err)
+	}
+	return nil
+}
+
+// UpsertProtocol creates p at the end of the catalog, or replaces the protocol
+// with the same name in place, and records the change against actor. It
+// reports whether the protocol was created.
+func (s *ProtocolsStore) UpsertProtocol(ctx context.Context, actor string, p types.Protocol) (bool, error) {
+	created := false
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		// Lock the table so concurrent creates don't take the same position.
+		if _, err := tx.Exec(ctx, `LOCK TABLE protocols IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+			return err
+		}
+		before, err := getProtocol(ctx, tx, p.Name)
+		if err != nil {
+			return err
+		}
+		var position int
+		if err := tx.QueryRow(ctx, `SELECT COALESCE((SELECT position FROM protocols WHERE name = $1), (SELECT MAX(position) + 1 FROM protocols), 0)`, p.Name).Scan(&position); err != nil {
+			return err
+		}
+		batch := &pgx.Batch{}
+		queueProtocolUpsert(batch, p, position)
+		if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+			return err
+		}
+		created = before == nil
+		action := types.AuditActionUpdate
+		if created {
+			action = types.AuditActionCreate
+		}
+		return writeAuditEntry(ctx, tx, actor, action, types.AuditEntityProtocol, p.Name, before, p)
+	})
+	if err != nil {
+		return false, fmt.Errorf("upserting protocol: %w", err)
+	}
+	return created, nil
+}
+
+// DeleteProtocol removes a protocol and records the change against actor,
+// reporting whether the protocol existed.
+func (s *ProtocolsStore) DeleteProtocol(ctx context.Context, actor, name string) (bool, error) {
+	deleted := false
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		before, err := getProtocol(ctx, tx, name)
+		if err != nil || before == nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM protocols WHERE name = $1`, name); err != nil {
+			return err
+		}
+		deleted = true
+		return writeAuditEntry(ctx, tx, actor, types.AuditActionDelete, types.AuditEntityProtocol, name, before, nil)
+	})
+	if err != nil {
+		return false, fmt.Errorf("deleting protocol: %w", err)
+	}
+	return deleted, nil
+}
+
+// getProtocol locks and returns the protocol named name, or nil when there is
+// none.
+func getProtocol(ctx context.Context, tx pgx.Tx, name string) (*types.Protocol, error) {
+	p, err := scanProtocol(tx.QueryRow(ctx, `SELECT `+protocolColumns+` FROM protocols WHERE name = $1 FOR UPDATE`, name))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// queueProtocolUpsert queues an insert of p at position, which overwrites an
+// existing protocol of the same name.
+func queueProtocolUpsert(batch *pgx.Batch, p types.Protocol, position int) {
+	const upsert = `
+		INSERT INTO protocols (name, position, tags, website_url, icon_url, background_url,
+		                       description, is_blacklisted, is_trending, is_wc_not_supported, networks, translations)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (name) DO UPDATE SET
+			position = EXCLUDED.position, tags = EXCLUDED.tags, website_url = EXCLUDED.website_url,
+			icon_url = EXCLUDED.icon_url, background_url = EXCLUDED.background_url,
+			description = EXCLUDED.description, is_blacklisted = EXCLUDED.is_blacklisted,
+			is_trending = EXCLUDED.is_trending, is_wc_not_supported = EXCLUDED.is_wc_not_supported,
+			networks = EXCLUDED.networks, translations = EXCLUDED.translations, updated_at = now()`
+	tags := p.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	translations := p.Translations
+	if translations == nil {
+		translations = map[string]types.ProtocolTranslation{}
+	}
+	batch.Queue(upsert, p.Name, position, tags, p.URL, p.IconURL, p.BackgroundURL,
+		p.Description, p.IsBlacklisted, p.IsTrending, p.IsWalletConnectNotSupported, p.Networks, translations)
+}
+
+// protocolLister is the read side of ProtocolsStore.
+type protocolLister interface {
+	ListProtocols(ctx context.Context) ([]types.Protocol, error)
+}
+
+// DBProtocolCatalog serves the protocols catalog from an in-memory snapshot of
+// the protocols table, reloaded by Run, so requests do no I/O. A failed reload
+// keeps serving the previous snapshot.
+type DBProtocolCatalog struct {
+	store    protocolLister
+	interval time.Duration
+	snapshot atomic.Pointer[[]types.Protocol]
+}
+
+func NewDBProtocolCatalog(store protocolLister, interval time.Duration) *DBProtocolCatalog {
+	return &DBProtocolCatalog{store: store, interval: interval}
+}
+
+// Protocols returns the latest snapshot, or types.ErrProtocolsNotLoaded until
+// the first successful Refresh.
+func (c *DBProtocolCatalog) Protocols(_ context.Context) ([]types.Protocol, error) {
+	protocols := c.snapshot.Load()
+	if protocols == nil {
+		return nil, types.ErrProtocolsNotLoaded
+	}
+	return *protocols, nil
+}
+
+// Refresh replaces the snapshot with the current table contents.
+func (c *DBProtocolCatalog) Refresh(ctx context.Context) error {
+	protocols, err := c.store.ListProtocols(ctx)
+	if err != nil {
+		return err
+	}
+	c.snapshot.Store(&protocols)
+	return nil
+}
+
+// Run refreshes the snapshot every interval until ctx is done.
+func (c *DBProtocolCatalog) Run(ctx context.Context) {
+	refresh.Loop{
+		Interval:       c.interval,
+		Refresh:        c.Refresh,
+		FailureMessage: "protocols: catalog refresh failed; keeping previous snapshot",
+	}.Run(ctx)
+}
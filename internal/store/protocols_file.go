@@ -0,0 +1,95 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/stellar/freighter-backend-v2/internal/logger"
+	"github.com/stellar/freighter-backend-v2/internal/metrics"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+	"github.com/stellar/freighter-backend-v2/internal/utils/refresh"
+)
+
+// FileProtocolCatalog serves the protocols catalog from a JSON file that Run
+// polls for changes. Each new version is validated and swapped in only when
+// valid; otherwise the last good version keeps being served. Polling, rather
+// than watching, also catches the symlink swap Kubernetes uses to update a
+// mounted ConfigMap.
+type FileProtocolCatalog struct {
+	path     string
+	interval time.Duration
+	rules    types.ProtocolRules
+	metrics  *metrics.Protocols
+
+	snapshot atomic.Pointer[[]types.Protocol]
+	// mu serializes Refresh, which owns version.
+	mu      sync.Mutex
+	version string
+}
+
+func NewFileProtocolCatalog(path string, interval time.Duration, rules types.ProtocolRules, m *metrics.Protocols) *FileProtocolCatalog {
+	return &FileProtocolCatalog{path: path, interval: interval, rules: rules, metrics: m}
+}
+
+// Protocols returns the last good version, or types.ErrProtocolsNotLoaded
+// until a valid file has been read.
+func (c *FileProtocolCatalog) Protocols(_ context.Context) ([]types.Protocol, error) {
+	protocols := c.snapshot.Load()
+	if protocols == nil {
+		return nil, types.ErrProtocolsNotLoaded
+	}
+	return *protocols, nil
+}
+
+// Refresh re-reads the file and swaps it in when its content changed and
+// validates. A rejected version is counted and returned as an error, but is
+// not retried until the file changes again.
+func (c *FileProtocolCatalog) Refresh(_ context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		metrics.RecordProtocolsReloadFailure(c.metrics, "read")
+		return fmt.Errorf("reading protocols file %s: %w", c.path, err)
+	}
+	sum := sha256.Sum256(data)
+	version := hex.EncodeToString(sum[:])
+	if version == c.version {
+		return nil
+	}
+
+	var protocols []types.Protocol
+	if err := json.Unmarshal(data, &protocols); err != nil {
+		c.version = version
+		metrics.RecordProtocolsReloadFailure(c.metrics, "parse")
+		return fmt.Errorf("parsing protocols file %s (sha256 %s): %w", c.path, version, err)
+	}
+	if err := types.ValidateProtocols(protocols, c.rules); err != nil {
+		c.version = version
+		metrics.RecordProtocolsReloadFailure(c.metrics, "invalid")
+		return fmt.Errorf("validating protocols file %s (sha256 %s): %w", c.path, version, err)
+	}
+
+	c.version = version
+	c.snapshot.Store(&protocols)
+	metrics.RecordProtocolsCatalogLoaded(c.metrics, version, time.Now())
+	logger.Info("protocols: loaded catalog", "path", c.path, "sha256", version, "count", len(protocols))
+	return nil
+}
+
+// Run refreshes the snapshot every interval until ctx is done.
+func (c *FileProtocolCatalog) Run(ctx context.Context) {
+	refresh.Loop{
+		Interval:       c.interval,
+		Refresh:        c.Refresh,
+		FailureMessage: "protocols: catalog reload failed; keeping last good version",
+	}.Run(ctx)
+}
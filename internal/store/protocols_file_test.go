@@ -0,0 +1,102 @@
+package store
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/metrics"
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+const (
+	validProtocolsV1 = `[{"name": "Blend", "tags": ["Lending"], "description": "Lending markets.", "website_url": "https://mainnet.blend.capital/", "icon_url": "https://icons.stellar.org/blend.svg"}]`
+	validProtocolsV2 = `[
+		{"name": "Blend", "tags": ["Lending"], "description": "Lending markets.", "website_url": "https://mainnet.blend.capital/", "icon_url": "https://icons.stellar.org/blend.svg"},
+		{"name": "Phoenix", "tags": ["DEX"], "description": "An AMM.", "website_url": "https://app.phoenix-hub.io/", "icon_url": "https://icons.stellar.org/phoenix.png"}
+	]`
+)
+
+func TestFileProtocolCatalog_KeepsLastGoodVersion(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "protocols.json")
+	m := metrics.NewProtocols(prometheus.NewRegistry())
+	catalog := NewFileProtocolCatalog(path, time.Minute, types.ProtocolRules{AllowedTags: []string{"DEX", "Lending"}}, m)
+	ctx := context.Background()
+
+	// Missing file: nothing to serve yet.
+	require.ErrorIs(t, catalog.Refresh(ctx), os.ErrNotExist)
+	_, err := catalog.Protocols(ctx)
+	require.ErrorIs(t, err, types.ErrProtocolsNotLoaded)
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.ReloadFailures.WithLabelValues("read")))
+
+	require.NoError(t, os.WriteFile(path, []byte(validProtocolsV1), 0o600))
+	require.NoError(t, catalog.Refresh(ctx))
+	got, err := catalog.Protocols(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, 1, testutil.CollectAndCount(m.CatalogInfo))
+
+	for reason, body := range map[string]string{
+		"parse":   `[{"name": "Blend"`,
+		"invalid": `[{"name": "Blend", "tags": ["Lendng"], "description": "d", "website_url": "https://a.org", "icon_url": "https://b.org"}]`,
+	} {
+		require.NoError(t, os.WriteFile(path, []byte(body), 0o600))
+		require.Error(t, catalog.Refresh(ctx), reason)
+		// The same bad version is rejected once, not on every poll.
+		require.NoError(t, catalog.Refresh(ctx), reason)
+		assert.Equal(t, float64(1), testutil.ToFloat64(m.ReloadFailures.WithLabelValues(reason)))
+
+		got, err = catalog.Protocols(ctx)
+		require.NoError(t, err)
+		require.Len(t, got, 1, "the last good version keeps being served after a %s failure", reason)
+	}
+
+	require.NoError(t, os.WriteFile(path, []byte(validProtocolsV2), 0o600))
+	require.NoError(t, catalog.Refresh(ctx))
+	got, err = catalog.Protocols(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "Phoenix", got[1].Name)
+}
+
+func TestFileProtocolCatalog_RunPicksUpChanges(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "protocols.json")
+	require.NoError(t, os.WriteFile(path, []byte(validProtocolsV1), 0o600))
+	catalog := NewFileProtocolCatalog(path, 10*time.Millisecond, types.ProtocolRules{}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		catalog.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		got, _ := catalog.Protocols(ctx)
+		return len(got) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, os.WriteFile(path, []byte(validProtocolsV2), 0o600))
+	assert.Eventually(t, func() bool {
+		got, _ := catalog.Protocols(ctx)
+		return len(got) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
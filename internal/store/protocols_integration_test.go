@@ -0,0 +1,110 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+func TestProtocolsStore_ReplaceAndList(t *testing.T) {
+	pool := startMigratedPostgres(t)
+	ctx := context.Background()
+	s := NewProtocolsStore(pool)
+	trending := true
+
+	require.NoError(t, s.ReplaceProtocols(ctx, "alice", []types.Protocol{
+		{Name: "Phoenix", URL: "https://app.phoenix-hub.io/", IconURL: "https://icons/phoenix.png", Networks: []string{types.PUBLIC}},
+		{Name: "Blend", Tags: []string{"Lending"}, URL: "https://mainnet.blend.capital/", IconURL: "https://icons/blend.svg", IsTrending: &trending, Networks: []string{types.PUBLIC, types.TESTNET},
+			Translations: map[string]types.ProtocolTranslation{"es": {Description: "Mercados de préstamos."}}},
+	}))
+
+	got, err := s.ListProtocols(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	// Slice order is display order, not name order.
+	assert.Equal(t, "Phoenix", got[0].Name)
+	assert.Empty(t, got[0].Tags)
+	assert.Nil(t, got[0].IsTrending)
+	assert.Equal(t, []string{types.PUBLIC, types.TESTNET}, got[1].Networks)
+	require.NotNil(t, got[1].IsTrending)
+	assert.True(t, *got[1].IsTrending)
+	assert.Equal(t, map[string]types.ProtocolTranslation{"es": {Description: "Mercados de préstamos."}}, got[1].Translations)
+	assert.Empty(t, got[0].Translations)
+
+	// A replace drops entries missing from the new catalog.
+	require.NoError(t, s.ReplaceProtocols(ctx, "alice", []types.Protocol{
+		{Name: "Blend", URL: "https://mainnet.blend.capital/", IconURL: "https://icons/blend.svg", Networks: []string{types.PUBLIC}},
+	}))
+	got, err = s.ListProtocols(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "Blend", got[0].Name)
+
+	// A failed replace leaves the previous catalog in place.
+	err = s.ReplaceProtocols(ctx, "alice", []types.Protocol{
+		{Name: "Soroswap", URL: "https://soroswap.finance/", IconURL: "https://icons/soroswap.svg", Networks: []string{"MAINNET"}},
+	})
+	require.Error(t, err)
+	got, err = s.ListProtocols(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "Blend", got[0].Name)
+}
+
+func TestProtocolsStore_UpsertAndDeleteAreAudited(t *testing.T) {
+	pool := startMigratedPostgres(t)
+	ctx := context.Background()
+	s := NewProtocolsStore(pool)
+
+	blend := types.Protocol{Name: "Blend", URL: "https://mainnet.blend.capital/", IconURL: "https://icons/blend.svg", Networks: []string{types.PUBLIC}}
+	phoenix := types.Protocol{Name: "Phoenix", URL: "https://app.phoenix-hub.io/", IconURL: "https://icons/phoenix.png", Networks: []string{types.PUBLIC}}
+	require.NoError(t, s.ReplaceProtocols(ctx, "importer", []types.Protocol{blend}))
+
+	created, err := s.UpsertProtocol(ctx, "alice", phoenix)
+	require.NoError(t, err)
+	assert.True(t, created)
+	blend.Description = "Lending markets."
+	created, err = s.UpsertProtocol(ctx, "bob", blend)
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	got, err := s.ListProtocols(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "Blend", got[0].Name, "an update keeps the protocol's position")
+	assert.Equal(t, "Lending markets.", got[0].Description)
+	assert.Equal(t, "Phoenix", got[1].Name, "a new protocol goes last")
+
+	deleted, err := s.DeleteProtocol(ctx, "alice", "Phoenix")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	deleted, err = s.DeleteProtocol(ctx, "alice", "Phoenix")
+	require.NoError(t, err)
+	assert.False(t, deleted)
+
+	entries, err := NewAuditLogStore(pool).ListAuditLog(ctx, "", "", 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 4, "a no-op delete is not audited")
+	assert.Equal(t, []string{types.AuditActionDelete, types.AuditActionUpdate, types.AuditActionCreate, types.AuditActionReplace},
+		[]string{entries[0].Action, entries[1].Action, entries[2].Action, entries[3].Action})
+	assert.Equal(t, "alice", entries[0].Actor)
+	assert.Nil(t, entries[0].After)
+	assert.Nil(t, entries[2].Before)
+	assert.JSONEq(t, `"Lending markets."`, mustJSONField(t, entries[1].After, "description"))
+
+	phoenixLog, err := NewAuditLogStore(pool).ListAuditLog(ctx, types.AuditEntityProtocol, "Phoenix", 10)
+	require.NoError(t, err)
+	assert.Len(t, phoenixLog, 2)
+}
+
+func mustJSONField(t *testing.T, doc []byte, field string) string {
+	t.Helper()
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(doc, &fields))
+	return string(fields[field])
+}
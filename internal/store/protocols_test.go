@@ -0,0 +1,54 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+type fakeProtocolLister struct {
+	protocols []types.Protocol
+	err       error
+}
+
+func (f *fakeProtocolLister) ListProtocols(context.Context) ([]types.Protocol, error) {
+	return f.protocols, f.err
+}
+
+func TestDBProtocolCatalog_RefreshSwapsSnapshot(t *testing.T) {
+	t.Parallel()
+
+	lister := &fakeProtocolLister{}
+	catalog := NewDBProtocolCatalog(lister, time.Minute)
+	ctx := context.Background()
+
+	_, err := catalog.Protocols(ctx)
+	assert.ErrorIs(t, err, types.ErrProtocolsNotLoaded)
+
+	lister.protocols = []types.Protocol{{Name: "Blend"}, {Name: "Phoenix"}}
+	require.NoError(t, catalog.Refresh(ctx))
+	got, err := catalog.Protocols(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "Blend", got[0].Name)
+
+	// A failed reload keeps the last good snapshot.
+	lister.err = errors.New("connection refused")
+	require.Error(t, catalog.Refresh(ctx))
+	got, err = catalog.Protocols(ctx)
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+
+	// An empty table is a valid, loaded catalog.
+	lister.protocols, lister.err = nil, nil
+	require.NoError(t, catalog.Refresh(ctx))
+	got, err = catalog.Protocols(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
@@ -87,3 +87,39 @@ func (r *RedisStore) SetJSON(ctx context.Context, key string, value any, ttl tim
 	}
 	return nil
 }
+
+// ZIncrBy adds incr[member] to the score of each member of the sorted set at
+// key in a single round trip and sets the key to expire after ttl.
+func (r *RedisStore) ZIncrBy(ctx context.Context, key string, incr map[string]float64, ttl time.Duration) error {
+	if len(incr) == 0 {
+		return nil
+	}
+	pipe := r.redis.Pipeline()
+	for m, by := range incr {
+		pipe.ZIncrBy(ctx, key, by, m)
+	}
+	pipe.Expire(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("redis ZINCRBY %s: %w", key, err)
+	}
+	return nil
+}
+
+// ZTop returns up to n of the highest-scored members of the sorted set at key,
+// mapped to their scores. A missing key yields an empty map.
+func (r *RedisStore) ZTop(ctx context.Context, key string, n int) (map[string]float64, error) {
+	out := make(map[string]float64, n)
+	if n <= 0 {
+		return out, nil
+	}
+	members, err := r.redis.ZRevRangeWithScores(ctx, key, 0, int64(n-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis ZREVRANGE %s: %w", key, err)
+	}
+	for _, z := range members {
+		if member, ok := z.Member.(string); ok {
+			out[member] = z.Score
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,37 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Values of AuditEntry.Action.
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionReplace = "replace"
+)
+
+// Values of AuditEntry.EntityType.
+const (
+	AuditEntityFeatureFlag = "feature_flag"
+	AuditEntityProtocol    = "protocol"
+	// AuditEntityProtocols is a whole-catalog replace by `protocols import`.
+	AuditEntityProtocols = "protocols"
+	// AuditEntityCollection is keyed "NETWORK/CONTRACT_ID".
+	AuditEntityCollection = "collection"
+)
+
+// AuditEntry records one change to data-driven config. Before is null for a
+// create and After is null for a delete.
+type AuditEntry struct {
+	ID         int64           `json:"id"`
+	Actor      string          `json:"actor"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityKey  string          `json:"entity_key"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
@@ -0,0 +1,117 @@
+// ABOUTME: Normalized response types for the Blockaid-backed scanning endpoints.
+// ABOUTME: Collapses Blockaid's per-product validation payloads into one risk verdict shape clients can switch on.
+package types
+
+import "encoding/json"
+
+// Risk levels reported in ScanVerdict.ResultType. They are the lower-cased
+// Blockaid result_type values; anything Blockaid reports outside this set
+// (including a validation that errored upstream) normalizes to RiskUnknown so
+// clients never have to handle an open-ended string.
+const (
+	RiskBenign    = "benign"
+	RiskWarning   = "warning"
+	RiskMalicious = "malicious"
+	RiskUnknown   = "unknown"
+)
+
+// BlockaidFeature is one signal Blockaid attached to a verdict (e.g. a known
+// drainer contract or an unverified destination). Type is Blockaid's own
+// severity bucket for the signal ("Benign", "Info", "Warning", "Malicious").
+type BlockaidFeature struct {
+	Type        string `json:"type"`
+	FeatureID   string `json:"feature_id"`
+	Description string `json:"description"`
+}
+
+// ScanVerdict is the normalized risk verdict returned by every scan route.
+// IsMalicious / IsSuspicious are derived from ResultType so clients that only
+// need a yes/no do not have to compare strings. Features is always non-nil.
+type ScanVerdict struct {
+	ResultType   string            `json:"result_type"`
+	IsMalicious  bool              `json:"is_malicious"`
+	IsSuspicious bool              `json:"is_suspicious"`
+	Description  string            `json:"description,omitempty"`
+	Reason       string            `json:"reason,omitempty"`
+	Features     []BlockaidFeature `json:"features"`
+	// ValidationError carries Blockaid's message when it answered 200 but could
+	// not validate the payload (ResultType is then RiskUnknown).
+	ValidationError string `json:"validation_error,omitempty"`
+}
+
+// TxScanResult is the scan-tx response: the verdict plus Blockaid's
+// simulation, forwarded verbatim so clients can keep rendering balance diffs.
+// Simulation is omitted when Blockaid did not return one; SimulationError is
+// set when it returned one with status "Error".
+type TxScanResult struct {
+	ScanVerdict
+	Simulation      json.RawMessage `json:"simulation,omitempty"`
+	SimulationError string          `json:"simulation_error,omitempty"`
+}
+
+// Dapp scan statuses. Blockaid answers "miss" for an origin it has not crawled
+// yet; the verdict is then RiskUnknown and the client should retry later rather
+// than treat the site as benign.
+const (
+	DappScanHit  = "hit"
+	DappScanMiss = "miss"
+)
+
+// DappScanResult is the scan-dapp response for one origin. Features lists the
+// attack types Blockaid matched, one entry per type.
+type DappScanResult struct {
+	ScanVerdict
+	URL    string `json:"url"`
+	Status string `json:"status"`
+}
+
+// AssetScanResult is one entry of the scan-assets response. MaliciousScore is
+// Blockaid's 0-1 confidence, forwarded as the decimal string it sends.
+type AssetScanResult struct {
+	ScanVerdict
+	MaliciousScore string `json:"malicious_score,omitempty"`
+}
+
+// Report events accepted by the warning-reporting routes, in Blockaid's own
+// spelling. A false positive is a warning the user believes is wrong; a false
+// negative is a threat Blockaid did not flag.
+const (
+	ReportFalsePositive = "FALSE_POSITIVE"
+	ReportFalseNegative = "FALSE_NEGATIVE"
+)
+
+// Report kinds, persisted in blockaid_reports.kind.
+const (
+	ReportKindAsset       = "asset"
+	ReportKindTransaction = "transaction"
+)
+
+// AssetWarningReport disputes the verdict on one canonical asset id.
+type AssetWarningReport struct {
+	Asset   string
+	Event   string
+	Details string
+}
+
+// TransactionWarningReport disputes the verdict on a transaction. The fields
+// mirror ScanTx so Blockaid can re-run the original scan.
+type TransactionWarningReport struct {
+	AccountAddress string
+	TxXDR          string
+	OriginURL      string
+	Event          string
+	Details        string
+}
+
+// BlockaidReport is the audit copy of a forwarded report. UserID is empty for
+// anonymous requests; ForwardError is empty when Blockaid accepted the report.
+type BlockaidReport struct {
+	Kind         string
+	Event        string
+	Network      string
+	Subject      string
+	OriginURL    string
+	Details      string
+	UserID       string
+	ForwardError string
+}
@@ -0,0 +1,19 @@
+// ABOUTME: Request and response types for the Coinbase onramp session-token endpoint.
+// ABOUTME: Mirrors the Coinbase CDP /onramp/v1/token contract, scoped to Stellar destinations.
+package types
+
+// OnrampTokenRequest asks Coinbase for a one-time onramp session bound to a
+// single Stellar destination. Assets are the asset codes the wallet will let
+// the user buy into that address (e.g. "XLM", "USDC").
+type OnrampTokenRequest struct {
+	Address string
+	Assets  []string
+}
+
+// OnrampSessionToken is the one-time session token the client passes to the
+// Coinbase onramp widget. It is short-lived and single-use, so it is never
+// cached.
+type OnrampSessionToken struct {
+	Token     string `json:"token"`
+	ChannelID string `json:"channel_id,omitempty"`
+}
@@ -0,0 +1,70 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/stellar/go-stellar-sdk/strkey"
+)
+
+// CollectibleMetadata is the SEP-50 metadata document a collectible's
+// token_uri points to, reduced to the fields Freighter renders. Image is
+// rewritten to an HTTPS gateway URL when the document uses ipfs://.
+type CollectibleMetadata struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Image       string                 `json:"image,omitempty"`
+	ExternalURL string                 `json:"external_url,omitempty"`
+	Attributes  []CollectibleAttribute `json:"attributes,omitempty"`
+}
+
+// CollectibleAttribute is one trait of a collectible. Value is kept as the
+// raw JSON string, number or boolean the document carried.
+type CollectibleAttribute struct {
+	TraitType   string          `json:"trait_type"`
+	Value       json.RawMessage `json:"value"`
+	DisplayType string          `json:"display_type,omitempty"`
+}
+
+// CollectionEnumeration names how a registered collection lists the tokens an
+// owner holds. SEP-50 defines no enumeration method, so each collection
+// declares the extension its contract implements.
+type CollectionEnumeration string
+
+const (
+	// CollectionEnumerationOwnerTokens calls get_owner_tokens(owner) and reads
+	// the Vec<u32> it returns, as the Meridian Pay contracts do.
+	CollectionEnumerationOwnerTokens CollectionEnumeration = "owner_tokens"
+	// CollectionEnumerationOwnerIndex calls balance(owner), then
+	// get_owner_token_id(owner, index) for each index below it, as contracts
+	// built on the OpenZeppelin enumerable extension do.
+	CollectionEnumerationOwnerIndex CollectionEnumeration = "owner_index"
+)
+
+// RegisteredCollection is a collection contract whose tokens are discovered
+// for the owner instead of being listed by the client.
+type RegisteredCollection struct {
+	Network     string                `json:"network"`
+	ContractID  string                `json:"contract_id"`
+	Enumeration CollectionEnumeration `json:"enumeration"`
+	// Label names the entry for operators; it is never returned to clients.
+	Label string `json:"label,omitempty"`
+}
+
+// Validate reports the first problem with c, if any.
+func (c RegisteredCollection) Validate() error {
+	switch c.Network {
+	case PUBLIC, TESTNET, FUTURENET:
+	default:
+		return fmt.Errorf("network must be %s, %s or %s, got %q", PUBLIC, TESTNET, FUTURENET, c.Network)
+	}
+	if decoded, err := strkey.Decode(strkey.VersionByteContract, c.ContractID); err != nil || len(decoded) != 32 {
+		return fmt.Errorf("invalid contract_id %q", c.ContractID)
+	}
+	switch c.Enumeration {
+	case CollectionEnumerationOwnerTokens, CollectionEnumerationOwnerIndex:
+	default:
+		return fmt.Errorf("enumeration must be %q or %q, got %q", CollectionEnumerationOwnerTokens, CollectionEnumerationOwnerIndex, c.Enumeration)
+	}
+	return nil
+}
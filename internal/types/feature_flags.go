@@ -0,0 +1,181 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/stellar/freighter-backend-v2/internal/utils/semver"
+)
+
+// FeatureFlag is a named switch returned by GET /api/v1/feature-flags. Rules
+// are tried in order and the first one matching the request decides; when
+// none matches the flag is Enabled.
+type FeatureFlag struct {
+	Key         string     `json:"key"`
+	Description string     `json:"description,omitempty"`
+	Enabled     bool       `json:"enabled"`
+	Rules       []FlagRule `json:"rules,omitempty"`
+}
+
+// FlagRule targets part of the traffic. Every condition that is set must hold
+// for the rule to match; a rule with no conditions matches everything.
+type FlagRule struct {
+	// Platforms matches the request's platform ("ios", "android", ...),
+	// case-insensitively.
+	Platforms []string `json:"platforms,omitempty"`
+	// Versions is a semver range (see semver.ParseRange) the client version
+	// must fall in. A request without a parseable version never matches it.
+	Versions string `json:"versions,omitempty"`
+	// versions is Versions as parsed by Validate, so evaluating a request
+	// does no parsing. A rule with Versions that has not been validated never
+	// matches.
+	versions *semver.Range
+	// Networks matches the request's network.
+	Networks []string `json:"networks,omitempty"`
+	// Percentage rolls the rule out to that share (0-100) of authenticated
+	// users, bucketed by user ID so a user keeps their answer between
+	// requests. Anonymous requests only match a 100% rollout.
+	Percentage *int `json:"percentage,omitempty"`
+	Enabled    bool `json:"enabled"`
+}
+
+// FlagContext is what a request tells the flag engine about its caller.
+type FlagContext struct {
+	Platform string
+	Version  string
+	Network  string
+	UserID   string
+}
+
+// ErrFeatureFlagsNotLoaded is returned by a FeatureFlagSource that has not
+// loaded the flags yet.
+var ErrFeatureFlagsNotLoaded = errors.New("feature flags not loaded yet")
+
+var featureFlagKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Validate checks the flag before it is served or stored: a snake_case key,
+// parseable version ranges, known networks and percentages within 0-100. It
+// keeps each rule's parsed range for Evaluate.
+func (f *FeatureFlag) Validate() error {
+	if !featureFlagKeyPattern.MatchString(f.Key) {
+		return fmt.Errorf("feature flag key %q must be snake_case", f.Key)
+	}
+	for i := range f.Rules {
+		rule := &f.Rules[i]
+		rule.versions = nil
+		if rule.Versions != "" {
+			rng, err := semver.ParseRange(rule.Versions)
+			if err != nil {
+				return fmt.Errorf("feature flag %q rule %d: %w", f.Key, i, err)
+			}
+			rule.versions = &rng
+		}
+		for _, network := range rule.Networks {
+			switch network {
+			case PUBLIC, TESTNET, FUTURENET:
+			default:
+				return fmt.Errorf("feature flag %q rule %d: network must be %s, %s or %s, got %q", f.Key, i, PUBLIC, TESTNET, FUTURENET, network)
+			}
+		}
+		if p := rule.Percentage; p != nil && (*p < 0 || *p > 100) {
+			return fmt.Errorf("feature flag %q rule %d: percentage must be between 0 and 100, got %d", f.Key, i, *p)
+		}
+	}
+	return nil
+}
+
+// ValidateFeatureFlags validates every flag and rejects duplicate keys.
+func ValidateFeatureFlags(flags []FeatureFlag) error {
+	seen := make(map[string]bool, len(flags))
+	for i := range flags {
+		f := &flags[i]
+		if err := f.Validate(); err != nil {
+			return err
+		}
+		if seen[f.Key] {
+			return fmt.Errorf("feature flag %q is listed twice", f.Key)
+		}
+		seen[f.Key] = true
+	}
+	return nil
+}
+
+// Evaluate returns whether the flag is on for fc.
+func (f FeatureFlag) Evaluate(fc FlagContext) bool {
+	for _, rule := range f.Rules {
+		if rule.matches(f.Key, fc) {
+			return rule.Enabled
+		}
+	}
+	return f.Enabled
+}
+
+func (r FlagRule) matches(key string, fc FlagContext) bool {
+	if len(r.Platforms) > 0 && !slices.ContainsFunc(r.Platforms, func(p string) bool { return strings.EqualFold(p, fc.Platform) }) {
+		return false
+	}
+	if len(r.Networks) > 0 && !slices.Contains(r.Networks, fc.Network) {
+		return false
+	}
+	if r.Versions != "" {
+		if r.versions == nil {
+			return false
+		}
+		v, err := semver.Parse(fc.Version)
+		if err != nil || !r.versions.Contains(v) {
+			return false
+		}
+	}
+	if r.Percentage != nil {
+		if *r.Percentage >= 100 {
+			return true
+		}
+		if fc.UserID == "" {
+			return false
+		}
+		return RolloutBucket(key, fc.UserID) < *r.Percentage
+	}
+	return true
+}
+
+// RolloutBucket places userID in one of 100 buckets for key. Hashing the key
+// in keeps rollouts of different flags independent of each other.
+func RolloutBucket(key, userID string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key + ":" + userID))
+	return int(h.Sum32() % 100)
+}
+
+// DefaultFeatureFlags are served when no flag source is configured, or while
+// the configured one has not loaded. They keep the behaviour the endpoint had
+// before flags were configurable: everything on, except for iOS 1.6.23.
+func DefaultFeatureFlags() []FeatureFlag {
+	ios1623 := []FlagRule{{Platforms: []string{"ios"}, Versions: "1.6.23", versions: &ios1623Range, Enabled: false}}
+	return []FeatureFlag{
+		{Key: FeatureFlagSwap, Enabled: true, Rules: ios1623},
+		{Key: FeatureFlagDiscover, Enabled: true, Rules: ios1623},
+		{Key: FeatureFlagOnramp, Enabled: true, Rules: ios1623},
+	}
+}
+
+// ios1623Range is parsed once, as the defaults may be evaluated on every
+// request.
+var ios1623Range = func() semver.Range {
+	rng, err := semver.ParseRange("1.6.23")
+	if err != nil {
+		panic(err)
+	}
+	return rng
+}()
+
+// Keys of the flags GET /api/v1/feature-flags also returns as top-level
+// fields, for clients that predate the flags map.
+const (
+	FeatureFlagSwap     = "swap"
+	FeatureFlagDiscover = "discover"
+	FeatureFlagOnramp   = "onramp"
+)
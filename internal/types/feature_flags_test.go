@@ -0,0 +1,110 @@
+package types
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func percentage(p int) *int { return &p }
+
+func TestFeatureFlag_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	flag := FeatureFlag{
+		Key:     "swap",
+		Enabled: true,
+		Rules: []FlagRule{
+			{Platforms: []string{"ios"}, Versions: "<1.7.0", Enabled: false},
+			{Networks: []string{FUTURENET}, Enabled: false},
+		},
+	}
+	require.NoError(t, flag.Validate())
+	cases := []struct {
+		name string
+		fc   FlagContext
+		want bool
+	}{
+		{"no rule matches", FlagContext{Platform: "android", Version: "1.0.0", Network: PUBLIC}, true},
+		{"old ios", FlagContext{Platform: "iOS", Version: "1.6.23"}, false},
+		{"new ios", FlagContext{Platform: "ios", Version: "1.7.0"}, true},
+		{"ios without a version skips the version rule", FlagContext{Platform: "ios"}, true},
+		{"second rule", FlagContext{Platform: "android", Network: FUTURENET}, false},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, flag.Evaluate(tc.fc), tc.name)
+	}
+}
+
+func TestFeatureFlag_UnvalidatedVersionRuleNeverMatches(t *testing.T) {
+	t.Parallel()
+
+	flag := FeatureFlag{Key: "swap", Enabled: true, Rules: []FlagRule{{Versions: "<1.7.0", Enabled: false}}}
+	fc := FlagContext{Version: "1.6.23"}
+	assert.True(t, flag.Evaluate(fc))
+	require.NoError(t, flag.Validate())
+	assert.False(t, flag.Evaluate(fc))
+}
+
+func TestFeatureFlag_EvaluatePercentageRollout(t *testing.T) {
+	t.Parallel()
+
+	flag := FeatureFlag{Key: "new_home", Rules: []FlagRule{{Percentage: percentage(25), Enabled: true}}}
+
+	on := 0
+	for i := range 1000 {
+		fc := FlagContext{UserID: fmt.Sprintf("user-%d", i)}
+		if flag.Evaluate(fc) {
+			on++
+		}
+		assert.Equal(t, flag.Evaluate(fc), flag.Evaluate(fc), "a user keeps their bucket")
+	}
+	assert.InDelta(t, 250, on, 60)
+
+	assert.False(t, flag.Evaluate(FlagContext{}), "anonymous callers are outside a partial rollout")
+	flag.Rules[0].Percentage = percentage(100)
+	assert.True(t, flag.Evaluate(FlagContext{}), "a full rollout includes anonymous callers")
+	flag.Rules[0].Percentage = percentage(0)
+	assert.False(t, flag.Evaluate(FlagContext{UserID: "user-1"}))
+}
+
+func TestRolloutBucket_IndependentPerFlag(t *testing.T) {
+	t.Parallel()
+
+	same := 0
+	for i := range 100 {
+		user := fmt.Sprintf("user-%d", i)
+		if RolloutBucket("flag_a", user) == RolloutBucket("flag_b", user) {
+			same++
+		}
+	}
+	assert.Less(t, same, 10)
+}
+
+func TestValidateFeatureFlags(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, ValidateFeatureFlags(DefaultFeatureFlags()))
+
+	for name, flags := range map[string][]FeatureFlag{
+		"bad key":       {{Key: "Swap-Enabled"}},
+		"duplicate key": {{Key: "swap"}, {Key: "swap"}},
+		"bad range":     {{Key: "swap", Rules: []FlagRule{{Versions: "~1.2"}}}},
+		"bad network":   {{Key: "swap", Rules: []FlagRule{{Networks: []string{"MAINNET"}}}}},
+		"bad percent":   {{Key: "swap", Rules: []FlagRule{{Percentage: percentage(101)}}}},
+	} {
+		assert.Error(t, ValidateFeatureFlags(flags), name)
+	}
+}
+
+func TestDefaultFeatureFlags_MatchLegacyBehaviour(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range DefaultFeatureFlags() {
+		assert.False(t, f.Evaluate(FlagContext{Platform: "ios", Version: "1.6.23"}), f.Key)
+		assert.True(t, f.Evaluate(FlagContext{Platform: "ios", Version: "1.7.23"}), f.Key)
+		assert.True(t, f.Evaluate(FlagContext{Platform: "android", Version: "1.6.23"}), f.Key)
+	}
+}
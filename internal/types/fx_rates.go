@@ -0,0 +1,20 @@
+package types
+
+import "errors"
+
+// BaseCurrency is the currency every price provider quotes in.
+const BaseCurrency = "USD"
+
+// ErrUnsupportedCurrency is returned when the FX rates source has no rate
+// for the requested currency code.
+var ErrUnsupportedCurrency = errors.New("unsupported currency")
+
+// FXRates is a table of BaseCurrency exchange rates: one BaseCurrency buys
+// Rates[code] units of code. PreviousRates holds the rates published before
+// Date (about a day earlier) and may be empty when the source has none; it
+// lets a 24h price change be restated in another currency.
+type FXRates struct {
+	Date          string             `json:"date"`
+	Rates         map[string]float64 `json:"rates"`
+	PreviousRates map[string]float64 `json:"previousRates,omitempty"`
+}
@@ -0,0 +1,11 @@
+// ABOUTME: Request types for the Horizon classic-ledger client.
+// ABOUTME: Responses reuse the SDK's Horizon protocol types unchanged.
+package types
+
+// HorizonPageParams are the optional paging knobs shared by Horizon's list
+// endpoints. Zero values fall back to Horizon's defaults (limit 10, ascending).
+type HorizonPageParams struct {
+	Cursor     string
+	Limit      uint
+	Descending bool
+}
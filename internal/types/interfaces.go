@@ -4,6 +4,8 @@ import (
 	"context"
 	"time"
 
+	hProtocol "github.com/stellar/go-stellar-sdk/protocols/horizon"
+	"github.com/stellar/go-stellar-sdk/protocols/horizon/operations"
 	"github.com/stellar/go-stellar-sdk/txnbuild"
 	"github.com/stellar/go-stellar-sdk/xdr"
 )
@@ -41,16 +43,46 @@ type RPCService interface {
 		timeout txnbuild.TimeBounds,
 		network string,
 	) (SimulateTransactionResponse, error)
+	// SimulateTransaction simulates a base64 TransactionEnvelope and returns
+	// everything needed to assemble it. A simulation the host rejects is
+	// reported in SimulationResult.Error, not as err.
+	SimulateTransaction(ctx context.Context, envelopeXDR, network string) (*SimulationResult, error)
 	GetLedgerEntries(ctx context.Context, keys []string, network string) ([]LedgerEntryMap, error)
+	// SubmitTransaction sends a signed envelope and waits for a terminal
+	// status until ctx's deadline, reporting TxStatusPending (not an error)
+	// if the deadline passes first.
+	SubmitTransaction(ctx context.Context, envelopeXDR, network string) (*TxStatus, error)
+	GetTransactionStatus(ctx context.Context, hash, network string) (*TxStatus, error)
+}
+
+// AccountBalancesSource builds the per-account results for the multi-account
+// balances endpoint. The returned interface{} is a []*AccountBalances.
+type AccountBalancesSource interface {
+	GetBalancesByAccountAddresses(ctx context.Context, addresses []string, network string) (interface{}, error)
 }
 
 type WalletBackendService interface {
 	Service
 	GetHealth(ctx context.Context, network string) (GetHealthResponse, error)
-	GetBalancesByAccountAddresses(ctx context.Context, addresses []string, network string) (interface{}, error)
+	AccountBalancesSource
 	GetAccountTransactions(ctx context.Context, address, network string, params AccountHistoryParams) (*PaginatedResponse[*AccountTransaction], error)
 }
 
+// HorizonService reads classic-ledger state from Horizon. It is the fallback
+// data source when wallet-backend is disabled, and only covers PUBLIC and
+// TESTNET (there is no futurenet Horizon configured).
+type HorizonService interface {
+	Service
+	// GetAccountDetails wraps services.ErrHorizonNotFound for unfunded
+	// accounts so callers can tell them apart from outages.
+	GetAccountDetails(ctx context.Context, network, accountID string) (*hProtocol.Account, error)
+	GetOffers(ctx context.Context, network, accountID string, params HorizonPageParams) (*hProtocol.OffersPage, error)
+	// GetPayments returns the account's payment-like operations (payment,
+	// path payments, create_account, account_merge).
+	GetPayments(ctx context.Context, network, accountID string, params HorizonPageParams) (*operations.OperationsPage, error)
+	GetFeeStats(ctx context.Context, network string) (*hProtocol.FeeStats, error)
+}
+
 // StellarExpertAsset is the subset of the Stellar Expert /asset/{id} response
 // we care about for pricing. A zero Price means unpriceable — either Stellar
 // Expert omitted the `price` field (a known but illiquid asset; JSON absence
@@ -65,8 +97,11 @@ type StellarExpertAsset struct {
 // quote_volume, base_volume, trades].
 type StellarExpertCandle [8]float64
 
-func (c StellarExpertCandle) TS() int64     { return int64(c[0]) }
-func (c StellarExpertCandle) Open() float64 { return c[1] }
+func (c StellarExpertCandle) TS() int64      { return int64(c[0]) }
+func (c StellarExpertCandle) Open() float64  { return c[1] }
+func (c StellarExpertCandle) Low() float64   { return c[2] }
+func (c StellarExpertCandle) High() float64  { return c[3] }
+func (c StellarExpertCandle) Close() float64 { return c[4] }
 
 type StellarExpertService interface {
 	Service
@@ -85,4 +120,165 @@ type PriceEntry struct {
 type PricesService interface {
 	Service
 	GetPrices(ctx context.Context, tokens []string, network string) (map[string]*PriceEntry, error)
+	// GetPriceHistory returns the chart series for one canonical token id
+	// over priceRange (one of PriceRanges).
+	GetPriceHistory(ctx context.Context, token, network, priceRange string) (*PriceHistory, error)
+	// Run does the service's background work, such as keeping the most
+	// requested prices fresh, until ctx is cancelled.
+	Run(ctx context.Context)
+}
+
+// PriceQuote is one provider's spot price for an asset. Change24h is the
+// trailing 24h percentage change, nil when the provider can't pin one.
+type PriceQuote struct {
+	Price     float64
+	Change24h *float64
+}
+
+// PriceProvider is one upstream source of spot prices behind PricesService.
+// GetQuote takes a canonical asset id and returns ErrAssetNotPriced when the
+// provider authoritatively has no price for it; any other error is treated
+// as transient.
+type PriceProvider interface {
+	Service
+	GetQuote(ctx context.Context, network, canonical string) (*PriceQuote, error)
+}
+
+// FXRatesProvider is one upstream source of BaseCurrency exchange rates.
+type FXRatesProvider interface {
+	Service
+	GetRates(ctx context.Context) (*FXRates, error)
+}
+
+// FXRatesService converts BaseCurrency token prices to other currencies.
+type FXRatesService interface {
+	Service
+	// ConvertPrices returns entries restated in currency (an ISO 4217 code):
+	// both the current price and the 24h change. nil entries stay nil.
+	// Currencies the rates source doesn't carry fail with
+	// ErrUnsupportedCurrency.
+	ConvertPrices(ctx context.Context, entries map[string]*PriceEntry, currency string) (map[string]*PriceEntry, error)
+	// SupportsCurrency reports whether the rates source carries currency, so
+	// a request for one it doesn't can be rejected before any pricing work.
+	// BaseCurrency is always supported.
+	SupportsCurrency(ctx context.Context, currency string) (bool, error)
+}
+
+// CollectibleMetadataService resolves a collectible's token_uri to its
+// SEP-50 metadata document.
+type CollectibleMetadataService interface {
+	Service
+	// GetMetadata fetches and validates the document at tokenURI (https:// or
+	// ipfs://). network only labels metrics.
+	GetMetadata(ctx context.Context, network, tokenURI string) (*CollectibleMetadata, error)
+}
+
+// CollectionRegistry lists the collection contracts whose tokens are
+// discovered for an owner by POST /api/v1/collectibles.
+type CollectionRegistry interface {
+	// Collections returns the entries registered for network, in a stable
+	// order.
+	Collections(ctx context.Context, network string) ([]RegisteredCollection, error)
+}
+
+// TokenDetailsService reads SEP-41 token metadata and balances by simulating
+// the token contract's read-only methods.
+type TokenDetailsService interface {
+	Service
+	// GetTokenDetails returns contractID's decimals, name and symbol, plus
+	// owner's balance when owner (a G- or C-address) is non-empty.
+	GetTokenDetails(ctx context.Context, network, contractID, owner string) (*TokenDetails, error)
+}
+
+// ContractAssetResolver maps Stellar Asset Contracts back to the classic
+// assets they wrap, so a SAC balance can be priced like its classic asset.
+type ContractAssetResolver interface {
+	Service
+	// ClassicAsset returns the canonical classic asset id ("XLM" or
+	// "CODE:ISSUER") wrapped by the SAC at contractID, or "" when contractID
+	// is some other contract, such as a custom SEP-41 token.
+	ClassicAsset(ctx context.Context, network, contractID string) (string, error)
+	// ClassicAssets resolves several contracts of one network at once. A
+	// contract it can't resolve right now is absent from the result.
+	ClassicAssets(ctx context.Context, network string, contractIDs []string) (map[string]string, error)
+}
+
+// BlockaidService fronts the Blockaid security-scanning API so clients never
+// hold the Blockaid API key.
+type BlockaidService interface {
+	Service
+	// ScanTx scores a transaction envelope. accountAddress is the account that
+	// will sign it; originURL is the dApp requesting the signature and may be
+	// empty for transactions built inside the wallet.
+	ScanTx(ctx context.Context, network, accountAddress, txXDR, originURL string) (*TxScanResult, error)
+	// ScanDapp scores a dApp origin (scheme://host). Blockaid's site scan is
+	// not network-specific.
+	ScanDapp(ctx context.Context, origin string) (*DappScanResult, error)
+	// ScanAssets scores canonical asset ids ("XLM" or "CODE:ISSUER") in bulk,
+	// keyed by the requested id. Every requested id is present in the result.
+	ScanAssets(ctx context.Context, network string, assets []string) (map[string]*AssetScanResult, error)
+	// ReportAsset and ReportTransaction forward a user's dispute of a verdict.
+	ReportAsset(ctx context.Context, network string, report AssetWarningReport) error
+	ReportTransaction(ctx context.Context, network string, report TransactionWarningReport) error
+}
+
+// BlockaidReportStore persists the audit copy of each warning report.
+type BlockaidReportStore interface {
+	InsertBlockaidReport(ctx context.Context, report BlockaidReport) error
+}
+
+// CoinbaseService fronts the Coinbase CDP onramp API so clients never hold
+// the CDP key.
+type CoinbaseService interface {
+	Service
+	GetOnrampSessionToken(ctx context.Context, req OnrampTokenRequest) (*OnrampSessionToken, error)
+}
+
+// FeatureFlagSource supplies the flags GET /api/v1/feature-flags evaluates.
+type FeatureFlagSource interface {
+	// FeatureFlags returns every flag, or ErrFeatureFlagsNotLoaded before
+	// the first load.
+	FeatureFlags(ctx context.Context) ([]FeatureFlag, error)
+}
+
+// FeatureFlagsService evaluates feature flags for a request.
+type FeatureFlagsService interface {
+	// Evaluate returns every flag's value for fc, keyed by flag key.
+	Evaluate(ctx context.Context, fc FlagContext) map[string]bool
+	// IsEnabled returns one flag's value for fc. A flag that is not defined
+	// is enabled, so declaring a flag key in code changes nothing until the
+	// flag is created.
+	IsEnabled(ctx context.Context, key string, fc FlagContext) bool
+}
+
+// AdminStore is what the admin API edits. Every mutation records the actor
+// and the before/after state in the audit log, in the same transaction.
+type AdminStore interface {
+	ListFeatureFlags(ctx context.Context) ([]FeatureFlag, error)
+	// UpsertFeatureFlag reports whether the flag was created.
+	UpsertFeatureFlag(ctx context.Context, actor string, f FeatureFlag) (bool, error)
+	// DeleteFeatureFlag reports whether the flag existed.
+	DeleteFeatureFlag(ctx context.Context, actor, key string) (bool, error)
+	ListProtocols(ctx context.Context) ([]Protocol, error)
+	// UpsertProtocol reports whether the protocol was created.
+	UpsertProtocol(ctx context.Context, actor string, p Protocol) (bool, error)
+	// DeleteProtocol reports whether the protocol existed.
+	DeleteProtocol(ctx context.Context, actor, name string) (bool, error)
+	// ListAuditLog returns up to limit entries, newest first, narrowed by
+	// entityType and entityKey when they are not empty.
+	ListAuditLog(ctx context.Context, entityType, entityKey string, limit int) ([]AuditEntry, error)
+}
+
+// Refresher keeps an in-memory snapshot in step with its source: Refresh
+// reloads it once and Run every interval until ctx is cancelled. A failed
+// reload keeps serving the previous snapshot.
+type Refresher interface {
+	Refresh(ctx context.Context) error
+	Run(ctx context.Context)
+}
+
+// ProtocolCatalog serves the Discover catalog for GET /api/v1/protocols.
+type ProtocolCatalog interface {
+	// Protocols returns the catalog in display order.
+	Protocols(ctx context.Context) ([]Protocol, error)
 }
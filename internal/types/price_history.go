@@ -0,0 +1,46 @@
+package types
+
+import (
+	"errors"
+	"slices"
+)
+
+// Supported chart ranges for GET /api/v1/token-prices/{token}/history.
+const (
+	PriceRange1D  = "1d"
+	PriceRange7D  = "7d"
+	PriceRange30D = "30d"
+	PriceRange1Y  = "1y"
+)
+
+// PriceRanges lists the supported chart ranges in ascending order.
+var PriceRanges = []string{PriceRange1D, PriceRange7D, PriceRange30D, PriceRange1Y}
+
+// ErrAssetNotPriced is returned when the price source does not know the
+// asset (or rejects its id), so there is no series to chart.
+var ErrAssetNotPriced = errors.New("asset has no price data")
+
+// IsValidPriceRange reports whether r is one of PriceRanges.
+func IsValidPriceRange(r string) bool {
+	return slices.Contains(PriceRanges, r)
+}
+
+// PricePoint is one bucket of a chart series. Prices are decimal strings for
+// the same BigNumber reasons as PriceEntry; Timestamp is the bucket open in
+// Unix seconds.
+type PricePoint struct {
+	Timestamp int64  `json:"timestamp"`
+	Open      string `json:"open"`
+	High      string `json:"high"`
+	Low       string `json:"low"`
+	Close     string `json:"close"`
+}
+
+// PriceHistory is a downsampled OHLC series for one asset over Range. Points
+// are ordered oldest first and are empty when the asset did not trade in the
+// window.
+type PriceHistory struct {
+	Range         string       `json:"range"`
+	ResolutionSec int          `json:"resolution"`
+	Points        []PricePoint `json:"points"`
+}
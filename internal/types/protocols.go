@@ -0,0 +1,138 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Protocol is one entry of the Discover catalog returned by
+// GET /api/v1/protocols.
+type Protocol struct {
+	Name                        string   `json:"name"`
+	Tags                        []string `json:"tags"`
+	URL                         string   `json:"website_url"`
+	IconURL                     string   `json:"icon_url"`
+	BackgroundURL               string   `json:"background_url,omitempty"`
+	Description                 string   `json:"description"`
+	IsBlacklisted               bool     `json:"is_blacklisted"`
+	IsTrending                  *bool    `json:"is_trending,omitempty"`
+	IsWalletConnectNotSupported bool     `json:"is_wc_not_supported"`
+	// Networks lists where the protocol is available. Catalogs imported from
+	// a file that leaves it out get the importer's default networks.
+	Networks []string `json:"networks,omitempty"`
+	// Translations holds localized copy keyed by BCP 47 language tag ("es",
+	// "pt-BR"). GET /api/v1/protocols substitutes the best match for the
+	// request's Accept-Language and never returns the map itself.
+	Translations map[string]ProtocolTranslation `json:"translations,omitempty"`
+}
+
+// ProtocolTranslation overrides a protocol's name and description in one
+// language. An empty field falls back to the untranslated value.
+type ProtocolTranslation struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// languageTagPattern is a loose BCP 47 shape check: a 2-3 letter language
+// followed by optional script/region/variant subtags.
+var languageTagPattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// ErrProtocolsNotLoaded is returned by a ProtocolCatalog that has not loaded
+// the catalog yet.
+var ErrProtocolsNotLoaded = errors.New("protocols catalog not loaded yet")
+
+// ProtocolRules are the optional checks ValidateProtocols applies on top of
+// the ones every catalog must pass.
+type ProtocolRules struct {
+	// AllowedTags is the tag vocabulary (--protocols-allowed-tags). Clients
+	// build their filters from tags, so a misspelt tag would silently split
+	// a category. Empty accepts any tag.
+	AllowedTags []string
+	// Strict also requires a description and accepts https URLs only
+	// (--protocols-strict-validation).
+	Strict bool
+}
+
+// ValidateProtocols checks a whole catalog before it is served or stored:
+// every entry needs a unique name without "/", http(s) website and icon URLs,
+// non-empty translations keyed by distinct language tags, and known
+// networks, plus whatever rules asks for.
+func ValidateProtocols(protocols []Protocol, rules ProtocolRules) error {
+	seen := make(map[string]bool, len(protocols))
+	for i, p := range protocols {
+		if p.Name == "" {
+			return fmt.Errorf("protocol %d: name is required", i)
+		}
+		// The name is a path segment of the admin API's protocol routes.
+		if strings.Contains(p.Name, "/") {
+			return fmt.Errorf("protocol %d: name %q must not contain \"/\"", i, p.Name)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("protocol %d: %q is listed twice", i, p.Name)
+		}
+		seen[p.Name] = true
+		if p.URL == "" || p.IconURL == "" {
+			return fmt.Errorf("protocol %q: website_url and icon_url are required", p.Name)
+		}
+		if err := validateURLs(p, rules.Strict); err != nil {
+			return fmt.Errorf("protocol %q: %w", p.Name, err)
+		}
+		if rules.Strict && p.Description == "" {
+			return fmt.Errorf("protocol %q: description is required", p.Name)
+		}
+		for _, tag := range p.Tags {
+			if len(rules.AllowedTags) > 0 && !slices.Contains(rules.AllowedTags, tag) {
+				return fmt.Errorf("protocol %q: tag %q is not one of %v", p.Name, tag, rules.AllowedTags)
+			}
+		}
+		languages := make(map[string]string, len(p.Translations))
+		for tag, translation := range p.Translations {
+			if !languageTagPattern.MatchString(tag) {
+				return fmt.Errorf("protocol %q: translation key %q is not a language tag", p.Name, tag)
+			}
+			if other, dup := languages[strings.ToLower(tag)]; dup {
+				return fmt.Errorf("protocol %q: translation keys %q and %q differ only in case", p.Name, other, tag)
+			}
+			languages[strings.ToLower(tag)] = tag
+			if translation.Name == "" && translation.Description == "" {
+				return fmt.Errorf("protocol %q: translation %q is empty", p.Name, tag)
+			}
+		}
+		for _, network := range p.Networks {
+			switch network {
+			case PUBLIC, TESTNET, FUTURENET:
+			default:
+				return fmt.Errorf("protocol %q: network must be %s, %s or %s, got %q", p.Name, PUBLIC, TESTNET, FUTURENET, network)
+			}
+		}
+	}
+	return nil
+}
+
+// validateURLs requires website, icon and (when set) background URLs to be
+// absolute http or https URLs, so wallets are never handed a javascript:,
+// data: or relative link. Strict accepts https only.
+func validateURLs(p Protocol, strict bool) error {
+	schemes := []string{"http", "https"}
+	if strict {
+		schemes = []string{"https"}
+	}
+	fields := []struct{ name, value string }{
+		{"website_url", p.URL},
+		{"icon_url", p.IconURL},
+		{"background_url", p.BackgroundURL},
+	}
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		if u, err := url.Parse(f.value); err != nil || !slices.Contains(schemes, u.Scheme) || u.Host == "" {
+			return fmt.Errorf("%s must be an absolute %s URL, got %q", f.name, strings.Join(schemes, " or "), f.value)
+		}
+	}
+	return nil
+}
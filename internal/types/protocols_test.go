@@ -0,0 +1,63 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateProtocols(t *testing.T) {
+	t.Parallel()
+
+	valid := Protocol{
+		Name:          "Blend",
+		Tags:          []string{"Lending"},
+		URL:           "https://mainnet.blend.capital/",
+		IconURL:       "https://icons.stellar.org/blend.svg",
+		BackgroundURL: "https://icons.stellar.org/blend.png",
+		Description:   "Lending markets.",
+		Networks:      []string{PUBLIC},
+		Translations:  map[string]ProtocolTranslation{"es": {Description: "Mercados de préstamos."}, "pt-BR": {Name: "Blend"}},
+	}
+	rules := ProtocolRules{AllowedTags: []string{"DEX", "Lending"}, Strict: true}
+	assert.NoError(t, ValidateProtocols([]Protocol{valid}, rules))
+
+	for name, mutate := range map[string]func(*Protocol){
+		"no name":             func(p *Protocol) { p.Name = "" },
+		"slash in name":       func(p *Protocol) { p.Name = "Blend/v2" },
+		"no website":          func(p *Protocol) { p.URL = "" },
+		"no icon":             func(p *Protocol) { p.IconURL = "" },
+		"javascript icon":     func(p *Protocol) { p.IconURL = "javascript:alert(1)" },
+		"data icon":           func(p *Protocol) { p.IconURL = "data:image/svg+xml;base64,PHN2Zz4=" },
+		"schemeless website":  func(p *Protocol) { p.URL = "mainnet.blend.capital" },
+		"relative background": func(p *Protocol) { p.BackgroundURL = "/blend.png" },
+		"unknown network":     func(p *Protocol) { p.Networks = []string{"MAINNET"} },
+		"bad language tag":    func(p *Protocol) { p.Translations = map[string]ProtocolTranslation{"spanish!": {Name: "x"}} },
+		"empty translation":   func(p *Protocol) { p.Translations = map[string]ProtocolTranslation{"es": {}} },
+		"case-only duplicate translation": func(p *Protocol) {
+			p.Translations = map[string]ProtocolTranslation{"pt-BR": {Name: "a"}, "pt-br": {Name: "b"}}
+		},
+	} {
+		p := valid
+		mutate(&p)
+		assert.Error(t, ValidateProtocols([]Protocol{p}, ProtocolRules{}), name)
+	}
+
+	for name, mutate := range map[string]func(*Protocol){
+		"no description": func(p *Protocol) { p.Description = "" },
+		"http website":   func(p *Protocol) { p.URL = "http://mainnet.blend.capital/" },
+		"http icon":      func(p *Protocol) { p.IconURL = "http://icons.stellar.org/blend.svg" },
+	} {
+		p := valid
+		mutate(&p)
+		assert.NoError(t, ValidateProtocols([]Protocol{p}, ProtocolRules{}), name+" passes by default")
+		assert.Error(t, ValidateProtocols([]Protocol{p}, rules), name+" fails strict validation")
+	}
+
+	assert.Error(t, ValidateProtocols([]Protocol{valid, valid}, rules), "duplicate names")
+
+	anyTag := valid
+	anyTag.Tags = []string{"Anything"}
+	assert.Error(t, ValidateProtocols([]Protocol{anyTag}, rules), "tag outside the vocabulary")
+	assert.NoError(t, ValidateProtocols([]Protocol{anyTag}, ProtocolRules{}), "an empty vocabulary accepts any tag")
+}
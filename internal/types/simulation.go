@@ -0,0 +1,24 @@
+package types
+
+// SimulationResult is the full outcome of a simulateTransaction call. XDR
+// fields are base64. Error carries the host's failure message for a
+// transaction that simulated but failed (e.g. a contract panic); the other
+// fields are then empty.
+type SimulationResult struct {
+	Error           string           `json:"error,omitempty"`
+	TransactionData string           `json:"transaction_data,omitempty"`
+	MinResourceFee  int64            `json:"min_resource_fee,string"`
+	Auth            []string         `json:"auth"`
+	Events          []string         `json:"events"`
+	ReturnValue     string           `json:"return_value,omitempty"`
+	RestorePreamble *RestorePreamble `json:"restore_preamble,omitempty"`
+	LatestLedger    uint32           `json:"latest_ledger"`
+}
+
+// RestorePreamble is set when the simulated footprint includes archived
+// entries: a RestoreFootprint transaction with this data and fee must land
+// before the simulated transaction can succeed.
+type RestorePreamble struct {
+	TransactionData string `json:"transaction_data"`
+	MinResourceFee  int64  `json:"min_resource_fee,string"`
+}
@@ -0,0 +1,13 @@
+// ABOUTME: Response types for SEP-41 token metadata and balance lookups.
+// ABOUTME: Values are read by simulating the token contract's read-only methods.
+package types
+
+// TokenDetails describes a SEP-41 token. Balance is the owner's balance in
+// the token's smallest unit as a base-10 integer string (i128 overflows
+// JSON numbers); it is empty when no owner was requested.
+type TokenDetails struct {
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Decimals uint32 `json:"decimals"`
+	Balance  string `json:"balance,omitempty"`
+}
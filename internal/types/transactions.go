@@ -0,0 +1,34 @@
+// ABOUTME: Response types for transaction submission and status polling.
+// ABOUTME: Status values follow stellar-rpc's sendTransaction and getTransaction vocabulary.
+package types
+
+const (
+	// TxStatusSuccess and TxStatusFailed are terminal: the transaction was
+	// included in a ledger.
+	TxStatusSuccess = "SUCCESS"
+	TxStatusFailed  = "FAILED"
+	// TxStatusPending means the transaction was accepted but not yet seen in
+	// a ledger when the server stopped waiting; poll the status endpoint.
+	TxStatusPending = "PENDING"
+	// TxStatusNotFound means stellar-rpc has no record of the hash, either
+	// because it is not yet ingested or it is outside the retention window.
+	TxStatusNotFound = "NOT_FOUND"
+	// TxStatusError means stellar-core rejected the transaction at
+	// submission; ResultCode says why.
+	TxStatusError = "ERROR"
+	// TxStatusTryAgainLater means stellar-core declined to queue the
+	// transaction (e.g. a pending one from the same account); resubmit.
+	TxStatusTryAgainLater = "TRY_AGAIN_LATER"
+)
+
+// TxStatus is the client-facing outcome of a submitted transaction.
+// ResultCode is the Horizon-style transaction result code (e.g.
+// "tx_success", "tx_bad_seq") and is empty while the outcome is unknown.
+// Ledger and CreatedAt are set once the transaction is in a ledger.
+type TxStatus struct {
+	Hash       string `json:"hash"`
+	Status     string `json:"status"`
+	ResultCode string `json:"result_code,omitempty"`
+	Ledger     uint32 `json:"ledger,omitempty"`
+	CreatedAt  int64  `json:"created_at,omitempty"`
+}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+
 	"github.com/stellar/freighter-backend-v2/internal/utils"
 )
 
@@ -17,13 +20,14 @@ const (
 
 var (
 	ErrEmpty     = errors.New("token id is empty")
-	ErrMalformed = errors.New("token id is malformed: expected \"XLM\" or \"CODE:ISSUER\"")
+	ErrMalformed = errors.New("token id is malformed: expected \"XLM\", \"CODE:ISSUER\" or a contract id")
 )
 
 // Normalize accepts a client-side token identifier and returns its canonical
 // form. "XLM", "xlm", and "native" all collapse to "XLM". A "CODE:ISSUER"
 // pair is validated (1-12 alphanumeric code, valid ed25519 public key issuer)
-// and returned with the code preserved as supplied.
+// and returned with the code preserved as supplied. A contract token — a
+// Stellar Asset Contract or any SEP-41 token — is its C-address, upper-cased.
 func Normalize(input string) (string, error) {
 	trimmed := strings.TrimSpace(input)
 	if trimmed == "" {
@@ -34,6 +38,10 @@ func Normalize(input string) (string, error) {
 		return NativeCanonical, nil
 	}
 
+	if contractID := strings.ToUpper(trimmed); utils.IsValidContractID(contractID) {
+		return contractID, nil
+	}
+
 	parts := strings.Split(trimmed, ":")
 	if len(parts) != 2 {
 		return "", ErrMalformed
@@ -50,10 +58,39 @@ func Normalize(input string) (string, error) {
 	return code + ":" + issuer, nil
 }
 
+// IsContract reports whether canonical is a contract token id.
+func IsContract(canonical string) bool {
+	return strings.HasPrefix(canonical, "C") && utils.IsValidContractID(canonical)
+}
+
+// SACContractID returns the C-address of the Stellar Asset Contract for a
+// canonical classic asset id on the network with the given passphrase. The
+// address is derived from the asset alone, so it exists whether or not the
+// contract has been deployed.
+func SACContractID(canonical, passphrase string) (string, error) {
+	asset := xdr.MustNewNativeAsset()
+	if canonical != NativeCanonical {
+		code, issuer, ok := strings.Cut(canonical, ":")
+		if !ok {
+			return "", fmt.Errorf("%w: %q is not a classic asset", ErrMalformed, canonical)
+		}
+		var err error
+		if asset, err = xdr.NewCreditAsset(code, issuer); err != nil {
+			return "", fmt.Errorf("%w: %w", ErrMalformed, err)
+		}
+	}
+	id, err := asset.ContractID(passphrase)
+	if err != nil {
+		return "", fmt.Errorf("deriving contract id for %s: %w", canonical, err)
+	}
+	return strkey.Encode(strkey.VersionByteContract, id[:])
+}
+
 // ToStellarExpert formats a canonical token id for the Stellar Expert
 // /asset/{id} endpoint. Native maps to "XLM"; classic assets become
 // "CODE-ISSUER-{1|2}" where the trailing type byte is derived from code length
-// (1-4 → 1 / credit_alphanum4, 5-12 → 2 / credit_alphanum12).
+// (1-4 → 1 / credit_alphanum4, 5-12 → 2 / credit_alphanum12). Contract tokens
+// keep their C-address, which is Stellar Expert's id for SEP-41 assets.
 func ToStellarExpert(canonical string) string {
 	if canonical == NativeCanonical {
 		return NativeCanonical
@@ -70,6 +107,14 @@ func ToStellarExpert(canonical string) string {
 	return fmt.Sprintf("%s-%s-%d", code, issuer, assetType)
 }
 
+// ToBlockaid formats a canonical classic asset id as the "CODE-ISSUER" address
+// Blockaid's Stellar token scanner expects. Contract tokens are scanned by
+// their C-address. Native has no Blockaid address and is returned unchanged;
+// callers do not scan it.
+func ToBlockaid(canonical string) string {
+	return strings.Replace(canonical, ":", "-", 1)
+}
+
 func isValidAssetCode(code string) bool {
 	n := len(code)
 	if n < 1 || n > maxCodeLen {
@@ -3,11 +3,16 @@ package assetid
 import (
 	"testing"
 
+	"github.com/stellar/go-stellar-sdk/network"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-const validIssuer = "GA5ZSEJYB37JRC5AVCIA5MOP4RHTM335X2KGX3IHOJAPP5RE34K4KZVN"
+const (
+	validIssuer = "GA5ZSEJYB37JRC5AVCIA5MOP4RHTM335X2KGX3IHOJAPP5RE34K4KZVN"
+	// usdcSAC is the pubnet Stellar Asset Contract for USDC:validIssuer.
+	usdcSAC = "CCW67TSZV3SSS2HXMBQ5JFGCKJNXKZM7UQUWUZPUTHXSTZLEO7SJMI75"
+)
 
 func TestNormalize(t *testing.T) {
 	t.Parallel()
@@ -35,6 +40,10 @@ func TestNormalize(t *testing.T) {
 		{"too many colons", "USDC:" + validIssuer + ":foo", "", true},
 		{"malformed issuer", "USDC:NOT-A-STELLAR-KEY", "", true},
 		{"surrounding whitespace trimmed", "  XLM ", "XLM", false},
+		{"contract id", usdcSAC, usdcSAC, false},
+		{"lower-case contract id upper-cased", " ccw67tszv3sss2hxmbq5jfgckjnxkzm7uquwuzputhxstzleo7sjmi75 ", usdcSAC, false},
+		{"contract id with bad checksum rejected", usdcSAC[:55] + "A", "", true},
+		{"account id rejected", validIssuer, "", true},
 	}
 
 	for _, tc := range cases {
@@ -65,6 +74,7 @@ func TestToStellarExpert(t *testing.T) {
 		{"1-char code uses type 1", "X:" + validIssuer, "X-" + validIssuer + "-1"},
 		{"5-char code uses type 2", "yXLM2:" + validIssuer, "yXLM2-" + validIssuer + "-2"},
 		{"12-char code uses type 2", "ABCDEFGHIJKL:" + validIssuer, "ABCDEFGHIJKL-" + validIssuer + "-2"},
+		{"contract keeps its address", usdcSAC, usdcSAC},
 	}
 
 	for _, tc := range cases {
@@ -75,3 +85,42 @@ func TestToStellarExpert(t *testing.T) {
 		})
 	}
 }
+
+func TestToBlockaid(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "XLM", ToBlockaid("XLM"))
+	assert.Equal(t, "USDC-"+validIssuer, ToBlockaid("USDC:"+validIssuer))
+	assert.Equal(t, "yXLM2-"+validIssuer, ToBlockaid("yXLM2:"+validIssuer))
+}
+
+func TestIsContract(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, IsContract(usdcSAC))
+	assert.False(t, IsContract("XLM"))
+	assert.False(t, IsContract("USDC:"+validIssuer))
+	assert.False(t, IsContract("CODE"))
+}
+
+func TestSACContractID(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		canonical  string
+		passphrase string
+		want       string
+	}{
+		{"XLM", network.PublicNetworkPassphrase, "CAS3J7GYLGXMF6TDJBBYYSE3HQ6BBSMLNUQ34T6TZMYMW2EVH34XOWMA"},
+		{"XLM", network.TestNetworkPassphrase, "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC"},
+		{"USDC:" + validIssuer, network.PublicNetworkPassphrase, usdcSAC},
+	}
+	for _, tc := range cases {
+		got, err := SACContractID(tc.canonical, tc.passphrase)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, got, tc.canonical)
+	}
+
+	_, err := SACContractID(usdcSAC, network.PublicNetworkPassphrase)
+	require.ErrorIs(t, err, ErrMalformed)
+}
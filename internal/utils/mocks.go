@@ -2,9 +2,13 @@ package utils
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 
 	"github.com/stellar/freighter-backend-v2/internal/types"
 	"github.com/stellar/go-stellar-sdk/clients/rpcclient"
+	hProtocol "github.com/stellar/go-stellar-sdk/protocols/horizon"
+	"github.com/stellar/go-stellar-sdk/protocols/horizon/operations"
 	"github.com/stellar/go-stellar-sdk/txnbuild"
 	"github.com/stellar/go-stellar-sdk/xdr"
 )
@@ -14,9 +18,20 @@ type MockRPCService struct {
 	SimulateResultOverride *xdr.ScVal
 	SimulatePanic          bool
 	TokenURIOverride       string
+	// OwnerTokenIDs, when set, is what the enumeration methods (get_owner_tokens,
+	// balance, get_owner_token_id) report the owner holding.
+	OwnerTokenIDs          []uint32
 	GetLedgerEntryOverride []types.LedgerEntryMap
 	GetLedgerEntryError    error
 	GetHealthFunc          func(network string) (types.GetHealthResponse, error)
+	SubmitTxResult         *types.TxStatus
+	SubmitTxError          error
+	LastSubmittedXDR       string
+	TxStatusResult         *types.TxStatus
+	TxStatusError          error
+	SimulateTxResult       *types.SimulationResult
+	SimulateTxError        error
+	LastSimulatedXDR       string
 }
 
 func (m *MockRPCService) ConfigureNetworkClient(network string) *rpcclient.Client {
@@ -38,6 +53,14 @@ func (m *MockRPCService) SimulateTx(ctx context.Context, tx *txnbuild.Transactio
 	return nil, nil
 }
 
+func (m *MockRPCService) SimulateTransaction(ctx context.Context, envelopeXDR, network string) (*types.SimulationResult, error) {
+	m.LastSimulatedXDR = envelopeXDR
+	if m.SimulateTxError != nil {
+		return nil, m.SimulateTxError
+	}
+	return m.SimulateTxResult, nil
+}
+
 func (m *MockRPCService) SimulateInvocation(
 	ctx context.Context,
 	contractId xdr.ScAddress,
@@ -62,6 +85,24 @@ func (m *MockRPCService) SimulateInvocation(
 	fn := string(functionName)
 
 	var result xdr.ScVal
+	if m.OwnerTokenIDs != nil {
+		switch fn {
+		case "get_owner_tokens":
+			scVec := xdr.ScVec{}
+			for _, id := range m.OwnerTokenIDs {
+				v := xdr.Uint32(id)
+				scVec = append(scVec, xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &v})
+			}
+			vecPtr := &scVec
+			return &xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &vecPtr}, nil
+		case "balance":
+			n := xdr.Uint32(len(m.OwnerTokenIDs)) //nolint:gosec // test fixture size
+			return &xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &n}, nil
+		case "get_owner_token_id":
+			id := xdr.Uint32(m.OwnerTokenIDs[*params[1].U32])
+			return &xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &id}, nil
+		}
+	}
 	switch fn {
 	case "get_owner_tokens":
 		scVec := xdr.ScVec{}
@@ -105,6 +146,21 @@ func (m *MockRPCService) GetLedgerEntries(ctx context.Context, keys []string, ne
 	return nil, nil
 }
 
+func (m *MockRPCService) SubmitTransaction(ctx context.Context, envelopeXDR, network string) (*types.TxStatus, error) {
+	m.LastSubmittedXDR = envelopeXDR
+	if m.SubmitTxError != nil {
+		return nil, m.SubmitTxError
+	}
+	return m.SubmitTxResult, nil
+}
+
+func (m *MockRPCService) GetTransactionStatus(ctx context.Context, hash, network string) (*types.TxStatus, error) {
+	if m.TxStatusError != nil {
+		return nil, m.TxStatusError
+	}
+	return m.TxStatusResult, nil
+}
+
 type MockWalletBackendService struct {
 	GetBalancesOverride interface{}
 	GetBalancesError    error
@@ -159,6 +215,12 @@ type MockPricesService struct {
 	GetPricesError    error
 	LastTokens        []string
 	LastNetwork       string
+
+	GetPriceHistoryFunc   func(ctx context.Context, token, network, priceRange string) (*types.PriceHistory, error)
+	GetPriceHistoryResult *types.PriceHistory
+	GetPriceHistoryError  error
+	LastToken             string
+	LastRange             string
 }
 
 func (m *MockPricesService) Name() string { return "mock-prices" }
@@ -177,3 +239,359 @@ func (m *MockPricesService) GetPrices(ctx context.Context, tokens []string, netw
 	}
 	return map[string]*types.PriceEntry{}, nil
 }
+
+func (m *MockPricesService) Run(context.Context) {}
+
+func (m *MockPricesService) GetPriceHistory(ctx context.Context, token, network, priceRange string) (*types.PriceHistory, error) {
+	m.LastToken = token
+	m.LastNetwork = network
+	m.LastRange = priceRange
+	if m.GetPriceHistoryFunc != nil {
+		return m.GetPriceHistoryFunc(ctx, token, network, priceRange)
+	}
+	if m.GetPriceHistoryError != nil {
+		return nil, m.GetPriceHistoryError
+	}
+	if m.GetPriceHistoryResult != nil {
+		return m.GetPriceHistoryResult, nil
+	}
+	return &types.PriceHistory{Range: priceRange, Points: []types.PricePoint{}}, nil
+}
+
+// MockFXRatesService converts by multiplying prices by Rates[currency] and
+// leaves the 24h change untouched. A currency missing from Rates is
+// unsupported.
+type MockFXRatesService struct {
+	Rates        map[string]float64
+	Err          error
+	LastCurrency string
+}
+
+func (m *MockFXRatesService) Name() string { return "mock-fx-rates" }
+
+func (m *MockFXRatesService) ConvertPrices(ctx context.Context, entries map[string]*types.PriceEntry, currency string) (map[string]*types.PriceEntry, error) {
+	m.LastCurrency = currency
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	rate, ok := m.Rates[currency]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", types.ErrUnsupportedCurrency, currency)
+	}
+	out := make(map[string]*types.PriceEntry, len(entries))
+	for token, entry := range entries {
+		if entry == nil {
+			out[token] = nil
+			continue
+		}
+		price, err := strconv.ParseFloat(entry.CurrentPrice, 64)
+		if err != nil {
+			return nil, err
+		}
+		out[token] = &types.PriceEntry{
+			CurrentPrice:             strconv.FormatFloat(price*rate, 'f', -1, 64),
+			PercentagePriceChange24h: entry.PercentagePriceChange24h,
+		}
+	}
+	return out, nil
+}
+
+func (m *MockFXRatesService) SupportsCurrency(ctx context.Context, currency string) (bool, error) {
+	if m.Err != nil {
+		return false, m.Err
+	}
+	_, ok := m.Rates[currency]
+	return ok, nil
+}
+
+type MockBlockaidService struct {
+	ScanTxFunc   func(ctx context.Context, network, accountAddress, txXDR, originURL string) (*types.TxScanResult, error)
+	ScanTxResult *types.TxScanResult
+	ScanTxError  error
+
+	ScanDappResult *types.DappScanResult
+	ScanDappError  error
+	LastOrigin     string
+
+	ScanAssetsFunc  func(ctx context.Context, network string, assets []string) (map[string]*types.AssetScanResult, error)
+	ScanAssetsError error
+	LastAssets      []string
+
+	ReportError           error
+	LastAssetReport       *types.AssetWarningReport
+	LastTransactionReport *types.TransactionWarningReport
+}
+
+func (m *MockBlockaidService) Name() string { return "mock-blockaid" }
+
+func (m *MockBlockaidService) ScanTx(ctx context.Context, network, accountAddress, txXDR, originURL string) (*types.TxScanResult, error) {
+	if m.ScanTxFunc != nil {
+		return m.ScanTxFunc(ctx, network, accountAddress, txXDR, originURL)
+	}
+	if m.ScanTxError != nil {
+		return nil, m.ScanTxError
+	}
+	return m.ScanTxResult, nil
+}
+
+func (m *MockBlockaidService) ScanDapp(ctx context.Context, origin string) (*types.DappScanResult, error) {
+	m.LastOrigin = origin
+	if m.ScanDappError != nil {
+		return nil, m.ScanDappError
+	}
+	return m.ScanDappResult, nil
+}
+
+func (m *MockBlockaidService) ScanAssets(ctx context.Context, network string, assets []string) (map[string]*types.AssetScanResult, error) {
+	m.LastAssets = assets
+	if m.ScanAssetsFunc != nil {
+		return m.ScanAssetsFunc(ctx, network, assets)
+	}
+	if m.ScanAssetsError != nil {
+		return nil, m.ScanAssetsError
+	}
+	out := make(map[string]*types.AssetScanResult, len(assets))
+	for _, a := range assets {
+		out[a] = &types.AssetScanResult{ScanVerdict: types.ScanVerdict{ResultType: types.RiskBenign, Features: []types.BlockaidFeature{}}}
+	}
+	return out, nil
+}
+
+func (m *MockBlockaidService) ReportAsset(ctx context.Context, network string, report types.AssetWarningReport) error {
+	m.LastAssetReport = &report
+	return m.ReportError
+}
+
+func (m *MockBlockaidService) ReportTransaction(ctx context.Context, network string, report types.TransactionWarningReport) error {
+	m.LastTransactionReport = &report
+	return m.ReportError
+}
+
+type MockBlockaidReportStore struct {
+	InsertError error
+	Reports     []types.BlockaidReport
+}
+
+func (m *MockBlockaidReportStore) InsertBlockaidReport(ctx context.Context, report types.BlockaidReport) error {
+	if m.InsertError != nil {
+		return m.InsertError
+	}
+	m.Reports = append(m.Reports, report)
+	return nil
+}
+
+type MockCoinbaseService struct {
+	Token       *types.OnrampSessionToken
+	Error       error
+	LastRequest *types.OnrampTokenRequest
+}
+
+func (m *MockCoinbaseService) Name() string { return "mock-coinbase" }
+
+func (m *MockCoinbaseService) GetOnrampSessionToken(ctx context.Context, req types.OnrampTokenRequest) (*types.OnrampSessionToken, error) {
+	m.LastRequest = &req
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	return m.Token, nil
+}
+
+type MockHorizonService struct {
+	GetAccountDetailsFunc func(network, accountID string) (*hProtocol.Account, error)
+	Offers                *hProtocol.OffersPage
+	Payments              *operations.OperationsPage
+	FeeStats              *hProtocol.FeeStats
+	Error                 error
+}
+
+func (m *MockHorizonService) Name() string { return "mock-horizon" }
+
+func (m *MockHorizonService) GetAccountDetails(ctx context.Context, network, accountID string) (*hProtocol.Account, error) {
+	if m.GetAccountDetailsFunc != nil {
+		return m.GetAccountDetailsFunc(network, accountID)
+	}
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	return &hProtocol.Account{AccountID: accountID}, nil
+}
+
+func (m *MockHorizonService) GetOffers(ctx context.Context, network, accountID string, params types.HorizonPageParams) (*hProtocol.OffersPage, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	return m.Offers, nil
+}
+
+func (m *MockHorizonService) GetPayments(ctx context.Context, network, accountID string, params types.HorizonPageParams) (*operations.OperationsPage, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	return m.Payments, nil
+}
+
+func (m *MockHorizonService) GetFeeStats(ctx context.Context, network string) (*hProtocol.FeeStats, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	return m.FeeStats, nil
+}
+
+type MockTokenDetailsService struct {
+	GetTokenDetailsFunc func(network, contractID, owner string) (*types.TokenDetails, error)
+}
+
+func (m *MockTokenDetailsService) Name() string { return "mock-token-details" }
+
+func (m *MockTokenDetailsService) GetTokenDetails(ctx context.Context, network, contractID, owner string) (*types.TokenDetails, error) {
+	if m.GetTokenDetailsFunc != nil {
+		return m.GetTokenDetailsFunc(network, contractID, owner)
+	}
+	return &types.TokenDetails{}, nil
+}
+
+type MockCollectibleMetadataService struct {
+	GetMetadataFunc func(network, tokenURI string) (*types.CollectibleMetadata, error)
+}
+
+func (m *MockCollectibleMetadataService) Name() string { return "mock-collectible-metadata" }
+
+func (m *MockCollectibleMetadataService) GetMetadata(ctx context.Context, network, tokenURI string) (*types.CollectibleMetadata, error) {
+	if m.GetMetadataFunc != nil {
+		return m.GetMetadataFunc(network, tokenURI)
+	}
+	return &types.CollectibleMetadata{}, nil
+}
+
+type MockCollectionRegistry struct {
+	Entries []types.RegisteredCollection
+	Err     error
+}
+
+func (m *MockCollectionRegistry) Collections(ctx context.Context, network string) ([]types.RegisteredCollection, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var out []types.RegisteredCollection
+	for _, entry := range m.Entries {
+		if entry.Network == network {
+			out = append(out, entry)
+		}
+	}
+	return out, nil
+}
+
+type MockProtocolCatalog struct {
+	Entries []types.Protocol
+	Err     error
+}
+
+func (m *MockProtocolCatalog) Protocols(ctx context.Context) ([]types.Protocol, error) {
+	return m.Entries, m.Err
+}
+
+type MockFeatureFlagSource struct {
+	Flags []types.FeatureFlag
+	Err   error
+}
+
+func (m *MockFeatureFlagSource) FeatureFlags(ctx context.Context) ([]types.FeatureFlag, error) {
+	return m.Flags, m.Err
+}
+
+// MockAdminStore is an in-memory types.AdminStore. Mutations append to
+// AuditLog; Err, when set, fails every call.
+type MockAdminStore struct {
+	Flags     []types.FeatureFlag
+	Protocols []types.Protocol
+	AuditLog  []types.AuditEntry
+	Err       error
+}
+
+func (m *MockAdminStore) ListFeatureFlags(ctx context.Context) ([]types.FeatureFlag, error) {
+	return m.Flags, m.Err
+}
+
+func (m *MockAdminStore) UpsertFeatureFlag(ctx context.Context, actor string, f types.FeatureFlag) (bool, error) {
+	if m.Err != nil {
+		return false, m.Err
+	}
+	for i := range m.Flags {
+		if m.Flags[i].Key == f.Key {
+			m.Flags[i] = f
+			m.audit(actor, types.AuditActionUpdate, types.AuditEntityFeatureFlag, f.Key)
+			return false, nil
+		}
+	}
+	m.Flags = append(m.Flags, f)
+	m.audit(actor, types.AuditActionCreate, types.AuditEntityFeatureFlag, f.Key)
+	return true, nil
+}
+
+func (m *MockAdminStore) DeleteFeatureFlag(ctx context.Context, actor, key string) (bool, error) {
+	if m.Err != nil {
+		return false, m.Err
+	}
+	for i := range m.Flags {
+		if m.Flags[i].Key == key {
+			m.Flags = append(m.Flags[:i], m.Flags[i+1:]...)
+			m.audit(actor, types.AuditActionDelete, types.AuditEntityFeatureFlag, key)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockAdminStore) ListProtocols(ctx context.Context) ([]types.Protocol, error) {
+	return m.Protocols, m.Err
+}
+
+func (m *MockAdminStore) UpsertProtocol(ctx context.Context, actor string, p types.Protocol) (bool, error) {
+	if m.Err != nil {
+		return false, m.Err
+	}
+	for i := range m.Protocols {
+		if m.Protocols[i].Name == p.Name {
+			m.Protocols[i] = p
+			m.audit(actor, types.AuditActionUpdate, types.AuditEntityProtocol, p.Name)
+			return false, nil
+		}
+	}
+	m.Protocols = append(m.Protocols, p)
+	m.audit(actor, types.AuditActionCreate, types.AuditEntityProtocol, p.Name)
+	return true, nil
+}
+
+func (m *MockAdminStore) DeleteProtocol(ctx context.Context, actor, name string) (bool, error) {
+	if m.Err != nil {
+		return false, m.Err
+	}
+	for i := range m.Protocols {
+		if m.Protocols[i].Name == name {
+			m.Protocols = append(m.Protocols[:i], m.Protocols[i+1:]...)
+			m.audit(actor, types.AuditActionDelete, types.AuditEntityProtocol, name)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockAdminStore) ListAuditLog(ctx context.Context, entityType, entityKey string, limit int) ([]types.AuditEntry, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var entries []types.AuditEntry
+	for i := len(m.AuditLog) - 1; i >= 0 && len(entries) < limit; i-- {
+		e := m.AuditLog[i]
+		if (entityType == "" || e.EntityType == entityType) && (entityKey == "" || e.EntityKey == entityKey) {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+func (m *MockAdminStore) audit(actor, action, entityType, entityKey string) {
+	m.AuditLog = append(m.AuditLog, types.AuditEntry{
+		ID: int64(len(m.AuditLog) + 1), Actor: actor, Action: action, EntityType: entityType, EntityKey: entityKey,
+	})
+}
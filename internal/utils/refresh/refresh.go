@@ -0,0 +1,44 @@
+package refresh
+
+import (
+	"context"
+	"time"
+
+	"github.com/stellar/freighter-backend-v2/internal/logger"
+)
+
+// Loop reloads an in-memory snapshot from its source every Interval. The
+// database- and file-backed registries, catalogs and flag sources each run
+// one from their Run method.
+type Loop struct {
+	Interval time.Duration
+	// Refresh reloads the snapshot once. On failure it keeps serving the
+	// previous one.
+	Refresh func(ctx context.Context) error
+	// FailureMessage is what a failed refresh is logged with.
+	FailureMessage string
+}
+
+// Run calls Refresh every Interval until ctx is done. A failure is logged
+// once, not on every tick while it persists, and failures caused by ctx
+// being cancelled are not logged at all.
+func (l Loop) Run(ctx context.Context) {
+	ticker := time.NewTicker(l.Interval)
+	defer ticker.Stop()
+	var lastErr string
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := l.Refresh(ctx)
+			if err != nil && ctx.Err() == nil && err.Error() != lastErr {
+				logger.Warn(l.FailureMessage, "error", err)
+			}
+			lastErr = ""
+			if err != nil {
+				lastErr = err.Error()
+			}
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package refresh
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoop_RefreshesUntilCancelled(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int64
+	loop := Loop{
+		Interval: 5 * time.Millisecond,
+		Refresh: func(context.Context) error {
+			if calls.Add(1)%2 == 0 {
+				return errors.New("source unavailable")
+			}
+			return nil
+		},
+		FailureMessage: "test: refresh failed",
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		loop.Run(ctx)
+		close(done)
+	}()
+	assert.Eventually(t, func() bool { return calls.Load() >= 3 }, time.Second, time.Millisecond, "failures don't stop the loop")
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		require.FailNow(t, "Run kept running after its context was cancelled")
+	}
+}
@@ -0,0 +1,251 @@
+package scval
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// ToJSON encodes v in its canonical JSON form, as produced by ToNative.
+func ToJSON(v xdr.ScVal) (json.RawMessage, error) {
+	native, err := ToNative(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(native)
+}
+
+// ToNative converts v into plain Go values that marshal to canonical JSON:
+//
+//	void                       -> null
+//	bool                       -> true | false
+//	u32, i32                   -> number
+//	u64, i64, u128, i128,
+//	u256, i256, timepoint,
+//	duration                   -> base-10 string
+//	symbol, string             -> string
+//	address                    -> strkey ("G...", "C...", "M...", "B...", "L...")
+//	bytes                      -> hex string
+//	vec                        -> array
+//	map                        -> object when every key is a distinct symbol
+//	                              or string, else [{"key": ..., "value": ...}]
+//	error                      -> {"error": {"type": "contract", "code": 7}}
+//
+// As in Value, integers wider than 32 bits are strings so they survive JSON
+// number precision. Ledger-key and contract-instance values are not contract
+// results and fail with ErrUnsupportedType.
+func ToNative(v xdr.ScVal) (any, error) {
+	return toNative(v, 0)
+}
+
+// Text returns v as a Go string when it is a string, symbol or address, the
+// types contracts use for names, URIs and owners.
+func Text(v xdr.ScVal) (string, error) {
+	switch v.Type {
+	case xdr.ScValTypeScvString, xdr.ScValTypeScvSymbol, xdr.ScValTypeScvAddress:
+		native, err := ToNative(v)
+		if err != nil {
+			return "", err
+		}
+		return native.(string), nil
+	}
+	return "", fmt.Errorf("got %s, want string, symbol or address", v.Type)
+}
+
+func toNative(v xdr.ScVal, depth int) (any, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("nesting exceeds %d levels", maxDepth)
+	}
+	switch v.Type {
+	case xdr.ScValTypeScvVoid:
+		return nil, nil
+	case xdr.ScValTypeScvBool:
+		return bool(v.MustB()), nil
+	case xdr.ScValTypeScvU32:
+		return uint32(v.MustU32()), nil
+	case xdr.ScValTypeScvI32:
+		return int32(v.MustI32()), nil
+	case xdr.ScValTypeScvU64:
+		return new(big.Int).SetUint64(uint64(v.MustU64())).String(), nil
+	case xdr.ScValTypeScvI64:
+		return big.NewInt(int64(v.MustI64())).String(), nil
+	case xdr.ScValTypeScvTimepoint:
+		return new(big.Int).SetUint64(uint64(v.MustTimepoint())).String(), nil
+	case xdr.ScValTypeScvDuration:
+		return new(big.Int).SetUint64(uint64(v.MustDuration())).String(), nil
+	case xdr.ScValTypeScvU128:
+		parts := v.MustU128()
+		return fromWords(false, uint64(parts.Hi), uint64(parts.Lo)).String(), nil
+	case xdr.ScValTypeScvI128:
+		parts := v.MustI128()
+		return fromWords(true, uint64(parts.Hi), uint64(parts.Lo)).String(), nil //nolint:gosec // two's-complement reinterpretation
+	case xdr.ScValTypeScvU256:
+		parts := v.MustU256()
+		return fromWords(false, uint64(parts.HiHi), uint64(parts.HiLo), uint64(parts.LoHi), uint64(parts.LoLo)).String(), nil
+	case xdr.ScValTypeScvI256:
+		parts := v.MustI256()
+		return fromWords(true, uint64(parts.HiHi), uint64(parts.HiLo), uint64(parts.LoHi), uint64(parts.LoLo)).String(), nil //nolint:gosec // two's-complement reinterpretation
+	case xdr.ScValTypeScvSymbol:
+		return string(v.MustSym()), nil
+	case xdr.ScValTypeScvString:
+		return string(v.MustStr()), nil
+	case xdr.ScValTypeScvBytes:
+		return hex.EncodeToString(v.MustBytes()), nil
+	case xdr.ScValTypeScvAddress:
+		addr, err := v.MustAddress().String()
+		if err != nil {
+			return nil, fmt.Errorf("address: %w", err)
+		}
+		return addr, nil
+	case xdr.ScValTypeScvVec:
+		vec := v.MustVec()
+		out := make([]any, 0)
+		if vec == nil {
+			return out, nil
+		}
+		for i, item := range *vec {
+			n, err := toNative(item, depth+1)
+			if err != nil {
+				return nil, fmt.Errorf("vec[%d]: %w", i, err)
+			}
+			out = append(out, n)
+		}
+		return out, nil
+	case xdr.ScValTypeScvMap:
+		m := v.MustMap()
+		if m == nil {
+			return map[string]any{}, nil
+		}
+		return mapToNative(*m, depth)
+	case xdr.ScValTypeScvError:
+		return errorToNative(v.MustError())
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, v.Type)
+}
+
+// mapToNative renders m as a JSON object when its keys are distinct strings or
+// symbols, which is how contracts encode structs. Any other map keeps its
+// entries as an ordered list of key/value pairs.
+func mapToNative(m xdr.ScMap, depth int) (any, error) {
+	type entry struct {
+		Key   any `json:"key"`
+		Value any `json:"value"`
+	}
+	entries := make([]entry, len(m))
+	object := make(map[string]any, len(m))
+	stringKeys := true
+	for i, e := range m {
+		key, err := toNative(e.Key, depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("map[%d].key: %w", i, err)
+		}
+		val, err := toNative(e.Val, depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("map[%d].value: %w", i, err)
+		}
+		entries[i] = entry{Key: key, Value: val}
+
+		if !stringKeys {
+			continue
+		}
+		if e.Key.Type != xdr.ScValTypeScvSymbol && e.Key.Type != xdr.ScValTypeScvString {
+			stringKeys = false
+			continue
+		}
+		k := key.(string)
+		if _, dup := object[k]; dup {
+			stringKeys = false
+			continue
+		}
+		object[k] = val
+	}
+	if stringKeys {
+		return object, nil
+	}
+	return entries, nil
+}
+
+func errorToNative(e xdr.ScError) (any, error) {
+	detail := map[string]any{"type": errorTypeName(e.Type)}
+	switch e.Type {
+	case xdr.ScErrorTypeSceContract:
+		detail["code"] = uint32(e.MustContractCode())
+	default:
+		code, ok := e.GetCode()
+		if !ok {
+			return nil, fmt.Errorf("error: %s has no code", e.Type)
+		}
+		detail["code"] = errorCodeName(code)
+	}
+	return map[string]any{"error": detail}, nil
+}
+
+func errorTypeName(t xdr.ScErrorType) string {
+	switch t {
+	case xdr.ScErrorTypeSceContract:
+		return "contract"
+	case xdr.ScErrorTypeSceWasmVm:
+		return "wasm_vm"
+	case xdr.ScErrorTypeSceContext:
+		return "context"
+	case xdr.ScErrorTypeSceStorage:
+		return "storage"
+	case xdr.ScErrorTypeSceObject:
+		return "object"
+	case xdr.ScErrorTypeSceCrypto:
+		return "crypto"
+	case xdr.ScErrorTypeSceEvents:
+		return "events"
+	case xdr.ScErrorTypeSceBudget:
+		return "budget"
+	case xdr.ScErrorTypeSceValue:
+		return "value"
+	case xdr.ScErrorTypeSceAuth:
+		return "auth"
+	}
+	return t.String()
+}
+
+func errorCodeName(c xdr.ScErrorCode) string {
+	switch c {
+	case xdr.ScErrorCodeScecArithDomain:
+		return "arith_domain"
+	case xdr.ScErrorCodeScecIndexBounds:
+		return "index_bounds"
+	case xdr.ScErrorCodeScecInvalidInput:
+		return "invalid_input"
+	case xdr.ScErrorCodeScecMissingValue:
+		return "missing_value"
+	case xdr.ScErrorCodeScecExistingValue:
+		return "existing_value"
+	case xdr.ScErrorCodeScecExceededLimit:
+		return "exceeded_limit"
+	case xdr.ScErrorCodeScecInvalidAction:
+		return "invalid_action"
+	case xdr.ScErrorCodeScecInternalError:
+		return "internal_error"
+	case xdr.ScErrorCodeScecUnexpectedType:
+		return "unexpected_type"
+	case xdr.ScErrorCodeScecUnexpectedSize:
+		return "unexpected_size"
+	}
+	return c.String()
+}
+
+// fromWords joins big-endian 64-bit words into an integer, reading them as
+// two's complement when signed. It is the inverse of uint64Words.
+func fromWords(signed bool, words ...uint64) *big.Int {
+	n := new(big.Int)
+	for _, w := range words {
+		n.Lsh(n, 64)
+		n.Or(n, new(big.Int).SetUint64(w))
+	}
+	bits := uint(len(words) * 64)
+	if signed && n.Bit(int(bits)-1) == 1 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), bits))
+	}
+	return n
+}
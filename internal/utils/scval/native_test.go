@@ -0,0 +1,180 @@
+package scval
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validContract = "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC"
+
+func sym(s string) xdr.ScVal {
+	v := xdr.ScSymbol(s)
+	return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &v}
+}
+
+func str(s string) xdr.ScVal {
+	v := xdr.ScString(s)
+	return xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &v}
+}
+
+func u32(n uint32) xdr.ScVal {
+	v := xdr.Uint32(n)
+	return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &v}
+}
+
+func scMap(entries ...xdr.ScMapEntry) xdr.ScVal {
+	m := xdr.ScMap(entries)
+	mp := &m
+	return xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &mp}
+}
+
+func TestToJSON_Scalars(t *testing.T) {
+	t.Parallel()
+
+	b := true
+	i32 := xdr.Int32(-7)
+	u64 := xdr.Uint64(18446744073709551615)
+	i64 := xdr.Int64(-9223372036854775808)
+	tp := xdr.TimePoint(1700000000)
+	bytes := xdr.ScBytes{0xde, 0xad}
+
+	cases := []struct {
+		name string
+		val  xdr.ScVal
+		want string
+	}{
+		{"void", xdr.ScVal{Type: xdr.ScValTypeScvVoid}, `null`},
+		{"bool", xdr.ScVal{Type: xdr.ScValTypeScvBool, B: &b}, `true`},
+		{"u32", u32(7), `7`},
+		{"i32", xdr.ScVal{Type: xdr.ScValTypeScvI32, I32: &i32}, `-7`},
+		{"u64", xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &u64}, `"18446744073709551615"`},
+		{"i64", xdr.ScVal{Type: xdr.ScValTypeScvI64, I64: &i64}, `"-9223372036854775808"`},
+		{"timepoint", xdr.ScVal{Type: xdr.ScValTypeScvTimepoint, Timepoint: &tp}, `"1700000000"`},
+		{"u128", xdr.ScVal{Type: xdr.ScValTypeScvU128, U128: &xdr.UInt128Parts{Hi: 1, Lo: 5}}, `"18446744073709551621"`},
+		{"i128 negative", xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &xdr.Int128Parts{Hi: -1, Lo: ^xdr.Uint64(0)}}, `"-1"`},
+		{"u256", xdr.ScVal{Type: xdr.ScValTypeScvU256, U256: &xdr.UInt256Parts{HiHi: 1}}, `"6277101735386680763835789423207666416102355444464034512896"`},
+		{"i256 negative", xdr.ScVal{Type: xdr.ScValTypeScvI256, I256: &xdr.Int256Parts{HiHi: -1, HiLo: ^xdr.Uint64(0), LoHi: ^xdr.Uint64(0), LoLo: ^xdr.Uint64(1)}}, `"-2"`},
+		{"symbol", sym("transfer"), `"transfer"`},
+		{"string", str(`say "hi"`), `"say \"hi\""`},
+		{"bytes", xdr.ScVal{Type: xdr.ScValTypeScvBytes, Bytes: &bytes}, `"dead"`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ToJSON(tc.val)
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.want, string(got))
+		})
+	}
+}
+
+func TestToJSON_Addresses(t *testing.T) {
+	t.Parallel()
+
+	for _, addr := range []string{validAccount, validContract} {
+		v, err := Value{Type: "address", Value: []byte(`"` + addr + `"`)}.ToScVal()
+		require.NoError(t, err)
+
+		got, err := ToJSON(v)
+		require.NoError(t, err)
+		assert.JSONEq(t, `"`+addr+`"`, string(got))
+	}
+}
+
+func TestToJSON_Containers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("vec", func(t *testing.T) {
+		t.Parallel()
+		vec := &xdr.ScVec{u32(1), sym("a")}
+		got, err := ToJSON(xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &vec})
+		require.NoError(t, err)
+		assert.JSONEq(t, `[1,"a"]`, string(got))
+	})
+
+	t.Run("nil vec is an empty array", func(t *testing.T) {
+		t.Parallel()
+		var vec *xdr.ScVec
+		got, err := ToJSON(xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &vec})
+		require.NoError(t, err)
+		assert.JSONEq(t, `[]`, string(got))
+	})
+
+	t.Run("struct-like map is an object", func(t *testing.T) {
+		t.Parallel()
+		got, err := ToJSON(scMap(
+			xdr.ScMapEntry{Key: sym("name"), Val: str("Meridian")},
+			xdr.ScMapEntry{Key: str("attributes"), Val: scMap(xdr.ScMapEntry{Key: sym("level"), Val: u32(3)})},
+		))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name":"Meridian","attributes":{"level":3}}`, string(got))
+	})
+
+	t.Run("map with non-string keys is a list of pairs", func(t *testing.T) {
+		t.Parallel()
+		got, err := ToJSON(scMap(
+			xdr.ScMapEntry{Key: u32(1), Val: sym("one")},
+			xdr.ScMapEntry{Key: u32(2), Val: sym("two")},
+		))
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"key":1,"value":"one"},{"key":2,"value":"two"}]`, string(got))
+	})
+
+	t.Run("symbol and string keys that collide keep every entry", func(t *testing.T) {
+		t.Parallel()
+		got, err := ToJSON(scMap(
+			xdr.ScMapEntry{Key: sym("a"), Val: u32(1)},
+			xdr.ScMapEntry{Key: str("a"), Val: u32(2)},
+		))
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"key":"a","value":1},{"key":"a","value":2}]`, string(got))
+	})
+}
+
+func TestToJSON_Errors(t *testing.T) {
+	t.Parallel()
+
+	code := xdr.Uint32(7)
+	got, err := ToJSON(xdr.ScVal{Type: xdr.ScValTypeScvError, Error: &xdr.ScError{Type: xdr.ScErrorTypeSceContract, ContractCode: &code}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"error":{"type":"contract","code":7}}`, string(got))
+
+	hostCode := xdr.ScErrorCodeScecMissingValue
+	got, err = ToJSON(xdr.ScVal{Type: xdr.ScValTypeScvError, Error: &xdr.ScError{Type: xdr.ScErrorTypeSceStorage, Code: &hostCode}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"error":{"type":"storage","code":"missing_value"}}`, string(got))
+}
+
+func TestToJSON_RejectsUnsupported(t *testing.T) {
+	t.Parallel()
+
+	_, err := ToJSON(xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance})
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+
+	_, err = ToJSON(scMap(xdr.ScMapEntry{Key: sym("k"), Val: xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance}}))
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+}
+
+func TestText(t *testing.T) {
+	t.Parallel()
+
+	got, err := Text(str("ipfs://bafy/1.json"))
+	require.NoError(t, err)
+	assert.Equal(t, "ipfs://bafy/1.json", got)
+
+	got, err = Text(sym("MPAY"))
+	require.NoError(t, err)
+	assert.Equal(t, "MPAY", got)
+
+	addr, err := Value{Type: "address", Value: []byte(`"` + validAccount + `"`)}.ToScVal()
+	require.NoError(t, err)
+	got, err = Text(addr)
+	require.NoError(t, err)
+	assert.Equal(t, validAccount, got)
+
+	_, err = Text(u32(1))
+	assert.Error(t, err)
+}
@@ -0,0 +1,269 @@
+package scval
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+
+	"github.com/stellar/freighter-backend-v2/internal/utils"
+)
+
+// maxDepth bounds vec/map nesting in a decoded argument so a hostile body
+// cannot recurse arbitrarily deep.
+const maxDepth = 16
+
+var ErrUnsupportedType = errors.New("unsupported ScVal type")
+
+// Value is the JSON form of an ScVal contract argument: a type tag and a
+// type-specific value.
+//
+//	{"type": "bool", "value": true}
+//	{"type": "void"}
+//	{"type": "u32" | "i32", "value": 7}
+//	{"type": "u64" | "i64" | "u128" | "i128" | "u256" | "i256", "value": "1000000"}
+//	{"type": "symbol" | "string", "value": "transfer"}
+//	{"type": "address", "value": "G..." | "C..."}
+//	{"type": "bytes", "value": "<hex>"}
+//	{"type": "vec", "value": [<Value>, ...]}
+//	{"type": "map", "value": [{"key": <Value>, "value": <Value>}, ...]}
+//	{"type": "xdr", "value": "<base64 ScVal>"}
+//
+// Integers wider than 32 bits are base-10 strings, since JSON numbers lose
+// precision above 2^53; plain numbers are accepted too.
+type Value struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type mapEntry struct {
+	Key   Value `json:"key"`
+	Value Value `json:"value"`
+}
+
+// ToScVal converts v into the ScVal it describes.
+func (v Value) ToScVal() (xdr.ScVal, error) {
+	return v.toScVal(0)
+}
+
+func (v Value) toScVal(depth int) (xdr.ScVal, error) {
+	if depth > maxDepth {
+		return xdr.ScVal{}, fmt.Errorf("nesting exceeds %d levels", maxDepth)
+	}
+	switch strings.ToLower(v.Type) {
+	case "void":
+		return xdr.ScVal{Type: xdr.ScValTypeScvVoid}, nil
+	case "bool":
+		var b bool
+		if err := json.Unmarshal(v.Value, &b); err != nil {
+			return xdr.ScVal{}, fmt.Errorf("bool: %w", err)
+		}
+		return xdr.ScVal{Type: xdr.ScValTypeScvBool, B: &b}, nil
+	case "u32":
+		n, err := v.integer(big.NewInt(0), big.NewInt(math.MaxUint32))
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("u32: %w", err)
+		}
+		u := xdr.Uint32(n.Uint64())
+		return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &u}, nil
+	case "i32":
+		n, err := v.integer(big.NewInt(math.MinInt32), big.NewInt(math.MaxInt32))
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("i32: %w", err)
+		}
+		i := xdr.Int32(n.Int64())
+		return xdr.ScVal{Type: xdr.ScValTypeScvI32, I32: &i}, nil
+	case "u64":
+		n, err := v.integer(big.NewInt(0), new(big.Int).SetUint64(math.MaxUint64))
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("u64: %w", err)
+		}
+		u := xdr.Uint64(n.Uint64())
+		return xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &u}, nil
+	case "i64":
+		n, err := v.integer(big.NewInt(math.MinInt64), big.NewInt(math.MaxInt64))
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("i64: %w", err)
+		}
+		i := xdr.Int64(n.Int64())
+		return xdr.ScVal{Type: xdr.ScValTypeScvI64, I64: &i}, nil
+	case "u128":
+		n, err := v.integer(big.NewInt(0), maxUnsigned(128))
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("u128: %w", err)
+		}
+		words := uint64Words(n, 2)
+		parts := xdr.UInt128Parts{Hi: xdr.Uint64(words[0]), Lo: xdr.Uint64(words[1])}
+		return xdr.ScVal{Type: xdr.ScValTypeScvU128, U128: &parts}, nil
+	case "i128":
+		n, err := v.integer(minSigned(128), maxSigned(128))
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("i128: %w", err)
+		}
+		words := uint64Words(n, 2)
+		parts := xdr.Int128Parts{Hi: xdr.Int64(words[0]), Lo: xdr.Uint64(words[1])} //nolint:gosec // two's-complement reinterpretation
+		return xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &parts}, nil
+	case "u256":
+		n, err := v.integer(big.NewInt(0), maxUnsigned(256))
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("u256: %w", err)
+		}
+		w := uint64Words(n, 4)
+		parts := xdr.UInt256Parts{HiHi: xdr.Uint64(w[0]), HiLo: xdr.Uint64(w[1]), LoHi: xdr.Uint64(w[2]), LoLo: xdr.Uint64(w[3])}
+		return xdr.ScVal{Type: xdr.ScValTypeScvU256, U256: &parts}, nil
+	case "i256":
+		n, err := v.integer(minSigned(256), maxSigned(256))
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("i256: %w", err)
+		}
+		w := uint64Words(n, 4)
+		parts := xdr.Int256Parts{HiHi: xdr.Int64(w[0]), HiLo: xdr.Uint64(w[1]), LoHi: xdr.Uint64(w[2]), LoLo: xdr.Uint64(w[3])} //nolint:gosec // two's-complement reinterpretation
+		return xdr.ScVal{Type: xdr.ScValTypeScvI256, I256: &parts}, nil
+	case "symbol":
+		s, err := v.text()
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("symbol: %w", err)
+		}
+		if len(s) > 32 {
+			return xdr.ScVal{}, fmt.Errorf("symbol: %q is longer than 32 characters", s)
+		}
+		sym := xdr.ScSymbol(s)
+		return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym}, nil
+	case "string":
+		s, err := v.text()
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("string: %w", err)
+		}
+		str := xdr.ScString(s)
+		return xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &str}, nil
+	case "address":
+		s, err := v.text()
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("address: %w", err)
+		}
+		addr, err := utils.ScAddressFromString(s)
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("address: %w", err)
+		}
+		return xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: addr}, nil
+	case "bytes":
+		s, err := v.text()
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("bytes: %w", err)
+		}
+		raw, err := hex.DecodeString(s)
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("bytes: %w", err)
+		}
+		b := xdr.ScBytes(raw)
+		return xdr.ScVal{Type: xdr.ScValTypeScvBytes, Bytes: &b}, nil
+	case "vec":
+		var items []Value
+		if err := json.Unmarshal(v.Value, &items); err != nil {
+			return xdr.ScVal{}, fmt.Errorf("vec: %w", err)
+		}
+		vec := make(xdr.ScVec, len(items))
+		for i, item := range items {
+			sv, err := item.toScVal(depth + 1)
+			if err != nil {
+				return xdr.ScVal{}, fmt.Errorf("vec[%d]: %w", i, err)
+			}
+			vec[i] = sv
+		}
+		vecPtr := &vec
+		return xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &vecPtr}, nil
+	case "map":
+		var entries []mapEntry
+		if err := json.Unmarshal(v.Value, &entries); err != nil {
+			return xdr.ScVal{}, fmt.Errorf("map: %w", err)
+		}
+		m := make(xdr.ScMap, len(entries))
+		for i, entry := range entries {
+			key, err := entry.Key.toScVal(depth + 1)
+			if err != nil {
+				return xdr.ScVal{}, fmt.Errorf("map[%d].key: %w", i, err)
+			}
+			val, err := entry.Value.toScVal(depth + 1)
+			if err != nil {
+				return xdr.ScVal{}, fmt.Errorf("map[%d].value: %w", i, err)
+			}
+			m[i] = xdr.ScMapEntry{Key: key, Val: val}
+		}
+		mapPtr := &m
+		return xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &mapPtr}, nil
+	case "xdr":
+		s, err := v.text()
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("xdr: %w", err)
+		}
+		var sv xdr.ScVal
+		if err := xdr.SafeUnmarshalBase64(s, &sv); err != nil {
+			return xdr.ScVal{}, fmt.Errorf("xdr: %w", err)
+		}
+		return sv, nil
+	}
+	return xdr.ScVal{}, fmt.Errorf("%w: %q", ErrUnsupportedType, v.Type)
+}
+
+func (v Value) text() (string, error) {
+	var s string
+	if err := json.Unmarshal(v.Value, &s); err != nil {
+		return "", err
+	}
+	return s, nil
+}
+
+// integer parses a JSON number or base-10 string and checks it is within
+// [lo, hi].
+func (v Value) integer(lo, hi *big.Int) (*big.Int, error) {
+	raw := strings.TrimSpace(string(v.Value))
+	if strings.HasPrefix(raw, `"`) {
+		var s string
+		if err := json.Unmarshal(v.Value, &s); err != nil {
+			return nil, err
+		}
+		raw = s
+	}
+	n, ok := new(big.Int).SetString(raw, 10)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a base-10 integer", raw)
+	}
+	if n.Cmp(lo) < 0 || n.Cmp(hi) > 0 {
+		return nil, fmt.Errorf("%s is out of range", n)
+	}
+	return n, nil
+}
+
+// uint64Words splits n's two's-complement representation into count
+// big-endian 64-bit words.
+func uint64Words(n *big.Int, count int) []uint64 {
+	bits := uint(count * 64)
+	u := new(big.Int).Set(n)
+	if u.Sign() < 0 {
+		u.Add(u, new(big.Int).Lsh(big.NewInt(1), bits))
+	}
+	words := make([]uint64, count)
+	mask := new(big.Int).SetUint64(math.MaxUint64)
+	for i := count - 1; i >= 0; i-- {
+		words[i] = new(big.Int).And(u, mask).Uint64()
+		u.Rsh(u, 64)
+	}
+	return words
+}
+
+func maxUnsigned(bits uint) *big.Int {
+	return new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), bits), big.NewInt(1))
+}
+
+func maxSigned(bits uint) *big.Int {
+	return maxUnsigned(bits - 1)
+}
+
+func minSigned(bits uint) *big.Int {
+	return new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), bits-1))
+}
@@ -0,0 +1,118 @@
+package scval
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validAccount = "GA5ZSEJYB37JRC5AVCIA5MOP4RHTM335X2KGX3IHOJAPP5RE34K4KZVN"
+
+func parse(t *testing.T, raw string) (xdr.ScVal, error) {
+	t.Helper()
+	var v Value
+	require.NoError(t, json.Unmarshal([]byte(raw), &v))
+	return v.ToScVal()
+}
+
+func TestValue_ToScVal_Scalars(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name  string
+		input string
+		check func(t *testing.T, v xdr.ScVal)
+	}{
+		{"void", `{"type":"void"}`, func(t *testing.T, v xdr.ScVal) { assert.Equal(t, xdr.ScValTypeScvVoid, v.Type) }},
+		{"bool", `{"type":"bool","value":true}`, func(t *testing.T, v xdr.ScVal) { assert.True(t, *v.B) }},
+		{"u32 number", `{"type":"u32","value":7}`, func(t *testing.T, v xdr.ScVal) { assert.EqualValues(t, 7, *v.U32) }},
+		{"i32 negative", `{"type":"I32","value":-7}`, func(t *testing.T, v xdr.ScVal) { assert.EqualValues(t, -7, *v.I32) }},
+		{"u64 string", `{"type":"u64","value":"18446744073709551615"}`, func(t *testing.T, v xdr.ScVal) { assert.EqualValues(t, uint64(18446744073709551615), *v.U64) }},
+		{"i64", `{"type":"i64","value":"-9223372036854775808"}`, func(t *testing.T, v xdr.ScVal) { assert.EqualValues(t, int64(-9223372036854775808), *v.I64) }},
+		{"u128 above 64 bits", `{"type":"u128","value":"18446744073709551621"}`, func(t *testing.T, v xdr.ScVal) {
+			assert.Equal(t, xdr.UInt128Parts{Hi: 1, Lo: 5}, *v.U128)
+		}},
+		{"i128 negative", `{"type":"i128","value":"-1"}`, func(t *testing.T, v xdr.ScVal) {
+			assert.Equal(t, xdr.Int128Parts{Hi: -1, Lo: ^xdr.Uint64(0)}, *v.I128)
+		}},
+		{"i256", `{"type":"i256","value":"-2"}`, func(t *testing.T, v xdr.ScVal) {
+			assert.Equal(t, xdr.Int256Parts{HiHi: -1, HiLo: ^xdr.Uint64(0), LoHi: ^xdr.Uint64(0), LoLo: ^xdr.Uint64(1)}, *v.I256)
+		}},
+		{"u256", `{"type":"u256","value":"1"}`, func(t *testing.T, v xdr.ScVal) { assert.Equal(t, xdr.UInt256Parts{LoLo: 1}, *v.U256) }},
+		{"symbol", `{"type":"symbol","value":"balance"}`, func(t *testing.T, v xdr.ScVal) { assert.EqualValues(t, "balance", *v.Sym) }},
+		{"string", `{"type":"string","value":"hello"}`, func(t *testing.T, v xdr.ScVal) { assert.EqualValues(t, "hello", *v.Str) }},
+		{"bytes", `{"type":"bytes","value":"deadbeef"}`, func(t *testing.T, v xdr.ScVal) { assert.EqualValues(t, []byte{0xde, 0xad, 0xbe, 0xef}, *v.Bytes) }},
+		{"address", `{"type":"address","value":"` + validAccount + `"}`, func(t *testing.T, v xdr.ScVal) {
+			assert.Equal(t, xdr.ScAddressTypeScAddressTypeAccount, v.Address.Type)
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			v, err := parse(t, tc.input)
+			require.NoError(t, err)
+			tc.check(t, v)
+		})
+	}
+}
+
+func TestValue_ToScVal_Composites(t *testing.T) {
+	t.Parallel()
+
+	v, err := parse(t, `{"type":"vec","value":[{"type":"u32","value":1},{"type":"map","value":[{"key":{"type":"symbol","value":"k"},"value":{"type":"bool","value":false}}]}]}`)
+	require.NoError(t, err)
+	vec := **v.Vec
+	require.Len(t, vec, 2)
+	assert.EqualValues(t, 1, *vec[0].U32)
+	m := **vec[1].Map
+	require.Len(t, m, 1)
+	assert.EqualValues(t, "k", *m[0].Key.Sym)
+	assert.False(t, *m[0].Val.B)
+
+	u := xdr.Uint32(9)
+	encoded, err := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &u})
+	require.NoError(t, err)
+	v, err = parse(t, `{"type":"xdr","value":"`+encoded+`"}`)
+	require.NoError(t, err)
+	assert.EqualValues(t, 9, *v.U32)
+}
+
+func TestValue_ToScVal_Errors(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"unknown type":      `{"type":"float","value":1.5}`,
+		"u32 overflow":      `{"type":"u32","value":4294967296}`,
+		"u64 negative":      `{"type":"u64","value":"-1"}`,
+		"i128 overflow":     `{"type":"i128","value":"170141183460469231731687303715884105728"}`,
+		"not an integer":    `{"type":"i64","value":"1.5"}`,
+		"long symbol":       `{"type":"symbol","value":"abcdefghijklmnopqrstuvwxyz0123456789"}`,
+		"bad address":       `{"type":"address","value":"nope"}`,
+		"bad hex":           `{"type":"bytes","value":"zz"}`,
+		"bad xdr":           `{"type":"xdr","value":"!!"}`,
+		"bad vec element":   `{"type":"vec","value":[{"type":"u32","value":-1}]}`,
+		"bool wrong type":   `{"type":"bool","value":"true"}`,
+		"missing map value": `{"type":"map","value":[{"key":{"type":"void"}}]}`,
+	}
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := parse(t, input)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestValue_ToScVal_DepthLimit(t *testing.T) {
+	t.Parallel()
+
+	raw := `{"type":"void"}`
+	for range maxDepth + 1 {
+		raw = `{"type":"vec","value":[` + raw + `]}`
+	}
+	_, err := parse(t, raw)
+	assert.ErrorContains(t, err, "nesting exceeds")
+}
@@ -0,0 +1,139 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrMalformed = errors.New("version is malformed: expected MAJOR[.MINOR[.PATCH]][-PRERELEASE]")
+
+// Version is a parsed semantic version. Build metadata is dropped; it never
+// affects ordering.
+type Version struct {
+	Major, Minor, Patch int
+	Prerelease          string
+}
+
+// Parse accepts "1.6.23", "v1.6.23", "1.6" (patch 0) and "1.7.0-beta.1".
+func Parse(input string) (Version, error) {
+	s := strings.TrimPrefix(strings.TrimSpace(input), "v")
+	s, _, _ = strings.Cut(s, "+")
+	core, prerelease, hasPrerelease := strings.Cut(s, "-")
+	if hasPrerelease && prerelease == "" {
+		return Version{}, ErrMalformed
+	}
+	parts := strings.Split(core, ".")
+	if len(parts) > 3 {
+		return Version{}, ErrMalformed
+	}
+	var nums [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || part != strconv.Itoa(n) {
+			return Version{}, ErrMalformed
+		}
+		nums[i] = n
+	}
+	return Version{Major: nums[0], Minor: nums[1], Patch: nums[2], Prerelease: prerelease}, nil
+}
+
+// Compare returns -1, 0 or 1 as v is older than, equal to or newer than o. A
+// prerelease sorts before its release; prereleases compare as strings.
+func (v Version) Compare(o Version) int {
+	for _, d := range [...]int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d != 0 {
+			if d < 0 {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case v.Prerelease == o.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case o.Prerelease == "":
+		return -1
+	default:
+		return strings.Compare(v.Prerelease, o.Prerelease)
+	}
+}
+
+// Range is a parsed version range: "||"-separated alternatives, each a
+// space-separated list of comparators that must all hold, e.g.
+// ">=1.6.0 <1.7.0 || 2.0.0". A comparator is an operator (=, >, >=, <, <=)
+// followed by a version; a bare version means "=". Prereleases order below
+// their release, so "<1.7.0" admits "1.7.0-beta".
+type Range struct {
+	alternatives [][]comparator
+}
+
+type comparator struct {
+	op      string
+	version Version
+}
+
+// ParseRange parses a Range. An empty string is rejected rather than read as
+// "any version": leave the range unset instead.
+func ParseRange(input string) (Range, error) {
+	var r Range
+	for _, alternative := range strings.Split(input, "||") {
+		fields := strings.Fields(alternative)
+		if len(fields) == 0 {
+			return Range{}, fmt.Errorf("version range %q has an empty alternative", input)
+		}
+		var all []comparator
+		for _, field := range fields {
+			op := "="
+			for _, candidate := range []string{">=", "<=", ">", "<", "="} {
+				if strings.HasPrefix(field, candidate) {
+					op = candidate
+					break
+				}
+			}
+			v, err := Parse(strings.TrimPrefix(field, op))
+			if err != nil {
+				return Range{}, fmt.Errorf("version range %q: %w", input, err)
+			}
+			all = append(all, comparator{op: op, version: v})
+		}
+		r.alternatives = append(r.alternatives, all)
+	}
+	return r, nil
+}
+
+// Contains reports whether v satisfies any alternative of r.
+func (r Range) Contains(v Version) bool {
+	for _, all := range r.alternatives {
+		ok := true
+		for _, c := range all {
+			if !c.holds(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (c comparator) holds(v Version) bool {
+	cmp := v.Compare(c.version)
+	switch c.op {
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	default:
+		return cmp == 0
+	}
+}
@@ -0,0 +1,86 @@
+package semver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		input string
+		want  Version
+	}{
+		{"1.6.23", Version{Major: 1, Minor: 6, Patch: 23}},
+		{"v2.0.1", Version{Major: 2, Patch: 1}},
+		{"1.6", Version{Major: 1, Minor: 6}},
+		{"3", Version{Major: 3}},
+		{"1.7.0-beta.1+build.5", Version{Major: 1, Minor: 7, Prerelease: "beta.1"}},
+	}
+	for _, tc := range cases {
+		got, err := Parse(tc.input)
+		require.NoError(t, err, tc.input)
+		assert.Equal(t, tc.want, got, tc.input)
+	}
+
+	for _, input := range []string{"", "1.2.3.4", "1.x", "01.2.3", "-1.0.0", "1.0.0-", "latest"} {
+		_, err := Parse(input)
+		assert.ErrorIs(t, err, ErrMalformed, input)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	ordered := []string{"1.0.0-alpha", "1.0.0-beta", "1.0.0", "1.0.1", "1.2.0", "1.10.0", "2.0.0"}
+	for i := range ordered {
+		for j := range ordered {
+			a, _ := Parse(ordered[i])
+			b, _ := Parse(ordered[j])
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			assert.Equal(t, want, a.Compare(b), "%s vs %s", ordered[i], ordered[j])
+		}
+	}
+}
+
+func TestRange(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		rng      string
+		contains []string
+		excludes []string
+	}{
+		{"1.6.23", []string{"1.6.23", "v1.6.23"}, []string{"1.6.22", "1.6.24"}},
+		{">=1.6.0 <1.7.0", []string{"1.6.0", "1.6.99"}, []string{"1.5.9", "1.6.0-rc.1", "1.7.0"}},
+		{"<1.5 || >=2.0.0", []string{"1.4.9", "2.0.0", "3.1.0"}, []string{"1.5.0", "1.9.9"}},
+		{">1.0.0 <=1.2.0", []string{"1.0.1", "1.2.0"}, []string{"1.0.0", "1.2.1"}},
+	}
+	for _, tc := range cases {
+		r, err := ParseRange(tc.rng)
+		require.NoError(t, err, tc.rng)
+		for _, s := range tc.contains {
+			v, err := Parse(s)
+			require.NoError(t, err)
+			assert.True(t, r.Contains(v), "%q should contain %s", tc.rng, s)
+		}
+		for _, s := range tc.excludes {
+			v, err := Parse(s)
+			require.NoError(t, err)
+			assert.False(t, r.Contains(v), "%q should exclude %s", tc.rng, s)
+		}
+	}
+
+	for _, rng := range []string{"", "||", ">=1.0.0 ||", ">=x", "~1.2"} {
+		_, err := ParseRange(rng)
+		assert.Error(t, err, rng)
+	}
+}
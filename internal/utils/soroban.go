@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+// SorobanEnvelope decodes a base64 TransactionEnvelope and checks it is one
+// simulateTransaction accepts: a V1 (non-fee-bump) envelope with exactly one
+// InvokeHostFunction, ExtendFootprintTTL or RestoreFootprint operation.
+func SorobanEnvelope(envelopeXDR string) (xdr.TransactionEnvelope, error) {
+	var envelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXDR, &envelope); err != nil {
+		return envelope, fmt.Errorf("decoding transaction envelope: %w", err)
+	}
+	if envelope.Type != xdr.EnvelopeTypeEnvelopeTypeTx || envelope.V1 == nil {
+		return envelope, errors.New("transaction envelope must be a V1 transaction, not a fee bump or V0")
+	}
+	ops := envelope.V1.Tx.Operations
+	if len(ops) != 1 {
+		return envelope, fmt.Errorf("soroban transactions carry exactly one operation, got %d", len(ops))
+	}
+	switch ops[0].Body.Type {
+	case xdr.OperationTypeInvokeHostFunction, xdr.OperationTypeExtendFootprintTtl, xdr.OperationTypeRestoreFootprint:
+		return envelope, nil
+	}
+	return envelope, fmt.Errorf("operation %s is not a soroban operation", ops[0].Body.Type)
+}
+
+// AssembleTransaction applies a successful simulation to the envelope it was
+// run against, the way the JS SDK's assembleTransaction does: it attaches the
+// simulated SorobanTransactionData, raises the fee by the minimum resource fee
+// (replacing any resource fee already present), and fills in the simulated
+// authorization entries when the invocation carries none. Any signatures are
+// dropped since the transaction hash changes.
+func AssembleTransaction(envelope xdr.TransactionEnvelope, sim *types.SimulationResult) (string, error) {
+	if sim.Error != "" {
+		return "", fmt.Errorf("cannot assemble a failed simulation: %s", sim.Error)
+	}
+	if envelope.V1 == nil || len(envelope.V1.Tx.Operations) != 1 {
+		return "", errors.New("envelope is not a single-operation V1 transaction")
+	}
+	tx := &envelope.V1.Tx
+
+	var data xdr.SorobanTransactionData
+	if err := xdr.SafeUnmarshalBase64(sim.TransactionData, &data); err != nil {
+		return "", fmt.Errorf("decoding simulated transaction data: %w", err)
+	}
+
+	classicFee := int64(tx.Fee)
+	if tx.Ext.SorobanData != nil {
+		classicFee -= int64(tx.Ext.SorobanData.ResourceFee)
+	}
+	fee := classicFee + sim.MinResourceFee
+	if classicFee < 0 || sim.MinResourceFee < 0 || fee > math.MaxUint32 {
+		return "", fmt.Errorf("assembled fee %d is out of range", fee)
+	}
+	tx.Fee = xdr.Uint32(fee)
+	tx.Ext = xdr.TransactionExt{V: 1, SorobanData: &data}
+
+	if invoke, ok := tx.Operations[0].Body.GetInvokeHostFunctionOp(); ok && len(invoke.Auth) == 0 && len(sim.Auth) > 0 {
+		auth := make([]xdr.SorobanAuthorizationEntry, len(sim.Auth))
+		for i, entry := range sim.Auth {
+			if err := xdr.SafeUnmarshalBase64(entry, &auth[i]); err != nil {
+				return "", fmt.Errorf("decoding simulated auth entry %d: %w", i, err)
+			}
+		}
+		invoke.Auth = auth
+		tx.Operations[0].Body.InvokeHostFunctionOp = &invoke
+	}
+	envelope.V1.Signatures = nil
+
+	assembled, err := xdr.MarshalBase64(envelope)
+	if err != nil {
+		return "", fmt.Errorf("encoding assembled transaction: %w", err)
+	}
+	return assembled, nil
+}
@@ -0,0 +1,162 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/network"
+	"github.com/stellar/go-stellar-sdk/txnbuild"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/freighter-backend-v2/internal/types"
+)
+
+func testInvokeEnvelope(t *testing.T, sign bool, ops ...txnbuild.Operation) string {
+	t.Helper()
+	kp := keypair.MustRandom()
+	if len(ops) == 0 {
+		contractID := xdr.ContractId{1}
+		ops = []txnbuild.Operation{&txnbuild.InvokeHostFunction{
+			HostFunction: xdr.HostFunction{
+				Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+				InvokeContract: &xdr.InvokeContractArgs{
+					ContractAddress: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID},
+					FunctionName:    "transfer",
+					Args:            xdr.ScVec{},
+				},
+			},
+			SourceAccount: kp.Address(),
+		}}
+	}
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &txnbuild.SimpleAccount{AccountID: kp.Address(), Sequence: 1},
+		IncrementSequenceNum: true,
+		Operations:           ops,
+		BaseFee:              txnbuild.MinBaseFee,
+		Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()},
+	})
+	require.NoError(t, err)
+	if sign {
+		tx, err = tx.Sign(network.TestNetworkPassphrase, kp)
+		require.NoError(t, err)
+	}
+	encoded, err := tx.Base64()
+	require.NoError(t, err)
+	return encoded
+}
+
+func testSimulation(t *testing.T) *types.SimulationResult {
+	t.Helper()
+	data, err := xdr.MarshalBase64(xdr.SorobanTransactionData{
+		Resources:   xdr.SorobanResources{Instructions: 1000, DiskReadBytes: 10, WriteBytes: 20},
+		ResourceFee: 5000,
+	})
+	require.NoError(t, err)
+	nonce := xdr.Int64(7)
+	contractID := xdr.ContractId{1}
+	auth, err := xdr.MarshalBase64(xdr.SorobanAuthorizationEntry{
+		Credentials: xdr.SorobanCredentials{Type: xdr.SorobanCredentialsTypeSorobanCredentialsSourceAccount},
+		RootInvocation: xdr.SorobanAuthorizedInvocation{
+			Function: xdr.SorobanAuthorizedFunction{
+				Type: xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeContractFn,
+				ContractFn: &xdr.InvokeContractArgs{
+					ContractAddress: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID},
+					FunctionName:    "transfer",
+					Args:            xdr.ScVec{{Type: xdr.ScValTypeScvI64, I64: &nonce}},
+				},
+			},
+			SubInvocations: []xdr.SorobanAuthorizedInvocation{},
+		},
+	})
+	require.NoError(t, err)
+	return &types.SimulationResult{TransactionData: data, MinResourceFee: 5000, Auth: []string{auth}, Events: []string{}}
+}
+
+func TestSorobanEnvelope(t *testing.T) {
+	t.Parallel()
+
+	_, err := SorobanEnvelope(testInvokeEnvelope(t, false))
+	assert.NoError(t, err)
+
+	_, err = SorobanEnvelope(testInvokeEnvelope(t, false, &txnbuild.RestoreFootprint{}))
+	assert.NoError(t, err)
+
+	_, err = SorobanEnvelope("not-xdr")
+	assert.ErrorContains(t, err, "decoding transaction envelope")
+
+	_, err = SorobanEnvelope(testInvokeEnvelope(t, false, &txnbuild.BumpSequence{BumpTo: 10}))
+	assert.ErrorContains(t, err, "not a soroban operation")
+
+	_, err = SorobanEnvelope(testInvokeEnvelope(t, false, &txnbuild.RestoreFootprint{}, &txnbuild.RestoreFootprint{}))
+	assert.ErrorContains(t, err, "exactly one operation")
+}
+
+func TestAssembleTransaction(t *testing.T) {
+	t.Parallel()
+
+	t.Run("applies data, fee and auth", func(t *testing.T) {
+		t.Parallel()
+		envelope, err := SorobanEnvelope(testInvokeEnvelope(t, true))
+		require.NoError(t, err)
+
+		assembled, err := AssembleTransaction(envelope, testSimulation(t))
+		require.NoError(t, err)
+
+		var out xdr.TransactionEnvelope
+		require.NoError(t, xdr.SafeUnmarshalBase64(assembled, &out))
+		assert.EqualValues(t, txnbuild.MinBaseFee+5000, out.V1.Tx.Fee)
+		require.NotNil(t, out.V1.Tx.Ext.SorobanData)
+		assert.EqualValues(t, 5000, out.V1.Tx.Ext.SorobanData.ResourceFee)
+		assert.EqualValues(t, 1000, out.V1.Tx.Ext.SorobanData.Resources.Instructions)
+		assert.Len(t, out.V1.Tx.Operations[0].Body.MustInvokeHostFunctionOp().Auth, 1)
+		assert.Empty(t, out.V1.Signatures, "signatures no longer match the assembled hash")
+	})
+
+	t.Run("reassembly replaces the previous resource fee", func(t *testing.T) {
+		t.Parallel()
+		envelope, err := SorobanEnvelope(testInvokeEnvelope(t, false))
+		require.NoError(t, err)
+		first, err := AssembleTransaction(envelope, testSimulation(t))
+		require.NoError(t, err)
+
+		envelope, err = SorobanEnvelope(first)
+		require.NoError(t, err)
+		sim := testSimulation(t)
+		sim.MinResourceFee = 8000
+		second, err := AssembleTransaction(envelope, sim)
+		require.NoError(t, err)
+
+		var out xdr.TransactionEnvelope
+		require.NoError(t, xdr.SafeUnmarshalBase64(second, &out))
+		// The first resource fee is replaced, not stacked.
+		assert.EqualValues(t, txnbuild.MinBaseFee+8000, out.V1.Tx.Fee)
+	})
+
+	t.Run("footprint operations take no auth", func(t *testing.T) {
+		t.Parallel()
+		envelope, err := SorobanEnvelope(testInvokeEnvelope(t, false, &txnbuild.RestoreFootprint{}))
+		require.NoError(t, err)
+		_, err = AssembleTransaction(envelope, testSimulation(t))
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed simulation", func(t *testing.T) {
+		t.Parallel()
+		envelope, err := SorobanEnvelope(testInvokeEnvelope(t, false))
+		require.NoError(t, err)
+		_, err = AssembleTransaction(envelope, &types.SimulationResult{Error: "HostError: contract panicked"})
+		assert.ErrorContains(t, err, "failed simulation")
+	})
+
+	t.Run("fee overflow", func(t *testing.T) {
+		t.Parallel()
+		envelope, err := SorobanEnvelope(testInvokeEnvelope(t, false))
+		require.NoError(t, err)
+		sim := testSimulation(t)
+		sim.MinResourceFee = 1 << 32
+		_, err = AssembleTransaction(envelope, sim)
+		assert.ErrorContains(t, err, "out of range")
+	})
+}
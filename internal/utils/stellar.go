@@ -4,10 +4,32 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/stellar/go-stellar-sdk/network"
 	"github.com/stellar/go-stellar-sdk/strkey"
 	"github.com/stellar/go-stellar-sdk/xdr"
+
+	"github.com/stellar/freighter-backend-v2/internal/types"
 )
 
+// SimulationSourceAccount is the source account for read-only simulations
+// with no natural caller. Simulation never loads the source account, so it
+// need not exist; this is the all-zero ed25519 key.
+const SimulationSourceAccount = "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"
+
+// NetworkPassphrase returns the passphrase for one of the supported network
+// names (PUBLIC, TESTNET, FUTURENET).
+func NetworkPassphrase(networkName string) (string, error) {
+	switch networkName {
+	case types.PUBLIC:
+		return network.PublicNetworkPassphrase, nil
+	case types.TESTNET:
+		return network.TestNetworkPassphrase, nil
+	case types.FUTURENET:
+		return network.FutureNetworkPassphrase, nil
+	}
+	return "", fmt.Errorf("unknown network: %s", networkName)
+}
+
 func IsValidContractID(s string) bool {
 	decoded, err := strkey.Decode(strkey.VersionByteContract, s)
 	return err == nil && len(decoded) == 32
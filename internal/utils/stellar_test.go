@@ -3,9 +3,11 @@ package utils
 import (
 	"testing"
 
+	"github.com/stellar/go-stellar-sdk/network"
 	"github.com/stellar/go-stellar-sdk/strkey"
 	"github.com/stellar/go-stellar-sdk/xdr"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestIsValidContractID(t *testing.T) {
@@ -287,3 +289,18 @@ func TestScVecToStrings(t *testing.T) {
 		assert.Nil(t, res)
 	})
 }
+
+func TestNetworkPassphrase(t *testing.T) {
+	for name, want := range map[string]string{
+		"PUBLIC":    network.PublicNetworkPassphrase,
+		"TESTNET":   network.TestNetworkPassphrase,
+		"FUTURENET": network.FutureNetworkPassphrase,
+	} {
+		got, err := NetworkPassphrase(name)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	_, err := NetworkPassphrase("MAINNET")
+	assert.Error(t, err)
+}